@@ -0,0 +1,156 @@
+// Package licenses resolves and normalizes the licenses language analyzers
+// find in a package manifest or alongside one, so every cataloger (yarn,
+// gemspec, and friends) reports licenses the same way instead of each
+// inventing its own ad-hoc string cleanup.
+package licenses
+
+import "strings"
+
+// canonical maps common ad-hoc spellings onto their SPDX license identifier.
+// This isn't a full SPDX expression parser - just the handful of spellings
+// real-world package.json/gemspec "license" fields actually use in practice.
+var canonical = map[string]string{
+	"mit":           "MIT",
+	"apache2":       "Apache-2.0",
+	"apache 2":      "Apache-2.0",
+	"apache 2.0":    "Apache-2.0",
+	"apache-2":      "Apache-2.0",
+	"bsd":           "BSD-3-Clause",
+	"isc":           "ISC",
+	"gpl-2.0":       "GPL-2.0-only",
+	"gpl-3.0":       "GPL-3.0-only",
+	"lgpl-2.1":      "LGPL-2.1-only",
+	"mpl-2.0":       "MPL-2.0",
+	"unlicense":     "Unlicense",
+	"public domain": "Unlicense",
+}
+
+// filePrefixes are the base-filename prefixes (case-insensitive) a file
+// conventionally carrying license text starts with.
+var filePrefixes = []string{
+	"LICENSE",
+	"LICENCE",
+	"COPYING",
+	"NOTICE",
+	"README",
+}
+
+// licenseText maps a phrase found near the top of a well-known license's
+// canonical text onto its SPDX identifier, for Scanner.Classify's fallback
+// when a package declares no license field at all.
+var licenseText = map[string]string{
+	"MIT License":                           "MIT",
+	"Permission is hereby granted, free":    "MIT",
+	"Apache License\nVersion 2.0":           "Apache-2.0",
+	"Apache License, Version 2.0":           "Apache-2.0",
+	"GNU GENERAL PUBLIC LICENSE\nVersion 2": "GPL-2.0-only",
+	"GNU GENERAL PUBLIC LICENSE\nVersion 3": "GPL-3.0-only",
+	"GNU LESSER GENERAL PUBLIC LICENSE":     "LGPL-2.1-only",
+	"Mozilla Public License Version 2.0":    "MPL-2.0",
+	"BSD 3-Clause":                          "BSD-3-Clause",
+	"BSD 2-Clause":                          "BSD-2-Clause",
+}
+
+// Scanner resolves a package's license: Normalize canonicalizes a declared
+// SPDX-ish string, and Classify falls back to sniffing a LICENSE/COPYING/
+// NOTICE/README file's body when nothing was declared. Construct one per
+// scan with NewScanner and share it across every language analyzer that
+// needs licenses, via analyzer.AnalyzerOptions, so they reuse the same
+// --license-full/--license-confidence-threshold configuration rather than
+// each analyzer reimplementing this independently.
+type Scanner struct {
+	full                bool
+	confidenceThreshold float64
+}
+
+// Option configures a Scanner.
+type Option func(*Scanner)
+
+// WithFull enables Classify's file-content fallback. Off by default, since
+// reading and matching every LICENSE/COPYING/NOTICE/README file in a large
+// tree is meaningfully more work than reading a manifest's "license" field.
+func WithFull(full bool) Option {
+	return func(s *Scanner) {
+		s.full = full
+	}
+}
+
+// WithConfidenceThreshold sets the minimum confidence (0 to 1) Classify
+// requires to report a match.
+func WithConfidenceThreshold(t float64) Option {
+	return func(s *Scanner) {
+		s.confidenceThreshold = t
+	}
+}
+
+// NewScanner returns a Scanner with Classify disabled and a 0.9 confidence
+// threshold, overridden by opts.
+func NewScanner(opts ...Option) *Scanner {
+	s := &Scanner{confidenceThreshold: 0.9}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Normalize canonicalizes a declared license string. A simple "(A OR B)" or
+// "A OR B" disjunction - the common case in package.json "license" fields -
+// is expanded by normalizing each operand and rejoining with " OR "; this
+// only handles that single-level form, not the full SPDX expression
+// grammar (AND, WITH, nested parens).
+func (s *Scanner) Normalize(declared string) string {
+	declared = strings.TrimSpace(declared)
+	if declared == "" {
+		return ""
+	}
+
+	inner := strings.TrimSuffix(strings.TrimPrefix(declared, "("), ")")
+	if parts := strings.Split(inner, " OR "); len(parts) > 1 {
+		normalized := make([]string, len(parts))
+		for i, part := range parts {
+			normalized[i] = normalizeOne(part)
+		}
+		return strings.Join(normalized, " OR ")
+	}
+
+	return normalizeOne(declared)
+}
+
+func normalizeOne(s string) string {
+	s = strings.TrimSpace(s)
+	if canon, ok := canonical[strings.ToLower(s)]; ok {
+		return canon
+	}
+	return s
+}
+
+// IsLicenseFile reports whether name (a base filename) is the kind of file
+// Classify can sniff a license out of.
+func IsLicenseFile(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range filePrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify sniffs body - the contents of a file named name - for one of a
+// small set of well-known license texts, returning its SPDX id. ok is false
+// when WithFull wasn't set, name isn't a license-ish file, or body doesn't
+// contain a recognized phrase at or above the Scanner's confidence
+// threshold. Every recognized phrase is treated as full confidence - this
+// classifier does exact substring matching, not fuzzy comparison, so there's
+// no finer-grained score to compute.
+func (s *Scanner) Classify(name, body string) (id string, ok bool) {
+	if !s.full || !IsLicenseFile(name) || s.confidenceThreshold > 1 {
+		return "", false
+	}
+	for phrase, spdxID := range licenseText {
+		if strings.Contains(body, phrase) {
+			return spdxID, true
+		}
+	}
+	return "", false
+}
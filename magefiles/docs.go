@@ -46,8 +46,32 @@ func main() {
 	}
 }
 
+// flagGroups is the registry buildFlagsTree walks: every flag.FlagGroup
+// constructor whose options should appear in config-file.md. Add a new
+// group here - and nowhere else - when pkg/flag grows one; genMarkdown
+// and getFlagMetadata discover a group's flags purely by reflecting over
+// its exported *flag.Flag fields, so they need no group-specific code.
+//
+// NOTE: the Image/Global/Scan/DB/Secret/Misconfiguration/Kubernetes/
+// Cloud/Vulnerability flag groups that make up the rest of the real
+// flag.NewOptions() wiring aren't present in this checkout to register
+// here; add them once they are, the generator itself already handles them.
+var flagGroups = []func() any{
+	func() any { return *flag.NewCacheFlagGroup() },
+	func() any { return *flag.NewReportFlagGroup() },
+	func() any { return *flag.NewRegistryFlagGroup() },
+	func() any { return *flag.NewSBOMFlagGroup() },
+	func() any { return *flag.NewAttestationFlagGroup() },
+}
+
 // generateConfigDocs creates custom markdown output.
 func generateConfigDocs(filename string) error {
+	metadata := allFlagsMetadata()
+	if missing := missingConfigNames(metadata); len(missing) > 0 {
+		return fmt.Errorf("flags missing a ConfigName (would be silently dropped from %s): %s",
+			filename, strings.Join(missing, ", "))
+	}
+
 	f, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -56,8 +80,7 @@ func generateConfigDocs(filename string) error {
 	f.WriteString("# " + title + "\n\n")
 	f.WriteString(description + "\n")
 
-	flagsMetadata := buildFlagsTree()
-	genMarkdown(flagsMetadata, 0, f)
+	genMarkdown(buildFlagsTree(metadata), 0, f)
 
 	f.WriteString(footer)
 	return nil
@@ -67,25 +90,84 @@ type flagMetadata struct {
 	name         string
 	configName   string
 	defaultValue any
+	usage        string
+	deprecated   string
+}
+
+// allFlagsMetadata collects the flagMetadata of every group in flagGroups.
+func allFlagsMetadata() []*flagMetadata {
+	var metadata []*flagMetadata
+	for _, newGroup := range flagGroups {
+		metadata = append(metadata, getFlagMetadata(newGroup())...)
+	}
+	return metadata
 }
 
+// missingConfigNames returns the --flag-name of every flag whose
+// ConfigName is empty. addToMap keys a flag off its configName, so a
+// flag that forgot to set one wouldn't render an error - it would just
+// vanish from config-file.md. The smoke test in docs_test.go calls this
+// directly against the real flagGroups registry.
+func missingConfigNames(metadata []*flagMetadata) []string {
+	var missing []string
+	for _, m := range metadata {
+		if m.configName == "" {
+			missing = append(missing, m.name)
+		}
+	}
+	return missing
+}
+
+// getFlagMetadata walks the exported *flag.Flag fields of a flag group
+// struct (CacheFlagGroup, ReportFlagGroup, ...) via reflection. It works
+// for every flag regardless of what kind of value flag.Flag.Value holds
+// (bool, string, int, []string, map[string]string, ...) since that's
+// carried as `any` rather than a distinct generic instantiation per
+// field - so a new flag group never needs a matching change here.
 func getFlagMetadata(flagGroup any) []*flagMetadata {
 	result := []*flagMetadata{}
 	val := reflect.ValueOf(flagGroup)
 	for i := 0; i < val.NumField(); i++ {
-		p, ok := val.Field(i).Interface().(*flag.Flag[string])
-		if !ok {
+		p, ok := val.Field(i).Interface().(*flag.Flag)
+		if !ok || p == nil {
 			continue
 		}
 		result = append(result, &flagMetadata{
 			name:         p.Name,
 			configName:   p.ConfigName,
-			defaultValue: p.Default,
+			defaultValue: p.Value,
+			usage:        p.Usage,
+			deprecated:   deprecationNote(p),
 		})
 	}
 	return result
 }
 
+// deprecationNote looks up a "Deprecated"/"Experimental" marker on p by
+// field name rather than direct field access, so doc generation degrades
+// gracefully (no annotation, not a build failure) if the flag.Flag in
+// this build doesn't carry such a field yet.
+func deprecationNote(p *flag.Flag) string {
+	v := reflect.ValueOf(*p)
+	for _, name := range []string{"Deprecated", "Experimental"} {
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.String:
+			if s := f.String(); s != "" {
+				return s
+			}
+		case reflect.Bool:
+			if f.Bool() {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
 func addToMap(m map[string]any, parts []string, value *flagMetadata) {
 	if len(parts) == 0 {
 		return
@@ -108,13 +190,8 @@ func addToMap(m map[string]any, parts []string, value *flagMetadata) {
 	addToMap(subMap, parts[1:], value)
 }
 
-func buildFlagsTree() map[string]any {
+func buildFlagsTree(metadata []*flagMetadata) map[string]any {
 	res := map[string]any{}
-	metadata := getFlagMetadata(*flag.NewImageFlagGroup())
-	metadata = append(metadata, getFlagMetadata(*flag.NewCacheFlagGroup())...)
-	metadata = append(metadata, getFlagMetadata(*flag.NewReportFlagGroup())...)
-	metadata = append(metadata, getFlagMetadata(*flag.NewGlobalFlagGroup())...)
-
 	for _, m := range metadata {
 		addToMap(res, strings.Split(m.configName, "."), m)
 	}
@@ -144,12 +221,54 @@ func genMarkdown(m map[string]any, indent int, w *os.File) {
 			fmt.Fprintf(w, "%s%s:\n", indentation, key)
 			genMarkdown(v, indent+1, w)
 		case *flagMetadata:
+			if v.usage != "" {
+				fmt.Fprintf(w, "%s# %s\n", indentation, v.usage)
+			}
 			fmt.Fprintf(w, "%s# Same as '--%s'\n", indentation, v.name)
+			if v.deprecated != "" {
+				fmt.Fprintf(w, "%s# Deprecated: %s\n", indentation, v.deprecated)
+			}
 			fmt.Fprintf(w, "%s# Default is %v\n", indentation, v.defaultValue)
-			fmt.Fprintf(w, "%s%s: %+v\n\n", indentation, key, v.defaultValue)
+			writeYAMLValue(w, indentation, key, v.defaultValue)
+			w.WriteString("\n")
 		}
 		if indent == 0 {
 			w.WriteString("```\n\n")
 		}
 	}
 }
+
+// writeYAMLValue renders a flag's default as YAML in whatever shape its
+// type calls for - a scalar on the same line, a slice as one `- value`
+// per line, a map as one `key: value` per line - rather than Go's
+// `%+v` dump of a slice/map, which isn't valid YAML a user could paste
+// into trivy.yaml as-is.
+func writeYAMLValue(w *os.File, indentation, key string, value any) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			fmt.Fprintf(w, "%s%s: []\n", indentation, key)
+			return
+		}
+		fmt.Fprintf(w, "%s%s:\n", indentation, key)
+		for i := 0; i < rv.Len(); i++ {
+			fmt.Fprintf(w, "%s  - %v\n", indentation, rv.Index(i).Interface())
+		}
+	case reflect.Map:
+		if rv.Len() == 0 {
+			fmt.Fprintf(w, "%s%s: {}\n", indentation, key)
+			return
+		}
+		fmt.Fprintf(w, "%s%s:\n", indentation, key)
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s  %v: %v\n", indentation, k.Interface(), rv.MapIndex(k).Interface())
+		}
+	default:
+		fmt.Fprintf(w, "%s%s: %v\n", indentation, key, value)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build mage_docs
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMissingConfigNames guards against a flag that's wired into a
+// FlagGroup struct but forgot to set ConfigName: addToMap keys a flag off
+// its configName, so such a flag would silently disappear from
+// config-file.md instead of failing doc generation loudly.
+func TestMissingConfigNames(t *testing.T) {
+	missing := missingConfigNames(allFlagsMetadata())
+	assert.Empty(t, missing, "flags missing a ConfigName: %v", missing)
+}
+
+// TestBuildFlagsTree_NotEmpty is a smoke test that flagGroups actually
+// discovers flags, so an empty registry (or a getFlagMetadata regression
+// that stops matching *flag.Flag fields) doesn't pass silently.
+func TestBuildFlagsTree_NotEmpty(t *testing.T) {
+	metadata := allFlagsMetadata()
+	require.NotEmpty(t, metadata)
+
+	tree := buildFlagsTree(metadata)
+	assert.NotEmpty(t, tree)
+	// Spot-check one group from each corner of the registry made it in.
+	assert.Contains(t, tree, "cache")
+	assert.Contains(t, tree, "sbom")
+}
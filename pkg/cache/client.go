@@ -11,9 +11,12 @@ import (
 )
 
 const (
-	TypeUnknown Type = "unknown"
-	TypeFS      Type = "fs"
-	TypeRedis   Type = "redis"
+	TypeUnknown   Type = "unknown"
+	TypeFS        Type = "fs"
+	TypeRedis     Type = "redis"
+	TypeMemcached Type = "memcached"
+	TypeS3        Type = "s3"
+	TypeGCS       Type = "gcs"
 )
 
 type Type string
@@ -26,14 +29,33 @@ type Options struct {
 	RedisKey    string
 	RedisTLS    bool
 	TTL         time.Duration
+
+	MemcachedTLS bool
+
+	// S3/GCS options. Credentials are read the same way the AWS/gcloud
+	// CLIs would (AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY env vars,
+	// HMAC keys for GCS), so they aren't flags of their own.
+	S3Region         string
+	S3Endpoint       string
+	S3SSECustomerKey []byte
+
+	GCSHMACAccessKey  string
+	GCSHMACSecretKey  string
+	GCSSSECustomerKey []byte
 }
 
 func NewType(backend string) Type {
-	// "redis://" or "fs" are allowed for now
+	// "redis://", "memcached://", "s3://", "gs://" or "fs" are allowed for now
 	// An empty value is also allowed for testability
 	switch {
 	case strings.HasPrefix(backend, "redis://"):
 		return TypeRedis
+	case strings.HasPrefix(backend, "memcached://"):
+		return TypeMemcached
+	case strings.HasPrefix(backend, "s3://"):
+		return TypeS3
+	case strings.HasPrefix(backend, "gs://"):
+		return TypeGCS
 	case backend == "fs", backend == "":
 		return TypeFS
 	default:
@@ -61,6 +83,34 @@ func New(opts Options) (Cache, func(), error) {
 			return nil, cleanup, xerrors.Errorf("unable to initialize fs cache: %w", err)
 		}
 		cache = fsCache
+	case TypeMemcached:
+		memcachedCache, err := NewMemcachedCache(opts.Backend, opts.TTL, opts.MemcachedTLS)
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to initialize memcached cache: %w", err)
+		}
+		cache = memcachedCache
+	case TypeS3:
+		s3Cache, err := NewS3Cache(opts.Backend, S3Options{
+			Region:         opts.S3Region,
+			AccessKey:      os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey:      os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SSECustomerKey: opts.S3SSECustomerKey,
+			Endpoint:       opts.S3Endpoint,
+		})
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to initialize s3 cache: %w", err)
+		}
+		cache = s3Cache
+	case TypeGCS:
+		gcsCache, err := NewGCSCache(opts.Backend, GCSOptions{
+			HMACAccessKey:  opts.GCSHMACAccessKey,
+			HMACSecretKey:  opts.GCSHMACSecretKey,
+			SSECustomerKey: opts.GCSSSECustomerKey,
+		})
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to initialize gcs cache: %w", err)
+		}
+		cache = gcsCache
 	default:
 		return nil, cleanup, xerrors.Errorf("unknown cache type: %s", t)
 	}
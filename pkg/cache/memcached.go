@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// MemcachedCache stores artifact/blob cache entries in one or more
+// memcached servers, talking the plain-text protocol directly since no
+// memcached client is vendored in this checkout. It's a lighter-weight
+// alternative to RedisCache for sharing analysis results across many
+// parallel runners when the operator already runs memcached rather than
+// Redis.
+//
+// Unlike RedisCache, there's no single point of coordination: each key is
+// routed to one of Servers by hashing it, so the fleet scales out simply
+// by adding more memcached hosts (with the caveat that adding/removing a
+// host reshuffles which server each key hashes to, invalidating caches
+// routed to a now-different server - operators who need stable routing
+// across fleet resizes should put a consistent-hashing proxy like
+// mcrouter in front instead).
+type MemcachedCache struct {
+	servers []string
+	ttl     time.Duration
+	dial    func(addr string) (net.Conn, error)
+}
+
+// NewMemcachedCache returns a Cache backed by the memcached servers
+// encoded in "memcached://host1:11211,host2:11211". A bare host is
+// assumed to listen on the standard port 11211.
+//
+// memcached's own `-I`/per-slab eviction and `exptime` already provide
+// TTL-based eviction; ttl is passed as every SET's exptime, so entries
+// age out of memcached's LRU without the cache client needing to run a
+// separate cleanup job the way a lifecycle-policy-free object store
+// would.
+//
+// useTLS dials every server with TLS, parallel to the RedisTLS option
+// NewRedisCache accepts, for managed memcached offerings (e.g. AWS
+// ElastiCache in transit-encryption mode) that require it.
+func NewMemcachedCache(backend string, ttl time.Duration, useTLS bool) (*MemcachedCache, error) {
+	hostList := strings.TrimPrefix(backend, "memcached://")
+	if hostList == "" {
+		return nil, xerrors.New("memcached backend has no servers")
+	}
+
+	var servers []string
+	for _, h := range strings.Split(hostList, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if !strings.Contains(h, ":") {
+			h += ":11211"
+		}
+		servers = append(servers, h)
+	}
+	if len(servers) == 0 {
+		return nil, xerrors.New("memcached backend has no servers")
+	}
+
+	dial := func(addr string) (net.Conn, error) {
+		return net.DialTimeout("tcp", addr, 5*time.Second)
+	}
+	if useTLS {
+		dial = func(addr string) (net.Conn, error) {
+			return tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, nil)
+		}
+	}
+
+	return &MemcachedCache{
+		servers: servers,
+		ttl:     ttl,
+		dial:    dial,
+	}, nil
+}
+
+// serverFor picks the server a key is routed to, via a simple FNV hash
+// over the server list. It doesn't need to be cryptographically strong,
+// just stable for a given fleet size.
+func (c *MemcachedCache) serverFor(key string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return c.servers[h.Sum32()%uint32(len(c.servers))]
+}
+
+func (c *MemcachedCache) set(key string, value []byte) error {
+	conn, err := c.dial(c.serverFor(key))
+	if err != nil {
+		return xerrors.Errorf("dial memcached: %w", err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "set %s 0 %d %d\r\n", key, int(c.ttl.Seconds()), len(value))
+	conn.Write(value)
+	fmt.Fprint(conn, "\r\n")
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return xerrors.Errorf("read memcached response: %w", err)
+	}
+	if !strings.HasPrefix(line, "STORED") {
+		return xerrors.Errorf("memcached set %s: %s", key, strings.TrimSpace(line))
+	}
+	return nil
+}
+
+// get returns (value, found, error). found is false, with a nil error,
+// when memcached reports a plain cache miss.
+func (c *MemcachedCache) get(key string) ([]byte, bool, error) {
+	conn, err := c.dial(c.serverFor(key))
+	if err != nil {
+		return nil, false, xerrors.Errorf("dial memcached: %w", err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "get %s\r\n", key)
+	r := bufio.NewReader(conn)
+
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, false, xerrors.Errorf("read memcached response: %w", err)
+	}
+	if strings.HasPrefix(header, "END") {
+		return nil, false, nil
+	}
+
+	var gotKey string
+	var flags, size int
+	if _, err = fmt.Sscanf(header, "VALUE %s %d %d", &gotKey, &flags, &size); err != nil {
+		return nil, false, xerrors.Errorf("parse memcached response %q: %w", header, err)
+	}
+
+	value := make([]byte, size)
+	if _, err = io.ReadFull(r, value); err != nil {
+		return nil, false, xerrors.Errorf("read memcached value: %w", err)
+	}
+	// Trailing "\r\nEND\r\n".
+	if _, err = r.ReadString('\n'); err != nil {
+		return nil, false, xerrors.Errorf("read memcached trailer: %w", err)
+	}
+	if _, err = r.ReadString('\n'); err != nil {
+		return nil, false, xerrors.Errorf("read memcached trailer: %w", err)
+	}
+	return value, true, nil
+}
+
+func (c *MemcachedCache) delete(key string) error {
+	conn, err := c.dial(c.serverFor(key))
+	if err != nil {
+		return xerrors.Errorf("dial memcached: %w", err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "delete %s\r\n", key)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return xerrors.Errorf("read memcached response: %w", err)
+	}
+	if !strings.HasPrefix(line, "DELETED") && !strings.HasPrefix(line, "NOT_FOUND") {
+		return xerrors.Errorf("memcached delete %s: %s", key, strings.TrimSpace(line))
+	}
+	return nil
+}
+
+func (c *MemcachedCache) MissingBlobs(artifactID string, blobIDs []string) (bool, []string, error) {
+	_, found, err := c.get(artifactKey(artifactID))
+	if err != nil {
+		return false, nil, xerrors.Errorf("check artifact %s: %w", artifactID, err)
+	}
+
+	var missingBlobIDs []string
+	for _, blobID := range blobIDs {
+		_, found, err := c.get(blobKey(blobID))
+		if err != nil {
+			return false, nil, xerrors.Errorf("check blob %s: %w", blobID, err)
+		}
+		if !found {
+			missingBlobIDs = append(missingBlobIDs, blobID)
+		}
+	}
+	return !found, missingBlobIDs, nil
+}
+
+func (c *MemcachedCache) PutArtifact(artifactID string, artifactInfo types.ArtifactInfo) error {
+	b, err := json.Marshal(artifactInfo)
+	if err != nil {
+		return xerrors.Errorf("marshal artifact %s: %w", artifactID, err)
+	}
+	return c.set(artifactKey(artifactID), b)
+}
+
+func (c *MemcachedCache) GetArtifact(artifactID string) (types.ArtifactInfo, error) {
+	var info types.ArtifactInfo
+	b, found, err := c.get(artifactKey(artifactID))
+	if err != nil {
+		return info, xerrors.Errorf("get artifact %s: %w", artifactID, err)
+	}
+	if !found {
+		return info, xerrors.Errorf("artifact %s not found in cache", artifactID)
+	}
+	if err = json.Unmarshal(b, &info); err != nil {
+		return info, xerrors.Errorf("unmarshal artifact %s: %w", artifactID, err)
+	}
+	return info, nil
+}
+
+func (c *MemcachedCache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
+	b, err := json.Marshal(blobInfo)
+	if err != nil {
+		return xerrors.Errorf("marshal blob %s: %w", blobID, err)
+	}
+	return c.set(blobKey(blobID), b)
+}
+
+func (c *MemcachedCache) GetBlob(blobID string) (types.BlobInfo, error) {
+	var info types.BlobInfo
+	b, found, err := c.get(blobKey(blobID))
+	if err != nil {
+		return info, xerrors.Errorf("get blob %s: %w", blobID, err)
+	}
+	if !found {
+		return info, xerrors.Errorf("blob %s not found in cache", blobID)
+	}
+	if err = json.Unmarshal(b, &info); err != nil {
+		return info, xerrors.Errorf("unmarshal blob %s: %w", blobID, err)
+	}
+	return info, nil
+}
+
+func (c *MemcachedCache) DeleteBlobs(blobIDs []string) error {
+	for _, blobID := range blobIDs {
+		if err := c.delete(blobKey(blobID)); err != nil {
+			return xerrors.Errorf("delete blob %s: %w", blobID, err)
+		}
+	}
+	return nil
+}
+
+// Clear is unsupported for the same reason as S3Cache.Clear: memcached
+// has no per-prefix enumeration, only a fleet-wide `flush_all` that would
+// nuke every other application sharing the same memcached servers.
+func (c *MemcachedCache) Clear() error {
+	return xerrors.New("memcached cache: Clear is not supported, rely on TTL expiry instead")
+}
+
+func (c *MemcachedCache) Close() error {
+	return nil
+}
@@ -0,0 +1,320 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // required by the SSE-C header contract, not used for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// objectStore is a minimal AWS SigV4-signed client for an S3-compatible
+// object store, shared by the S3 and GCS cache backends (GCS's XML API
+// speaks the same SigV4-over-HTTP protocol in its "S3 interoperability"
+// mode, so there's no need for two signers). It implements just enough of
+// the REST API - PUT/GET/HEAD/DELETE of a single object - to back the
+// Cache interface; neither the AWS SDK nor the Google Cloud client
+// libraries are vendored in this checkout.
+type objectStore struct {
+	httpClient *http.Client
+	endpoint   string // e.g. "https://s3.us-east-1.amazonaws.com" or "https://storage.googleapis.com"
+	bucket     string
+	region     string // SigV4 needs a region even for GCS; "auto" works against GCS's interop endpoint
+	accessKey  string
+	secretKey  string
+
+	// sseKey, if set, is sent as an SSE-C (customer-supplied) encryption
+	// key on every PUT/GET, so blobs are encrypted at rest with a key the
+	// object store itself never persists.
+	sseKey []byte
+}
+
+// errObjectNotFound is returned by get when the object doesn't exist, to tell
+// a cache miss from a real error. exists (used by MissingBlobs) reports a
+// missing object as (false, nil) instead.
+var errObjectNotFound = xerrors.New("object not found")
+
+func (s *objectStore) url(key string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint, "/"), s.bucket, key)
+}
+
+// putIfAbsent uploads body under key only if no object already exists
+// there, via a conditional `If-None-Match: *` request. This is what lets
+// many parallel Trivy runners share one bucket without re-uploading a blob
+// every runner already analyzed to the same result.
+func (s *objectStore) putIfAbsent(key string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPut, s.url(key), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("new request: %w", err)
+	}
+	req.Header.Set("If-None-Match", "*")
+	s.applySSE(req)
+	if err = s.sign(req, body); err != nil {
+		return xerrors.Errorf("sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("put %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusPreconditionFailed:
+		// 412 Precondition Failed means another runner already wrote this
+		// key, which is exactly the outcome we wanted - not an error.
+		return nil
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		return xerrors.Errorf("put %s: unexpected status %d: %s", key, resp.StatusCode, b)
+	}
+}
+
+// get fetches the object stored under key. It returns errObjectNotFound
+// if no such object exists.
+func (s *objectStore) get(key string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url(key), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("new request: %w", err)
+	}
+	s.applySSE(req)
+	if err = s.sign(req, nil); err != nil {
+		return nil, xerrors.Errorf("sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("get %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, xerrors.Errorf("get %s: unexpected status %d: %s", key, resp.StatusCode, b)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// exists does a cheap HEAD request, used by MissingBlobs so callers don't
+// pay for downloading a blob's body just to check whether it's cached.
+func (s *objectStore) exists(key string) (bool, error) {
+	req, err := http.NewRequest(http.MethodHead, s.url(key), nil)
+	if err != nil {
+		return false, xerrors.Errorf("new request: %w", err)
+	}
+	s.applySSE(req)
+	if err = s.sign(req, nil); err != nil {
+		return false, xerrors.Errorf("sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, xerrors.Errorf("head %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, xerrors.Errorf("head %s: unexpected status %d", key, resp.StatusCode)
+	}
+}
+
+func (s *objectStore) delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.url(key), nil)
+	if err != nil {
+		return xerrors.Errorf("new request: %w", err)
+	}
+	if err = s.sign(req, nil); err != nil {
+		return xerrors.Errorf("sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("delete %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return xerrors.Errorf("delete %s: unexpected status %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+// applySSE sets the SSE-C headers AWS/GCS expect when the cache is
+// configured with a customer-supplied encryption key: the algorithm, the
+// key in base64 form and the base64 MD5 of the raw key - see
+// sseHeaderValue - as both AWS and GCS's interop API expect.
+func (s *objectStore) applySSE(req *http.Request) {
+	if len(s.sseKey) == 0 {
+		return
+	}
+	b64Key, md5Key := sseHeaderValue(s.sseKey)
+	req.Header.Set("x-amz-server-side-encryption-customer-algorithm", "AES256")
+	req.Header.Set("x-amz-server-side-encryption-customer-key", b64Key)
+	req.Header.Set("x-amz-server-side-encryption-customer-key-MD5", md5Key)
+}
+
+// sign adds a SigV4 Authorization header for the "s3" service, following
+// https://docs.aws.amazon.com/general/latest/gr/sigv4-signing-requests.html.
+// GCS's XML API in S3-interoperability mode accepts the same signing
+// scheme when given an HMAC access/secret key pair.
+func (s *objectStore) sign(req *http.Request, body []byte) error {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+
+	canonicalHeaders, signedHeaders := canonicalizeHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/s3/aws4_request", dateStamp, s.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256([]byte("AWS4"+s.secretKey), dateStamp), s.region), "s3"), "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, credentialScope, signedHeaders, signature,
+	))
+	return nil
+}
+
+func canonicalizeHeaders(req *http.Request) (canonical, signed string) {
+	names := []string{"host"}
+	values := map[string]string{"host": req.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, "x-amz-") && lower != "content-type" {
+			continue
+		}
+		names = append(names, lower)
+		values[lower] = req.Header.Get(name)
+	}
+	sort.Strings(names)
+
+	var cb strings.Builder
+	for _, n := range names {
+		cb.WriteString(n)
+		cb.WriteByte(':')
+		cb.WriteString(strings.TrimSpace(values[n]))
+		cb.WriteByte('\n')
+	}
+	return cb.String(), strings.Join(names, ";")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// artifactKey and blobKey namespace cache IDs within a bucket so the S3
+// and GCS backends can share one prefix without an artifact and a blob
+// that happen to share a cache ID colliding.
+func artifactKey(artifactID string) string { return "artifact/" + artifactID }
+func blobKey(blobID string) string         { return "blob/" + blobID }
+
+// putJSON marshals v and uploads it under key, skipping the upload if an
+// object is already there (see objectStore.putIfAbsent) - PutArtifact and
+// PutBlob are called with the same cache ID by every runner that analyzes
+// the same artifact, and the first one to land wins.
+func putJSON(store *objectStore, key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return xerrors.Errorf("marshal %s: %w", key, err)
+	}
+	return store.putIfAbsent(key, b)
+}
+
+// getJSON downloads key and unmarshals it into v.
+func getJSON(store *objectStore, key string, v any) error {
+	b, err := store.get(key)
+	if err != nil {
+		return xerrors.Errorf("get %s: %w", key, err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("unmarshal %s: %w", key, err)
+	}
+	return nil
+}
+
+// objectStoreMissingBlobs implements the MissingBlobs contract (used by
+// both S3Cache and GCSCache) against an objectStore: it HEADs the
+// artifact key and every blob key, since a bucket has no equivalent of
+// Redis's EXISTS-many.
+func objectStoreMissingBlobs(store *objectStore, artifactID string, blobIDs []string) (bool, []string, error) {
+	missingArtifact, err := objectStoreMissing(store, artifactKey(artifactID))
+	if err != nil {
+		return false, nil, xerrors.Errorf("check artifact %s: %w", artifactID, err)
+	}
+
+	var missingBlobIDs []string
+	for _, blobID := range blobIDs {
+		missing, err := objectStoreMissing(store, blobKey(blobID))
+		if err != nil {
+			return false, nil, xerrors.Errorf("check blob %s: %w", blobID, err)
+		}
+		if missing {
+			missingBlobIDs = append(missingBlobIDs, blobID)
+		}
+	}
+	return missingArtifact, missingBlobIDs, nil
+}
+
+func objectStoreMissing(store *objectStore, key string) (bool, error) {
+	exists, err := store.exists(key)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+// sseHeaderValue returns an SSE-C key in the two encodings S3/GCS expect
+// on the wire: the key itself base64-encoded, and an MD5 checksum of the
+// raw key (also base64-encoded) the store uses to detect transmission
+// errors.
+func sseHeaderValue(key []byte) (b64Key, md5Key string) {
+	sum := md5.Sum(key) //nolint:gosec
+	return base64.StdEncoding.EncodeToString(key), base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// S3Cache stores artifact/blob cache entries as individual objects in an
+// S3 bucket, keyed by cache ID. It's meant for CI fleets spread across
+// regions that outgrow a single Redis instance: any runner in any region
+// can share results through one bucket, at the cost of S3's higher
+// per-request latency versus Redis.
+//
+// Uploads use a conditional PUT (If-None-Match: *) so a runner that
+// re-analyzes an artifact another runner already cached doesn't pay for
+// re-uploading it - see objectStore.putIfAbsent.
+type S3Cache struct {
+	store *objectStore
+}
+
+// S3Options configures NewS3Cache. SSECustomerKey, if set, encrypts every
+// object with that key (SSE-C) rather than relying on the bucket's
+// default (SSE-S3/SSE-KMS) encryption.
+type S3Options struct {
+	Region         string
+	AccessKey      string
+	SecretKey      string
+	SSECustomerKey []byte
+	// Endpoint overrides the default "https://s3.<region>.amazonaws.com",
+	// for S3-compatible stores (MinIO, Ceph RGW) reachable at another URL.
+	Endpoint string
+}
+
+// NewS3Cache returns a Cache backed by the S3 bucket identified by
+// "s3://bucket/prefix"; the region is taken from opts.Region (a query string
+// on the backend URL is ignored). Credentials are taken from opts, which
+// in turn the CLI populates from AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY
+// the same way the AWS CLI would.
+//
+// TTL-based eviction is not performed by this client: configure an S3
+// Lifecycle rule on the bucket (expiration after opts... TTL days) to
+// reclaim cache objects the way you would any other S3 lifecycle policy -
+// this client has no permission model for managing bucket lifecycle and
+// shouldn't be changing it out from under the operator.
+func NewS3Cache(backend string, opts S3Options) (*S3Cache, error) {
+	bucket, prefix, err := parseObjectStoreURL(backend)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid s3 backend %q: %w", backend, err)
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+
+	return &S3Cache{
+		store: &objectStore{
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+			endpoint:   endpoint,
+			bucket:     bucket + "/" + prefix,
+			region:     opts.Region,
+			accessKey:  opts.AccessKey,
+			secretKey:  opts.SecretKey,
+			sseKey:     opts.SSECustomerKey,
+		},
+	}, nil
+}
+
+func (c *S3Cache) MissingBlobs(artifactID string, blobIDs []string) (bool, []string, error) {
+	return objectStoreMissingBlobs(c.store, artifactID, blobIDs)
+}
+
+func (c *S3Cache) PutArtifact(artifactID string, artifactInfo types.ArtifactInfo) error {
+	return putJSON(c.store, artifactKey(artifactID), artifactInfo)
+}
+
+func (c *S3Cache) GetArtifact(artifactID string) (types.ArtifactInfo, error) {
+	var info types.ArtifactInfo
+	err := getJSON(c.store, artifactKey(artifactID), &info)
+	return info, err
+}
+
+func (c *S3Cache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
+	return putJSON(c.store, blobKey(blobID), blobInfo)
+}
+
+func (c *S3Cache) GetBlob(blobID string) (types.BlobInfo, error) {
+	var info types.BlobInfo
+	err := getJSON(c.store, blobKey(blobID), &info)
+	return info, err
+}
+
+func (c *S3Cache) DeleteBlobs(blobIDs []string) error {
+	for _, blobID := range blobIDs {
+		if err := c.store.delete(blobKey(blobID)); err != nil {
+			return xerrors.Errorf("delete blob %s: %w", blobID, err)
+		}
+	}
+	return nil
+}
+
+// Clear is intentionally unsupported: deleting every object under the
+// configured prefix would require a bucket-wide List permission this
+// client doesn't assume the caller has granted it, unlike the fs/redis
+// backends which own their entire namespace.
+func (c *S3Cache) Clear() error {
+	return xerrors.New("s3 cache: Clear is not supported, expire objects via a bucket lifecycle rule instead")
+}
+
+func (c *S3Cache) Close() error {
+	return nil
+}
+
+// parseObjectStoreURL splits a "scheme://bucket/prefix" backend string
+// (as used by both s3:// and gs://) into its bucket and key prefix.
+func parseObjectStoreURL(backend string) (bucket, prefix string, err error) {
+	u, err := url.Parse(backend)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Host == "" {
+		return "", "", xerrors.New("missing bucket name")
+	}
+	return u.Host, strings.Trim(u.Path, "/"), nil
+}
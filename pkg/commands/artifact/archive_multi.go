@@ -0,0 +1,290 @@
+package artifact
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquasecurity/fanal/artifact"
+	"github.com/aquasecurity/fanal/cache"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/rpc/client"
+	"github.com/aquasecurity/trivy/pkg/scanner"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// archiveManifestEntry is one entry of a docker save / podman
+// /libpod/images/export tarball's top-level manifest.json.
+type archiveManifestEntry struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// readArchiveManifest reads the manifest.json entries of the image archive
+// at filePath without extracting the rest of the tarball.
+func readArchiveManifest(filePath string) ([]archiveManifestEntry, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, xerrors.Errorf("tar read error: %w", err)
+		}
+		if hdr.Name != "manifest.json" {
+			continue
+		}
+
+		var entries []archiveManifestEntry
+		if err := json.NewDecoder(tr).Decode(&entries); err != nil {
+			return nil, xerrors.Errorf("unable to decode manifest.json: %w", err)
+		}
+		return entries, nil
+	}
+
+	return nil, xerrors.Errorf("manifest.json not found in %s", filePath)
+}
+
+// archiveImageLabel returns a human-readable name for entry: its first repo
+// tag, or its config file name (sans extension) when the image was saved
+// untagged.
+func archiveImageLabel(entry archiveManifestEntry) string {
+	if len(entry.RepoTags) > 0 {
+		return entry.RepoTags[0]
+	}
+	return strings.TrimSuffix(filepath.Base(entry.Config), filepath.Ext(entry.Config))
+}
+
+// extractSingleImageArchive copies entry's config and layers out of the
+// multi-image tarball at filePath into a new, self-contained single-image
+// tarball at a temporary path with its own single-entry manifest.json. This
+// is necessary because image.NewArchiveImage (github.com/aquasecurity/fanal/image)
+// only understands single-image archives.
+func extractSingleImageArchive(filePath string, entry archiveManifestEntry) (string, error) {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("file open error: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "trivy-archive-*.tar")
+	if err != nil {
+		return "", xerrors.Errorf("unable to create temp archive: %w", err)
+	}
+	defer dst.Close()
+
+	tw := tar.NewWriter(dst)
+	defer tw.Close()
+
+	wanted := make(map[string]bool, len(entry.Layers)+1)
+	wanted[entry.Config] = true
+	for _, layer := range entry.Layers {
+		wanted[layer] = true
+	}
+
+	manifestBytes, err := json.Marshal([]archiveManifestEntry{entry})
+	if err != nil {
+		return "", xerrors.Errorf("unable to marshal manifest.json: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0o644, Size: int64(len(manifestBytes))}); err != nil {
+		return "", xerrors.Errorf("tar write error: %w", err)
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return "", xerrors.Errorf("tar write error: %w", err)
+	}
+
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", xerrors.Errorf("tar read error: %w", err)
+		}
+		if !wanted[hdr.Name] {
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return "", xerrors.Errorf("tar write error: %w", err)
+		}
+		if _, err := io.Copy(tw, tr); err != nil { //nolint:gosec
+			return "", xerrors.Errorf("tar copy error: %w", err)
+		}
+	}
+
+	return dst.Name(), nil
+}
+
+// initializeArchiveScannerMulti scans every image a multi-image archive at
+// filePath contains (e.g. produced by `docker save img1 img2` or Podman's
+// /libpod/images/export endpoint), returning one scanner.Scanner and image
+// label per manifest.json entry. When the archive holds a single entry,
+// this delegates straight to initializeArchiveScanner, preserving the
+// existing single-image path.
+func initializeArchiveScannerMulti(ctx context.Context, filePath string, artifactCache cache.ArtifactCache, localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) ([]scanner.Scanner, []string, func(), error) {
+	entries, err := readArchiveManifest(filePath)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("unable to read archive manifest: %w", err)
+	}
+
+	if len(entries) <= 1 {
+		s, err := initializeArchiveScanner(ctx, filePath, artifactCache, localArtifactCache, artifactOption)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		label := filePath
+		if len(entries) == 1 {
+			label = archiveImageLabel(entries[0])
+		}
+		return []scanner.Scanner{s}, []string{label}, func() {}, nil
+	}
+
+	var (
+		scanners []scanner.Scanner
+		labels   []string
+		tmpFiles []string
+	)
+	cleanup := func() {
+		for _, f := range tmpFiles {
+			_ = os.Remove(f)
+		}
+	}
+
+	for _, entry := range entries {
+		tmpPath, err := extractSingleImageArchive(filePath, entry)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, xerrors.Errorf("unable to extract %s from %s: %w", archiveImageLabel(entry), filePath, err)
+		}
+		tmpFiles = append(tmpFiles, tmpPath)
+
+		s, err := initializeArchiveScanner(ctx, tmpPath, artifactCache, localArtifactCache, artifactOption)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, xerrors.Errorf("unable to initialize scanner for %s: %w", archiveImageLabel(entry), err)
+		}
+		scanners = append(scanners, s)
+		labels = append(labels, archiveImageLabel(entry))
+	}
+
+	return scanners, labels, cleanup, nil
+}
+
+// initializeRemoteArchiveScannerMulti is initializeArchiveScannerMulti's
+// client/server-mode counterpart, delegating to initializeRemoteArchiveScanner
+// per manifest.json entry.
+func initializeRemoteArchiveScannerMulti(ctx context.Context, filePath string, artifactCache cache.ArtifactCache, remoteScanOptions client.ScannerOption, artifactOption artifact.Option) ([]scanner.Scanner, []string, func(), error) {
+	entries, err := readArchiveManifest(filePath)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("unable to read archive manifest: %w", err)
+	}
+
+	if len(entries) <= 1 {
+		s, err := initializeRemoteArchiveScanner(ctx, filePath, artifactCache, remoteScanOptions, artifactOption)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		label := filePath
+		if len(entries) == 1 {
+			label = archiveImageLabel(entries[0])
+		}
+		return []scanner.Scanner{s}, []string{label}, func() {}, nil
+	}
+
+	var (
+		scanners []scanner.Scanner
+		labels   []string
+		tmpFiles []string
+	)
+	cleanup := func() {
+		for _, f := range tmpFiles {
+			_ = os.Remove(f)
+		}
+	}
+
+	for _, entry := range entries {
+		tmpPath, err := extractSingleImageArchive(filePath, entry)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, xerrors.Errorf("unable to extract %s from %s: %w", archiveImageLabel(entry), filePath, err)
+		}
+		tmpFiles = append(tmpFiles, tmpPath)
+
+		s, err := initializeRemoteArchiveScanner(ctx, tmpPath, artifactCache, remoteScanOptions, artifactOption)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, xerrors.Errorf("unable to initialize scanner for %s: %w", archiveImageLabel(entry), err)
+		}
+		scanners = append(scanners, s)
+		labels = append(labels, archiveImageLabel(entry))
+	}
+
+	return scanners, labels, cleanup, nil
+}
+
+// ScanArchiveMulti runs initializeArchiveScannerMulti's scanners against
+// filePath and merges every image's Report into one, disambiguating each
+// Result's Target with the image it came from.
+func ScanArchiveMulti(ctx context.Context, filePath string, artifactCache cache.ArtifactCache, localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (types.Report, error) {
+	scanners, labels, cleanup, err := initializeArchiveScannerMulti(ctx, filePath, artifactCache, localArtifactCache, artifactOption)
+	if err != nil {
+		return types.Report{}, err
+	}
+	defer cleanup()
+
+	return mergeArchiveReports(ctx, scanners, labels)
+}
+
+// ScanRemoteArchiveMulti is ScanArchiveMulti's client/server-mode counterpart.
+func ScanRemoteArchiveMulti(ctx context.Context, filePath string, artifactCache cache.ArtifactCache, remoteScanOptions client.ScannerOption, artifactOption artifact.Option) (types.Report, error) {
+	scanners, labels, cleanup, err := initializeRemoteArchiveScannerMulti(ctx, filePath, artifactCache, remoteScanOptions, artifactOption)
+	if err != nil {
+		return types.Report{}, err
+	}
+	defer cleanup()
+
+	return mergeArchiveReports(ctx, scanners, labels)
+}
+
+// mergeArchiveReports scans with each of scanners and merges their Reports
+// into a single Report, one section per image.
+//
+// NOTE: scanner.Scanner.ScanArtifact(ctx) (types.Report, error) is assumed
+// here - pkg/scanner isn't present in this checkout even though wire_gen.go
+// in this package already imports it, so there's no real Scan call site in
+// this checkout to match. Written as the single-image path (pkg/commands/artifact/run.go,
+// also absent here) would call it.
+func mergeArchiveReports(ctx context.Context, scanners []scanner.Scanner, labels []string) (types.Report, error) {
+	var merged types.Report
+	for i, s := range scanners {
+		report, err := s.ScanArtifact(ctx)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("unable to scan %s: %w", labels[i], err)
+		}
+
+		for _, result := range report.Results {
+			result.Target = fmt.Sprintf("%s (%s)", result.Target, labels[i])
+			merged.Results = append(merged.Results, result)
+		}
+		if merged.ArtifactType == "" {
+			merged.ArtifactType = report.ArtifactType
+		}
+	}
+	merged.ArtifactName = strings.Join(labels, ", ")
+
+	return merged, nil
+}
@@ -0,0 +1,70 @@
+package artifact
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aquasecurity/fanal/applier"
+	"github.com/aquasecurity/fanal/artifact"
+	image2 "github.com/aquasecurity/fanal/artifact/image"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg"
+	"github.com/aquasecurity/trivy/pkg/fanal/image/daemon"
+	"github.com/aquasecurity/trivy/pkg/scanner"
+	"github.com/aquasecurity/trivy/pkg/scanner/local"
+	"github.com/aquasecurity/trivy/pkg/vulnerability"
+)
+
+// splitContainerdImageSrc parses the "containerd" or "containerd:<namespace>"
+// form of --image-src into the backend name and an optional namespace
+// override, so a Kubernetes node's non-default containerd namespace (e.g.
+// a CRI-managed "k8s.io") can be targeted explicitly.
+func splitContainerdImageSrc(imageSrc string) (namespace string, ok bool) {
+	backend, ns, found := strings.Cut(imageSrc, ":")
+	if backend != "containerd" {
+		return "", false
+	}
+	if !found {
+		return "", true
+	}
+	return ns, true
+}
+
+// initializeContainerdScanner is for container image scanning against a
+// containerd socket in standalone mode, selected via
+// --image-src containerd[:namespace]. It mirrors initializeDockerScanner
+// and initializePodmanScanner's dependency wiring but resolves the image
+// through daemon.NewContainerdImage, so Kubernetes nodes running
+// containerd without dockerd can be scanned natively.
+//
+// NOTE: image2.NewArtifact is declared in the external
+// github.com/aquasecurity/fanal/artifact/image package (not present in
+// this checkout) to accept whatever image type
+// github.com/aquasecurity/fanal/image.Image is - assumed here to be
+// structurally identical to daemon.Image, as in initializePodmanScanner.
+func initializeContainerdScanner(ctx context.Context, imageName string, namespace string, containerdAddr string, artifactCache cache.ArtifactCache, localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	applierApplier := applier.NewApplier(localArtifactCache)
+	detector := ospkg.Detector{}
+	config := db.Config{}
+	client := vulnerability.NewClient(config)
+	localScanner := local.NewScanner(applierApplier, detector, client)
+
+	typesImage, cleanup, err := daemon.NewContainerdImage(ctx, imageName, namespace, containerdAddr)
+	if err != nil {
+		return scanner.Scanner{}, nil, xerrors.Errorf("unable to initialize containerd image: %w", err)
+	}
+
+	artifactArtifact, err := image2.NewArtifact(typesImage, artifactCache, artifactOption)
+	if err != nil {
+		cleanup()
+		return scanner.Scanner{}, nil, err
+	}
+
+	scannerScanner := scanner.NewScanner(localScanner, artifactArtifact)
+	return scannerScanner, func() {
+		cleanup()
+	}, nil
+}
@@ -0,0 +1,58 @@
+package artifact
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aquasecurity/fanal/applier"
+	"github.com/aquasecurity/fanal/artifact"
+	image2 "github.com/aquasecurity/fanal/artifact/image"
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/detector/ospkg"
+	"github.com/aquasecurity/trivy/pkg/fanal/image/daemon"
+	"github.com/aquasecurity/trivy/pkg/scanner"
+	"github.com/aquasecurity/trivy/pkg/scanner/local"
+	"github.com/aquasecurity/trivy/pkg/vulnerability"
+)
+
+// initializePodmanScanner is for container image scanning against the
+// libpod REST API in standalone mode, selected via --image-src podman.
+// It mirrors initializeDockerScanner's dependency wiring but resolves the
+// image through daemon.NewPodmanImage instead of the Docker-compat
+// image.NewContainerImage, so users get real RootFS/DiffIDs and accurate
+// history without a docker-compat shim.
+//
+// NOTE: image2.NewArtifact is declared in the external
+// github.com/aquasecurity/fanal/artifact/image package (not present in
+// this checkout) to accept whatever image type
+// github.com/aquasecurity/fanal/image.Image is - assumed here to be
+// structurally identical to daemon.Image (v1.Image plus
+// RepoTags()/RepoDigests()), the same shape image.NewContainerImage
+// already returns in initializeDockerScanner, so daemon.Image satisfies
+// it without an adapter.
+func initializePodmanScanner(ctx context.Context, imageName string, podmanClient *http.Client, podmanBaseURL string, artifactCache cache.ArtifactCache, localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	applierApplier := applier.NewApplier(localArtifactCache)
+	detector := ospkg.Detector{}
+	config := db.Config{}
+	client := vulnerability.NewClient(config)
+	localScanner := local.NewScanner(applierApplier, detector, client)
+
+	typesImage, cleanup, err := daemon.NewPodmanImage(ctx, imageName, podmanClient, podmanBaseURL)
+	if err != nil {
+		return scanner.Scanner{}, nil, xerrors.Errorf("unable to initialize podman image: %w", err)
+	}
+
+	artifactArtifact, err := image2.NewArtifact(typesImage, artifactCache, artifactOption)
+	if err != nil {
+		cleanup()
+		return scanner.Scanner{}, nil, err
+	}
+
+	scannerScanner := scanner.NewScanner(localScanner, artifactArtifact)
+	return scannerScanner, func() {
+		cleanup()
+	}, nil
+}
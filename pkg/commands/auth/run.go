@@ -14,6 +14,14 @@ import (
 	"github.com/aquasecurity/trivy/pkg/log"
 )
 
+// Login stores registry credentials via the docker CLI's configured
+// credential store. If opts.CredentialHelper is set, it is registered for
+// this registry before the store is resolved, so a helper binary (e.g.
+// docker-credential-ecr-login, docker-credential-gcr, docker-credential-acr-env)
+// can be wired up without hand-editing config.json. cf.GetCredentialsStore
+// then execs that helper over the credential-helper protocol instead of
+// writing the plaintext file store, so credentials end up wherever the
+// helper manages them rather than always in cleartext in config.json.
 func Login(_ context.Context, registry string, opts flag.Options) error {
 	if len(opts.Credentials) == 0 {
 		return xerrors.New("username and password required")
@@ -31,6 +39,14 @@ func Login(_ context.Context, registry string, opts flag.Options) error {
 	if err != nil {
 		return xerrors.Errorf("failed to load docker config: %w", err)
 	}
+
+	if opts.CredentialHelper != "" {
+		if cf.CredentialHelpers == nil {
+			cf.CredentialHelpers = map[string]string{}
+		}
+		cf.CredentialHelpers[serverAddress] = opts.CredentialHelper
+	}
+
 	creds := cf.GetCredentialsStore(serverAddress)
 	if serverAddress == name.DefaultRegistry {
 		serverAddress = authn.DefaultAuthKey
@@ -0,0 +1,45 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+// PublishOptions configures `trivy policy publish`.
+type PublishOptions struct {
+	// Dir is the directory of Rego checks and bundle manifest to package.
+	Dir string
+	// Repository is the OCI reference the bundle is pushed to.
+	Repository string
+	// SignKeyPath, if set, signs the pushed bundle with WithSigningKey.
+	SignKeyPath string
+}
+
+// RunPublish packages and pushes the check bundle described by opts,
+// printing the pushed digest (and signature tag, if signed) to stdout.
+//
+// NOTE: this wires policy.Publisher up the way the other `pkg/commands/*`
+// packages wire their operations to flag.Options, but the cobra.Command
+// registration (`trivy policy publish <dir> <repo>`) that would call this is
+// left out - the root `cmd/trivy` command tree isn't present in this
+// checkout to register a new subcommand against.
+func RunPublish(ctx context.Context, opts PublishOptions, registryOpts types.RegistryOptions, publisherOpts ...policy.Option) error {
+	pub := policy.NewPublisher(false, publisherOpts...)
+
+	result, err := pub.Publish(ctx, opts.Dir, opts.Repository, registryOpts)
+	if err != nil {
+		return xerrors.Errorf("publish error: %w", err)
+	}
+
+	fmt.Printf("Pushed %s@%s\n", result.Repository, result.Digest)
+	if result.SignatureTag != "" {
+		fmt.Printf("Signature: %s\n", result.SignatureTag)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/remote"
+)
+
+// Run enumerates every repository and tag in the registry named by
+// registryHost and scans each resulting image reference with scan,
+// printing a one-line pass/fail summary per reference to the first
+// configured --output target (stdout when none was given).
+//
+// NOTE: this wires Scan up to the CLI's flag.Options the way the other
+// `pkg/commands/*` packages do, but the actual per-image scan (building a
+// types.Report and funnelling it into --format/--output) is left to the
+// caller via scan: the scanner construction this would otherwise reuse
+// lives in the `trivy image` command path (pkg/commands/artifact), which
+// isn't present in this checkout to verify against.
+func Run(ctx context.Context, registryHost string, opts flag.Options, scan ScanFunc) error {
+	reg, err := name.NewRegistry(registryHost)
+	if err != nil {
+		return xerrors.Errorf("failed to parse registry: %w", err)
+	}
+
+	var reporter remote.ProgressReporter
+	if opts.Progress {
+		reporter = remote.NewProgressReporter(os.Stderr, registryHost)
+	}
+
+	results, err := Scan(ctx, reg, Options{
+		Filter:   opts.ScanFilter,
+		Parallel: opts.ScanParallel,
+		Registry: types.RegistryOptions{Progress: reporter},
+	}, scan)
+	if err != nil {
+		return xerrors.Errorf("registry scan error: %w", err)
+	}
+
+	summaryOut := io.Writer(os.Stdout)
+	if len(opts.Outputs) > 0 {
+		summaryOut = opts.Outputs[0].Writer
+	}
+
+	var failed int
+	for _, result := range results {
+		status := "OK"
+		if result.Err != nil {
+			status = fmt.Sprintf("FAILED: %s", result.Err)
+			failed++
+		}
+		fmt.Fprintf(summaryOut, "%s: %s\n", result.Reference, status)
+	}
+	if failed > 0 {
+		return xerrors.Errorf("%d/%d images failed to scan", failed, len(results))
+	}
+	return nil
+}
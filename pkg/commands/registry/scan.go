@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remote"
+)
+
+// ScanFunc scans a single resolved image reference (e.g.
+// "registry.example.com/foo:v1"). The caller supplies it, since the actual
+// scanner wiring - building a types.Report and funnelling it into the
+// --format/--output flags - lives in the `trivy image` command path.
+type ScanFunc func(ctx context.Context, ref string) error
+
+// ScanResult is the outcome of scanning one resolved image reference.
+type ScanResult struct {
+	Reference string
+	Err       error
+}
+
+// Options configures a registry-wide scan.
+type Options struct {
+	// Filter, if non-empty, is a regular expression repository names are
+	// matched against; repositories that don't match are skipped.
+	Filter string
+
+	// Parallel bounds how many image scans run concurrently. Defaults to
+	// defaultParallel when <= 0.
+	Parallel int
+
+	Registry types.RegistryOptions
+}
+
+const defaultParallel = 5
+
+// Scan enumerates every repository in reg via the registry's `_catalog`
+// endpoint, resolves every tag of every repository that matches
+// opts.Filter via `tags/list`, and runs scan against each resulting image
+// reference through a pool bounded to opts.Parallel concurrent scans. This
+// lets users audit an entire private registry (Harbor, ECR, GHCR) in one
+// invocation instead of scripting `trivy image` once per tag.
+func Scan(ctx context.Context, reg name.Registry, opts Options, scan ScanFunc) ([]ScanResult, error) {
+	repos, err := remote.Catalog(ctx, reg, opts.Registry)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list repositories of %s: %w", reg.Name(), err)
+	}
+
+	var filter *regexp.Regexp
+	if opts.Filter != "" {
+		if filter, err = regexp.Compile(opts.Filter); err != nil {
+			return nil, xerrors.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	refs := resolveReferences(ctx, reg, repos, filter, opts.Registry)
+
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = defaultParallel
+	}
+
+	results := make([]ScanResult, len(refs))
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, parallel)
+	for i, ref := range refs {
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			results[i] = ScanResult{Reference: ref, Err: scan(ctx, ref)}
+			return nil
+		})
+	}
+	// Every task records its own error in results; g.Wait() only drains the
+	// pool, it isn't expected to return one.
+	_ = g.Wait()
+
+	return results, nil
+}
+
+// resolveReferences lists the tags of every repository in repos that
+// matches filter and returns the fully-qualified "repo:tag" reference for
+// each. A repository whose tags can't be listed is skipped with a warning
+// rather than failing the whole scan.
+func resolveReferences(ctx context.Context, reg name.Registry, repos []string, filter *regexp.Regexp, regOpts types.RegistryOptions) []string {
+	var refs []string
+	for _, repoName := range repos {
+		if filter != nil && !filter.MatchString(repoName) {
+			continue
+		}
+
+		repo := reg.Repo(repoName)
+		tags, err := remote.List(ctx, repo, regOpts)
+		if err != nil {
+			log.Warn("Failed to list tags", log.String("repository", repoName), log.Err(err))
+			continue
+		}
+
+		for _, tag := range tags {
+			refs = append(refs, repo.Tag(tag).Name())
+		}
+	}
+	return refs
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Evaluator builds a ComplianceReport by matching a scan's findings back to
+// the controls of a ComplianceSpec.
+//
+// NOTE: the request asked for this as spec.Evaluator, but report.go already
+// depends on pkg/compliance/spec (for spec.Status, via the pre-existing
+// BuildSummary/DefaultStatus handling) - putting the evaluator in spec
+// instead would need spec to import this package right back, an import
+// cycle. Evaluator lives here instead, still exported as part of this
+// package's public surface the CLI subcommand is meant to call.
+type Evaluator struct {
+	spec spec.ComplianceSpec
+}
+
+// NewEvaluator returns an Evaluator for complianceSpec.
+func NewEvaluator(complianceSpec spec.ComplianceSpec) *Evaluator {
+	return &Evaluator{spec: complianceSpec}
+}
+
+// Evaluate matches scanReport's findings against every control in the spec
+// Evaluator was built with, returning a ComplianceReport with one
+// ControlCheckResult per control, in spec order.
+func (e *Evaluator) Evaluate(scanReport types.Report) (*ComplianceReport, error) {
+	cr := &ComplianceReport{
+		ID:               e.spec.Spec.ID,
+		Title:            e.spec.Spec.Title,
+		Description:      e.spec.Spec.Description,
+		Version:          e.spec.Spec.Version,
+		RelatedResources: e.spec.Spec.RelatedResources,
+	}
+
+	for _, control := range e.spec.Spec.Controls {
+		result, err := e.evaluateControl(control, scanReport)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to evaluate control %q: %w", control.ID, err)
+		}
+		cr.Results = append(cr.Results, result)
+	}
+
+	return cr, nil
+}
+
+// evaluateControl attributes scanReport's findings to control: a control
+// with no checks at all reports its DefaultStatus unconditionally; otherwise
+// every check ID it references (AND'd together - the control only matches a
+// Result once every one of its checks matched it) is searched for across
+// every Result in scanReport, and every Result where all its checks matched
+// is kept as part of this control's evidence.
+func (e *Evaluator) evaluateControl(control spec.Control, scanReport types.Report) (*ControlCheckResult, error) {
+	result := &ControlCheckResult{
+		ControlCheckID:  control.ID,
+		ControlName:     control.Name,
+		ControlSeverity: control.Severity,
+		DefaultStatus:   control.DefaultStatus,
+	}
+
+	checkIDs := controlCheckIDs(control)
+	if len(checkIDs) == 0 {
+		return result, nil
+	}
+
+	for _, target := range scanReport.Results {
+		matched := matchControl(checkIDs, target)
+		found := make(map[string]struct{}, len(checkIDs))
+		for _, m := range matched.Misconfigurations {
+			found[m.AVDID] = struct{}{}
+		}
+		for _, v := range matched.Vulnerabilities {
+			found[v.VulnerabilityID] = struct{}{}
+		}
+		for _, s := range matched.Secrets {
+			found[s.RuleID] = struct{}{}
+		}
+		if len(found) < len(checkIDs) {
+			continue
+		}
+		result.Results = append(result.Results, matched)
+	}
+
+	return result, nil
+}
+
+// controlCheckIDs returns the set of check IDs control requires - every
+// check in control.Checks must match (an AND combination); a control that
+// lists alternative check IDs it's satisfied by any one of (an OR
+// combination) lists them as separate SpecCheck entries with the same
+// control ID in the spec today, so AND is this package's only combination to
+// implement until the spec format grows an explicit OR grouping.
+func controlCheckIDs(control spec.Control) map[string]struct{} {
+	ids := make(map[string]struct{}, len(control.Checks))
+	for _, check := range control.Checks {
+		ids[check.ID] = struct{}{}
+	}
+	return ids
+}
+
+// matchControl filters target's findings down to the ones whose check ID is
+// one of checkIDs.
+func matchControl(checkIDs map[string]struct{}, target types.Result) types.Result {
+	matched := types.Result{Target: target.Target, Class: target.Class, Type: target.Type}
+
+	for _, m := range target.Misconfigurations {
+		if _, ok := checkIDs[m.AVDID]; ok {
+			matched.Misconfigurations = append(matched.Misconfigurations, m)
+		}
+	}
+	for _, v := range target.Vulnerabilities {
+		if _, ok := checkIDs[v.VulnerabilityID]; ok {
+			matched.Vulnerabilities = append(matched.Vulnerabilities, v)
+		}
+	}
+	for _, s := range target.Secrets {
+		if _, ok := checkIDs[s.RuleID]; ok {
+			matched.Secrets = append(matched.Secrets, s)
+		}
+	}
+
+	return matched
+}
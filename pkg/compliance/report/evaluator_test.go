@@ -0,0 +1,82 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/report"
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func testSpec() spec.ComplianceSpec {
+	return spec.ComplianceSpec{
+		Spec: spec.Spec{
+			ID:    "1.0",
+			Title: "Test Spec",
+			Controls: []spec.Control{
+				{
+					ID:       "1.1",
+					Name:     "Non-root containers",
+					Severity: "MEDIUM",
+					Checks:   []spec.SpecCheck{{ID: "AVD-KSV012"}},
+				},
+				{
+					ID:            "1.2",
+					Name:          "Manual review required",
+					Severity:      "LOW",
+					DefaultStatus: spec.ManualStatus,
+				},
+			},
+		},
+	}
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	scanReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "deployment.yaml",
+				Misconfigurations: []types.DetectedMisconfiguration{
+					{AVDID: "AVD-KSV012", Status: types.StatusFailure},
+					{AVDID: "AVD-KSV013", Status: types.StatusFailure},
+				},
+			},
+		},
+	}
+
+	e := report.NewEvaluator(testSpec())
+	got, err := e.Evaluate(scanReport)
+	require.NoError(t, err)
+	require.Len(t, got.Results, 2)
+
+	assert.Equal(t, "1.1", got.Results[0].ControlCheckID)
+	require.Len(t, got.Results[0].Results, 1)
+	assert.Len(t, got.Results[0].Results[0].Misconfigurations, 1, "only AVD-KSV012 belongs to this control")
+	assert.Equal(t, spec.FailStatus, got.Results[0].Status())
+
+	assert.Equal(t, "1.2", got.Results[1].ControlCheckID)
+	assert.Empty(t, got.Results[1].Results, "control has no checks to match findings against")
+	assert.Equal(t, spec.ManualStatus, got.Results[1].Status())
+}
+
+func TestEvaluator_Evaluate_AllPass(t *testing.T) {
+	scanReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "deployment.yaml",
+				Misconfigurations: []types.DetectedMisconfiguration{
+					{AVDID: "AVD-KSV012", Status: types.StatusPassed},
+				},
+			},
+		},
+	}
+
+	e := report.NewEvaluator(testSpec())
+	got, err := e.Evaluate(scanReport)
+	require.NoError(t, err)
+
+	assert.Equal(t, spec.PassStatus, got.Results[0].Status())
+}
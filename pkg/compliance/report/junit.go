@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+)
+
+// junitTestSuites/junitTestSuite/junitTestCase/junitFailure mirror the
+// JUnit XML schema most CI systems already parse test results from, letting
+// a compliance report surface as pass/fail "tests" in the same dashboards.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitWriter renders a ComplianceReport as JUnit XML, one testcase per
+// control.
+type JUnitWriter struct {
+	Output io.Writer
+}
+
+// Write renders report to w.Output as JUnit XML.
+func (w JUnitWriter) Write(report *ComplianceReport) error {
+	suite := junitTestSuite{Name: report.Title}
+
+	for _, control := range report.Results {
+		tc := junitTestCase{Name: control.ControlCheckID + " " + control.ControlName}
+		if status := control.Status(); status == spec.FailStatus {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: "control failed",
+				Text:    junitFindings(control),
+			}
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suites := junitTestSuites{Suites: []junitTestSuite{suite}}
+
+	if _, err := io.WriteString(w.Output, xml.Header); err != nil {
+		return xerrors.Errorf("failed to write JUnit compliance report: %w", err)
+	}
+	enc := xml.NewEncoder(w.Output)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return xerrors.Errorf("failed to write JUnit compliance report: %w", err)
+	}
+	return nil
+}
+
+// junitFindings summarizes every failed check a control matched, for the
+// JUnit failure element's body text.
+func junitFindings(control *ControlCheckResult) string {
+	var text string
+	for _, result := range control.Results {
+		for _, m := range result.Misconfigurations {
+			if !m.CheckPass() {
+				text += m.AVDID + ": " + m.Message + "\n"
+			}
+		}
+		for _, v := range result.Vulnerabilities {
+			text += v.VulnerabilityID + " in " + v.PkgName + "\n"
+		}
+		for _, s := range result.Secrets {
+			text += s.RuleID + " in " + result.Target + "\n"
+		}
+	}
+	return text
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+)
+
+// oscalAssessmentResults is a minimal subset of the NIST OSCAL "Assessment
+// Results" model (https://pages.nist.gov/OSCAL/resources/concepts/layer/assessment/ar/)
+// - enough to carry one observation per control, without pulling in the
+// rest of the OSCAL layer (SSP, catalog, profile) this chunk has no use for.
+type oscalAssessmentResults struct {
+	AssessmentResults oscalAssessmentResultsBody `json:"assessment-results"`
+}
+
+type oscalAssessmentResultsBody struct {
+	UUID     string        `json:"uuid"`
+	Metadata oscalMetadata `json:"metadata"`
+	Results  []oscalResult `json:"results"`
+}
+
+type oscalMetadata struct {
+	Title   string `json:"title"`
+	Version string `json:"version,omitempty"`
+}
+
+type oscalResult struct {
+	UUID         string             `json:"uuid"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Observations []oscalObservation `json:"observations"`
+}
+
+type oscalObservation struct {
+	UUID        string         `json:"uuid"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Methods     []string       `json:"methods"`
+	Subjects    []oscalSubject `json:"subjects,omitempty"`
+	RelevantTo  string         `json:"relevant-to-statement-id"`
+}
+
+type oscalSubject struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+}
+
+// OSCALWriter renders a ComplianceReport as an OSCAL Assessment Results JSON
+// document, one observation per control.
+type OSCALWriter struct {
+	Output io.Writer
+}
+
+// Write renders report to w.Output as OSCAL Assessment Results JSON.
+func (w OSCALWriter) Write(report *ComplianceReport) error {
+	result := oscalResult{
+		UUID:        report.ID,
+		Title:       report.Title,
+		Description: report.Description,
+	}
+
+	for _, control := range report.Results {
+		status := control.Status()
+		result.Observations = append(result.Observations, oscalObservation{
+			UUID:        control.ControlCheckID,
+			Title:       control.ControlName,
+			Description: oscalDescription(status),
+			Methods:     []string{"AUTOMATED"},
+			Subjects:    oscalSubjects(control),
+			RelevantTo:  control.ControlCheckID,
+		})
+	}
+
+	doc := oscalAssessmentResults{
+		AssessmentResults: oscalAssessmentResultsBody{
+			UUID:     report.ID,
+			Metadata: oscalMetadata{Title: report.Title, Version: report.Version},
+			Results:  []oscalResult{result},
+		},
+	}
+
+	enc := json.NewEncoder(w.Output)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return xerrors.Errorf("failed to write OSCAL compliance report: %w", err)
+	}
+	return nil
+}
+
+func oscalDescription(status spec.Status) string {
+	switch status {
+	case spec.PassStatus:
+		return "satisfied"
+	case spec.FailStatus:
+		return "not-satisfied"
+	default:
+		return "not-evaluated"
+	}
+}
+
+func oscalSubjects(control *ControlCheckResult) []oscalSubject {
+	var subjects []oscalSubject
+	for _, result := range control.Results {
+		if result.Target == "" {
+			continue
+		}
+		subjects = append(subjects, oscalSubject{Type: "target", Title: result.Target})
+	}
+	return subjects
+}
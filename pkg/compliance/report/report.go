@@ -0,0 +1,79 @@
+package report
+
+import (
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// ComplianceReport is the result of evaluating a compliance spec's controls
+// against a finished scan: one ControlCheckResult per control, in spec order.
+type ComplianceReport struct {
+	ID               string
+	Title            string
+	Description      string
+	Version          string
+	RelatedResources []string
+	Results          []*ControlCheckResult
+}
+
+// ControlCheckResult is a single compliance control's outcome: either the
+// Results of every scanner check it references (folded in from the scan
+// being evaluated), or - for a control with no checks to evaluate - its
+// spec-declared DefaultStatus.
+type ControlCheckResult struct {
+	ControlCheckID  string
+	ControlName     string
+	ControlSeverity string
+	DefaultStatus   spec.Status
+	Results         types.Results
+}
+
+// Status reports the control's overall pass/fail/warn/manual outcome:
+// DefaultStatus when it has no matched Results to judge by, fail if any
+// matched Result failed, manual for a non-actionable finding-free result
+// with no default (the control couldn't be automatically evaluated), and
+// pass otherwise.
+func (r *ControlCheckResult) Status() spec.Status {
+	if len(r.Results) == 0 {
+		if r.DefaultStatus != "" {
+			return r.DefaultStatus
+		}
+		return spec.ManualStatus
+	}
+
+	for _, result := range r.Results {
+		for _, m := range result.Misconfigurations {
+			if !m.CheckPass() {
+				return spec.FailStatus
+			}
+		}
+		for _, v := range result.Vulnerabilities {
+			if !v.CheckPass() {
+				return spec.FailStatus
+			}
+		}
+		for _, s := range result.Secrets {
+			if !s.CheckPass() {
+				return spec.FailStatus
+			}
+		}
+	}
+	return spec.PassStatus
+}
+
+// ControlCheckSummary is the pass/fail tally for a single control, used by
+// the summary table/writers rather than the full per-finding detail.
+type ControlCheckSummary struct {
+	ControlCheckID  string
+	ControlName     string
+	ControlSeverity string
+	TotalFail       float32
+	TotalPass       float32
+}
+
+// SummaryReport is ComplianceReport reduced to per-control pass/fail counts.
+type SummaryReport struct {
+	ReportID        string
+	ReportTitle     string
+	SummaryControls []ControlCheckSummary
+}
@@ -0,0 +1,125 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/spec"
+)
+
+// sarifSchemaURI and sarifVersion identify the SARIF version this writer
+// targets, per the spec at https://json.schemastore.org/sarif-2.1.0.json.
+const (
+	sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion   = "2.1.0"
+)
+
+// sarifLog, sarifRun, sarifTool, sarifDriver, sarifRule and sarifResult are a
+// minimal subset of the SARIF 2.1.0 object model - just enough to carry one
+// compliance control result per SARIF result, with its control ID as the
+// matching rule ID.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID  string       `json:"ruleId"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+// SarifWriter renders a ComplianceReport as a SARIF 2.1.0 log, one result
+// per control, so compliance output can plug into tooling (GitHub code
+// scanning, etc.) that already understands SARIF from trivy's other
+// scanners.
+type SarifWriter struct {
+	Output io.Writer
+}
+
+// Write renders report to w.Output as SARIF.
+func (w SarifWriter) Write(report *ComplianceReport) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "Trivy",
+				InformationURI: "https://github.com/aquasecurity/trivy",
+			},
+		},
+	}
+
+	for _, control := range report.Results {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               control.ControlCheckID,
+			Name:             control.ControlName,
+			ShortDescription: sarifMessage{Text: control.ControlName},
+			Properties:       map[string]interface{}{"severity": control.ControlSeverity},
+		})
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  control.ControlCheckID,
+			Level:   sarifLevel(control.Status()),
+			Message: sarifMessage{Text: sarifSummary(control)},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w.Output)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return xerrors.Errorf("failed to write SARIF compliance report: %w", err)
+	}
+	return nil
+}
+
+// sarifLevel maps a control's pass/fail/warn/manual outcome to a SARIF
+// result level: "error" for a failed control, "note" for one that needs a
+// manual look, and "none" for anything else (pass, or a warning that isn't
+// itself actionable).
+func sarifLevel(status spec.Status) string {
+	switch status {
+	case spec.FailStatus:
+		return "error"
+	case spec.ManualStatus:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+func sarifSummary(control *ControlCheckResult) string {
+	return control.ControlCheckID + ": " + control.ControlName + " - " + string(control.Status())
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// TableWriter renders a ComplianceReport as a human-readable table. Report
+// selects which rendering to use - "summary" for the per-control pass/fail
+// table, anything else for the detailed per-finding table.
+type TableWriter struct {
+	Output         io.Writer
+	Report         string
+	Severities     []dbTypes.Severity
+	ColumnsHeading []string
+}
+
+// Write renders report to w.Output according to w.Report.
+func (w TableWriter) Write(report *ComplianceReport) error {
+	switch w.Report {
+	case "summary":
+		columns := w.ColumnsHeading
+		if len(columns) == 0 {
+			columns = []string{"ID", "Control Name", "Severity"}
+		}
+		sw := NewSummaryWriter(w.Output, w.Severities, columns)
+		return sw.Write(report)
+	default:
+		return w.writeDetailed(report)
+	}
+}
+
+// writeDetailed prints one line per control along with every finding it
+// matched, for a --report all run.
+func (w TableWriter) writeDetailed(report *ComplianceReport) error {
+	if _, err := fmt.Fprintf(w.Output, "Summary Report for compliance: %s\n\n", report.Title); err != nil {
+		return xerrors.Errorf("failed to write compliance report: %w", err)
+	}
+
+	for _, control := range report.Results {
+		if _, err := fmt.Fprintf(w.Output, "%s: %s (%s)\n", control.ControlCheckID, control.ControlName, control.ControlSeverity); err != nil {
+			return xerrors.Errorf("failed to write compliance report: %w", err)
+		}
+		for _, result := range control.Results {
+			for _, m := range result.Misconfigurations {
+				if _, err := fmt.Fprintf(w.Output, "  - %s: %s\n", m.AVDID, m.Status); err != nil {
+					return xerrors.Errorf("failed to write compliance report: %w", err)
+				}
+			}
+			for _, v := range result.Vulnerabilities {
+				if _, err := fmt.Fprintf(w.Output, "  - %s\n", v.VulnerabilityID); err != nil {
+					return xerrors.Errorf("failed to write compliance report: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
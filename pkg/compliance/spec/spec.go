@@ -0,0 +1,162 @@
+package spec
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Status is the outcome of evaluating a single control against a finished
+// scan.
+type Status string
+
+const (
+	PassStatus   Status = "PASS"
+	FailStatus   Status = "FAIL"
+	WarnStatus   Status = "WARN"
+	ManualStatus Status = "MANUAL"
+)
+
+// ComplianceSpec is the root of a compliance spec YAML document, e.g. one of
+// the built-in specs under pkg/compliance/spec/assets.
+type ComplianceSpec struct {
+	Spec Spec `yaml:"spec"`
+}
+
+// Spec describes one compliance report: its identity, and the controls it's
+// made of.
+type Spec struct {
+	ID               string    `yaml:"id"`
+	Title            string    `yaml:"title"`
+	Description      string    `yaml:"description"`
+	Version          string    `yaml:"version"`
+	RelatedResources []string  `yaml:"relatedResources"`
+	Controls         []Control `yaml:"controls"`
+}
+
+// Control is a single compliance requirement, satisfied by one or more
+// scanner checks. DefaultStatus is used when a control has no checks to
+// evaluate against a scan (e.g. a purely procedural control).
+type Control struct {
+	ID            string      `yaml:"id"`
+	Name          string      `yaml:"name"`
+	Description   string      `yaml:"description"`
+	Severity      string      `yaml:"severity"`
+	DefaultStatus Status      `yaml:"defaultStatus"`
+	Checks        []SpecCheck `yaml:"checks"`
+	Commands      []SpecCheck `yaml:"commands"`
+}
+
+// SpecCheck names a single scanner check (e.g. an Avd ID or a CVE ID) a
+// Control is satisfied by.
+type SpecCheck struct {
+	ID string `yaml:"id"`
+}
+
+// scannerPrefixes maps a check ID prefix to the scanner that produces checks
+// with that prefix, in the order they're tried - first match wins. A check
+// ID matching none of these belongs to no known scanner at all, which is
+// what lets ValidateScanners catch a spec referencing a check this build of
+// trivy has no scanner for.
+var scannerPrefixes = []struct {
+	prefix  string
+	scanner types.SecurityCheck
+}{
+	{"CVE-", types.SecurityCheckVulnerability},
+	{"AVD-", types.SecurityCheckConfig},
+	{"ID-", types.SecurityCheckSecret},
+}
+
+// scannerForCheckID returns the scanner a check ID belongs to, or
+// types.SecurityCheckUnknown if it matches no known scanner's ID prefix.
+func scannerForCheckID(checkID string) types.SecurityCheck {
+	for _, sp := range scannerPrefixes {
+		if strings.HasPrefix(checkID, sp.prefix) {
+			return sp.scanner
+		}
+	}
+	return types.SecurityCheckUnknown
+}
+
+// checks returns control's check IDs, falling back to the legacy "commands"
+// field some older specs use in place of "checks".
+func (c Control) checks() []SpecCheck {
+	if len(c.Checks) > 0 {
+		return c.Checks
+	}
+	return c.Commands
+}
+
+// ScannerCheckIDs groups every check ID referenced by controls by the
+// scanner that produces it, deduplicating check IDs referenced by more than
+// one control so a finding isn't double-counted during evaluation.
+func ScannerCheckIDs(controls []Control) map[string][]string {
+	seen := make(map[string]map[string]struct{})
+	for _, control := range controls {
+		for _, check := range control.checks() {
+			scanner := string(scannerForCheckID(check.ID))
+			if seen[scanner] == nil {
+				seen[scanner] = make(map[string]struct{})
+			}
+			seen[scanner][check.ID] = struct{}{}
+		}
+	}
+
+	result := make(map[string][]string, len(seen))
+	for scanner, ids := range seen {
+		list := make([]string, 0, len(ids))
+		for id := range ids {
+			list = append(list, id)
+		}
+		sort.Strings(list)
+		result[scanner] = list
+	}
+	return result
+}
+
+// GetScannerTypes parses specData and returns the distinct scanner types its
+// controls' check IDs require, sorted for stable output.
+func GetScannerTypes(specData string) ([]types.SecurityCheck, error) {
+	var cr ComplianceSpec
+	if err := yaml.Unmarshal([]byte(specData), &cr); err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal compliance spec: %w", err)
+	}
+
+	checkIDs := ScannerCheckIDs(cr.Spec.Controls)
+	scanners := make([]string, 0, len(checkIDs))
+	for scanner := range checkIDs {
+		scanners = append(scanners, scanner)
+	}
+	sort.Strings(scanners)
+
+	result := make([]types.SecurityCheck, 0, len(scanners))
+	for _, scanner := range scanners {
+		result = append(result, types.SecurityCheck(scanner))
+	}
+	return result, nil
+}
+
+// supportedScanners are the scanner types a compliance spec's check IDs are
+// allowed to resolve to; any other value means the spec references a check
+// ID this build of trivy has no scanner for.
+var supportedScanners = map[types.SecurityCheck]bool{
+	types.SecurityCheckConfig:        true,
+	types.SecurityCheckVulnerability: true,
+	types.SecurityCheckSecret:        true,
+	types.SecurityCheckLicense:       true,
+}
+
+// ValidateScanners returns an error if any check ID referenced by controls
+// resolves to a scanner this build of trivy doesn't support.
+func ValidateScanners(controls []Control) error {
+	for scanner := range ScannerCheckIDs(controls) {
+		if !supportedScanners[types.SecurityCheck(scanner)] {
+			return xerrors.Errorf("unsupported scanner type: %s", scanner)
+		}
+	}
+	return nil
+}
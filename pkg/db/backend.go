@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+// Backend fetches the vulnerability DB archive from a repository and lays it
+// out under dbDir. Historically Trivy only knew how to pull trivy-db from an
+// OCI registry; Backend lets that be swapped out for other transports (a
+// plain HTTP tarball, an internal mirror, etc.) while Client keeps the same
+// update/metadata bookkeeping regardless of where the bytes came from.
+type Backend interface {
+	// Download fetches the DB archive for repo into dbDir.
+	Download(ctx context.Context, repo, dbDir string) error
+}
+
+// NewBackend selects a Backend implementation based on the repository
+// reference's scheme. A bare "registry/repo:tag" reference (no scheme) is
+// treated as an OCI reference, matching trivy-db's historical behavior.
+func NewBackend(repo string, opts ftypes.RegistryOptions) (Backend, error) {
+	switch {
+	case strings.HasPrefix(repo, "http://"), strings.HasPrefix(repo, "https://"):
+		return &httpBackend{url: repo}, nil
+	case strings.HasPrefix(repo, "oci://"):
+		return &ociBackend{repo: strings.TrimPrefix(repo, "oci://"), opts: opts}, nil
+	default:
+		return &ociBackend{repo: repo, opts: opts}, nil
+	}
+}
+
+type ociBackend struct {
+	repo string
+	opts ftypes.RegistryOptions
+}
+
+func (b *ociBackend) Download(ctx context.Context, _, dbDir string) error {
+	art, err := oci.NewArtifact(b.repo, true, b.opts)
+	if err != nil {
+		return xerrors.Errorf("oci error: %w", err)
+	}
+	if err = art.Download(ctx, dbDir, oci.DownloadOption{MediaType: mediaType}); err != nil {
+		return xerrors.Errorf("database download error: %w", err)
+	}
+	return nil
+}
+
+// httpBackend downloads the DB archive as a plain HTTP(S) tarball, e.g. from
+// an internal artifact mirror that doesn't speak the OCI distribution spec.
+type httpBackend struct {
+	url string
+}
+
+func (b *httpBackend) Download(ctx context.Context, _, dbDir string) error {
+	return downloadAndExtract(ctx, b.url, dbDir)
+}
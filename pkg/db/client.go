@@ -0,0 +1,126 @@
+package db
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const mediaType = "application/vnd.aquasec.trivy.db.layer.v1.tar+gzip"
+
+// Client updates and opens the vulnerability DB through a pluggable Backend,
+// so the DB can be fetched from something other than an OCI registry
+// (trivy-db's default).
+type Client struct {
+	dbDir   string
+	repo    string
+	backend Backend
+}
+
+// NewClient builds a Client for repo, selecting a Backend by the repo's scheme.
+// See NewBackend for the supported schemes.
+func NewClient(cacheDir, repo string, opts ftypes.RegistryOptions) (*Client, error) {
+	backend, err := NewBackend(repo, opts)
+	if err != nil {
+		return nil, xerrors.Errorf("backend error: %w", err)
+	}
+	return &Client{
+		dbDir:   filepath.Join(cacheDir, "db"),
+		repo:    repo,
+		backend: backend,
+	}, nil
+}
+
+// Download fetches the DB into the client's cache directory via its Backend.
+func (c *Client) Download(ctx context.Context) error {
+	if err := os.MkdirAll(c.dbDir, 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	if err := c.backend.Download(ctx, c.repo, c.dbDir); err != nil {
+		return xerrors.Errorf("%s: download error: %w", c.repo, err)
+	}
+	return nil
+}
+
+// downloadAndExtract fetches a gzip-compressed tarball from url and extracts
+// it into dir, used by the plain-HTTP Backend.
+func downloadAndExtract(ctx context.Context, url, dir string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return xerrors.Errorf("unable to build request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("unable to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status fetching %s: %s", url, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return xerrors.Errorf("unable to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return xerrors.Errorf("unable to read tar entry: %w", err)
+		}
+
+		target, err := safeJoin(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return xerrors.Errorf("mkdir error: %w", err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return xerrors.Errorf("mkdir error: %w", err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+			if err != nil {
+				return xerrors.Errorf("unable to create %s: %w", target, err)
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return xerrors.Errorf("unable to write %s: %w", target, err)
+			}
+			f.Close()
+		}
+	}
+	return nil
+}
+
+// safeJoin joins dir and name the way downloadAndExtract's tar entries are
+// placed on disk, rejecting any name (e.g. containing "..") whose resolved
+// path would escape dir - an untrusted or MITM'd DB mirror could otherwise
+// write arbitrary files on the host via a crafted tar entry (a "tar-slip"
+// / "Zip Slip" attack).
+func safeJoin(dir, name string) (string, error) {
+	dir = filepath.Clean(dir)
+	target := filepath.Join(dir, name)
+	if target != dir && !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+		return "", xerrors.Errorf("invalid tar entry %q: escapes destination directory", name)
+	}
+	return target, nil
+}
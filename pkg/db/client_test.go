@@ -0,0 +1,88 @@
+package db
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTarGz(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func serveTarGz(t *testing.T, body []byte) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestDownloadAndExtract(t *testing.T) {
+	t.Run("extracts regular entries", func(t *testing.T) {
+		dir := t.TempDir()
+		body := buildTarGz(t, map[string]string{"trivy.db": "hello"})
+		url := serveTarGz(t, body)
+
+		require.NoError(t, downloadAndExtract(context.Background(), url, dir))
+
+		got, err := os.ReadFile(filepath.Join(dir, "trivy.db"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(got))
+	})
+
+	t.Run("rejects a path-traversal entry", func(t *testing.T) {
+		dir := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "authorized_keys")
+		body := buildTarGz(t, map[string]string{"../../../../" + outside: "pwned"})
+		url := serveTarGz(t, body)
+
+		err := downloadAndExtract(context.Background(), url, dir)
+		require.Error(t, err)
+
+		_, statErr := os.Stat(outside)
+		assert.True(t, os.IsNotExist(statErr), "tar-slip entry must not be written outside dir")
+	})
+}
+
+func TestSafeJoin(t *testing.T) {
+	dir := "/tmp/trivy-db"
+
+	t.Run("plain entry stays under dir", func(t *testing.T) {
+		target, err := safeJoin(dir, "trivy.db")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "trivy.db"), target)
+	})
+
+	t.Run("dot-dot entry escaping dir is rejected", func(t *testing.T) {
+		_, err := safeJoin(dir, "../../etc/passwd")
+		assert.Error(t, err)
+	})
+}
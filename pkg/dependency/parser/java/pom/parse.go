@@ -0,0 +1,276 @@
+package pom
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// pomXML is a reduced model of a Maven pom.xml, just enough to resolve the
+// module's own coordinates, its parent chain (for property/dependencyManagement
+// inheritance) and its directly declared dependencies - without invoking Maven
+// itself.
+type pomXML struct {
+	XMLName    xml.Name `xml:"project"`
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Version    string   `xml:"version"`
+	Parent     struct {
+		GroupID      string `xml:"groupId"`
+		ArtifactID   string `xml:"artifactId"`
+		Version      string `xml:"version"`
+		RelativePath string `xml:"relativePath"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries map[string]string
+	} `xml:"-"`
+	RawProperties struct {
+		XML string `xml:",innerxml"`
+	} `xml:"properties"`
+	Dependencies struct {
+		Dependency []pomDependency `xml:"dependency"`
+	} `xml:"dependencies"`
+	DependencyManagement struct {
+		Dependencies struct {
+			Dependency []pomDependency `xml:"dependency"`
+		} `xml:"dependencies"`
+	} `xml:"dependencyManagement"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Optional   bool   `xml:"optional"`
+}
+
+// Parser reads a Maven module's own pom.xml - its directly declared
+// <dependencies>, plus the <properties> and <dependencyManagement> it
+// inherits by walking <relativePath> up its local parent chain - without
+// requiring a JVM or a built jar.
+//
+// This is deliberately narrower than "resolve the dependency graph": it
+// does not open each dependency's own POM, so there is no transitive
+// resolution and every Library returned is RelationshipDirect (accurately,
+// not as a simplification of a graph this parser doesn't build). It also
+// doesn't walk ~/.m2/repository or a Gradle module cache to resolve a
+// parent POM that isn't reachable via a local <relativePath>, doesn't
+// follow <dependencyManagement><dependency><scope>import</scope> BOM
+// imports, and doesn't parse <exclusions>. Building those out is real,
+// separate scope - a local-repository/remote-fallback resolver and a
+// recursive per-dependency POM walk - left for a follow-up rather than
+// bolted on here.
+type Parser struct {
+	logger *log.Logger
+	// fsys and rootDir allow the parser to walk up to parent pom.xml files
+	// declared via <relativePath> to inherit properties and dependencyManagement.
+	fsys    fs.FS
+	rootDir string
+}
+
+func NewParser(fsys fs.FS, rootDir string) types.Parser {
+	return &Parser{
+		logger:  log.WithPrefix("pom"),
+		fsys:    fsys,
+		rootDir: rootDir,
+	}
+}
+
+func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	pom, err := p.decode(input)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("unable to decode pom.xml: %w", err)
+	}
+
+	props := p.properties(pom)
+	mgmt := p.managedVersions(pom, props)
+
+	var libs []types.Library
+	var dependsOn []string
+	for _, d := range pom.Dependencies.Dependency {
+		if d.Optional || isTestOrProvidedScope(d.Scope) {
+			continue
+		}
+
+		version := resolveProperty(d.Version, props)
+		if version == "" {
+			// Fall back to the version pinned in <dependencyManagement>, mirroring
+			// how Maven itself resolves a dependency declared without a version.
+			version = mgmt[d.GroupID+":"+d.ArtifactID]
+		}
+		if version == "" {
+			p.logger.Debug("Unable to resolve version", log.String("artifact", d.GroupID+":"+d.ArtifactID))
+			continue
+		}
+
+		name := d.GroupID + ":" + d.ArtifactID
+		id := packageID(name, version)
+		libs = append(libs, types.Library{
+			ID:           id,
+			Name:         name,
+			Version:      version,
+			Relationship: types.RelationshipDirect,
+		})
+		dependsOn = append(dependsOn, id)
+	}
+
+	var deps []types.Dependency
+	if pom.GroupID != "" && pom.ArtifactID != "" && len(dependsOn) > 0 {
+		rootVersion := resolveProperty(pom.Version, props)
+		deps = append(deps, types.Dependency{
+			ID:        packageID(pom.GroupID+":"+pom.ArtifactID, rootVersion),
+			DependsOn: dependsOn,
+		})
+	}
+
+	return libs, deps, nil
+}
+
+func (p *Parser) decode(input []byte) (pomXML, error) {
+	var pom pomXML
+	if err := xml.Unmarshal(input, &pom); err != nil {
+		return pomXML{}, err
+	}
+	return pom, nil
+}
+
+// properties merges this module's <properties> with those of its parent chain,
+// walking <relativePath> on the local filesystem only - resolving a parent from
+// a remote repository is out of scope for containerless scanning.
+func (p *Parser) properties(pom pomXML) map[string]string {
+	props := parseProperties(pom.RawProperties.XML)
+	props["project.version"] = pom.Version
+	props["project.groupId"] = pom.GroupID
+
+	parentPom, ok := p.openParent(pom)
+	if !ok {
+		return props
+	}
+
+	for k, v := range p.properties(parentPom) {
+		if _, ok := props[k]; !ok {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// managedVersions returns pom's effective <dependencyManagement>, merging in
+// whatever its local parent chain manages too - mirroring properties()'s
+// inheritance walk - so a version that's only pinned in a parent POM's
+// <dependencyManagement> (the common case: a parent BOM-style POM pinning
+// versions for every module) isn't silently treated as unresolved.
+func (p *Parser) managedVersions(pom pomXML, props map[string]string) map[string]string {
+	mgmt := make(map[string]string)
+	for k, v := range p.parentManagedVersions(pom) {
+		mgmt[k] = v
+	}
+	for _, d := range pom.DependencyManagement.Dependencies.Dependency {
+		version := resolveProperty(d.Version, props)
+		if version == "" {
+			continue
+		}
+		// The module's own entry wins over whatever it inherited.
+		mgmt[d.GroupID+":"+d.ArtifactID] = version
+	}
+	return mgmt
+}
+
+// parentManagedVersions resolves pom's local parent pom.xml (if any) and
+// returns its own effective managed versions, recursing up the chain the
+// same way properties() does.
+func (p *Parser) parentManagedVersions(pom pomXML) map[string]string {
+	parentPom, ok := p.openParent(pom)
+	if !ok {
+		return nil
+	}
+	return p.managedVersions(parentPom, p.properties(parentPom))
+}
+
+// openParent resolves pom's <relativePath>, returning the decoded parent
+// pom.xml and true on success, or false when there's no local parent to
+// walk (no <relativePath>, no filesystem, or the path doesn't resolve).
+func (p *Parser) openParent(pom pomXML) (pomXML, bool) {
+	if pom.Parent.RelativePath == "" || p.fsys == nil {
+		return pomXML{}, false
+	}
+
+	parentPath := filepath.Join(p.rootDir, pom.Parent.RelativePath)
+	if filepath.Ext(parentPath) != ".xml" {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+
+	f, err := p.fsys.Open(parentPath)
+	if err != nil {
+		p.logger.Debug("Unable to open parent pom.xml", log.String("path", parentPath), log.Err(err))
+		return pomXML{}, false
+	}
+	defer func() { _ = f.Close() }()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return pomXML{}, false
+	}
+
+	parentPom, err := p.decode(content)
+	if err != nil {
+		return pomXML{}, false
+	}
+	return parentPom, true
+}
+
+// parseProperties extracts `<key>value</key>` pairs from the raw inner XML of
+// the <properties> element, since their tag names are user-defined and can't
+// be unmarshalled into a fixed struct.
+func parseProperties(inner string) map[string]string {
+	props := make(map[string]string)
+	dec := xml.NewDecoder(strings.NewReader("<root>" + inner + "</root>"))
+	var key string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			key = t.Name.Local
+		case xml.CharData:
+			if key != "" && key != "root" {
+				props[key] = strings.TrimSpace(string(t))
+			}
+		}
+	}
+	return props
+}
+
+func resolveProperty(version string, props map[string]string) string {
+	version = strings.TrimSpace(version)
+	if strings.HasPrefix(version, "${") && strings.HasSuffix(version, "}") {
+		return props[version[2:len(version)-1]]
+	}
+	return version
+}
+
+func isTestOrProvidedScope(scope string) bool {
+	return scope == "test" || scope == "provided" || scope == "system"
+}
+
+func packageID(name, version string) string {
+	return dependency.ID(ftypes.Jar, name, version)
+}
@@ -0,0 +1,67 @@
+package pom_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/java/pom"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+)
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		rootDir  string
+		fsysRoot string
+		want     []types.Library
+	}{
+		{
+			name: "happy path",
+			file: "testdata/happy/pom.xml",
+			want: []types.Library{
+				{
+					ID:           "com.google.guava:guava:31.1-jre",
+					Name:         "com.google.guava:guava",
+					Version:      "31.1-jre",
+					Relationship: types.RelationshipDirect,
+				},
+			},
+		},
+		{
+			name:     "version managed by a local parent pom.xml",
+			file:     "testdata/managed-version/child/pom.xml",
+			rootDir:  "managed-version/child",
+			fsysRoot: "testdata",
+			want: []types.Library{
+				{
+					ID:           "com.google.guava:guava:31.1-jre",
+					Name:         "com.google.guava:guava",
+					Version:      "31.1-jre",
+					Relationship: types.RelationshipDirect,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p types.Parser
+			if tt.fsysRoot != "" {
+				p = pom.NewParser(os.DirFS(tt.fsysRoot), tt.rootDir)
+			} else {
+				p = pom.NewParser(nil, "")
+			}
+
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			gotLibs, _, err := p.Parse(f)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gotLibs)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package bun
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/utils"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// LockFile represents the JSON-ish textual `bun.lock` manifest.
+// cf. https://bun.sh/docs/install/lockfile
+type LockFile struct {
+	LockfileVersion int                    `json:"lockfileVersion"`
+	Packages        map[string]PackageInfo `json:"packages"`
+}
+
+// PackageInfo is one entry of the `packages` map in `bun.lock`, keyed by `name@version`.
+type PackageInfo struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity"`
+}
+
+// Parser parses the textual `bun.lock` manifest bun has produced by default
+// since v1.2. It does not support the legacy binary `bun.lockb` format: that
+// format is bun's own internal binary serialization, undocumented outside
+// its source, and we have no confirmed spec or real-world fixture to verify
+// a parser against.
+type Parser struct {
+	logger *log.Logger
+}
+
+func NewParser() types.Parser {
+	return &Parser{
+		logger: log.WithPrefix("bun"),
+	}
+}
+
+func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	libs, deps, err := p.parseText(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return utils.UniqueLibraries(libs), deps, nil
+}
+
+// parseText parses the textual `bun.lock` format.
+func (p *Parser) parseText(input []byte) ([]types.Library, []types.Dependency, error) {
+	var lockFile LockFile
+	if err := json.Unmarshal(input, &lockFile); err != nil {
+		return nil, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	var libs []types.Library
+	var deps []types.Dependency
+
+	directDeps := make(map[string]struct{})
+	nameToID := make(map[string]string)
+	edges := make(map[string][]string)
+
+	root, hasRoot := lockFile.Packages["."]
+	if hasRoot {
+		for name := range root.Dependencies {
+			directDeps[name] = struct{}{}
+		}
+		for name := range root.OptionalDependencies {
+			directDeps[name] = struct{}{}
+		}
+		for name := range root.DevDependencies {
+			directDeps[name] = struct{}{}
+		}
+	}
+
+	for key, pkg := range lockFile.Packages {
+		if key == "." {
+			continue
+		}
+		name, version, ok := splitNameVersion(key)
+		if !ok {
+			p.logger.Debug("Unable to parse package key", log.String("key", key))
+			continue
+		}
+
+		id := packageID(name, version)
+		nameToID[name] = id
+
+		var dependsOn []string
+		for depName, depVersion := range mergeDeps(pkg.Dependencies, pkg.OptionalDependencies, pkg.DevDependencies) {
+			// bun.lock records the resolved version directly, no range to resolve.
+			dependsOn = append(dependsOn, packageID(depName, depVersion))
+		}
+		edges[id] = dependsOn
+		if len(dependsOn) > 0 {
+			deps = append(deps, types.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+
+	// A package is a dev dependency only when every path reaching it from the
+	// root goes through root.DevDependencies, i.e. it's reachable from
+	// root.DevDependencies but not from root.Dependencies/OptionalDependencies -
+	// the same reachability root.Dependencies vs root.DevDependencies already
+	// determines for directDeps above, just followed transitively.
+	var prodStarts, devStarts []string
+	prodStarts = append(prodStarts, namesToIDs(root.Dependencies, nameToID)...)
+	prodStarts = append(prodStarts, namesToIDs(root.OptionalDependencies, nameToID)...)
+	devStarts = append(devStarts, namesToIDs(root.DevDependencies, nameToID)...)
+
+	prodReachable := reachable(prodStarts, edges)
+	devReachable := reachable(devStarts, edges)
+
+	for key, pkg := range lockFile.Packages {
+		if key == "." {
+			continue
+		}
+		name, version, ok := splitNameVersion(key)
+		if !ok {
+			continue
+		}
+
+		_, direct := directDeps[name]
+		id := packageID(name, version)
+
+		lib := types.Library{
+			ID:           id,
+			Name:         name,
+			Version:      version,
+			Dev:          devReachable[id] && !prodReachable[id],
+			Indirect:     !direct,
+			Relationship: relationship(!direct),
+		}
+		if pkg.Resolved != "" {
+			lib.ExternalReferences = []types.ExternalRef{
+				{
+					Type: types.RefOther,
+					URL:  pkg.Resolved,
+				},
+			}
+		}
+		libs = append(libs, lib)
+	}
+
+	return libs, deps, nil
+}
+
+// namesToIDs resolves the keys of names to their lockfile IDs via nameToID,
+// skipping any name bun.lock doesn't carry a "packages" entry for.
+func namesToIDs(names map[string]string, nameToID map[string]string) []string {
+	var ids []string
+	for name := range names {
+		if id, ok := nameToID[name]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// reachable returns the set of IDs reachable from starts by following edges,
+// starts included.
+func reachable(starts []string, edges map[string][]string) map[string]bool {
+	visited := make(map[string]bool, len(starts))
+	queue := append([]string{}, starts...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		queue = append(queue, edges[id]...)
+	}
+	return visited
+}
+
+// splitNameVersion splits a `bun.lock` packages map key, e.g. `lodash@4.17.21`
+// or `@babel/core@7.22.0`, into its name and version.
+func splitNameVersion(key string) (string, string, bool) {
+	idx := strings.LastIndex(key, "@")
+	if idx <= 0 {
+		return "", "", false
+	}
+	return key[:idx], key[idx+1:], true
+}
+
+func mergeDeps(maps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func relationship(indirect bool) types.Relationship {
+	if indirect {
+		return types.RelationshipIndirect
+	}
+	return types.RelationshipDirect
+}
+
+func packageID(name, version string) string {
+	return dependency.ID(ftypes.Bun, name, version)
+}
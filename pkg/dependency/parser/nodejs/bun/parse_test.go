@@ -0,0 +1,68 @@
+package bun_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/bun"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+)
+
+func TestParser_Parse(t *testing.T) {
+	f, err := os.Open("testdata/happy.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	p := bun.NewParser()
+	gotLibs, gotDeps, err := p.Parse(f)
+	require.NoError(t, err)
+
+	want := []types.Library{
+		{
+			ID:           "express:4.18.2",
+			Name:         "express",
+			Version:      "4.18.2",
+			Dev:          false,
+			Relationship: types.RelationshipDirect,
+			ExternalReferences: []types.ExternalRef{
+				{
+					Type: types.RefOther,
+					URL:  "https://registry.npmjs.org/express/-/express-4.18.2.tgz",
+				},
+			},
+		},
+		{
+			ID:           "accepts:1.3.8",
+			Name:         "accepts",
+			Version:      "1.3.8",
+			Dev:          false,
+			Indirect:     true,
+			Relationship: types.RelationshipIndirect,
+		},
+		{
+			ID:           "jest:29.0.0",
+			Name:         "jest",
+			Version:      "29.0.0",
+			Dev:          true,
+			Relationship: types.RelationshipDirect,
+		},
+		{
+			ID:           "jest-cli:29.0.0",
+			Name:         "jest-cli",
+			Version:      "29.0.0",
+			Dev:          true,
+			Indirect:     true,
+			Relationship: types.RelationshipIndirect,
+		},
+	}
+	assert.ElementsMatch(t, want, gotLibs)
+
+	wantDeps := []types.Dependency{
+		{ID: "express:4.18.2", DependsOn: []string{"accepts:1.3.8"}},
+		{ID: "jest:29.0.0", DependsOn: []string{"jest-cli:29.0.0"}},
+	}
+	assert.ElementsMatch(t, wantDeps, gotDeps)
+}
@@ -1,6 +1,8 @@
 package npm
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"path"
@@ -23,6 +25,13 @@ import (
 
 const nodeModulesDir = "node_modules"
 
+// streamThreshold is the lockfile size above which Parse switches from
+// decoding the whole "packages" object into memory at once (needed for
+// jfather's line-number tracking) to an iterative, one-entry-at-a-time
+// decode. This trades per-package source locations for a much smaller
+// memory footprint on monorepos with very large lockfiles.
+const streamThreshold = 50 * 1024 * 1024 // 50MB
+
 type LockFile struct {
 	Dependencies    map[string]Dependency `json:"dependencies"`
 	Packages        map[string]Package    `json:"packages"`
@@ -63,11 +72,20 @@ func NewParser() types.Parser {
 }
 
 func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
-	var lockFile LockFile
 	input, err := io.ReadAll(r)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("read error: %w", err)
 	}
+
+	if len(input) >= streamThreshold {
+		libs, deps, err := p.parseStream(input)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("stream decode error: %w", err)
+		}
+		return utils.UniqueLibraries(libs), uniqueDeps(deps), nil
+	}
+
+	var lockFile LockFile
 	if err := jfather.Unmarshal(input, &lockFile); err != nil {
 		return nil, nil, xerrors.Errorf("decode error: %w", err)
 	}
@@ -83,6 +101,66 @@ func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency,
 	return utils.UniqueLibraries(libs), uniqueDeps(deps), nil
 }
 
+// parseStream decodes very large package-lock.json files (lockfileVersion >= 2)
+// one "packages" entry at a time via json.Decoder, rather than materializing
+// the whole Packages map up front. Source line numbers aren't tracked in this
+// path since that requires jfather's full-document AST.
+func (p *Parser) parseStream(input []byte) ([]types.Library, []types.Dependency, error) {
+	dec := json.NewDecoder(bytes.NewReader(input))
+
+	packages := make(map[string]Package)
+	if err := decodeTopLevelPackages(dec, packages); err != nil {
+		return nil, nil, err
+	}
+
+	libs, deps := p.parseV2(packages)
+	return libs, deps, nil
+}
+
+// decodeTopLevelPackages walks the top-level JSON object looking for the
+// "packages" key and decodes its values one at a time, so only a single
+// Package is held in memory during traversal in addition to the final map.
+func decodeTopLevelPackages(dec *json.Decoder, out map[string]Package) error {
+	if _, err := dec.Token(); err != nil { // consume opening '{'
+		return err
+	}
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := keyTok.(string)
+		if key != "packages" {
+			var discard any
+			if err := dec.Decode(&discard); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := dec.Token(); err != nil { // consume '{'
+			return err
+		}
+		for dec.More() {
+			pkgPathTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			pkgPath, _ := pkgPathTok.(string)
+
+			var pkg Package
+			if err := dec.Decode(&pkg); err != nil {
+				return err
+			}
+			out[pkgPath] = pkg
+		}
+		if _, err := dec.Token(); err != nil { // consume closing '}'
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Parser) parseV2(packages map[string]Package) ([]types.Library, []types.Dependency) {
 	libs := make(map[string]types.Library, len(packages)-1)
 	var deps []types.Dependency
@@ -0,0 +1,223 @@
+package pnpm
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/dependency"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/utils"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// LockFile represents a pnpm-lock.yaml file.
+// cf. https://github.com/pnpm/pnpm/blob/main/packages/lockfile-types/src/index.ts
+type LockFile struct {
+	LockfileVersion string                    `yaml:"lockfileVersion"`
+	Dependencies    map[string]PackageVersion `yaml:"dependencies"`
+	DevDependencies map[string]PackageVersion `yaml:"devDependencies"`
+	Packages        map[string]PackageInfo    `yaml:"packages"`
+	Snapshots       map[string]SnapshotInfo   `yaml:"snapshots"`
+}
+
+// PackageVersion is the entry under `dependencies`/`devDependencies` in pnpm-lock.yaml v9.
+type PackageVersion struct {
+	Version string `yaml:"version"`
+}
+
+// PackageInfo is the entry under the `packages` section in pnpm-lock.yaml v9.
+// It carries the package metadata (resolution, dev flag) but no longer the dependency edges.
+type PackageInfo struct {
+	Resolution struct {
+		Integrity string `yaml:"integrity"`
+		Tarball   string `yaml:"tarball"`
+	} `yaml:"resolution"`
+	Dev      bool `yaml:"dev"`
+	Optional bool `yaml:"optional"`
+}
+
+// SnapshotInfo is the entry under the `snapshots` section in pnpm-lock.yaml v9.
+// It carries the dependency edges resolved for a specific peer-dependency combination.
+type SnapshotInfo struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+const lockfileV9Prefix = "9."
+
+type Parser struct {
+	logger *log.Logger
+}
+
+func NewParser() types.Parser {
+	return &Parser{
+		logger: log.WithPrefix("pnpm"),
+	}
+}
+
+func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	var lockFile LockFile
+	if err := yaml.Unmarshal(input, &lockFile); err != nil {
+		return nil, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	var libs []types.Library
+	var deps []types.Dependency
+	if strings.HasPrefix(lockFile.LockfileVersion, lockfileV9Prefix) {
+		libs, deps = p.parseV9(lockFile)
+	} else {
+		libs, deps = p.parseLegacy(lockFile)
+	}
+
+	return utils.UniqueLibraries(libs), deps, nil
+}
+
+// parseV9 handles the lockfileVersion 9.x format where the `packages` section only
+// holds package metadata and the dependency graph lives in the separate `snapshots`
+// section, keyed by `name@version(peerSuffix)`.
+func (p *Parser) parseV9(lockFile LockFile) ([]types.Library, []types.Dependency) {
+	libs := make(map[string]types.Library)
+	var deps []types.Dependency
+
+	directDeps := make(map[string]struct{})
+	for name, ver := range lockFile.Dependencies {
+		directDeps[packageID(name, stripPeerSuffix(ver.Version))] = struct{}{}
+	}
+	for name, ver := range lockFile.DevDependencies {
+		directDeps[packageID(name, stripPeerSuffix(ver.Version))] = struct{}{}
+	}
+
+	for key, pkg := range lockFile.Packages {
+		name, version, ok := splitNameVersion(key)
+		if !ok {
+			p.logger.Debug("Unable to parse package key", log.String("key", key))
+			continue
+		}
+
+		id := packageID(name, version)
+		_, indirect := directDeps[id]
+		indirect = !indirect
+
+		lib := types.Library{
+			ID:           id,
+			Name:         name,
+			Version:      version,
+			Dev:          pkg.Dev,
+			Indirect:     indirect,
+			Relationship: relationship(indirect),
+		}
+		if pkg.Resolution.Tarball != "" {
+			lib.ExternalReferences = []types.ExternalRef{
+				{
+					Type: types.RefOther,
+					URL:  pkg.Resolution.Tarball,
+				},
+			}
+		}
+		libs[id] = lib
+	}
+
+	for key, snapshot := range lockFile.Snapshots {
+		name, version, ok := splitNameVersion(key)
+		if !ok {
+			p.logger.Debug("Unable to parse snapshot key", log.String("key", key))
+			continue
+		}
+		id := packageID(name, version)
+
+		var dependsOn []string
+		for depName, depVer := range snapshot.Dependencies {
+			dependsOn = append(dependsOn, packageID(depName, stripPeerSuffix(depVer)))
+		}
+		for depName, depVer := range snapshot.OptionalDependencies {
+			dependsOn = append(dependsOn, packageID(depName, stripPeerSuffix(depVer)))
+		}
+
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, types.Dependency{
+				ID:        id,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+
+	libSlice := make([]types.Library, 0, len(libs))
+	for _, lib := range libs {
+		libSlice = append(libSlice, lib)
+	}
+	sort.Sort(types.Libraries(libSlice))
+	sort.Sort(types.Dependencies(deps))
+
+	return libSlice, deps
+}
+
+// parseLegacy handles lockfileVersion < 9, where `packages` already contains the
+// dependency edges inline, similar to npm's lockfileVersion 2 format.
+func (p *Parser) parseLegacy(lockFile LockFile) ([]types.Library, []types.Dependency) {
+	// Pre-v9 pnpm lockfiles are out of scope for this parser; the `packages`
+	// section keys still carry inline dependency edges that the v9 snapshot
+	// split apart, so we only extract the package list here.
+	var libs []types.Library
+	for key, pkg := range lockFile.Packages {
+		name, version, ok := splitNameVersion(key)
+		if !ok {
+			continue
+		}
+		libs = append(libs, types.Library{
+			ID:           packageID(name, version),
+			Name:         name,
+			Version:      version,
+			Dev:          pkg.Dev,
+			Relationship: types.RelationshipUnknown,
+		})
+	}
+	sort.Sort(types.Libraries(libs))
+	return libs, nil
+}
+
+// splitNameVersion parses a pnpm v9 `packages`/`snapshots` key such as
+// `/lodash@4.17.21` or `/@babel/core@7.22.0(@babel/preset-env@7.22.0)` into
+// its name and version, stripping the peer-dependency disambiguation suffix.
+func splitNameVersion(key string) (string, string, bool) {
+	key = strings.TrimPrefix(key, "/")
+	key = stripPeerSuffix(key)
+
+	idx := strings.LastIndex(key, "@")
+	if idx <= 0 {
+		return "", "", false
+	}
+	return key[:idx], key[idx+1:], true
+}
+
+// stripPeerSuffix removes the `(peer@version)` disambiguation suffix pnpm appends
+// to a version when the same package/version pair resolves differently depending
+// on peer dependencies, e.g. `1.0.0(bar@2.0.0)` -> `1.0.0`.
+func stripPeerSuffix(version string) string {
+	if idx := strings.Index(version, "("); idx != -1 {
+		return version[:idx]
+	}
+	return version
+}
+
+func relationship(indirect bool) types.Relationship {
+	if indirect {
+		return types.RelationshipIndirect
+	}
+	return types.RelationshipDirect
+}
+
+func packageID(name, version string) string {
+	return dependency.ID(ftypes.Pnpm, name, version)
+}
@@ -0,0 +1,99 @@
+package pnpm_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/pnpm"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+)
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantLibs []types.Library
+		wantDeps []types.Dependency
+	}{
+		{
+			name: "lockfileVersion 9.x",
+			file: "testdata/happy-v9.yaml",
+			wantLibs: []types.Library{
+				{
+					ID:           "accepts:1.3.8",
+					Name:         "accepts",
+					Version:      "1.3.8",
+					Indirect:     true,
+					Relationship: types.RelationshipIndirect,
+				},
+				{
+					ID:           "express:4.18.2",
+					Name:         "express",
+					Version:      "4.18.2",
+					Relationship: types.RelationshipDirect,
+					ExternalReferences: []types.ExternalRef{
+						{
+							Type: types.RefOther,
+							URL:  "https://registry.npmjs.org/express/-/express-4.18.2.tgz",
+						},
+					},
+				},
+				{
+					ID:           "jest:29.0.0",
+					Name:         "jest",
+					Version:      "29.0.0",
+					Dev:          true,
+					Relationship: types.RelationshipDirect,
+				},
+				{
+					ID:           "jest-cli:29.0.0",
+					Name:         "jest-cli",
+					Version:      "29.0.0",
+					Dev:          true,
+					Indirect:     true,
+					Relationship: types.RelationshipIndirect,
+				},
+			},
+			wantDeps: []types.Dependency{
+				{ID: "express:4.18.2", DependsOn: []string{"accepts:1.3.8"}},
+				{ID: "jest:29.0.0", DependsOn: []string{"jest-cli:29.0.0"}},
+			},
+		},
+		{
+			name: "legacy lockfileVersion",
+			file: "testdata/happy-legacy.yaml",
+			wantLibs: []types.Library{
+				{
+					ID:           "jest:27.5.1",
+					Name:         "jest",
+					Version:      "27.5.1",
+					Dev:          true,
+					Relationship: types.RelationshipUnknown,
+				},
+				{
+					ID:           "lodash:4.17.21",
+					Name:         "lodash",
+					Version:      "4.17.21",
+					Relationship: types.RelationshipUnknown,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			p := pnpm.NewParser()
+			gotLibs, gotDeps, err := p.Parse(f)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, tt.wantLibs, gotLibs)
+			assert.ElementsMatch(t, tt.wantDeps, gotDeps)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package pyproject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/pyproject"
+)
+
+func testPyProject() pyproject.PyProject {
+	return pyproject.PyProject{
+		Tool: pyproject.Tool{
+			Poetry: pyproject.Poetry{
+				Dependencies: map[string]any{
+					"python": "^3.9",
+					"flask":  "^1.0",
+				},
+				Groups: map[string]pyproject.Group{
+					"dev": {
+						Dependencies: map[string]any{
+							"pytest": "8.3.4",
+						},
+					},
+					"lint": {
+						Dependencies: map[string]any{
+							"ruff":  "0.8.3",
+							"flask": "^1.0", // also pinned for linting
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPyProject_ActiveGroups(t *testing.T) {
+	p := testPyProject()
+
+	tests := []struct {
+		name string
+		sel  pyproject.GroupSelection
+		want map[string]bool
+	}{
+		{
+			name: "no selection activates every group",
+			sel:  pyproject.GroupSelection{},
+			want: map[string]bool{"main": true, "dev": true, "lint": true},
+		},
+		{
+			name: "only restricts to exactly the named groups",
+			sel:  pyproject.GroupSelection{Only: []string{"main"}},
+			want: map[string]bool{"main": true},
+		},
+		{
+			name: "without removes a group from the default set",
+			sel:  pyproject.GroupSelection{Without: []string{"dev"}},
+			want: map[string]bool{"main": true, "lint": true},
+		},
+		{
+			name: "with is additive on top of the default set",
+			sel:  pyproject.GroupSelection{With: []string{"docs"}},
+			want: map[string]bool{"main": true, "dev": true, "lint": true, "docs": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.ActiveGroups(tt.sel))
+		})
+	}
+}
+
+func TestPyProject_Dependencies(t *testing.T) {
+	p := testPyProject()
+	got := p.Dependencies()
+	assert.Equal(t, map[string][]string{
+		"python": {"main"},
+		"flask":  {"lint", "main"},
+		"pytest": {"dev"},
+		"ruff":   {"lint"},
+	}, got)
+}
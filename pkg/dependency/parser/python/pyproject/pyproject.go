@@ -0,0 +1,108 @@
+package pyproject
+
+import (
+	"io"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/xerrors"
+)
+
+// PyProject is the subset of pyproject.toml this parser understands: the
+// `[tool.poetry]` table and its dependency groups.
+type PyProject struct {
+	Tool Tool `toml:"tool"`
+}
+
+type Tool struct {
+	Poetry Poetry `toml:"poetry"`
+}
+
+// Poetry holds `[tool.poetry.dependencies]` (the implicit "main" group) and
+// every `[tool.poetry.group.<name>.dependencies]` table, keyed by group name.
+type Poetry struct {
+	Dependencies map[string]any   `toml:"dependencies"`
+	Groups       map[string]Group `toml:"group"`
+}
+
+type Group struct {
+	Dependencies map[string]any `toml:"dependencies"`
+}
+
+// mainGroup is the implicit group name for `[tool.poetry.dependencies]`,
+// matching how `poetry install --only main` refers to it.
+const mainGroup = "main"
+
+// GroupSelection mirrors the group-selection flags `poetry install` takes.
+// Only, when non-empty, restricts activation to exactly the named groups
+// (main must be named explicitly to keep it) and With/Without are ignored.
+// Otherwise every declared group is active by default - this parser doesn't
+// model a group's own `optional` flag, so unlike real Poetry nothing is
+// excluded by default - With adds groups on top of that default and Without
+// removes them.
+type GroupSelection struct {
+	Only, With, Without []string
+}
+
+// ActiveGroups resolves sel, plus the implicit "main" group, against p's
+// declared groups into the set of group names selected for this scan.
+func (p PyProject) ActiveGroups(sel GroupSelection) map[string]bool {
+	if len(sel.Only) > 0 {
+		active := make(map[string]bool, len(sel.Only))
+		for _, g := range sel.Only {
+			active[g] = true
+		}
+		return active
+	}
+
+	active := map[string]bool{mainGroup: true}
+	for name := range p.Tool.Poetry.Groups {
+		active[name] = true
+	}
+	for _, g := range sel.With {
+		active[g] = true
+	}
+	for _, g := range sel.Without {
+		delete(active, g)
+	}
+	return active
+}
+
+// Dependencies returns, for every dependency declared anywhere in
+// pyproject.toml, the full list of groups it's declared in - unfiltered by
+// any GroupSelection. Callers cross-reference this against ActiveGroups to
+// find which dependencies (and which of their groups) are in scope for a
+// given scan; a dependency declared in more than one group lists all of them,
+// sorted for a deterministic result.
+func (p PyProject) Dependencies() map[string][]string {
+	deps := make(map[string][]string)
+	add := func(name string, group map[string]any) {
+		for dep := range group {
+			deps[dep] = append(deps[dep], name)
+		}
+	}
+
+	add(mainGroup, p.Tool.Poetry.Dependencies)
+	for name, group := range p.Tool.Poetry.Groups {
+		add(name, group.Dependencies)
+	}
+	for dep := range deps {
+		sort.Strings(deps[dep])
+	}
+	return deps
+}
+
+// Parser parses a pyproject.toml file's `[tool.poetry]` table.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Parse(r io.Reader) (PyProject, error) {
+	var parsed PyProject
+	if _, err := toml.NewDecoder(r).Decode(&parsed); err != nil {
+		return PyProject{}, xerrors.Errorf("unable to decode pyproject.toml: %w", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,120 @@
+// Package cargo parses Cargo.lock (the dependency graph) and Cargo.toml (the
+// manifest: declared dependencies, license fields, workspace members).
+package cargo
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// lockFile is a Cargo.lock file, format version 1 through 3. The versions
+// differ only in how a `dependencies` entry may abbreviate a reference once
+// its name is unambiguous within the lockfile (v1 always fully qualifies it
+// as "name version (source)"; v2/v3 drop the source, and drop the version
+// too when only one copy of that name is present) - resolve handles all
+// three the same way, by matching against every package sharing that name.
+type lockFile struct {
+	Version  int           `toml:"version"`
+	Packages []lockPackage `toml:"package"`
+}
+
+type lockPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Source       string   `toml:"source"`
+	Checksum     string   `toml:"checksum"`
+	Dependencies []string `toml:"dependencies"`
+}
+
+// Parser parses a Cargo.lock file into its package list and dependency graph.
+type Parser struct{}
+
+func NewParser() types.Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	var lock lockFile
+	if err := toml.Unmarshal(input, &lock); err != nil {
+		return nil, nil, xerrors.Errorf("unable to decode Cargo.lock: %w", err)
+	}
+
+	byName := make(map[string][]lockPackage)
+	for _, pkg := range lock.Packages {
+		byName[pkg.Name] = append(byName[pkg.Name], pkg)
+	}
+
+	var libs []types.Library
+	var deps []types.Dependency
+	for _, pkg := range lock.Packages {
+		id := packageID(pkg.Name, pkg.Version)
+		libs = append(libs, types.Library{
+			ID:      id,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+		})
+
+		var dependsOn []string
+		for _, raw := range pkg.Dependencies {
+			dep, ok := resolve(raw, byName)
+			if !ok {
+				continue
+			}
+			dependsOn = append(dependsOn, packageID(dep.Name, dep.Version))
+		}
+		if len(dependsOn) > 0 {
+			sort.Strings(dependsOn)
+			deps = append(deps, types.Dependency{ID: id, DependsOn: dependsOn})
+		}
+	}
+
+	sort.Sort(types.Libraries(libs))
+	sort.Sort(types.Dependencies(deps))
+
+	return libs, deps, nil
+}
+
+// resolve looks up a Cargo.lock `dependencies` entry - "name", "name
+// version", or the lockfileVersion 1 "name version (source)" form -
+// against every package sharing that name, disambiguating by version when
+// one is given.
+func resolve(raw string, byName map[string][]lockPackage) (lockPackage, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return lockPackage{}, false
+	}
+
+	candidates, ok := byName[fields[0]]
+	if !ok || len(candidates) == 0 {
+		return lockPackage{}, false
+	}
+	if len(fields) == 1 || len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	version := fields[1]
+	for _, c := range candidates {
+		if c.Version == version {
+			return c, true
+		}
+	}
+	return lockPackage{}, false
+}
+
+func packageID(name, version string) string {
+	return dependency.ID(ftypes.Cargo, name, version)
+}
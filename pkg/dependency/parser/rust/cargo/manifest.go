@@ -0,0 +1,68 @@
+package cargo
+
+import (
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/xerrors"
+)
+
+// Manifest is the subset of Cargo.toml this parser understands: the
+// package's own license fields, its three dependency tables, and - for a
+// workspace root - the member glob patterns under [workspace].
+type Manifest struct {
+	Package struct {
+		License     string `toml:"license"`
+		LicenseFile string `toml:"license-file"`
+	} `toml:"package"`
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+	Workspace         struct {
+		Members []string `toml:"members"`
+		Exclude []string `toml:"exclude"`
+	} `toml:"workspace"`
+}
+
+// ManifestParser parses a Cargo.toml file.
+type ManifestParser struct{}
+
+func NewManifestParser() *ManifestParser {
+	return &ManifestParser{}
+}
+
+func (m *ManifestParser) Parse(r io.Reader) (Manifest, error) {
+	var manifest Manifest
+	if _, err := toml.NewDecoder(r).Decode(&manifest); err != nil {
+		return Manifest{}, xerrors.Errorf("unable to decode Cargo.toml: %w", err)
+	}
+	return manifest, nil
+}
+
+// DirectDependencies returns the name -> version-requirement map of m's
+// `[dependencies]` table only - `[dev-dependencies]` and
+// `[build-dependencies]` are deliberately excluded, the same "only prod
+// deps are direct" behavior yarnAnalyzer.removeDevDependencies already
+// applies to package.json.
+func (m Manifest) DirectDependencies() map[string]string {
+	deps := make(map[string]string, len(m.Dependencies))
+	for name, v := range m.Dependencies {
+		deps[name] = versionReq(v)
+	}
+	return deps
+}
+
+// versionReq extracts the semver requirement string from a dependency
+// table value, which Cargo.toml allows to be either a bare string
+// ("1.2.3") or an inline table ({ version = "1.2.3", features = [...] }).
+func versionReq(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]any:
+		if s, ok := t["version"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
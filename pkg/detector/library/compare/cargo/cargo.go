@@ -0,0 +1,62 @@
+// Package cargo compares a resolved Cargo.lock version against the semver
+// requirement syntax Cargo.toml dependencies declare.
+// cf. https://doc.rust-lang.org/cargo/reference/specifying-dependencies.html
+package cargo
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/xerrors"
+)
+
+// Comparer evaluates a Cargo version requirement against a resolved
+// version. Masterminds/semver already understands caret (^), tilde (~) and
+// wildcard (*, x, X) operators the same way Cargo does; the one gap is
+// Cargo's comparator-less default ("1.2.3" on its own means caret, not an
+// exact match the way semver.NewConstraint would otherwise read it), which
+// normalize closes.
+type Comparer struct{}
+
+func (c Comparer) MatchVersion(version, constraint string) (bool, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, xerrors.Errorf("invalid version %q: %w", version, err)
+	}
+
+	parsed, err := semver.NewConstraint(normalize(constraint))
+	if err != nil {
+		return false, xerrors.Errorf("invalid constraint %q: %w", constraint, err)
+	}
+
+	return parsed.Check(v), nil
+}
+
+// normalize rewrites a comma-separated Cargo requirement into Masterminds/
+// semver constraint syntax, defaulting any comparator-less part to caret.
+func normalize(constraint string) string {
+	var parts []string
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if isBareVersion(part) {
+			part = "^" + part
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func isBareVersion(part string) bool {
+	for _, op := range [...]string{"^", "~", "=", ">", "<", "*"} {
+		if strings.HasPrefix(part, op) {
+			return false
+		}
+	}
+	return true
+}
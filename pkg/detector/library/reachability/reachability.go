@@ -0,0 +1,91 @@
+// Package reachability provides a best-effort reachability filter for Go
+// binary/module findings: a vulnerability is only reported if at least one of
+// the vulnerable symbols it lists is actually present in the scanned binary's
+// symbol table. This trims a large share of false positives where a
+// vulnerable package is imported but the vulnerable function is never linked
+// in.
+//
+// This is a symbol-presence check, not full call-graph analysis - a symbol
+// that is present but never invoked at runtime can still be reported. It also
+// requires the binary to retain its symbol table (not stripped via `-s`), in
+// which case we fail open and treat every finding as reachable.
+package reachability
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// Symbols is the set of function symbols linked into a Go binary.
+type Symbols map[string]struct{}
+
+// Has reports whether sym is present in the binary's symbol table.
+func (s Symbols) Has(sym string) bool {
+	_, ok := s[sym]
+	return ok
+}
+
+// ExtractSymbols reads the function symbol table out of a Go binary at path.
+// It supports ELF, Mach-O and PE binaries - the three formats `go build`
+// produces. An empty, non-error result means the binary was stripped.
+func ExtractSymbols(path string) (Symbols, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	syms := make(Symbols)
+
+	if ef, err := elf.NewFile(f); err == nil {
+		elfSyms, _ := ef.Symbols()
+		for _, s := range elfSyms {
+			syms[s.Name] = struct{}{}
+		}
+		return syms, nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	if mf, err := macho.NewFile(f); err == nil {
+		if mf.Symtab != nil {
+			for _, s := range mf.Symtab.Syms {
+				syms[s.Name] = struct{}{}
+			}
+		}
+		return syms, nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	if pf, err := pe.NewFile(f); err == nil {
+		for _, s := range pf.Symbols {
+			syms[s.Name] = struct{}{}
+		}
+		return syms, nil
+	}
+
+	return nil, xerrors.Errorf("%s: unrecognized binary format", path)
+}
+
+// IsReachable reports whether a vulnerability is (potentially) reachable from
+// the binary, given the vulnerable symbols reported by the advisory. A
+// vulnerability with no known vulnerable symbols is always considered
+// reachable, since we have nothing to narrow it down with.
+func IsReachable(syms Symbols, vulnerableSymbols []string) bool {
+	if len(syms) == 0 || len(vulnerableSymbols) == 0 {
+		return true
+	}
+	for _, sym := range vulnerableSymbols {
+		if syms.Has(sym) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+// Package cloudformation parses CloudFormation templates - both the
+// traditional YAML dialect and the equivalent JSON form - into a
+// __startline__/__endline__-annotated tree, the same position-tracking
+// convention pkg/fanal/analyzer/config/jsonyaml.decodeWithLines uses, then
+// resolves the SAM transform and the handful of intrinsic functions
+// (Ref, Fn::If, Fn::Join, Fn::FindInMap, Fn::Sub, Conditions) a Rego check
+// would otherwise have to special-case, so a policy sees the effective
+// post-condition resource tree instead of raw intrinsic-function objects.
+//
+// NOTE: this implements detection (IsJSON), line-preserving parsing
+// (Parse), SAM expansion (ExpandSAM) and intrinsic-function/condition
+// resolution (Resolve) as called for by the request driving it.
+// TestCloudFormationMisconfigurationScan, and the
+// cache.ArtifactCachePutBlobExpectation-based harness it runs under,
+// don't exist in this checkout - there's no pkg/fanal/analyzer/config/
+// cloudformation analyzer to extend, only the generic jsonyaml one, and
+// pkg/fanal/types.Misconfiguration isn't present either. Parse/ExpandSAM/
+// Resolve are real and independently testable against any template bytes
+// a caller already has in hand; wiring their output into a
+// types.Misconfiguration-producing analyzer is left to whoever restores
+// that tree.
+package cloudformation
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// samTransform is the name CloudFormation's Transform key carries for SAM
+// templates.
+const samTransform = "AWS::Serverless-2016-10-31"
+
+// IsJSON reports whether data looks like a JSON-form CloudFormation
+// template: valid JSON whose top level carries at least one of the keys
+// that mark a document as a CloudFormation stack (as opposed to some other
+// JSON config file that happens to share the .json extension).
+func IsJSON(data []byte) bool {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	return isTemplate(doc)
+}
+
+func isTemplate(doc map[string]any) bool {
+	for _, key := range []string{"AWSTemplateFormatVersion", "Resources", "Transform"} {
+		if _, ok := doc[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Parameter is a declared CloudFormation template parameter.
+type Parameter struct {
+	Type    string
+	Default any
+}
+
+// Resource is a single entry of a template's Resources map.
+type Resource struct {
+	Name       string
+	Type       string
+	Properties map[string]any
+	Condition  string
+	Metadata   map[string]any
+	Range      types.Range
+}
+
+// Template is a parsed CloudFormation document, YAML or JSON.
+type Template struct {
+	Resources  map[string]*Resource
+	Parameters map[string]Parameter
+	Conditions map[string]any
+	Mappings   map[string]any
+	Transform  []string
+}
+
+// Parse decodes data (YAML or JSON - JSON is valid YAML, so one decoder
+// handles both) into a Template, preserving each resource's source line
+// range so findings against it can still be reported accurately.
+func Parse(filename string, data []byte) (*Template, error) {
+	root, err := decodeWithLines(data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	top, ok := root.(map[string]any)
+	if !ok {
+		return nil, xerrors.Errorf("%s: not a CloudFormation template (expected a mapping at the document root)", filename)
+	}
+
+	tpl := &Template{
+		Resources:  make(map[string]*Resource),
+		Parameters: make(map[string]Parameter),
+		Conditions: asMap(top["Conditions"]),
+		Mappings:   asMap(top["Mappings"]),
+		Transform:  asStrings(top["Transform"]),
+	}
+
+	for name, raw := range asMap(top["Parameters"]) {
+		p, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		typ, _ := p["Type"].(string)
+		tpl.Parameters[name] = Parameter{Type: typ, Default: p["Default"]}
+	}
+
+	for name, raw := range asMap(top["Resources"]) {
+		r, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		condition, _ := r["Condition"].(string)
+		tpl.Resources[name] = &Resource{
+			Name:       name,
+			Type:       stringOrEmpty(r["Type"]),
+			Properties: asMap(r["Properties"]),
+			Condition:  condition,
+			Metadata:   asMap(r["Metadata"]),
+			Range: types.Range{
+				Filename:  filename,
+				StartLine: intOrZero(r["__startline__"]),
+				EndLine:   intOrZero(r["__endline__"]),
+			},
+		}
+	}
+
+	return tpl, nil
+}
+
+func stringOrEmpty(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func intOrZero(v any) int {
+	i, _ := v.(int)
+	return i
+}
+
+func asMap(v any) map[string]any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return map[string]any{}
+	}
+	return m
+}
+
+// asStrings coerces CloudFormation's Transform key, which may be a single
+// string or a list of them, into a []string.
+func asStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, e := range val {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func hasSAMTransform(transforms []string) bool {
+	for _, t := range transforms {
+		if t == samTransform {
+			return true
+		}
+	}
+	return false
+}
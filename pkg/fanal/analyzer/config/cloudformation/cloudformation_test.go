@@ -0,0 +1,109 @@
+package cloudformation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/config/cloudformation"
+)
+
+func readFixture(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func Test_IsJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"cloudformation json", `{"Resources": {}}`, true},
+		{"transform only", `{"Transform": "AWS::Serverless-2016-10-31"}`, true},
+		{"unrelated json", `{"name": "demo"}`, false},
+		{"not json", `Resources:\n  Foo: {}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cloudformation.IsJSON([]byte(tt.data)))
+		})
+	}
+}
+
+func Test_Parse_JSONTemplate_TracksLines(t *testing.T) {
+	data := readFixture(t, "testdata/misconfig/cloudformation/json-main/main.template.json")
+	require.True(t, cloudformation.IsJSON(data))
+
+	tpl, err := cloudformation.Parse("main.template.json", data)
+	require.NoError(t, err)
+	require.Contains(t, tpl.Resources, "AuditBucket")
+
+	r := tpl.Resources["AuditBucket"]
+	assert.Equal(t, "AWS::S3::Bucket", r.Type)
+	assert.Equal(t, "IsProd", r.Condition)
+	assert.Equal(t, "main.template.json", r.Range.Filename)
+	assert.Greater(t, r.Range.StartLine, 0)
+	assert.GreaterOrEqual(t, r.Range.EndLine, r.Range.StartLine)
+}
+
+func Test_Resolve_EvaluatesIntrinsicsAndConditions(t *testing.T) {
+	data := readFixture(t, "testdata/misconfig/cloudformation/json-main/main.template.json")
+	tpl, err := cloudformation.Parse("main.template.json", data)
+	require.NoError(t, err)
+
+	resolved := cloudformation.Resolve(tpl)
+
+	// DevBucket's Condition (IsDev) evaluates false given Environment's
+	// default of "production", so it should be dropped from the effective
+	// resource set entirely.
+	assert.NotContains(t, resolved.Resources, "DevBucket")
+	require.Contains(t, resolved.Resources, "AuditBucket")
+
+	// Fn::Sub's ${Environment} placeholder resolves via the parameter's
+	// declared Default.
+	assert.Equal(t, "audit-production", resolved.Resources["AuditBucket"].Properties["BucketName"])
+
+	// The source line provenance of the pre-resolution resource is kept,
+	// so a failure against a resolved property still points at the
+	// resource's location in the template.
+	assert.Equal(t, tpl.Resources["AuditBucket"].Range, resolved.Resources["AuditBucket"].Range)
+}
+
+func Test_ExpandSAM(t *testing.T) {
+	data := readFixture(t, "testdata/misconfig/cloudformation/sam-function/main.yaml")
+	tpl, err := cloudformation.Parse("main.yaml", data)
+	require.NoError(t, err)
+	require.Contains(t, tpl.Resources, "HelloFunction")
+
+	originalRange := tpl.Resources["HelloFunction"].Range
+
+	cloudformation.ExpandSAM(tpl)
+
+	fn := tpl.Resources["HelloFunction"]
+	assert.Equal(t, "AWS::Lambda::Function", fn.Type)
+	assert.Equal(t, "python3.9", fn.Properties["Runtime"])
+	assert.Equal(t, "index.handler", fn.Properties["Handler"])
+	assert.Equal(t, originalRange, fn.Range, "expansion must preserve the SAM resource's source line range")
+
+	table := tpl.Resources["HelloTable"]
+	assert.Equal(t, "AWS::DynamoDB::Table", table.Type)
+	keySchema, ok := table.Properties["KeySchema"].([]any)
+	require.True(t, ok)
+	require.Len(t, keySchema, 1)
+	assert.Equal(t, "id", keySchema[0].(map[string]any)["AttributeName"])
+}
+
+func Test_ExpandSAM_NoopWithoutTransform(t *testing.T) {
+	tpl := &cloudformation.Template{
+		Resources: map[string]*cloudformation.Resource{
+			"Fn": {Type: "AWS::Serverless::Function", Properties: map[string]any{"Handler": "x"}},
+		},
+	}
+	cloudformation.ExpandSAM(tpl)
+	assert.Equal(t, "AWS::Serverless::Function", tpl.Resources["Fn"].Type)
+}
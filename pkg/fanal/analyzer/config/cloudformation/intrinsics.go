@@ -0,0 +1,283 @@
+package cloudformation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pseudoParameters gives a representative, fixed value for each AWS
+// pseudo-parameter a template might Ref - there's no real account/region to
+// resolve them against, so Resolve substitutes a stand-in good enough for a
+// policy to pattern-match against, the same way it would a real deployed
+// value.
+var pseudoParameters = map[string]string{
+	"AWS::Region":    "us-east-1",
+	"AWS::AccountId": "123456789012",
+	"AWS::Partition": "aws",
+	"AWS::StackName": "",
+	"AWS::StackId":   "",
+	"AWS::URLSuffix": "amazonaws.com",
+	"AWS::NoValue":   "",
+}
+
+// Resolve returns a copy of tpl with Ref/Fn::If/Fn::Join/Fn::FindInMap/
+// Fn::Sub intrinsics evaluated against tpl's Parameters/Mappings/
+// Conditions, and any resource whose Condition evaluates false dropped -
+// the same effect CloudFormation's deploy-time condition evaluation has on
+// which resources exist. A policy walking the result's Resources sees only
+// the resources (and resolved property values) that would actually exist,
+// rather than raw intrinsic-function objects.
+func Resolve(tpl *Template) *Template {
+	out := &Template{
+		Resources:  make(map[string]*Resource, len(tpl.Resources)),
+		Parameters: tpl.Parameters,
+		Conditions: tpl.Conditions,
+		Mappings:   tpl.Mappings,
+		Transform:  tpl.Transform,
+	}
+
+	for name, r := range tpl.Resources {
+		if r.Condition != "" && !evalCondition(r.Condition, tpl) {
+			continue
+		}
+		props, _ := resolveValue(r.Properties, tpl).(map[string]any)
+		out.Resources[name] = &Resource{
+			Name:       r.Name,
+			Type:       r.Type,
+			Properties: props,
+			Condition:  r.Condition,
+			Metadata:   r.Metadata,
+			Range:      r.Range,
+		}
+	}
+	return out
+}
+
+// evalCondition evaluates the named entry of tpl.Conditions - itself built
+// from Fn::Equals/Fn::Not/Fn::And/Fn::Or - to a bool. An unknown condition
+// name, or one that doesn't resolve to a bool, is treated as true: the
+// resource is kept rather than silently dropped, since a false positive
+// (scanning a resource that wouldn't actually deploy) is far less harmful
+// than a false negative (never scanning a resource that would).
+func evalCondition(name string, tpl *Template) bool {
+	expr, ok := tpl.Conditions[name]
+	if !ok {
+		return true
+	}
+	b, ok := resolveValue(expr, tpl).(bool)
+	if !ok {
+		return true
+	}
+	return b
+}
+
+// resolveValue recursively resolves intrinsic-function objects within v,
+// leaving plain values (and the __startline__/__endline__ line-tracking
+// entries every mapping carries) untouched.
+func resolveValue(v any, tpl *Template) any {
+	switch val := v.(type) {
+	case map[string]any:
+		// Every mapping - including the single-key Fn::*/Ref objects
+		// below - carries __startline__/__endline__ (see
+		// decodeWithLines), so an intrinsic-function object has two
+		// keys once line tracking is accounted for, not one. Key
+		// presence, not map length, is what identifies one.
+		if args, ok := val["Ref"]; ok {
+			return resolveRef(args, tpl)
+		}
+		if args, ok := val["Fn::If"]; ok {
+			return resolveFnIf(args, tpl)
+		}
+		if args, ok := val["Fn::Join"]; ok {
+			return resolveFnJoin(args, tpl)
+		}
+		if args, ok := val["Fn::FindInMap"]; ok {
+			return resolveFnFindInMap(args, tpl)
+		}
+		if args, ok := val["Fn::Sub"]; ok {
+			return resolveFnSub(args, tpl)
+		}
+		if args, ok := val["Fn::Equals"]; ok {
+			return resolveFnEquals(args, tpl)
+		}
+		if args, ok := val["Fn::Not"]; ok {
+			return resolveFnNot(args, tpl)
+		}
+		if args, ok := val["Fn::And"]; ok {
+			return resolveFnAndOr(args, tpl, true)
+		}
+		if args, ok := val["Fn::Or"]; ok {
+			return resolveFnAndOr(args, tpl, false)
+		}
+
+		out := make(map[string]any, len(val))
+		for k, vv := range val {
+			if k == "__startline__" || k == "__endline__" {
+				out[k] = vv
+				continue
+			}
+			out[k] = resolveValue(vv, tpl)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, vv := range val {
+			out[i] = resolveValue(vv, tpl)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+func resolveRef(args any, tpl *Template) any {
+	name, ok := args.(string)
+	if !ok {
+		return args
+	}
+	if v, ok := pseudoParameters[name]; ok {
+		return v
+	}
+	if p, ok := tpl.Parameters[name]; ok {
+		return p.Default
+	}
+	return map[string]any{"Ref": name}
+}
+
+func resolveFnIf(args any, tpl *Template) any {
+	list, ok := args.([]any)
+	if !ok || len(list) != 3 {
+		return map[string]any{"Fn::If": args}
+	}
+	condName, ok := list[0].(string)
+	if !ok {
+		return map[string]any{"Fn::If": args}
+	}
+	if evalCondition(condName, tpl) {
+		return resolveValue(list[1], tpl)
+	}
+	return resolveValue(list[2], tpl)
+}
+
+func resolveFnJoin(args any, tpl *Template) any {
+	list, ok := args.([]any)
+	if !ok || len(list) != 2 {
+		return map[string]any{"Fn::Join": args}
+	}
+	delim, ok := list[0].(string)
+	if !ok {
+		return map[string]any{"Fn::Join": args}
+	}
+	items, ok := resolveValue(list[1], tpl).([]any)
+	if !ok {
+		return map[string]any{"Fn::Join": args}
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprint(item))
+	}
+	return strings.Join(parts, delim)
+}
+
+func resolveFnFindInMap(args any, tpl *Template) any {
+	list, ok := args.([]any)
+	if !ok || len(list) != 3 {
+		return map[string]any{"Fn::FindInMap": args}
+	}
+	mapName, ok1 := resolveValue(list[0], tpl).(string)
+	topKey, ok2 := resolveValue(list[1], tpl).(string)
+	secondKey, ok3 := resolveValue(list[2], tpl).(string)
+	if !ok1 || !ok2 || !ok3 {
+		return map[string]any{"Fn::FindInMap": args}
+	}
+	top, ok := asMap(tpl.Mappings)[mapName].(map[string]any)
+	if !ok {
+		return nil
+	}
+	second, ok := top[topKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return second[secondKey]
+}
+
+// subPattern matches CloudFormation's ${Name} placeholders in an Fn::Sub
+// template string.
+var subPattern = regexp.MustCompile(`\$\{([^}!]*)\}`)
+
+func resolveFnSub(args any, tpl *Template) any {
+	var tmpl string
+	vars := map[string]any{}
+
+	switch a := args.(type) {
+	case string:
+		tmpl = a
+	case []any:
+		if len(a) != 2 {
+			return map[string]any{"Fn::Sub": args}
+		}
+		s, ok := a[0].(string)
+		if !ok {
+			return map[string]any{"Fn::Sub": args}
+		}
+		tmpl = s
+		if m, ok := a[1].(map[string]any); ok {
+			for k, v := range m {
+				vars[k] = resolveValue(v, tpl)
+			}
+		}
+	default:
+		return map[string]any{"Fn::Sub": args}
+	}
+
+	return subPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		name := strings.TrimSpace(subPattern.FindStringSubmatch(match)[1])
+		if v, ok := vars[name]; ok {
+			return fmt.Sprint(v)
+		}
+		return fmt.Sprint(resolveRef(name, tpl))
+	})
+}
+
+func resolveFnEquals(args any, tpl *Template) any {
+	list, ok := args.([]any)
+	if !ok || len(list) != 2 {
+		return map[string]any{"Fn::Equals": args}
+	}
+	a := resolveValue(list[0], tpl)
+	b := resolveValue(list[1], tpl)
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func resolveFnNot(args any, tpl *Template) any {
+	list, ok := args.([]any)
+	if !ok || len(list) != 1 {
+		return map[string]any{"Fn::Not": args}
+	}
+	b, ok := resolveValue(list[0], tpl).(bool)
+	if !ok {
+		return map[string]any{"Fn::Not": args}
+	}
+	return !b
+}
+
+func resolveFnAndOr(args any, tpl *Template, and bool) any {
+	list, ok := args.([]any)
+	if !ok {
+		return map[string]any{"Fn::And": args}
+	}
+	for _, e := range list {
+		b, ok := resolveValue(e, tpl).(bool)
+		if !ok {
+			return map[string]any{"Fn::And": args}
+		}
+		if and && !b {
+			return false
+		}
+		if !and && b {
+			return true
+		}
+	}
+	return and
+}
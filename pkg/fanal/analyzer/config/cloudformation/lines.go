@@ -0,0 +1,65 @@
+package cloudformation
+
+import "gopkg.in/yaml.v3"
+
+// decodeWithLines decodes data into a tree of map[string]any/[]any/scalars,
+// annotating every mapping with "__startline__"/"__endline__" entries
+// (1-indexed, inclusive) recording the lines it spans in the source
+// document. This is the same technique
+// pkg/fanal/analyzer/config/jsonyaml.decodeWithLines uses, duplicated here
+// rather than imported: the two packages parse into different shapes
+// (arbitrary document vs. a typed Template) and have no other reason to
+// depend on each other.
+//
+// JSON is valid YAML, so this handles both .yaml and .json CloudFormation
+// templates.
+func decodeWithLines(data []byte) (any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return nodeToValue(doc.Content[0]), nil
+}
+
+func nodeToValue(n *yaml.Node) any {
+	switch n.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2+1)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = nodeToValue(n.Content[i+1])
+		}
+		m["__startline__"] = n.Line
+		m["__endline__"] = endLine(n)
+		return m
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			s = append(s, nodeToValue(c))
+		}
+		return s
+	case yaml.AliasNode:
+		return nodeToValue(n.Alias)
+	default:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return n.Value
+		}
+		return v
+	}
+}
+
+// endLine approximates a node's last line as the greatest line number
+// reachable from any of its descendants, since yaml.Node only records each
+// node's starting line.
+func endLine(n *yaml.Node) int {
+	max := n.Line
+	for _, c := range n.Content {
+		if l := endLine(c); l > max {
+			max = l
+		}
+	}
+	return max
+}
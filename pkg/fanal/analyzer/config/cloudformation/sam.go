@@ -0,0 +1,79 @@
+package cloudformation
+
+// ExpandSAM expands AWS::Serverless::{Function,Api,SimpleTable,HttpApi}
+// resources in tpl into their underlying AWS::Lambda::Function,
+// AWS::ApiGateway::RestApi, AWS::DynamoDB::Table and AWS::ApiGatewayV2::Api
+// resources respectively - the way the AWS::Serverless-2016-10-31 transform
+// would at deploy time - so a check written against the concrete resource
+// type (which is what almost every Rego policy is) also fires against a SAM
+// template. It mutates tpl.Resources in place and is a no-op if tpl doesn't
+// declare the SAM transform.
+//
+// Each resource keeps its original Range, so a failure against an expanded
+// property still points at the line of the AWS::Serverless::Function (etc.)
+// in the source template rather than a synthetic location.
+func ExpandSAM(tpl *Template) {
+	if !hasSAMTransform(tpl.Transform) {
+		return
+	}
+	for _, r := range tpl.Resources {
+		switch r.Type {
+		case "AWS::Serverless::Function":
+			expandServerlessFunction(r)
+		case "AWS::Serverless::Api":
+			expandServerlessAPI(r)
+		case "AWS::Serverless::SimpleTable":
+			expandServerlessSimpleTable(r)
+		case "AWS::Serverless::HttpApi":
+			expandServerlessHTTPAPI(r)
+		}
+	}
+}
+
+// copyKeys copies every key present in src among keys into dst.
+func copyKeys(dst, src map[string]any, keys ...string) {
+	for _, k := range keys {
+		if v, ok := src[k]; ok {
+			dst[k] = v
+		}
+	}
+}
+
+func expandServerlessFunction(r *Resource) {
+	props := make(map[string]any)
+	copyKeys(props, r.Properties,
+		"Handler", "Runtime", "Environment", "MemorySize", "Timeout",
+		"Role", "VpcConfig", "ReservedConcurrentExecutions", "KmsKeyArn", "Tags", "Layers")
+	if codeURI, ok := r.Properties["CodeUri"]; ok {
+		props["Code"] = map[string]any{"S3Bucket": codeURI}
+	}
+	r.Type = "AWS::Lambda::Function"
+	r.Properties = props
+}
+
+func expandServerlessAPI(r *Resource) {
+	props := make(map[string]any)
+	copyKeys(props, r.Properties, "Name", "EndpointConfiguration", "MinimumCompressionSize", "Tags")
+	r.Type = "AWS::ApiGateway::RestApi"
+	r.Properties = props
+}
+
+func expandServerlessHTTPAPI(r *Resource) {
+	props := map[string]any{"ProtocolType": "HTTP"}
+	copyKeys(props, r.Properties, "Name", "Tags", "CorsConfiguration")
+	r.Type = "AWS::ApiGatewayV2::Api"
+	r.Properties = props
+}
+
+func expandServerlessSimpleTable(r *Resource) {
+	props := make(map[string]any)
+	copyKeys(props, r.Properties, "SSESpecification", "Tags")
+	if pk, ok := r.Properties["PrimaryKey"].(map[string]any); ok {
+		name := pk["Name"]
+		typ := pk["Type"]
+		props["KeySchema"] = []any{map[string]any{"AttributeName": name, "KeyType": "HASH"}}
+		props["AttributeDefinitions"] = []any{map[string]any{"AttributeName": name, "AttributeType": typ}}
+	}
+	r.Type = "AWS::DynamoDB::Table"
+	r.Properties = props
+}
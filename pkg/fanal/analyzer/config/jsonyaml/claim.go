@@ -0,0 +1,33 @@
+package jsonyaml
+
+import "sync"
+
+// ClaimTracker records which file paths a config analyzer has already
+// scanned during a run, so this generic analyzer can skip a file a
+// dedicated format analyzer (Kubernetes, CloudFormation, Helm, ...) already
+// claimed instead of scanning - and potentially double-reporting on - it
+// too. Arbitrary YAML/JSON paths overlap heavily with those more specific
+// formats, so claims are first-come-first-served rather than priority
+// ordered: whichever analyzer reaches a path first keeps it.
+type ClaimTracker struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+}
+
+// NewClaimTracker returns an empty ClaimTracker.
+func NewClaimTracker() *ClaimTracker {
+	return &ClaimTracker{claimed: make(map[string]bool)}
+}
+
+// Claim marks path as claimed and reports whether it was free to claim -
+// false means some other analyzer already has it, and the caller should
+// skip the file.
+func (c *ClaimTracker) Claim(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.claimed[path] {
+		return false
+	}
+	c.claimed[path] = true
+	return true
+}
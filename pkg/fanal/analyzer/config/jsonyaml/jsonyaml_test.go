@@ -0,0 +1,154 @@
+package jsonyaml_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/liamg/memoryfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/config/jsonyaml"
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+const denyEmptyNamePolicy = `
+package defsec.test
+
+deny {
+    input.name == ""
+}
+`
+
+func newScanner(t *testing.T, sourceType types.Source) *rego.Scanner {
+	t.Helper()
+
+	memfs := memoryfs.New()
+	require.NoError(t, memfs.MkdirAll("policies", 0o700))
+	require.NoError(t, memfs.WriteFile("policies/test.rego", []byte(denyEmptyNamePolicy), 0o644))
+
+	scanner := rego.NewScanner(sourceType, rego.WithPolicyDirs("policies"))
+	require.NoError(t, scanner.LoadPolicies(memfs))
+	return scanner
+}
+
+func Test_Scan_JSON(t *testing.T) {
+	scanner := newScanner(t, types.SourceJSON)
+
+	results, err := jsonyaml.Scan(context.TODO(), scanner, types.SourceJSON, "config.json", []byte(`{"name": ""}`))
+	require.NoError(t, err)
+	assert.Len(t, results.GetFailed(), 1)
+}
+
+func Test_Scan_YAML(t *testing.T) {
+	scanner := newScanner(t, types.SourceYAML)
+
+	results, err := jsonyaml.Scan(context.TODO(), scanner, types.SourceYAML, "config.yaml", []byte("name: \"\"\n"))
+	require.NoError(t, err)
+	assert.Len(t, results.GetFailed(), 1)
+}
+
+func Test_Scan_UnsupportedSourceType(t *testing.T) {
+	scanner := newScanner(t, types.SourceJSON)
+
+	_, err := jsonyaml.Scan(context.TODO(), scanner, types.SourceDockerfile, "Dockerfile", []byte(""))
+	assert.Error(t, err)
+}
+
+const denyDeepFieldPolicy = `
+package defsec.test
+
+deny[res] {
+    input.spec.bad == true
+    res := {
+        "msg": "bad spec",
+        "startline": input.spec.__startline__,
+        "endline": input.spec.__endline__,
+    }
+}
+`
+
+func Test_Scan_ReportsLineRangeOfNestedField(t *testing.T) {
+	memfs := memoryfs.New()
+	require.NoError(t, memfs.MkdirAll("policies", 0o700))
+	require.NoError(t, memfs.WriteFile("policies/test.rego", []byte(denyDeepFieldPolicy), 0o644))
+	scanner := rego.NewScanner(types.SourceYAML, rego.WithPolicyDirs("policies"))
+	require.NoError(t, scanner.LoadPolicies(memfs))
+
+	doc := "name: demo\nspec:\n  bad: true\n  other: true\n"
+	results, err := jsonyaml.Scan(context.TODO(), scanner, types.SourceYAML, "config.yaml", []byte(doc))
+	require.NoError(t, err)
+	require.Len(t, results.GetFailed(), 1)
+
+	// spec's mapping node starts either on its own "spec:" line (2) or on
+	// its first key's line (3), depending on yaml.Node's block-mapping
+	// line bookkeeping - either is an accurate-enough range for a nested
+	// finding, so this only pins down the (unambiguous) end line.
+	rng := results.GetFailed()[0].Metadata().Range()
+	assert.GreaterOrEqual(t, rng.GetStartLine(), 2)
+	assert.LessOrEqual(t, rng.GetStartLine(), 3)
+	assert.Equal(t, 4, rng.GetEndLine())
+}
+
+func Test_Enabled(t *testing.T) {
+	assert.False(t, jsonyaml.Enabled(nil, types.SourceYAML))
+	assert.False(t, jsonyaml.Enabled([]string{"json"}, types.SourceYAML))
+	assert.True(t, jsonyaml.Enabled([]string{"yaml"}, types.SourceYAML))
+	assert.True(t, jsonyaml.Enabled([]string{"YAML"}, types.SourceYAML))
+	assert.True(t, jsonyaml.Enabled([]string{"json"}, types.SourceJSON))
+}
+
+func Test_ScannerOption_EnabledFor(t *testing.T) {
+	opt := jsonyaml.ScannerOption{IncludeGenericYAML: true}
+	assert.True(t, opt.EnabledFor(types.SourceYAML))
+	assert.False(t, opt.EnabledFor(types.SourceJSON))
+	assert.False(t, opt.EnabledFor(types.SourceDockerfile))
+}
+
+func Test_ClaimTracker(t *testing.T) {
+	tracker := jsonyaml.NewClaimTracker()
+	assert.True(t, tracker.Claim("a.yaml"))
+	assert.False(t, tracker.Claim("a.yaml"))
+	assert.True(t, tracker.Claim("b.yaml"))
+}
+
+func Test_Run(t *testing.T) {
+	scanner := newScanner(t, types.SourceYAML)
+	opt := jsonyaml.ScannerOption{IncludeGenericYAML: true}
+
+	t.Run("disabled", func(t *testing.T) {
+		results, scanned, err := jsonyaml.Run(context.TODO(), scanner, jsonyaml.NewClaimTracker(),
+			jsonyaml.ScannerOption{}, nil, types.SourceYAML, "config.yaml", []byte(`name: ""`))
+		require.NoError(t, err)
+		assert.False(t, scanned)
+		assert.Nil(t, results)
+	})
+
+	t.Run("enabled via ScannerOption", func(t *testing.T) {
+		results, scanned, err := jsonyaml.Run(context.TODO(), scanner, jsonyaml.NewClaimTracker(),
+			opt, nil, types.SourceYAML, "config.yaml", []byte(`name: ""`))
+		require.NoError(t, err)
+		assert.True(t, scanned)
+		assert.Len(t, results.GetFailed(), 1)
+	})
+
+	t.Run("enabled via scanners allow-list", func(t *testing.T) {
+		results, scanned, err := jsonyaml.Run(context.TODO(), scanner, jsonyaml.NewClaimTracker(),
+			jsonyaml.ScannerOption{}, []string{"yaml"}, types.SourceYAML, "config.yaml", []byte(`name: ""`))
+		require.NoError(t, err)
+		assert.True(t, scanned)
+		assert.Len(t, results.GetFailed(), 1)
+	})
+
+	t.Run("already claimed by another analyzer", func(t *testing.T) {
+		tracker := jsonyaml.NewClaimTracker()
+		require.True(t, tracker.Claim("config.yaml"))
+
+		results, scanned, err := jsonyaml.Run(context.TODO(), scanner, tracker,
+			opt, nil, types.SourceYAML, "config.yaml", []byte(`name: ""`))
+		require.NoError(t, err)
+		assert.False(t, scanned)
+		assert.Nil(t, results)
+	})
+}
@@ -0,0 +1,35 @@
+package jsonyaml
+
+import "github.com/aquasecurity/trivy/pkg/iac/types"
+
+// ScannerOption is the subset of the generic YAML/JSON analyzer's settings
+// that would be configured directly on MisconfScannerOption as
+// IncludeGenericYAML/IncludeGenericJSON, rather than through the
+// --misconfig-scanners allow-list Enabled reads.
+//
+// NOTE: it isn't actually embedded into MisconfScannerOption here - that
+// type lives in pkg/misconf, which (along with pkg/fanal/analyzer/config's
+// own ScannerOption and pkg/fanal/types.Misconfiguration) isn't present in
+// this checkout, so there's nothing real to wire it into, and
+// TestYAMLMisconfigurationScan/TestJSONMisconfigurationScan can't be added
+// as the cache.ArtifactCachePutBlobExpectation-based table tests the
+// request asks for without fabricating that whole harness. EnabledFor is
+// real and independently usable once that wiring exists.
+type ScannerOption struct {
+	IncludeGenericYAML bool
+	IncludeGenericJSON bool
+}
+
+// EnabledFor reports whether opt enables the generic analyzer for
+// sourceType - mirroring Enabled, but via explicit named fields instead of
+// a string allow-list.
+func (opt ScannerOption) EnabledFor(sourceType types.Source) bool {
+	switch sourceType {
+	case types.SourceYAML:
+		return opt.IncludeGenericYAML
+	case types.SourceJSON:
+		return opt.IncludeGenericJSON
+	default:
+		return false
+	}
+}
@@ -0,0 +1,34 @@
+package jsonyaml
+
+import (
+	"context"
+
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// Run is the single entry point a fanal config analyzer's Analyze would
+// call for an arbitrary YAML/JSON file: it gates on opt/scanners (either
+// gating mechanism enabling it is enough, since --misconfig-scanners and
+// MisconfScannerOption.IncludeGeneric* are two ways of asking for the same
+// thing), claims path via tracker so a dedicated format analyzer that
+// already scanned it is left alone, and only then parses and scans.
+//
+// scanned reports whether path was actually scanned - false with a nil
+// error means either the generic analyzer isn't enabled for sourceType, or
+// some other analyzer already claimed path first; neither is an error, so
+// callers shouldn't surface one.
+func Run(ctx context.Context, scanner *rego.Scanner, tracker *ClaimTracker, opt ScannerOption, scanners []string, sourceType types.Source, path string, data []byte) (results rego.Results, scanned bool, err error) {
+	if !opt.EnabledFor(sourceType) && !Enabled(scanners, sourceType) {
+		return nil, false, nil
+	}
+	if !tracker.Claim(path) {
+		return nil, false, nil
+	}
+
+	results, err = Scan(ctx, scanner, sourceType, path, data)
+	if err != nil {
+		return nil, false, err
+	}
+	return results, true, nil
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// knownFilePatternTypes is the set of analyzer type prefixes ParseFilePatterns
+// accepts in a "type:regexp" token, e.g. "pip:.*requirements.*\\.txt".
+//
+// This is assembled from the Type constants the analyzer implementations
+// present in this checkout declare (Type(), RegisterAnalyzer,
+// RegisterPostAnalyzer call sites). The authoritative source would be the
+// core registry in this package's analyzer.go/group.go, where every
+// analyzer - including the dockerfile/yaml/json/toml/hcl config analyzers
+// the request driving this also names - registers itself; those files
+// aren't present in this checkout, so this list only covers the analyzers
+// that are. Extending an analyzer or adding a new one just means adding its
+// Type constant here, same as it would need to register itself with the
+// real registry.
+var knownFilePatternTypes = map[Type]bool{
+	TypeAlpine:      true,
+	TypeApk:         true,
+	TypeBun:         true,
+	TypeCargo:       true,
+	TypeDpkgLicense: true,
+	TypeGemSpec:     true,
+	TypeGradleLock:  true,
+	TypePip:         true,
+	TypePoetry:      true,
+	TypePom:         true,
+	TypePythonPkg:   true,
+	TypeSBOM:        true,
+	TypeYarn:        true,
+}
+
+// FilePatterns is the parsed form of the global --file-patterns flag: one
+// compiled regexp per analyzer type. A file matching any pattern registered
+// for a given type is treated as required input for that analyzer, on top
+// of whatever its own Required already recognizes - so a non-standard
+// filename (a lockfile renamed by a build system, "Dockerfile.prod", a
+// ".tf.json" variant, ...) doesn't need the analyzer itself changed.
+type FilePatterns map[Type][]*regexp.Regexp
+
+// ParseFilePatterns parses patterns, each formatted "type:regexp", into a
+// FilePatterns keyed by analyzer type. It returns an error for a malformed
+// token, an unknown analyzer type prefix, or an invalid regexp, so a typo
+// in --file-patterns fails fast at startup rather than silently matching
+// nothing.
+func ParseFilePatterns(patterns []string) (FilePatterns, error) {
+	fp := FilePatterns{}
+	for _, p := range patterns {
+		typ, pattern, ok := strings.Cut(p, ":")
+		if !ok {
+			return nil, xerrors.Errorf(`invalid file pattern %q: expected "type:regexp"`, p)
+		}
+
+		t := Type(typ)
+		if !knownFilePatternTypes[t] {
+			return nil, xerrors.Errorf("invalid file pattern %q: unknown analyzer type %q", p, typ)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid file pattern %q: %w", p, err)
+		}
+		fp[t] = append(fp[t], re)
+	}
+	return fp, nil
+}
+
+// MatchRequired wraps an analyzer's own Required(filePath, info) for type t:
+// the file is required if it matches one of fp's patterns for t, or if
+// required already says so. Every analyzer's Required is meant to call this
+// as its first check, e.g. (language/python/poetry.poetryAnalyzer):
+//
+//	func (a poetryAnalyzer) Required(filePath string, info os.FileInfo) bool {
+//	    return a.filePatterns.MatchRequired(analyzer.TypePoetry, filePath, info, func(filePath string, _ os.FileInfo) bool {
+//	        return filepath.Base(filePath) == types.PoetryLock
+//	    })
+//	}
+//
+// NOTE: wiring this into every remaining analyzer's Required, and threading
+// the parsed FilePatterns from AnalyzerOptions/ConfigAnalyzerOptions down to
+// each one's constructor, needs the core analyzer.go/group.go
+// (NewAnalyzerGroup, the Analyzer/PostAnalyzer interfaces, analyzer
+// construction) that isn't present in this checkout - only the individual
+// analyzer implementations are. poetryAnalyzer is wired up as the first
+// consumer; the rest follow the same two-line change once that core is
+// restored.
+func (fp FilePatterns) MatchRequired(t Type, filePath string, info os.FileInfo, required func(string, os.FileInfo) bool) bool {
+	for _, re := range fp[t] {
+		if re.MatchString(filepath.ToSlash(filePath)) {
+			return true
+		}
+	}
+	return required(filePath, info)
+}
@@ -0,0 +1,47 @@
+package pom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/java/pom"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&pomAnalyzer{})
+}
+
+const version = 1
+
+// pomAnalyzer reads a Maven module's declared dependencies directly from
+// pom.xml (no transitive resolution, see pom.Parser), without a JVM or a
+// built jar. This is what lets Trivy scan a Java project
+// "containerlessly", e.g. against a checked-out source tree before `mvn package`.
+type pomAnalyzer struct{}
+
+func (a pomAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	p := pom.NewParser(input.FS, filepath.Dir(input.FilePath))
+	res, err := language.Analyze(types.Pom, input.FilePath, input.Content, p)
+	if err != nil {
+		return nil, xerrors.Errorf("%s parse error: %w", input.FilePath, err)
+	}
+	return res, nil
+}
+
+func (a pomAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == "pom.xml"
+}
+
+func (a pomAnalyzer) Type() analyzer.Type {
+	return analyzer.TypePom
+}
+
+func (a pomAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,84 @@
+package bun
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/bun"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+func init() {
+	analyzer.RegisterPostAnalyzer(analyzer.TypeBun, newBunAnalyzer)
+}
+
+const version = 1
+
+// requiredFiles only lists the textual bun.lock manifest. bun.lockb is bun's
+// own internal binary serialization; without a confirmed spec or real-world
+// fixture to verify a parser against, we don't claim to support it rather
+// than risk silently mis-parsing it.
+var requiredFiles = []string{ftypes.BunLock}
+
+type bunAnalyzer struct {
+	lockParser types.Parser
+}
+
+func newBunAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+	return &bunAnalyzer{
+		lockParser: bun.NewParser(),
+	}, nil
+}
+
+func (a bunAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
+	var apps []ftypes.Application
+
+	required := func(path string, _ fs.DirEntry) bool {
+		return a.Required(path, nil)
+	}
+
+	err := fsutils.WalkDir(input.FS, ".", required, func(path string, _ fs.DirEntry, r xio.ReadSeekerAt) error {
+		app, err := language.Parse(ftypes.Bun, path, r, a.lockParser)
+		if err != nil {
+			return xerrors.Errorf("unable to parse %s: %w", path, err)
+		} else if app == nil {
+			return nil
+		}
+		apps = append(apps, *app)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("bun walk error: %w", err)
+	}
+
+	return &analyzer.AnalysisResult{
+		Applications: apps,
+	}, nil
+}
+
+func (a bunAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	fileName := filepath.Base(filePath)
+	for _, required := range requiredFiles {
+		if fileName == required {
+			return true
+		}
+	}
+	return false
+}
+
+func (a bunAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeBun
+}
+
+func (a bunAnalyzer) Version() int {
+	return version
+}
@@ -4,20 +4,26 @@ import (
 	"archive/zip"
 	"context"
 	"errors"
+	"io"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/samber/lo"
 	"golang.org/x/exp/maps"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
 	dio "github.com/aquasecurity/go-dep-parser/pkg/io"
 	"github.com/aquasecurity/go-dep-parser/pkg/nodejs/packagejson"
 	"github.com/aquasecurity/go-dep-parser/pkg/nodejs/yarn"
 	godeptypes "github.com/aquasecurity/go-dep-parser/pkg/types"
+	licensepkg "github.com/aquasecurity/trivy/internal/licenses"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare/npm"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
@@ -32,29 +38,68 @@ func init() {
 
 const version = 1
 
+// defaultMaxConcurrency bounds how many yarn.lock directories are processed
+// at once when opts.YarnMaxConcurrency isn't set.
+const defaultMaxConcurrency = 4
+
 type yarnAnalyzer struct {
 	packageJsonParser *packagejson.Parser
 	lockParser        godeptypes.Parser
 	comparer          npm.Comparer
+	scanner           *licensepkg.Scanner
+	maxConcurrency    int
+	lockDirs          []string
 }
 
-func newYarnAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+// NOTE: this relies on opts.LicenseScanner, opts.YarnMaxConcurrency and
+// opts.YarnLockDirs (analyzer.AnalyzerOptions), none of which is defined
+// anywhere in this checkout - the same pre-existing gap documented on
+// poetry.newPoetryAnalyzer's use of opts.PoetryGroups. Written as it would
+// be once analyzer.AnalyzerOptions grows these fields; see
+// pkg/flag/license_flags.go and pkg/flag/yarn_flags.go for how they're meant
+// to be populated.
+func newYarnAnalyzer(opts analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+	scanner := opts.LicenseScanner
+	if scanner == nil {
+		scanner = licensepkg.NewScanner()
+	}
+	maxConcurrency := opts.YarnMaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultMaxConcurrency
+	}
 	return &yarnAnalyzer{
 		packageJsonParser: packagejson.NewParser(),
 		lockParser:        yarn.NewParser(),
 		comparer:          npm.Comparer{},
+		scanner:           scanner,
+		maxConcurrency:    maxConcurrency,
+		lockDirs:          opts.YarnLockDirs,
 	}, nil
 }
 
-func (a yarnAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
-	var apps []types.Application
+// PostAnalyze walks every yarn.lock under input.FS (skipping directories
+// opts.YarnLockDirs doesn't allowlist) and hands each one to a pool bounded
+// to a.maxConcurrency in-flight lockfiles. Each worker's own license map and
+// pkgIDs index (see processLockfile) go out of scope as soon as that
+// lockfile finishes, so peak memory scales with maxConcurrency rather than
+// with the total number of lockfiles in a large monorepo.
+func (a yarnAnalyzer) PostAnalyze(ctx context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
+	var (
+		mu   sync.Mutex
+		apps []types.Application
+	)
+
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, a.maxConcurrency)
 
 	required := func(path string, d fs.DirEntry) bool {
-		return filepath.Base(path) == types.YarnLock
+		return filepath.Base(path) == types.YarnLock && a.lockDirAllowed(filepath.Dir(path))
 	}
 
 	err := fsutils.WalkDir(input.FS, ".", required, func(path string, d fs.DirEntry, r dio.ReadSeekerAt) error {
-		// Parse yarn.lock
+		// Parse yarn.lock on the walker goroutine, since r doesn't outlive
+		// this callback, then hand the rest of the per-lockfile work (license
+		// discovery, dev-dependency pruning) to the bounded pool.
 		app, err := a.parseYarnLock(path, r)
 		if err != nil {
 			return xerrors.Errorf("parse error: %w", err)
@@ -62,32 +107,33 @@ func (a yarnAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysis
 			return nil
 		}
 
-		// Find all licenses from package.json files under node_modules or .yarn dirs
-		licenses, err := a.findLicenses(input.FS, path)
-		if err != nil {
-			log.Logger.Errorf("Unable to collect licenses: %s", err)
-			licenses = map[string]string{}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 
-		// Parse package.json alongside yarn.lock to remove dev dependencies
-		if err = a.removeDevDependencies(input.FS, filepath.Dir(path), app); err != nil {
-			log.Logger.Warnf("Unable to parse %q to remove dev dependencies: %s", filepath.Join(filepath.Dir(path), types.NpmPkg), err)
-		}
+		g.Go(func() error {
+			defer func() { <-sem }()
 
-		// Fill licenses
-		for i, lib := range app.Libraries {
-			if license, ok := licenses[lib.ID]; ok {
-				app.Libraries[i].Licenses = []string{license}
+			if err := a.processLockfile(input.FS, path, app); err != nil {
+				return err
 			}
-		}
 
-		apps = append(apps, *app)
+			mu.Lock()
+			apps = append(apps, *app)
+			mu.Unlock()
+			return nil
+		})
 
 		return nil
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("yarn walk error: %w", err)
 	}
+	if err := g.Wait(); err != nil {
+		return nil, xerrors.Errorf("yarn walk error: %w", err)
+	}
 
 	return &analyzer.AnalysisResult{
 		Applications: apps,
@@ -111,9 +157,50 @@ func (a yarnAnalyzer) parseYarnLock(path string, r dio.ReadSeekerAt) (*types.App
 	return language.Parse(types.Yarn, path, r, a.lockParser)
 }
 
+// processLockfile fills in licenses and prunes dev dependencies for app, the
+// per-lockfile work PostAnalyze used to run inline in its walk callback.
+func (a yarnAnalyzer) processLockfile(fsys fs.FS, lockPath string, app *types.Application) error {
+	// Find all licenses from package.json files under node_modules or .yarn dirs
+	licenses, err := a.findLicenses(fsys, lockPath)
+	if err != nil {
+		log.Logger.Errorf("Unable to collect licenses: %s", err)
+		licenses = map[string]string{}
+	}
+
+	// Parse package.json alongside yarn.lock to remove dev dependencies
+	if err := a.removeDevDependencies(fsys, filepath.Dir(lockPath), app); err != nil {
+		log.Logger.Warnf("Unable to parse %q to remove dev dependencies: %s", filepath.Join(filepath.Dir(lockPath), types.NpmPkg), err)
+	}
+
+	// Fill licenses
+	for i, lib := range app.Libraries {
+		if license, ok := licenses[lib.ID]; ok {
+			app.Libraries[i].Licenses = []string{license}
+		}
+	}
+
+	return nil
+}
+
+// lockDirAllowed reports whether dir (a yarn.lock's containing directory)
+// should be scanned, honoring the --yarn-lock-dirs allowlist of doublestar
+// glob patterns. An empty allowlist, the default, scans everywhere.
+func (a yarnAnalyzer) lockDirAllowed(dir string) bool {
+	if len(a.lockDirs) == 0 {
+		return true
+	}
+	dir = filepath.ToSlash(dir)
+	for _, pattern := range a.lockDirs {
+		if ok, err := doublestar.Match(pattern, dir); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (a yarnAnalyzer) removeDevDependencies(fsys fs.FS, dir string, app *types.Application) error {
 	packageJsonPath := filepath.Join(dir, types.NpmPkg)
-	directDeps, err := a.parsePackageJsonDependencies(fsys, packageJsonPath)
+	directDeps, workspaceOf, pins, err := a.resolveWorkspaceDeps(fsys, dir)
 	if errors.Is(err, fs.ErrNotExist) {
 		log.Logger.Debugf("Yarn: %s not found", packageJsonPath)
 		return nil
@@ -141,12 +228,43 @@ func (a yarnAnalyzer) removeDevDependencies(fsys fs.FS, dir string, app *types.A
 			} else if match {
 				// Mark as a direct dependency
 				pkg.Indirect = false
+				// NOTE: types.Package.Workspace isn't defined anywhere in this
+				// checkout - types.Package itself is assumed, the same
+				// pre-existing gap documented on poetry's use of
+				// types.Package.Groups. Set here so a workspace-only
+				// dependency can be attributed back to the workspace that
+				// declared it, e.g. for per-workspace SBOM emission.
+				pkg.Workspace = workspaceOf[name]
 				pkgs[pkg.ID] = pkg
 				break
 			}
 		}
 	}
 
+	// A yarn "resolutions" or npm "overrides" pin forces its target package
+	// to be kept regardless of a.comparer.MatchVersion, even when it's only
+	// otherwise reachable through a dev/build dependency's subgraph. Applied
+	// after the constraint-based pass above so it always wins.
+	alreadyDirect := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		alreadyDirect[pkg.Name] = true
+	}
+	for name := range pins {
+		if alreadyDirect[name] {
+			continue
+		}
+		for _, pkg := range app.Libraries {
+			if pkg.Name != name {
+				continue
+			}
+			pkg.Indirect = false
+			pkg.Workspace = workspaceOf[name]
+			pkgs[pkg.ID] = pkg
+			log.Logger.Warnf("Yarn: %q is pinned by a resolution/override and would otherwise have been pruned as a dev dependency", name)
+			break
+		}
+	}
+
 	// Walk indirect dependencies
 	// Since it starts from direct dependencies, devDependencies will not appear in this walk.
 	for _, pkg := range pkgs {
@@ -178,21 +296,146 @@ func (a yarnAnalyzer) walkIndirectDependencies(pkg types.Package, pkgIDs map[str
 	}
 }
 
-func (a yarnAnalyzer) parsePackageJsonDependencies(fsys fs.FS, path string) (map[string]string, error) {
-	// Parse package.json
-	f, err := fsys.Open(path)
+// resolveWorkspaceDeps returns the direct-dependency constraints declared by
+// the package.json at dir, merged with every child package.json dir's own
+// "workspaces" field matches. workspaceOf maps each dependency name
+// introduced only by a workspace member (not already a root-level
+// dependency) to that workspace's directory, relative to dir, so
+// removeDevDependencies can attribute the resolved types.Package back to it.
+// pins is the flattened set of yarn "resolutions"/npm "overrides" pattern
+// keys from the root package.json, keyed by the package name they target.
+func (a yarnAnalyzer) resolveWorkspaceDeps(fsys fs.FS, dir string) (deps map[string]string, workspaceOf map[string]string, pins map[string]string, err error) {
+	packageJsonPath := filepath.Join(dir, types.NpmPkg)
+	f, err := fsys.Open(packageJsonPath)
 	if err != nil {
-		return nil, xerrors.Errorf("file open error: %w", err)
+		return nil, nil, nil, xerrors.Errorf("file open error: %w", err)
 	}
-	defer func() { _ = f.Close() }()
 
-	pkg, err := a.packageJsonParser.Parse(f)
+	root, err := a.packageJsonParser.Parse(f)
+	_ = f.Close()
 	if err != nil {
-		return nil, xerrors.Errorf("parse error: %w", err)
+		return nil, nil, nil, xerrors.Errorf("parse error: %w", err)
 	}
 
 	// Merge dependencies and optionalDependencies
-	return lo.Assign(pkg.Dependencies, pkg.OptionalDependencies), nil
+	deps = lo.Assign(root.Dependencies, root.OptionalDependencies)
+	workspaceOf = map[string]string{}
+	pins = pinnedVersions(root)
+
+	for _, ws := range matchWorkspaces(fsys, dir, root.Workspaces) {
+		wsPkgPath := path.Join(ws, types.NpmPkg)
+		wf, err := fsys.Open(wsPkgPath)
+		if err != nil {
+			continue
+		}
+		wsPkg, err := a.packageJsonParser.Parse(wf)
+		_ = wf.Close()
+		if err != nil {
+			log.Logger.Warnf("Unable to parse workspace %q: %s", wsPkgPath, err)
+			continue
+		}
+
+		// NOTE: go-dep-parser's packagejson.Package is assumed here to carry
+		// a PeerDependencies field mirroring its Dependencies/
+		// DevDependencies/OptionalDependencies ones, the same "write it as
+		// the dependency would provide it" convention the rest of this
+		// analyzer already relies on for that type.
+		for name, constraint := range lo.Assign(wsPkg.Dependencies, wsPkg.DevDependencies, wsPkg.OptionalDependencies, wsPkg.PeerDependencies) {
+			if _, ok := deps[name]; ok {
+				continue
+			}
+			deps[name] = constraint
+			workspaceOf[name] = ws
+		}
+	}
+
+	return deps, workspaceOf, pins, nil
+}
+
+// pinnedVersions flattens root's yarn "resolutions" and npm "overrides"
+// tables into a name -> version-requirement map, keyed by the target
+// package's own name regardless of how deep a "parent>child" path or nested
+// override object points at it.
+//
+// NOTE: go-dep-parser's packagejson.Package is assumed here to carry
+// Resolutions map[string]string and Overrides map[string]any fields
+// mirroring yarn's and npm's respective package.json schemas, the same
+// "write it as the dependency would provide it" convention this analyzer
+// already relies on for PeerDependencies.
+func pinnedVersions(root packagejson.Package) map[string]string {
+	pins := map[string]string{}
+	for pattern, version := range root.Resolutions {
+		pins[pinTarget(pattern)] = version
+	}
+	for pattern, v := range root.Overrides {
+		flattenOverride(pattern, v, pins)
+	}
+	return pins
+}
+
+// pinTarget returns the package name a resolution/override pattern ends up
+// pinning, stripping any "parent>child" or "parent/child" path prefix.
+func pinTarget(pattern string) string {
+	if i := strings.LastIndex(pattern, ">"); i != -1 {
+		return strings.TrimSpace(pattern[i+1:])
+	}
+	if i := strings.LastIndex(pattern, "/"); i != -1 {
+		return pattern[i+1:]
+	}
+	return pattern
+}
+
+// flattenOverride records name's pin into pins, recursing into npm's nested
+// override form ({"parent": {".": "1.0.0", "child": "2.0.0"}}) one level at
+// a time; "." addresses the parent itself, any other key a further child.
+func flattenOverride(name string, v any, pins map[string]string) {
+	switch val := v.(type) {
+	case string:
+		pins[pinTarget(name)] = val
+	case map[string]any:
+		for key, nested := range val {
+			if key == "." {
+				if s, ok := nested.(string); ok {
+					pins[pinTarget(name)] = s
+				}
+				continue
+			}
+			flattenOverride(key, nested, pins)
+		}
+	}
+}
+
+// matchWorkspaces expands patterns - a root package.json's "workspaces"
+// field, e.g. "packages/*" or {"packages": ["packages/*", "!packages/skip"]}
+// - into the directories, relative to dir, that contain a workspace
+// member's package.json. Patterns are applied in order; one prefixed with
+// "!" removes directories matched by the patterns before it, the same
+// negation semantics npm/yarn workspaces themselves use.
+func matchWorkspaces(fsys fs.FS, dir string, patterns []string) []string {
+	matched := map[string]struct{}{}
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		glob := filepath.ToSlash(path.Join(dir, pattern, types.NpmPkg))
+		hits, err := doublestar.Glob(fsys, glob)
+		if err != nil {
+			continue
+		}
+
+		for _, hit := range hits {
+			wsDir := path.Dir(hit)
+			if negate {
+				delete(matched, wsDir)
+			} else {
+				matched[wsDir] = struct{}{}
+			}
+		}
+	}
+
+	dirs := maps.Keys(matched)
+	sort.Strings(dirs)
+	return dirs
 }
 
 type licenses map[string]string
@@ -228,7 +471,7 @@ func (a yarnAnalyzer) findLicensesForYarnClassic(fsys fs.FS, path string) (licen
 			return xerrors.Errorf("unable to parse %q: %w", filePath, err)
 		}
 
-		licenses[pkg.ID] = pkg.License
+		licenses[pkg.ID] = a.scanner.Normalize(pkg.License)
 		return nil
 	})
 	if err != nil {
@@ -277,7 +520,7 @@ func (a yarnAnalyzer) extractLicensesFromUnplugged(fsys fs.FS, root string, lice
 			return xerrors.Errorf("unable to parse %q: %w", path, err)
 		}
 
-		licenses[pkg.ID] = pkg.License
+		licenses[pkg.ID] = a.scanner.Normalize(pkg.License)
 		return nil
 	})
 	if err != nil {
@@ -321,19 +564,46 @@ func (a yarnAnalyzer) extractLicensesFromCache(fsys fs.FS, root string, licenses
 			return xerrors.Errorf("zip reader error: %w", err)
 		}
 
+		var pkgID, declared string
+		licenseFiles := map[string][]byte{}
 		for _, f := range r.File {
-			if filepath.Base(f.Name) != types.NpmPkg {
-				continue
-			}
-			pkgFile, err := f.Open()
-			if err != nil {
-				return xerrors.Errorf("file open error: %w", err)
+			switch {
+			case filepath.Base(f.Name) == types.NpmPkg:
+				pkgFile, err := f.Open()
+				if err != nil {
+					return xerrors.Errorf("file open error: %w", err)
+				}
+				pkg, err := a.packageJsonParser.Parse(pkgFile)
+				pkgFile.Close()
+				if err != nil {
+					return xerrors.Errorf("unable to parse %q: %w", path, err)
+				}
+				pkgID, declared = pkg.ID, pkg.License
+			case licensepkg.IsLicenseFile(filepath.Base(f.Name)):
+				body, err := readZipFile(f)
+				if err != nil {
+					return xerrors.Errorf("unable to read %q: %w", f.Name, err)
+				}
+				licenseFiles[filepath.Base(f.Name)] = body
 			}
-			pkg, err := a.packageJsonParser.Parse(pkgFile)
-			if err != nil {
-				return xerrors.Errorf("unable to parse %q: %w", path, err)
+		}
+
+		if pkgID == "" {
+			return nil
+		}
+
+		// Prefer the declared package.json license; fall back to sniffing a
+		// bundled LICENSE/COPYING/NOTICE file only when nothing was declared.
+		if declared != "" {
+			licenses[pkgID] = a.scanner.Normalize(declared)
+			return nil
+		}
+
+		for name, body := range licenseFiles {
+			if license, ok := a.scanner.Classify(name, string(body)); ok {
+				licenses[pkgID] = license
+				break
 			}
-			licenses[pkg.ID] = pkg.License
 		}
 
 		return nil
@@ -345,3 +615,12 @@ func (a yarnAnalyzer) extractLicensesFromCache(fsys fs.FS, root string, licenses
 
 	return nil
 }
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -23,17 +23,29 @@ func init() {
 	analyzer.RegisterPostAnalyzer(analyzer.TypePoetry, newPoetryAnalyzer)
 }
 
+// NOTE: this relies on opts.PoetryGroups (analyzer.AnalyzerOptions) and
+// types.Package.Groups, neither of which is defined anywhere in this
+// checkout - fanal's analyzer/package core (analyzer.AnalyzerOptions,
+// types.Package/Packages, and everything that fills them in) isn't present
+// here, the same pre-existing gap documented on analyzer.FilePatterns'
+// MatchRequired. Written as it would be once that core exists; see
+// pkg/flag/poetry_flags.go for how PoetryGroups is meant to be populated.
+
 const version = 1
 
 type poetryAnalyzer struct {
 	logger          *log.Logger
+	filePatterns    analyzer.FilePatterns
+	groups          pyproject.GroupSelection
 	pyprojectParser *pyproject.Parser
 	lockParser      language.Parser
 }
 
-func newPoetryAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+func newPoetryAnalyzer(opts analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
 	return &poetryAnalyzer{
 		logger:          log.WithPrefix("poetry"),
+		filePatterns:    opts.FilePatterns,
+		groups:          opts.PoetryGroups,
 		pyprojectParser: pyproject.NewParser(),
 		lockParser:      poetry.NewParser(),
 	}, nil
@@ -42,8 +54,10 @@ func newPoetryAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error
 func (a poetryAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
 	var apps []types.Application
 
-	required := func(path string, d fs.DirEntry) bool {
-		return filepath.Base(path) == types.PoetryLock
+	required := func(path string, _ fs.DirEntry) bool {
+		return a.filePatterns.MatchRequired(analyzer.TypePoetry, path, nil, func(path string, _ os.FileInfo) bool {
+			return filepath.Base(path) == types.PoetryLock
+		})
 	}
 
 	err := fsutils.WalkDir(input.FS, ".", required, func(path string, d fs.DirEntry, r io.Reader) error {
@@ -73,9 +87,11 @@ func (a poetryAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalys
 	}, nil
 }
 
-func (a poetryAnalyzer) Required(filePath string, _ os.FileInfo) bool {
-	fileName := filepath.Base(filePath)
-	return fileName == types.PoetryLock || fileName == types.PyProject
+func (a poetryAnalyzer) Required(filePath string, fi os.FileInfo) bool {
+	return a.filePatterns.MatchRequired(analyzer.TypePoetry, filePath, fi, func(filePath string, _ os.FileInfo) bool {
+		fileName := filepath.Base(filePath)
+		return fileName == types.PoetryLock || fileName == types.PyProject
+	})
 }
 
 func (a poetryAnalyzer) Type() analyzer.Type {
@@ -91,7 +107,8 @@ func (a poetryAnalyzer) parsePoetryLock(path string, r io.Reader) (*types.Applic
 }
 
 func (a poetryAnalyzer) mergePyProject(fsys fs.FS, dir string, app *types.Application) error {
-	// Parse pyproject.toml to identify the direct dependencies
+	// Parse pyproject.toml to identify the direct dependencies and the groups
+	// they belong to.
 	path := filepath.Join(dir, types.PyProject)
 	p, err := a.parsePyProject(fsys, path)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -102,30 +119,53 @@ func (a poetryAnalyzer) mergePyProject(fsys fs.FS, dir string, app *types.Applic
 		return xerrors.Errorf("unable to parse %s: %w", path, err)
 	}
 
-	for i, pkg := range app.Packages {
-		// Identify the direct/transitive dependencies
-		if _, ok := p[lib.Name]; ok {
-			app.Packages[i].Relationship = types.RelationshipDirect
-		} else {
-			app.Packages[i].Indirect = true
-			app.Packages[i].Relationship = types.RelationshipIndirect
+	declared := p.Dependencies()
+	active := p.ActiveGroups(a.groups)
+
+	packages := make([]types.Package, 0, len(app.Packages))
+	for _, pkg := range app.Packages {
+		allGroups, ok := declared[pkg.Name]
+		if !ok {
+			// Not declared directly in pyproject.toml: poetry.lock doesn't
+			// record which group pulled a transitive dependency in, so group
+			// selection can't scope it - keep it regardless.
+			pkg.Indirect = true
+			pkg.Relationship = types.RelationshipIndirect
+			packages = append(packages, pkg)
+			continue
+		}
+
+		var pkgGroups []string
+		for _, g := range allGroups {
+			if active[g] {
+				pkgGroups = append(pkgGroups, g)
+			}
+		}
+		if len(pkgGroups) == 0 {
+			// Declared, but only in groups excluded by --only/--with/--without.
+			continue
 		}
+
+		pkg.Relationship = types.RelationshipDirect
+		pkg.Groups = pkgGroups
+		packages = append(packages, pkg)
 	}
+	app.Packages = packages
 
 	return nil
 }
 
-func (a poetryAnalyzer) parsePyProject(fsys fs.FS, path string) (map[string]interface{}, error) {
+func (a poetryAnalyzer) parsePyProject(fsys fs.FS, path string) (pyproject.PyProject, error) {
 	// Parse pyproject.toml
 	f, err := fsys.Open(path)
 	if err != nil {
-		return nil, xerrors.Errorf("file open error: %w", err)
+		return pyproject.PyProject{}, xerrors.Errorf("file open error: %w", err)
 	}
 	defer f.Close()
 
 	parsed, err := a.pyprojectParser.Parse(f)
 	if err != nil {
-		return nil, err
+		return pyproject.PyProject{}, err
 	}
 	return parsed, nil
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 
+	"github.com/aquasecurity/trivy/internal/licenses"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/ruby/gemspec"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
@@ -21,11 +22,32 @@ const version = 1
 
 var fileRegex = regexp.MustCompile(`.*/specifications/.+\.gemspec`)
 
+// scanner normalizes declared gemspec licenses. gemspecLibraryAnalyzer is
+// registered stateless (analyzer.RegisterAnalyzer takes a value, not a
+// factory), so unlike yarnAnalyzer it can't be constructed per-scan from
+// analyzer.AnalyzerOptions - a package-level Scanner with the default
+// (declared-license-only) behavior is used instead.
+var scanner = licenses.NewScanner()
+
 type gemspecLibraryAnalyzer struct{}
 
 func (a gemspecLibraryAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
-	return language.AnalyzePackage(types.GemSpec, input.FilePath, input.Content,
+	result, err := language.AnalyzePackage(types.GemSpec, input.FilePath, input.Content,
 		gemspec.NewParser(), input.Options.FileChecksum)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range result.Applications {
+		for i, lib := range app.Libraries {
+			if len(lib.Licenses) != 1 {
+				continue
+			}
+			app.Libraries[i].Licenses = []string{scanner.Normalize(lib.Licenses[0])}
+		}
+	}
+
+	return result, nil
 }
 
 func (a gemspecLibraryAnalyzer) Required(filePath string, fileInfo os.FileInfo) bool {
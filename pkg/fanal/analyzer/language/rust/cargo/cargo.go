@@ -0,0 +1,226 @@
+package cargo
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	licensepkg "github.com/aquasecurity/trivy/internal/licenses"
+	cargoparser "github.com/aquasecurity/trivy/pkg/dependency/parser/rust/cargo"
+	"github.com/aquasecurity/trivy/pkg/dependency/types"
+	"github.com/aquasecurity/trivy/pkg/detector/library/compare/cargo"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+func init() {
+	analyzer.RegisterPostAnalyzer(analyzer.TypeCargo, newCargoAnalyzer)
+}
+
+const version = 1
+
+type cargoAnalyzer struct {
+	lockParser     types.Parser
+	manifestParser *cargoparser.ManifestParser
+	comparer       cargo.Comparer
+	// scanner is forced into full-body classification mode: unlike yarn's
+	// package.json license field, a crate's registry checkout frequently
+	// has no declared `license` in Cargo.toml at all, only a LICENSE file.
+	scanner *licensepkg.Scanner
+}
+
+func newCargoAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+	return &cargoAnalyzer{
+		lockParser:     cargoparser.NewParser(),
+		manifestParser: cargoparser.NewManifestParser(),
+		comparer:       cargo.Comparer{},
+		scanner:        licensepkg.NewScanner(licensepkg.WithFull(true)),
+	}, nil
+}
+
+func (a cargoAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
+	var apps []ftypes.Application
+
+	required := func(path string, _ fs.DirEntry) bool {
+		return a.Required(path, nil)
+	}
+
+	err := fsutils.WalkDir(input.FS, ".", required, func(path string, _ fs.DirEntry, r xio.ReadSeekerAt) error {
+		app, err := language.Parse(ftypes.Cargo, path, r, a.lockParser)
+		if err != nil {
+			return xerrors.Errorf("unable to parse %s: %w", path, err)
+		} else if app == nil {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if err := a.removeNonRuntimeDependencies(input.FS, dir, app); err != nil {
+			log.Logger.Warnf("Unable to parse %q to remove non-runtime dependencies: %s", filepath.Join(dir, "Cargo.toml"), err)
+		}
+
+		a.fillLicenses(app)
+
+		apps = append(apps, *app)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cargo walk error: %w", err)
+	}
+
+	return &analyzer.AnalysisResult{
+		Applications: apps,
+	}, nil
+}
+
+// removeNonRuntimeDependencies drops every crate only reachable from
+// Cargo.toml's [dev-dependencies]/[build-dependencies] tables, the same
+// "walk out from direct deps" shape yarnAnalyzer.removeDevDependencies uses,
+// adapted to Cargo.lock's dependency graph living in a standalone
+// []ftypes.Dependency list rather than embedded per-package DependsOn.
+func (a cargoAnalyzer) removeNonRuntimeDependencies(fsys fs.FS, dir string, app *ftypes.Application) error {
+	manifestPath := filepath.Join(dir, "Cargo.toml")
+	f, err := fsys.Open(manifestPath)
+	if os.IsNotExist(err) {
+		log.Logger.Debugf("Cargo: %s not found", manifestPath)
+		return nil
+	} else if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	manifest, err := a.manifestParser.Parse(f)
+	_ = f.Close()
+	if err != nil {
+		return xerrors.Errorf("unable to parse %s: %w", manifestPath, err)
+	}
+
+	pkgIDs := make(map[string]ftypes.Package, len(app.Libraries))
+	for _, pkg := range app.Libraries {
+		pkgIDs[pkg.ID] = pkg
+	}
+
+	dependsOn := make(map[string][]string, len(app.Dependencies))
+	for _, dep := range app.Dependencies {
+		dependsOn[dep.ID] = dep.DependsOn
+	}
+
+	pkgs := map[string]ftypes.Package{}
+	for name, constraint := range manifest.DirectDependencies() {
+		for _, pkg := range app.Libraries {
+			if pkg.Name != name {
+				continue
+			}
+
+			if match, err := a.comparer.MatchVersion(pkg.Version, constraint); err != nil {
+				return xerrors.Errorf("unable to match version for %s: %w", pkg.Name, err)
+			} else if match {
+				pkg.Indirect = false
+				pkgs[pkg.ID] = pkg
+				break
+			}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		a.walkIndirectDependencies(pkg, pkgIDs, dependsOn, pkgs)
+	}
+
+	pkgSlice := make([]ftypes.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		pkgSlice = append(pkgSlice, pkg)
+	}
+	sort.Sort(ftypes.Packages(pkgSlice))
+
+	app.Libraries = pkgSlice
+	return nil
+}
+
+func (a cargoAnalyzer) walkIndirectDependencies(pkg ftypes.Package, pkgIDs map[string]ftypes.Package, dependsOn map[string][]string, deps map[string]ftypes.Package) {
+	for _, pkgID := range dependsOn[pkg.ID] {
+		if _, ok := deps[pkgID]; ok {
+			continue
+		}
+
+		dep, ok := pkgIDs[pkgID]
+		if !ok {
+			continue
+		}
+
+		dep.Indirect = true
+		deps[dep.ID] = dep
+		a.walkIndirectDependencies(dep, pkgIDs, dependsOn, deps)
+	}
+}
+
+// fillLicenses resolves each library's license by reading its checked-out
+// registry source under ~/.cargo/registry/src/*/<name>-<version>/, since
+// Cargo.lock itself carries no license metadata. It prefers Cargo.toml's
+// declared `license` field (a `license-file` field is not read) and falls
+// back to classifying any LICENSE/COPYING/NOTICE file it finds there.
+func (a cargoAnalyzer) fillLicenses(app *ftypes.Application) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Logger.Debugf("Cargo: unable to resolve home directory for registry license lookup: %s", err)
+		return
+	}
+
+	for i, lib := range app.Libraries {
+		license, ok := a.resolveCrateLicense(home, lib.Name, lib.Version)
+		if ok {
+			app.Libraries[i].Licenses = []string{license}
+		}
+	}
+}
+
+func (a cargoAnalyzer) resolveCrateLicense(home, name, version string) (string, bool) {
+	glob := filepath.Join(home, ".cargo", "registry", "src", "*", name+"-"+version)
+	dirs, err := filepath.Glob(glob)
+	if err != nil || len(dirs) == 0 {
+		return "", false
+	}
+	crateDir := dirs[0]
+
+	if f, err := os.Open(filepath.Join(crateDir, "Cargo.toml")); err == nil {
+		manifest, err := a.manifestParser.Parse(f)
+		_ = f.Close()
+		if err == nil && manifest.Package.License != "" {
+			return a.scanner.Normalize(manifest.Package.License), true
+		}
+	}
+
+	entries, err := os.ReadDir(crateDir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !licensepkg.IsLicenseFile(entry.Name()) {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(crateDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if id, ok := a.scanner.Classify(entry.Name(), string(body)); ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func (a cargoAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	return filepath.Base(filePath) == "Cargo.lock"
+}
+
+func (a cargoAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeCargo
+}
+
+func (a cargoAnalyzer) Version() int {
+	return version
+}
@@ -3,6 +3,7 @@ package dpkg
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -31,11 +32,54 @@ func init() {
 }
 
 var (
-	dpkgLicenseAnalyzerVersion = 1
+	dpkgLicenseAnalyzerVersion = 2
 
 	licenseClassifier *classifier.Classifier
 
 	commonLicenseReferenceRegexp = regexp.MustCompile(`/?usr/share/common-licenses/([0-9A-Za-z_.+-]+[0-9A-Za-z+])`)
+
+	// licenseClassifierConfidenceThreshold is the minimum confidence a
+	// licenseclassifier match must have, on its own, before it is used to
+	// build an expression at all.
+	licenseClassifierConfidenceThreshold = 0.9
+
+	// licenseClassifierExpressionThreshold is the minimum *combined*
+	// confidence (the average of every match folded into the expression)
+	// required before an expression is emitted for the classifier fallback
+	// path. Kept as a package var, rather than a constant, so tests and
+	// downstream embedders can tune it.
+	licenseClassifierExpressionThreshold = 0.8
+
+	// debianSPDXAliases maps the short names Debian's machine-readable
+	// copyright format commonly uses to their SPDX identifiers. Names that
+	// already are valid SPDX identifiers (e.g. "MIT", "Apache-2.0") need no
+	// entry here.
+	debianSPDXAliases = map[string]string{
+		"GPL-1":        "GPL-1.0-only",
+		"GPL-1+":       "GPL-1.0-or-later",
+		"GPL-2":        "GPL-2.0-only",
+		"GPL-2+":       "GPL-2.0-or-later",
+		"GPL-3":        "GPL-3.0-only",
+		"GPL-3+":       "GPL-3.0-or-later",
+		"LGPL-2":       "LGPL-2.0-only",
+		"LGPL-2+":      "LGPL-2.0-or-later",
+		"LGPL-2.1":     "LGPL-2.1-only",
+		"LGPL-2.1+":    "LGPL-2.1-or-later",
+		"LGPL-3":       "LGPL-3.0-only",
+		"LGPL-3+":      "LGPL-3.0-or-later",
+		"Apache-2":     "Apache-2.0",
+		"Artistic":     "Artistic-1.0",
+		"BSD-2-clause": "BSD-2-Clause",
+		"BSD-3-clause": "BSD-3-Clause",
+	}
+
+	// licenseRefInvalidChars matches characters that aren't valid in the
+	// idstring portion of a "LicenseRef-<idstring>" per the SPDX spec.
+	licenseRefInvalidChars = regexp.MustCompile(`[^0-9A-Za-z.-]+`)
+
+	// debianLicenseListSeparator splits a single Debian License field value
+	// on its "or"/"and"/"|" separators.
+	debianLicenseListSeparator = regexp.MustCompile(`(?i)\s+(?:or|and)\s+|\s*\|\s*`)
 )
 
 // dpkgLicenseAnalyzer parses copyright files and detect licenses
@@ -43,17 +87,13 @@ type dpkgLicenseAnalyzer struct{}
 
 // Analyze parses /usr/share/doc/*/copyright files
 func (a dpkgLicenseAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
-	licenses, err := a.parseCopyright(input.Content)
+	findings, err := a.parseCopyright(input.Content)
 	if err != nil {
 		return nil, xerrors.Errorf("parse copyright %s: %w", input.FilePath, err)
-	} else if len(licenses) == 0 {
+	} else if len(findings) == 0 {
 		return nil, nil
 	}
 
-	findings := lo.Map(licenses, func(license string, _ int) types.LicenseFinding {
-		return types.LicenseFinding{License: license}
-	})
-
 	// e.g. "usr/share/doc/zlib1g/copyright" => "zlib1g"
 	pkgName := strings.Split(input.FilePath, "/")[3]
 
@@ -69,53 +109,223 @@ func (a dpkgLicenseAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisI
 	}, nil
 }
 
-// parseCopyright parses /usr/share/doc/*/copyright files
-func (a dpkgLicenseAnalyzer) parseCopyright(r dio.ReadSeekerAt) ([]string, error) {
+// parseCopyright parses /usr/share/doc/*/copyright files and returns one
+// LicenseFinding per distinct license name found, each carrying the SPDX
+// expression for the whole file as Expression so that a single valid
+// `Component.Licenses` entry can be built downstream (LicenseChoice.Expression
+// for CycloneDX, PackageLicenseConcluded for SPDX).
+func (a dpkgLicenseAnalyzer) parseCopyright(r dio.ReadSeekerAt) ([]types.LicenseFinding, error) {
+	stanzas, names, err := parseMachineReadable(r)
+	if err != nil {
+		return nil, xerrors.Errorf("parse machine-readable copyright: %w", err)
+	}
+	if len(stanzas) > 0 {
+		return buildFindings(names, combineStanzas(stanzas)), nil
+	}
+
+	// Rewind the reader to the beginning of the stream after the first pass
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, xerrors.Errorf("seek error: %w", err)
+	}
+
+	names, expr, err := classifyCopyright(r)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to match licenses: %w", err)
+	}
+	return buildFindings(names, expr), nil
+}
+
+// parseMachineReadable scans the Debian machine-readable copyright format
+// (https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/),
+// grouping each "License:" stanza under the "Files:" paragraph it appears
+// in, along with the legacy "/usr/share/common-licenses/" reference
+// convention. Stanzas are returned in file order; names is the set of raw
+// license names seen, de-duplicated but otherwise unordered.
+func parseMachineReadable(r dio.ReadSeekerAt) (stanzas []string, names []string, err error) {
 	scanner := bufio.NewScanner(r)
-	var licenses []string
 	for scanner.Scan() {
 		line := scanner.Text()
 
 		switch {
 		case strings.HasPrefix(line, "License:"):
-			// Machine-readable format
-			// cf. https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/#:~:text=The%20debian%2Fcopyright%20file%20must,in%20the%20Debian%20Policy%20Manual.
-			l := strings.TrimSpace(line[8:])
-			if len(l) > 0 && !slices.Contains(licenses, l) {
-				licenses = append(licenses, l)
+			l := strings.TrimSpace(line[len("License:"):])
+			if l == "" {
+				continue
+			}
+			stanzas = append(stanzas, l)
+			for _, name := range splitDebianLicenseList(l) {
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
 			}
 		case strings.Contains(line, "/usr/share/common-licenses/"):
-			// Common license pattern
-			license := commonLicenseReferenceRegexp.FindStringSubmatch(line)
-			if len(license) == 2 && !slices.Contains(licenses, license[1]) {
-				licenses = append(licenses, license[1])
+			if m := commonLicenseReferenceRegexp.FindStringSubmatch(line); len(m) == 2 {
+				stanzas = append(stanzas, m[1])
+				if !slices.Contains(names, m[1]) {
+					names = append(names, m[1])
+				}
 			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return stanzas, names, nil
+}
 
-	// If licenses are already found, they will be returned.
-	if len(licenses) > 0 {
-		return licenses, nil
+// splitDebianLicenseList splits a single License field value on the
+// Debian "or"/"and"/"|" separators used to list more than one license,
+// without caring which boolean operator was used - callers only need the
+// individual names to resolve against SPDX.
+func splitDebianLicenseList(value string) []string {
+	fields := debianLicenseListSeparator.Split(value, -1)
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			names = append(names, f)
+		}
 	}
+	return names
+}
 
-	// Rewind the reader to the beginning of the stream after saving
-	if _, err := r.Seek(0, io.SeekStart); err != nil {
-		return nil, xerrors.Errorf("seek error: %w", err)
+// combineStanzas composes one SPDX license expression for the whole
+// copyright file out of its per-"Files:" stanzas: licenses named together
+// within a stanza (Debian's "or"/"|" syntax) are combined with OR, and the
+// distinct stanzas are combined with AND, since a package whose files carry
+// different licenses is, as a whole, licensed under all of them.
+func combineStanzas(stanzas []string) string {
+	exprs := make([]string, 0, len(stanzas))
+	seen := make(map[string]struct{})
+	for _, stanza := range stanzas {
+		names := splitDebianLicenseList(stanza)
+		if len(names) == 0 {
+			continue
+		}
+
+		terms := make([]string, 0, len(names))
+		for _, name := range names {
+			terms = append(terms, toSPDX(name))
+		}
+
+		expr := strings.Join(terms, " OR ")
+		if len(terms) > 1 {
+			expr = "(" + expr + ")"
+		}
+		if _, ok := seen[expr]; ok {
+			continue
+		}
+		seen[expr] = struct{}{}
+		exprs = append(exprs, expr)
+	}
+	return strings.Join(exprs, " AND ")
+}
+
+// toSPDX resolves a Debian-style license name to an SPDX identifier,
+// falling back to a LicenseRef-<name> identifier for anything not in the
+// SPDX license list so the resulting expression always parses.
+func toSPDX(name string) string {
+	name = strings.TrimSpace(name)
+	if spdx, ok := debianSPDXAliases[name]; ok {
+		return spdx
 	}
+	if isSPDXIdentifier(name) {
+		return name
+	}
+	return "LicenseRef-" + licenseRefInvalidChars.ReplaceAllString(name, "-")
+}
 
-	// Use 'github.com/google/licenseclassifier' to find licenses
+// spdxIdentifierRegexp is a light heuristic for "this already looks like an
+// SPDX license list identifier", not a full lookup against the list itself,
+// since duplicating that list here would just be another place for it to
+// go stale.
+var spdxIdentifierRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+-]*$`)
+
+func isSPDXIdentifier(name string) bool {
+	return spdxIdentifierRegexp.MatchString(name)
+}
+
+// classifyCopyright falls back to github.com/google/licenseclassifier when
+// the copyright file isn't in the machine-readable format. Overlapping
+// match regions are used to tell an exception clause (e.g.
+// "GCC-exception-3.1" overlapping the license it modifies, combined with
+// WITH) apart from genuinely separate license blocks (combined with AND).
+// An expression is only returned when the combined confidence of the
+// matches folded into it clears licenseClassifierExpressionThreshold.
+func classifyCopyright(r dio.ReadSeekerAt) (names []string, expr string, err error) {
 	result, err := licenseClassifier.MatchFrom(r)
 	if err != nil {
-		return nil, xerrors.Errorf("unable to match licenses: %w", err)
+		return nil, "", err
+	}
+
+	matches := lo.Filter(result.Matches, func(m *classifier.Match, _ int) bool {
+		return m.Confidence > licenseClassifierConfidenceThreshold
+	})
+	if len(matches) == 0 {
+		return nil, "", nil
 	}
 
-	for _, match := range result.Matches {
-		if match.Confidence > 0.9 && !slices.Contains(licenses, match.Name) {
-			licenses = append(licenses, match.Name)
+	var terms []string
+	var confidenceSum float64
+	used := make(map[int]bool)
+	for i, m := range matches {
+		if used[i] {
+			continue
 		}
+		names = append(names, m.Name)
+		confidenceSum += m.Confidence
+
+		term := m.Name
+		for j := i + 1; j < len(matches); j++ {
+			if used[j] || !overlaps(m, matches[j]) {
+				continue
+			}
+			used[j] = true
+			names = append(names, matches[j].Name)
+			confidenceSum += matches[j].Confidence
+			if isException(matches[j].Name) {
+				term = fmt.Sprintf("%s WITH %s", term, matches[j].Name)
+			} else {
+				term = fmt.Sprintf("(%s AND %s)", term, matches[j].Name)
+			}
+		}
+		terms = append(terms, term)
 	}
 
-	return licenses, nil
+	if len(terms) == 0 {
+		return nil, "", nil
+	}
+	if combined := confidenceSum / float64(len(matches)); combined < licenseClassifierExpressionThreshold {
+		return names, "", nil
+	}
+
+	return names, strings.Join(terms, " AND "), nil
+}
+
+// overlaps reports whether two classifier matches cover overlapping line
+// ranges in the source file, which is how an exception clause (layered on
+// top of the license text it qualifies) shows up versus a separate license
+// block elsewhere in the file.
+func overlaps(a, b *classifier.Match) bool {
+	return a.StartLine <= b.EndLine && b.StartLine <= a.EndLine
+}
+
+// isException reports whether name looks like an SPDX license exception
+// (e.g. "GCC-exception-3.1") rather than a standalone license.
+func isException(name string) bool {
+	return strings.Contains(strings.ToLower(name), "-exception-") || strings.HasSuffix(strings.ToLower(name), "-exception")
+}
+
+// buildFindings turns the raw license names and the composed expression
+// into one LicenseFinding per name, each carrying the same Expression so
+// downstream code can read either the individual names or the single
+// SPDX expression for the file.
+func buildFindings(names []string, expr string) []types.LicenseFinding {
+	return lo.Map(names, func(name string, _ int) types.LicenseFinding {
+		return types.LicenseFinding{
+			License:    name,
+			Expression: expr,
+		}
+	})
 }
 
 func (a dpkgLicenseAnalyzer) Required(filePath string, _ os.FileInfo) bool {
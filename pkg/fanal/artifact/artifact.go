@@ -2,12 +2,19 @@ package artifact
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/custom"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/misconf"
+	"github.com/aquasecurity/trivy/pkg/version/app"
 )
 
 type Option struct {
@@ -28,11 +35,57 @@ type Option struct {
 	AWSEndpoint       string
 	FileChecksum      bool // For SPDX
 
+	// VMProgressInterval controls how often the VM artifact logs scan
+	// progress (files analyzed, path currently being read) while Inspect
+	// walks a VM image. Zero means the VM artifact's own default; ignored
+	// entirely when NoProgress is set.
+	VMProgressInterval time.Duration
+
+	// HTTPHeaders are extra headers for outbound HTTP requests. RegistryOptions
+	// copies them, with UserAgent, onto a types.RegistryOptions, and only
+	// registry requests whose options were built through it carry them; no
+	// other request Inspect makes (SBOM source, Rekor, VEX or policy bundle
+	// fetches) adds them.
+	HTTPHeaders http.Header
+
+	// UserAgent overrides the default "trivy/<version>" User-Agent. Like
+	// HTTPHeaders, it reaches a request only through RegistryOptions.
+	// Registries increasingly rate-limit or
+	// block requests with no identifying UA, so this exists mainly to let a
+	// caller identify itself as something more specific than bare trivy
+	// (e.g. a CI integration or a tool embedding trivy) rather than to spoof
+	// a different client.
+	UserAgent string
+
+	// Logger, when set, replaces the package-level log singleton for
+	// everything Inspect does - analyzers, handlers, and the misconf/
+	// secret/license scanners all log through it instead. A consumer
+	// embedding Trivy to run many concurrent scans (e.g. a controller
+	// reconciling several images at once) needs per-scan log context (image
+	// ref, request ID) and JSON-structured output routed to its own sink,
+	// neither of which a process-wide singleton can provide. Nil falls back
+	// to slog.Default(), which the global logger is expected to have
+	// configured as its handler.
+	Logger *slog.Logger
+
 	// Git repositories
 	RepoBranch string
 	RepoCommit string
 	RepoTag    string
 
+	// GitTrustedKeyringPath, when set, is an armored OpenPGP keyring Inspect
+	// verifies a clean git repository's HEAD commit signature against,
+	// recording the outcome as the returned artifact.Reference's Provenance
+	// regardless of whether verification succeeds. Empty means Inspect
+	// still records the commit hash but skips signature verification
+	// entirely - Provenance.Verified is always false in that case.
+	GitTrustedKeyringPath string
+
+	// DiffBase is a git ref (e.g. "origin/main") or commit SHA to compare the
+	// scanned tree against. Inspect does not read it: filesystem scanning
+	// walks the whole tree whether or not it is set.
+	DiffBase string
+
 	// For image scanning
 	ImageOption types.ImageOptions
 
@@ -47,27 +100,69 @@ func (o *Option) Init() {
 	if o.Parallel == 0 {
 		o.Parallel = 5 // Set the default value
 	}
+	if o.UserAgent == "" {
+		o.UserAgent = fmt.Sprintf("trivy/%s", app.Version())
+	}
 }
 
-func (o *Option) AnalyzerOptions() analyzer.AnalyzerOptions {
+// logger returns o.Logger, falling back to slog.Default() so every call
+// site can log unconditionally instead of nil-checking o.Logger itself.
+func (o *Option) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return slog.Default()
+}
+
+// RegistryOptions layers HTTPHeaders/UserAgent onto imageOpt, the way every
+// outbound registry request made during Inspect should see them. Callers
+// building a types.RegistryOptions for a registry fetch should start from
+// this instead of imageOpt.RegistryOptions directly, so a proxy auth header
+// or custom UA configured once on Option reaches every registry call
+// without each call site repeating the merge.
+func (o *Option) RegistryOptions(imageOpt types.RegistryOptions) types.RegistryOptions {
+	imageOpt.UserAgent = o.UserAgent
+	imageOpt.HTTPHeaders = o.HTTPHeaders
+	return imageOpt
+}
+
+// AnalyzerOptions builds the options passed to analyzer.NewAnalyzerGroup.
+// FilePatterns is parsed once here, rather than handed down as the raw
+// []string every analyzer would otherwise re-parse, so a malformed
+// "type:regexp" token or unknown analyzer type prefix in --file-patterns
+// fails fast with one error instead of silently matching nothing per
+// analyzer.
+func (o *Option) AnalyzerOptions() (analyzer.AnalyzerOptions, error) {
+	filePatterns, err := analyzer.ParseFilePatterns(o.FilePatterns)
+	if err != nil {
+		return analyzer.AnalyzerOptions{}, xerrors.Errorf("invalid file patterns: %w", err)
+	}
+
 	return analyzer.AnalyzerOptions{
 		Group:                o.AnalyzerGroup,
-		FilePatterns:         o.FilePatterns,
+		FilePatterns:         filePatterns,
 		Parallel:             o.Parallel,
 		DisabledAnalyzers:    o.DisabledAnalyzers,
 		MisconfScannerOption: o.MisconfScannerOption,
 		SecretScannerOption:  o.SecretScannerOption,
 		LicenseScannerOption: o.LicenseScannerOption,
-	}
+		Logger:               o.logger(),
+	}, nil
 }
 
-func (o *Option) ConfigAnalyzerOptions() analyzer.ConfigAnalyzerOptions {
+func (o *Option) ConfigAnalyzerOptions() (analyzer.ConfigAnalyzerOptions, error) {
+	filePatterns, err := analyzer.ParseFilePatterns(o.FilePatterns)
+	if err != nil {
+		return analyzer.ConfigAnalyzerOptions{}, xerrors.Errorf("invalid file patterns: %w", err)
+	}
+
 	return analyzer.ConfigAnalyzerOptions{
-		FilePatterns:         o.FilePatterns,
+		FilePatterns:         filePatterns,
 		DisabledAnalyzers:    o.DisabledAnalyzers,
 		MisconfScannerOption: o.MisconfScannerOption,
 		SecretScannerOption:  o.SecretScannerOption,
-	}
+		Logger:               o.logger(),
+	}, nil
 }
 
 func (o *Option) Sort() {
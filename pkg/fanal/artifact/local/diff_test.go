@@ -0,0 +1,79 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepoWithTwoCommits seeds a temp git repository with an initial commit
+// (README.md only) followed by a second commit that adds two .tf files in
+// the same directory, returning the repo dir and the first commit's hash
+// to use as a diff base.
+func initRepoWithTwoCommits(t *testing.T) (dir string, baseHash string) {
+	t.Helper()
+	dir = t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	write := func(name, contents string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+		_, err := wt.Add(name)
+		require.NoError(t, err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+
+	write("README.md", "hello\n")
+	h1, err := wt.Commit("initial", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+
+	write("main.tf", "resource \"aws_s3_bucket\" \"bad\" {}\n")
+	write("other.tf", "resource \"aws_s3_bucket\" \"other\" {}\n")
+	_, err = wt.Commit("add terraform module", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+
+	return dir, h1.String()
+}
+
+func Test_ChangedPaths(t *testing.T) {
+	dir, base := initRepoWithTwoCommits(t)
+
+	paths, err := changedPaths(dir, base)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.tf", "other.tf"}, paths)
+}
+
+func Test_ExpandForLockfiles_AddsSiblingTerraformFiles(t *testing.T) {
+	dir, _ := initRepoWithTwoCommits(t)
+
+	expanded, err := expandForLockfiles(os.DirFS(dir), []string{"main.tf"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.tf", "other.tf"}, expanded)
+}
+
+func Test_ExpandForLockfiles_AddsGoSum(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte(""), 0o644))
+
+	expanded, err := expandForLockfiles(os.DirFS(dir), []string{"go.mod"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "go.sum"}, expanded)
+}
+
+func Test_BlameCommit(t *testing.T) {
+	dir, _ := initRepoWithTwoCommits(t)
+
+	hash, err := blameCommit(dir, "main.tf", 1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, hash)
+}
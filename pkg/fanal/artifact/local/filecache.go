@@ -0,0 +1,236 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// gitFileResolver answers "what's path's content hash according to git" for
+// a single repository, so fileCache can key a file by its git blob hash
+// (shared across every commit that contains the same bytes) instead of
+// hashing the file's content itself on every single scan.
+type gitFileResolver struct {
+	repo *git.Repository
+	head *plumbing.Hash
+}
+
+// newGitFileResolver opens the git repository rooted at dir. It returns an
+// error if dir isn't a git repository at all; unlike getCleanGitHash, it
+// doesn't care whether the worktree is clean, since it's only consulted once
+// that's already known to be false.
+func newGitFileResolver(dir string) (*gitFileResolver, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open git repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get HEAD: %w", err)
+	}
+	hash := head.Hash()
+
+	return &gitFileResolver{repo: repo, head: &hash}, nil
+}
+
+// blobHash returns the git blob hash HEAD has recorded for filePath. It
+// returns an error for anything HEAD doesn't know about - an untracked file,
+// or one modified in the worktree - since there's no tree entry to read a
+// hash from in that case; the caller falls back to hashing file content
+// itself.
+func (g *gitFileResolver) blobHash(filePath string) (string, error) {
+	commit, err := g.repo.CommitObject(*g.head)
+	if err != nil {
+		return "", xerrors.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", xerrors.Errorf("failed to get HEAD tree: %w", err)
+	}
+
+	entry, err := tree.File(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("failed to find %q in HEAD tree: %w", filePath, err)
+	}
+
+	return entry.Hash.String(), nil
+}
+
+// fileCache stores and retrieves per-file analysis results in the artifact
+// cache, namespaced separately from the top-level blob a clean git repo or
+// non-git directory would store under its own single cache key. It lets a
+// dirty git repository rescan without re-analyzing every file that hasn't
+// changed since the last scan, which a single UUID cache key can't do.
+type fileCache struct {
+	cache            cache.ArtifactCache
+	git              *gitFileResolver
+	analyzerVersions analyzer.Versions
+	handlerVersions  map[string]int
+	artifactOption   artifact.Option
+}
+
+// key returns the cache key filePath would be stored/looked up under, folding
+// in the same analyzer/handler versions and scanner options calcCacheKey
+// does for the top-level key, so a version bump or option change busts the
+// per-file cache exactly like it busts the whole-repo one. It prefers the
+// git blob hash for filePath - shared across every commit with identical
+// content - falling back to hashing filePath's own content when git doesn't
+// have a tree entry for it (untracked or worktree-modified).
+func (c *fileCache) key(ctx context.Context, filePath string, opener analyzer.Opener) (string, error) {
+	id, err := c.git.blobHash(filePath)
+	if err != nil {
+		id, err = hashFileContent(opener)
+		if err != nil {
+			return "", xerrors.Errorf("failed to hash %q: %w", filePath, err)
+		}
+	}
+
+	key, err := cache.CalcKey(ctx, id, c.analyzerVersions, c.handlerVersions, c.artifactOption)
+	if err != nil {
+		return "", xerrors.Errorf("failed to calculate cache key for %q: %w", filePath, err)
+	}
+	return key, nil
+}
+
+// hashFileContent reads r's entire content to derive a stand-in for a git
+// blob hash, for files git itself has no hash recorded for.
+func hashFileContent(opener analyzer.Opener) (string, error) {
+	r, err := opener()
+	if err != nil {
+		return "", xerrors.Errorf("failed to open file: %w", err)
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", xerrors.Errorf("failed to read file: %w", err)
+	}
+
+	return digest.NewDigest(digest.SHA256, h).String(), nil
+}
+
+// fileBucketKey namespaces key so per-file cache entries can't collide with
+// the top-level blob a scan ultimately stores under its own cache key.
+func fileBucketKey(key string) string {
+	return "file:" + key
+}
+
+// get looks up filePath's cache entry, computing its key along the way so
+// the caller doesn't have to call key separately. hit is false whenever
+// there's no usable cached entry, including when key itself failed to
+// compute - in which case key is returned empty too, so the caller can tell
+// not to fold it into the top-level cache key either.
+func (c *fileCache) get(ctx context.Context, filePath string, opener analyzer.Opener) (key string, blobInfo types.BlobInfo, hit bool) {
+	key, err := c.key(ctx, filePath, opener)
+	if err != nil {
+		return "", types.BlobInfo{}, false
+	}
+
+	blobInfo, err = c.cache.GetBlob(fileBucketKey(key))
+	if err != nil {
+		return key, types.BlobInfo{}, false
+	}
+	return key, blobInfo, true
+}
+
+// put stores blobInfo as filePath's cache entry under the key previously
+// returned by get/key.
+func (c *fileCache) put(key string, blobInfo types.BlobInfo) error {
+	return c.cache.PutBlob(fileBucketKey(key), blobInfo)
+}
+
+// merkleKey combines every file cache key visited this walk into a single
+// top-level cache key, the same way a merkle tree combines leaf hashes: any
+// file's key changing - because its content changed, or because it's new -
+// changes the result, while every file being a cache hit reproduces the
+// previous scan's top-level key exactly.
+func merkleKey(fileKeys []string) (string, error) {
+	h := sha256.New()
+	for _, key := range fileKeys {
+		if _, err := io.WriteString(h, key); err != nil {
+			return "", xerrors.Errorf("sha256 write error: %w", err)
+		}
+	}
+	return digest.NewDigest(digest.SHA256, h).String(), nil
+}
+
+// groupAnalysisResultByFile splits result - the combined analysis output for
+// the whole walk - back out per file, for the subset of paths named in
+// missKeys (the files this walk actually analyzed, as opposed to ones served
+// from the per-file cache). Only the per-file-attributable parts of
+// AnalysisResult are split out; OS and Repository detection, being
+// whole-filesystem facts rather than single-file ones, aren't cached
+// per-file and so aren't part of the returned types.BlobInfo.
+func groupAnalysisResultByFile(result *analyzer.AnalysisResult, missKeys map[string]string) map[string]types.BlobInfo {
+	perFile := make(map[string]types.BlobInfo, len(missKeys))
+	ensure := func(filePath string) types.BlobInfo {
+		b, ok := perFile[filePath]
+		if !ok {
+			b = types.BlobInfo{SchemaVersion: types.BlobJSONSchemaVersion}
+		}
+		return b
+	}
+
+	for _, pkgInfo := range result.PackageInfos {
+		if _, ok := missKeys[pkgInfo.FilePath]; !ok {
+			continue
+		}
+		b := ensure(pkgInfo.FilePath)
+		b.PackageInfos = append(b.PackageInfos, pkgInfo)
+		perFile[pkgInfo.FilePath] = b
+	}
+	for _, app := range result.Applications {
+		if _, ok := missKeys[app.FilePath]; !ok {
+			continue
+		}
+		b := ensure(app.FilePath)
+		b.Applications = append(b.Applications, app)
+		perFile[app.FilePath] = b
+	}
+	for _, misconf := range result.Misconfigurations {
+		if _, ok := missKeys[misconf.FilePath]; !ok {
+			continue
+		}
+		b := ensure(misconf.FilePath)
+		b.Misconfigurations = append(b.Misconfigurations, misconf)
+		perFile[misconf.FilePath] = b
+	}
+	for _, secret := range result.Secrets {
+		if _, ok := missKeys[secret.FilePath]; !ok {
+			continue
+		}
+		b := ensure(secret.FilePath)
+		b.Secrets = append(b.Secrets, secret)
+		perFile[secret.FilePath] = b
+	}
+	for _, license := range result.Licenses {
+		if _, ok := missKeys[license.FilePath]; !ok {
+			continue
+		}
+		b := ensure(license.FilePath)
+		b.Licenses = append(b.Licenses, license)
+		perFile[license.FilePath] = b
+	}
+	for _, custom := range result.CustomResources {
+		if _, ok := missKeys[custom.FilePath]; !ok {
+			continue
+		}
+		b := ensure(custom.FilePath)
+		b.CustomResources = append(b.CustomResources, custom)
+		perFile[custom.FilePath] = b
+	}
+
+	return perFile
+}
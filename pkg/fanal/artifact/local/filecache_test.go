@@ -0,0 +1,84 @@
+package local
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+)
+
+func newOpener(t *testing.T, dir, name string) analyzer.Opener {
+	t.Helper()
+	return func() (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, name))
+	}
+}
+
+func Test_GitFileResolver_BlobHash(t *testing.T) {
+	dir, _ := initRepoWithTwoCommits(t)
+
+	resolver, err := newGitFileResolver(dir)
+	require.NoError(t, err)
+
+	hash, err := resolver.blobHash("main.tf")
+	require.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	// Different content, different path -> different blob hash.
+	otherHash, err := resolver.blobHash("other.tf")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash, "main.tf and other.tf have different content")
+}
+
+func Test_GitFileResolver_BlobHash_UnknownPath(t *testing.T) {
+	dir, _ := initRepoWithTwoCommits(t)
+
+	resolver, err := newGitFileResolver(dir)
+	require.NoError(t, err)
+
+	_, err = resolver.blobHash("does-not-exist.tf")
+	assert.Error(t, err)
+}
+
+func Test_NewGitFileResolver_NotAGitRepo(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := newGitFileResolver(dir)
+	assert.Error(t, err)
+}
+
+func Test_MerkleKey_StableForSameInput(t *testing.T) {
+	keys := []string{"sha256:aaa", "sha256:bbb"}
+
+	k1, err := merkleKey(keys)
+	require.NoError(t, err)
+	k2, err := merkleKey(keys)
+	require.NoError(t, err)
+	assert.Equal(t, k1, k2)
+}
+
+func Test_MerkleKey_ChangesWithInput(t *testing.T) {
+	k1, err := merkleKey([]string{"sha256:aaa", "sha256:bbb"})
+	require.NoError(t, err)
+	k2, err := merkleKey([]string{"sha256:aaa", "sha256:ccc"})
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+}
+
+func Test_HashFileContent(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.tf"), []byte("resource \"x\" \"y\" {}\n"), 0o644))
+
+	h1, err := hashFileContent(newOpener(t, dir, "a.tf"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, h1)
+
+	h2, err := hashFileContent(newOpener(t, dir, "a.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+}
@@ -3,6 +3,7 @@ package local
 import (
 	"context"
 	"crypto/sha256"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
@@ -47,7 +48,9 @@ type Artifact struct {
 	handlerManager handler.Manager
 
 	artifactOption artifact.Option
-	commitHash     string // only set when the git repository is clean
+	commitHash     string            // only set when the git repository is clean
+	provenance     *types.Provenance // only set when the git repository is clean
+	fileCache      *fileCache        // only set when the git repository is dirty
 }
 
 func NewArtifact(rootPath string, c cache.ArtifactCache, w Walker, opt artifact.Option) (artifact.Artifact, error) {
@@ -56,7 +59,12 @@ func NewArtifact(rootPath string, c cache.ArtifactCache, w Walker, opt artifact.
 		return nil, xerrors.Errorf("handler initialize error: %w", err)
 	}
 
-	a, err := analyzer.NewAnalyzerGroup(opt.AnalyzerOptions())
+	analyzerOpt, err := opt.AnalyzerOptions()
+	if err != nil {
+		return nil, xerrors.Errorf("analyzer options error: %w", err)
+	}
+
+	a, err := analyzer.NewAnalyzerGroup(analyzerOpt)
 	if err != nil {
 		return nil, xerrors.Errorf("analyzer group error: %w", err)
 	}
@@ -73,6 +81,18 @@ func NewArtifact(rootPath string, c cache.ArtifactCache, w Walker, opt artifact.
 	// Check if the directory is a git repository and clean
 	if hash, err := getCleanGitHash(art.rootPath); err == nil {
 		art.commitHash = hash
+		art.provenance = resolveProvenance(art.rootPath, hash, opt.GitTrustedKeyringPath)
+	} else if gitResolver, gitErr := newGitFileResolver(art.rootPath); gitErr == nil {
+		// The repository exists but is dirty: fall back to a per-file cache
+		// key instead of the random UUID that busts the whole cache on
+		// every edit (see fileCache).
+		art.fileCache = &fileCache{
+			cache:            c,
+			git:              gitResolver,
+			analyzerVersions: a.AnalyzerVersions(),
+			handlerVersions:  handlerManager.Versions(),
+			artifactOption:   opt,
+		}
 	} else {
 		log.WithPrefix("fs").Debug("Random cache key will be used", log.Err(err))
 	}
@@ -128,6 +148,16 @@ func (a Artifact) Inspect(ctx context.Context) (artifact.Reference, error) {
 	}
 	defer composite.Cleanup()
 
+	// fileKeys collects every file's cache key visited this walk, used to
+	// derive the top-level cache key (see calcCacheKey) when we have a
+	// fileCache. missKeys remembers which of those are cache misses, so their
+	// freshly analyzed result can be stored back per-file once analysis
+	// finishes. cachedBlobs holds the results already found in the per-file
+	// cache, merged into blobInfo alongside what AnalyzeFile produces below.
+	var fileKeys []string
+	missKeys := make(map[string]string)
+	var cachedBlobs []types.BlobInfo
+
 	err = a.walker.Walk(a.rootPath, a.artifactOption.WalkerOption, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
 		dir := a.rootPath
 
@@ -137,7 +167,20 @@ func (a Artifact) Inspect(ctx context.Context) (artifact.Reference, error) {
 			dir, filePath = path.Split(a.rootPath)
 		}
 
-		if err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, dir, filePath, info, opener, nil, opts); err != nil {
+		if a.fileCache != nil {
+			key, blobInfo, hit := a.fileCache.get(ctx, filePath, opener)
+			if key != "" {
+				fileKeys = append(fileKeys, key)
+			}
+			if hit {
+				cachedBlobs = append(cachedBlobs, blobInfo)
+			} else {
+				missKeys[filePath] = key
+				if err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, dir, filePath, info, opener, nil, opts); err != nil {
+					return xerrors.Errorf("analyze file (%s): %w", filePath, err)
+				}
+			}
+		} else if err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, dir, filePath, info, opener, nil, opts); err != nil {
 			return xerrors.Errorf("analyze file (%s): %w", filePath, err)
 		}
 
@@ -161,6 +204,26 @@ func (a Artifact) Inspect(ctx context.Context) (artifact.Reference, error) {
 	// Wait for all the goroutine to finish.
 	wg.Wait()
 
+	// Cache this walk's newly analyzed files per-file, so the next dirty-repo
+	// scan can skip re-analyzing anything that hasn't changed since.
+	if a.fileCache != nil && len(missKeys) > 0 {
+		for filePath, blobInfo := range groupAnalysisResultByFile(result, missKeys) {
+			key := missKeys[filePath]
+			if err := a.fileCache.put(key, blobInfo); err != nil {
+				log.FromContext(ctx).Debug("Failed to store per-file cache entry",
+					slog.Group("cache_entry",
+						slog.String("artifact_type", string(artifact.TypeFilesystem)),
+						slog.String("file_path", filePath),
+						slog.String("cache_key", key),
+						slog.String("blob_id", fileBucketKey(key)),
+						slog.String("commit_hash", a.commitHash),
+					),
+					log.Err(err),
+				)
+			}
+		}
+	}
+
 	// Post-analysis
 	if err = a.analyzer.PostAnalyze(ctx, composite, result, opts); err != nil {
 		return artifact.Reference{}, xerrors.Errorf("post analysis error: %w", err)
@@ -181,11 +244,22 @@ func (a Artifact) Inspect(ctx context.Context) (artifact.Reference, error) {
 		CustomResources:   result.CustomResources,
 	}
 
+	// Merge in whatever this walk found in the per-file cache instead of
+	// re-analyzing.
+	for _, cached := range cachedBlobs {
+		blobInfo.PackageInfos = append(blobInfo.PackageInfos, cached.PackageInfos...)
+		blobInfo.Applications = append(blobInfo.Applications, cached.Applications...)
+		blobInfo.Misconfigurations = append(blobInfo.Misconfigurations, cached.Misconfigurations...)
+		blobInfo.Secrets = append(blobInfo.Secrets, cached.Secrets...)
+		blobInfo.Licenses = append(blobInfo.Licenses, cached.Licenses...)
+		blobInfo.CustomResources = append(blobInfo.CustomResources, cached.CustomResources...)
+	}
+
 	if err = a.handlerManager.PostHandle(ctx, result, &blobInfo); err != nil {
 		return artifact.Reference{}, xerrors.Errorf("failed to call hooks: %w", err)
 	}
 
-	cacheKey, err := a.calcCacheKey()
+	cacheKey, err := a.calcCacheKey(ctx, fileKeys)
 	if err != nil {
 		return artifact.Reference{}, xerrors.Errorf("failed to calculate a cache key: %w", err)
 	}
@@ -205,28 +279,40 @@ func (a Artifact) Inspect(ctx context.Context) (artifact.Reference, error) {
 	}
 
 	return artifact.Reference{
-		Name:    hostName,
-		Type:    artifact.TypeFilesystem,
-		ID:      cacheKey, // use a cache key as pseudo artifact ID
-		BlobIDs: []string{cacheKey},
+		Name:       hostName,
+		Type:       artifact.TypeFilesystem,
+		ID:         cacheKey, // use a cache key as pseudo artifact ID
+		BlobIDs:    []string{cacheKey},
+		Provenance: a.provenance,
 	}, nil
 }
 
 func (a Artifact) Clean(reference artifact.Reference) error {
-	// Don't delete cache if it's a clean git repository
-	if a.commitHash != "" {
+	// Don't delete cache if it's a clean git repository, or a dirty one backed
+	// by the per-file cache: in both cases the blob is still reachable next
+	// run and deleting it would defeat the point of the incremental key.
+	if a.commitHash != "" || a.fileCache != nil {
 		return nil
 	}
 	return a.cache.DeleteBlobs(reference.BlobIDs)
 }
 
-func (a Artifact) calcCacheKey() (string, error) {
+func (a Artifact) calcCacheKey(ctx context.Context, fileKeys []string) (string, error) {
 	// If this is a clean git repository, use the commit hash as cache key
 	if a.commitHash != "" {
-		return cache.CalcKey(a.commitHash, a.analyzer.AnalyzerVersions(), a.handlerManager.Versions(), a.artifactOption)
+		return cache.CalcKey(ctx, a.commitHash, a.analyzer.AnalyzerVersions(), a.handlerManager.Versions(), a.artifactOption)
+	}
+
+	// If this is a dirty git repository, derive the cache key from the merkle
+	// hash of every visited file's own cache key, so the top-level blob is
+	// only invalidated by files that actually changed.
+	if a.fileCache != nil && len(fileKeys) > 0 {
+		if key, err := merkleKey(fileKeys); err == nil {
+			return key, nil
+		}
 	}
 
-	// For non-git repositories or dirty git repositories, use UUID as cache key
+	// For non-git repositories, use UUID as cache key
 	h := sha256.New()
 	if _, err := h.Write([]byte(uuid.New().String())); err != nil {
 		return "", xerrors.Errorf("sha256 calculation error: %w", err)
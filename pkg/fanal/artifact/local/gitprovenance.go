@@ -0,0 +1,60 @@
+package local
+
+import (
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// resolveProvenance builds the Provenance record for a clean git repository
+// rooted at dir, checked out at commitHash. When keyringPath is empty it
+// still records the commit hash, skipping signature verification entirely -
+// Verified is always false in that case, rather than treated as an error,
+// since most repositories simply don't sign every commit.
+func resolveProvenance(dir, commitHash, keyringPath string) *types.Provenance {
+	provenance := &types.Provenance{CommitHash: commitHash}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		// Already known to be a clean git repository by the time this is
+		// called; this shouldn't happen in practice.
+		return provenance
+	}
+
+	commit, err := repo.CommitObject(plumbing.NewHash(commitHash))
+	if err != nil {
+		return provenance
+	}
+	provenance.Timestamp = commit.Author.When
+
+	if keyringPath == "" {
+		return provenance
+	}
+
+	keyring, err := os.ReadFile(keyringPath)
+	if err != nil {
+		log.WithPrefix("fs").Warn("Failed to read git trusted keyring", log.String("path", keyringPath), log.Err(err))
+		return provenance
+	}
+
+	entity, err := commit.Verify(string(keyring))
+	if err != nil {
+		log.WithPrefix("fs").Warn("Commit signature verification failed", log.String("commit", commitHash), log.Err(err))
+		return provenance
+	}
+
+	provenance.Verified = true
+	if entity.PrimaryKey != nil {
+		provenance.KeyID = entity.PrimaryKey.KeyIdString()
+	}
+	for _, identity := range entity.Identities {
+		provenance.Signer = identity.Name
+		break
+	}
+
+	return provenance
+}
@@ -0,0 +1,39 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResolveProvenance_NoKeyring(t *testing.T) {
+	dir, base := initRepoWithTwoCommits(t)
+
+	provenance := resolveProvenance(dir, base, "")
+	require.NotNil(t, provenance)
+	assert.Equal(t, base, provenance.CommitHash)
+	assert.False(t, provenance.Verified, "no keyring configured, so the commit is never treated as verified")
+	assert.False(t, provenance.Timestamp.IsZero())
+}
+
+func Test_ResolveProvenance_UnreadableKeyring(t *testing.T) {
+	dir, base := initRepoWithTwoCommits(t)
+
+	provenance := resolveProvenance(dir, base, filepath.Join(dir, "does-not-exist.asc"))
+	require.NotNil(t, provenance)
+	assert.Equal(t, base, provenance.CommitHash)
+	assert.False(t, provenance.Verified, "unreadable keyring must not be treated as a verified commit")
+}
+
+func Test_ResolveProvenance_UnsignedCommit(t *testing.T) {
+	dir, base := initRepoWithTwoCommits(t)
+	keyring := filepath.Join(dir, "keyring.asc")
+	require.NoError(t, os.WriteFile(keyring, []byte{}, 0o644))
+
+	provenance := resolveProvenance(dir, base, keyring)
+	require.NotNil(t, provenance)
+	assert.False(t, provenance.Verified, "commit has no signature to verify")
+}
@@ -0,0 +1,57 @@
+package local
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// filePool runs file-analysis tasks across a bounded number of goroutines
+// and stops handing out new work as soon as ctx is cancelled or any task
+// returns an error, so a long-running analysis (e.g. a Rego evaluation) on
+// one file doesn't delay the rest of the scan noticing it should stop.
+//
+// NOTE: this is the worker-pool half of the redesign described in the
+// request this commit implements. Inspect itself can't be rewired to
+// submit file-analysis tasks through it in this checkout: fs.go's current
+// walker-callback body calls a.walker.Walk and a.analyzer.AnalyzeFile,
+// whose declaring packages (pkg/fanal/walker and the rest of
+// pkg/fanal/analyzer beyond this directory, plus pkg/semaphore) aren't
+// present here, so there's no way to verify their real signatures or keep
+// TestArtifact_Inspect's byte-identical BlobInfo assertions green against a
+// rewritten call site. filePool is real and independently testable; wiring
+// it into Inspect (and adding artifact.Option.Parallelism) is left for when
+// those packages are available to check against.
+type filePool struct {
+	g   *errgroup.Group
+	sem chan struct{}
+}
+
+// newFilePool returns a filePool bounded to parallel concurrent tasks, along
+// with a context that's cancelled as soon as any submitted task returns an
+// error.
+func newFilePool(ctx context.Context, parallel int) (*filePool, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	return &filePool{g: g, sem: make(chan struct{}, parallel)}, ctx
+}
+
+// submit runs task on the pool, blocking until a worker slot is free. It
+// skips task entirely if ctx is already done by the time a slot opens up.
+func (p *filePool) submit(ctx context.Context, task func() error) {
+	p.sem <- struct{}{}
+	p.g.Go(func() error {
+		defer func() { <-p.sem }()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		return task()
+	})
+}
+
+// wait blocks until every submitted task has returned, and reports the
+// first error any of them returned, if any.
+func (p *filePool) wait() error {
+	return p.g.Wait()
+}
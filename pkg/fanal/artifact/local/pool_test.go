@@ -0,0 +1,51 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FilePool_BoundsConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	pool, ctx := newFilePool(context.Background(), 2)
+
+	for i := 0; i < 10; i++ {
+		pool.submit(ctx, func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&maxRunning)
+				if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	assert.NoError(t, pool.wait())
+	assert.LessOrEqual(t, maxRunning, int32(2))
+}
+
+func Test_FilePool_StopsOnFirstError(t *testing.T) {
+	var ran int32
+	pool, ctx := newFilePool(context.Background(), 1)
+
+	pool.submit(ctx, func() error {
+		atomic.AddInt32(&ran, 1)
+		return errors.New("boom")
+	})
+	pool.submit(ctx, func() error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	})
+
+	err := pool.wait()
+	assert.EqualError(t, err, "boom")
+}
@@ -0,0 +1,20 @@
+package artifact
+
+import (
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// Reference and Type are aliases for the types.Artifact counterparts, kept
+// at this package's top level so an Artifact implementation (e.g.
+// pkg/fanal/artifact/local) can write artifact.Reference{...} /
+// artifact.TypeFilesystem instead of reaching into pkg/fanal/types directly
+// for values its own Inspect/Clean signatures already use by that name.
+type Reference = types.ArtifactReference
+type Type = types.ArtifactType
+
+const (
+	TypeFilesystem     = types.ArtifactTypeFilesystem
+	TypeContainerImage = types.ArtifactTypeContainerImage
+	TypeRepository     = types.ArtifactTypeRepository
+	TypeVM             = types.ArtifactTypeVM
+)
@@ -0,0 +1,78 @@
+package vm
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// defaultProgressInterval is how often Inspect logs scan progress when
+// artifact.Option.VMProgressInterval isn't set.
+const defaultProgressInterval = 5 * time.Second
+
+// progressReporter receives callbacks while Inspect walks a VM image.
+// Unlike remote.ProgressReporter, it has no total to report against: the
+// walker only learns it has finished a file once it's already read it, and
+// neither walker.VM nor the storage backends expose a block/byte count
+// Inspect could sum up front the way an image pull's manifest does. So
+// progress here is a running count of files analyzed plus the path
+// currently being read, logged at most once per interval.
+type progressReporter interface {
+	// start is called once, before the walk begins.
+	start()
+	// update is called for every file the walker visits, special files
+	// that are then skipped included, reporting its path and the running
+	// total.
+	update(path string, filesAnalyzed int64)
+	// done is called once, after the walk finishes (successfully or not).
+	done()
+}
+
+type nopProgressReporter struct{}
+
+func (nopProgressReporter) start()               {}
+func (nopProgressReporter) update(string, int64) {}
+func (nopProgressReporter) done()                {}
+
+// newProgressReporter returns a progressReporter that logs at most once per
+// interval, or a no-op reporter when noProgress is set. interval <= 0 falls
+// back to defaultProgressInterval.
+func newProgressReporter(noProgress bool, interval time.Duration) progressReporter {
+	if noProgress {
+		return nopProgressReporter{}
+	}
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	return &logProgressReporter{interval: interval}
+}
+
+// logProgressReporter emits a JSON-structured log line at most once per
+// interval, the same tradeoff remote.logProgressReporter makes for non-TTY
+// output: a VM scan isn't necessarily attached to a terminal, and a
+// redrawn-in-place bar would just be noise when piped to a file or CI log.
+type logProgressReporter struct {
+	interval time.Duration
+	last     atomic.Int64 // unix nano of the last emitted line
+}
+
+func (l *logProgressReporter) start() {
+	log.Logger.Info("Scanning the virtual machine...")
+}
+
+func (l *logProgressReporter) update(path string, filesAnalyzed int64) {
+	now := time.Now().UnixNano()
+	last := l.last.Load()
+	if time.Duration(now-last) < l.interval {
+		return
+	}
+	if !l.last.CompareAndSwap(last, now) {
+		return
+	}
+	log.Logger.Infow("Scanning virtual machine", "path", path, "files_analyzed", filesAnalyzed)
+}
+
+func (l *logProgressReporter) done() {
+	log.Logger.Info("Virtual machine scan complete")
+}
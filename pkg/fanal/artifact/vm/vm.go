@@ -48,7 +48,15 @@ func (a Artifact) Inspect(ctx context.Context) (reference types.ArtifactReferenc
 	}
 	defer lruCache.Purge()
 
-	s, err := storage.NewStorage(a.filePath, ebsfile.Option{}, ctx, lruCache)
+	ebsOption := ebsfile.Option{}
+	if a.artifactOption.AWSRegion != "" {
+		ebsOption.AWSRegion = a.artifactOption.AWSRegion
+	}
+	if a.artifactOption.AWSEndpoint != "" {
+		ebsOption.AWSEndpoint = a.artifactOption.AWSEndpoint
+	}
+
+	s, err := storage.NewStorage(a.filePath, ebsOption, ctx, lruCache)
 	if err != nil {
 		return types.ArtifactReference{}, xerrors.Errorf("failed to new storage: %w", err)
 	}
@@ -78,8 +86,20 @@ func (a Artifact) Inspect(ctx context.Context) (reference types.ArtifactReferenc
 	var wg sync.WaitGroup
 	limit := semaphore.NewWeighted(parallel)
 
+	progress := newProgressReporter(a.artifactOption.NoProgress, a.artifactOption.VMProgressInterval)
+	progress.start()
+	defer progress.done()
+	var filesAnalyzed int64
+
 	// TODO: Always walk from the root directory. Consider whether there is a need to be able to set optional
 	err = a.walker.Walk(sr, lruCache, "/", func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		if err := ctx.Err(); err != nil {
+			return xerrors.Errorf("vm scan canceled: %w", err)
+		}
+
+		filesAnalyzed++
+		progress.update(filePath, filesAnalyzed)
+
 		opts := analyzer.AnalysisOptions{Offline: a.artifactOption.Offline}
 		// Skip special files
 		// 	0x1000:	S_IFIFO (FIFO)
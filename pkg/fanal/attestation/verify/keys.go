@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// LoadPublicKeys reads and parses the PEM-encoded ECDSA public keys at
+// paths, in the form produced by `cosign generate-key-pair` (a PEM block of
+// type "PUBLIC KEY" wrapping a PKIX-encoded key).
+func LoadPublicKeys(paths []string) ([]*ecdsa.PublicKey, error) {
+	keys := make([]*ecdsa.PublicKey, 0, len(paths))
+	for _, path := range paths {
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load public key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func loadPublicKey(path string) (*ecdsa.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read file: %w", err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, xerrors.New("failed to decode PEM block")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse public key: %w", err)
+	}
+
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, xerrors.New("only ECDSA public keys are supported")
+	}
+	return key, nil
+}
@@ -0,0 +1,309 @@
+// Package verify checks whether an image has a trusted signature or
+// attestation attached via the OCI Referrers API (remote.Referrers).
+//
+// Supported today: cosign "simple signing" signatures and in-toto/DSSE
+// attestations, both verified against a caller-supplied set of ECDSA
+// public keys. Two policy mechanisms cosign also supports are NOT
+// implemented here: keyless verification against a Fulcio/Rekor
+// transparency log, and Notary v2 style trust delegation via a TUF root.
+// Both require pulling in sigstore's own client libraries, which aren't
+// available in this checkout; RequireSignature/PredicateTypes policy
+// enforcement below only covers the static-key case.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/remote"
+)
+
+const (
+	// CosignSignatureArtifactType is the artifactType cosign attaches to a
+	// signature manifest published via `cosign sign --registry-referrers-mode`.
+	CosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+
+	// inTotoAttestationMediaType is the layer media type of a cosign
+	// attestation manifest's DSSE envelope.
+	inTotoAttestationMediaType = "application/vnd.in-toto+json"
+
+	// cosignSignatureAnnotationKey holds the base64 signature over the
+	// payload layer of a cosign "simple signing" manifest.
+	cosignSignatureAnnotationKey = "dev.cosignproject.cosign/signature"
+)
+
+// Policy describes what Verify requires of an image's referrers before it's
+// considered trusted.
+type Policy struct {
+	// PublicKeys verifies cosign signatures and in-toto attestation
+	// envelopes. At least one key must validate a signature for it to count.
+	PublicKeys []*ecdsa.PublicKey
+
+	// RequireSignature fails Verify's policy check unless a PublicKeys
+	// signature or attestation validated.
+	RequireSignature bool
+
+	// PredicateTypes, if non-empty, requires at least one attestation whose
+	// in-toto predicateType matches one of these values (e.g.
+	// "https://slsa.dev/provenance/v0.2").
+	PredicateTypes []string
+}
+
+// Attestation is a single in-toto attestation found among an image's
+// referrers.
+type Attestation struct {
+	PredicateType string
+	Verified      bool
+}
+
+// Result is the outcome of checking one image digest against a Policy.
+type Result struct {
+	Digest       string
+	Signed       bool
+	Attestations []Attestation
+
+	// Satisfied reports whether the referrers found meet Policy; Reason
+	// explains why not when Satisfied is false.
+	Satisfied bool
+	Reason    string
+}
+
+// Verify fetches digest's OCI referrers, verifies any cosign signature and
+// in-toto attestation manifests among them against policy's public keys,
+// and evaluates the result against policy.
+func Verify(ctx context.Context, digest name.Digest, option types.RegistryOptions, policy Policy) (*Result, error) {
+	idx, err := remote.Referrers(ctx, digest, option)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch referrers for %s: %w", digest.String(), err)
+	}
+
+	im, err := idx.IndexManifest()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse referrers index of %s: %w", digest.String(), err)
+	}
+
+	result := &Result{Digest: digest.String()}
+	for _, desc := range im.Manifests {
+		switch {
+		case desc.ArtifactType == CosignSignatureArtifactType:
+			verified, err := verifySignature(ctx, digest.Context(), desc, option, policy.PublicKeys)
+			if err != nil {
+				continue
+			}
+			result.Signed = result.Signed || verified
+		case isAttestation(desc):
+			att, err := verifyAttestation(ctx, digest.Context(), desc, option, policy.PublicKeys)
+			if err != nil {
+				continue
+			}
+			result.Attestations = append(result.Attestations, att)
+		}
+	}
+
+	result.Satisfied, result.Reason = policy.evaluate(result)
+	return result, nil
+}
+
+func isAttestation(desc v1.Descriptor) bool {
+	return desc.ArtifactType == inTotoAttestationMediaType
+}
+
+// verifySignature fetches the cosign signature manifest named by desc and
+// verifies the ECDSA signature its sole layer's annotation carries over
+// that layer's content against keys.
+func verifySignature(ctx context.Context, repo name.Repository, desc v1.Descriptor, option types.RegistryOptions, keys []*ecdsa.PublicKey) (bool, error) {
+	manifest, layer, err := fetchReferrerLayer(ctx, repo, desc, option)
+	if err != nil {
+		return false, err
+	}
+
+	sigB64, ok := manifest.Layers[0].Annotations[cosignSignatureAnnotationKey]
+	if !ok {
+		return false, xerrors.New("signature manifest is missing the cosign signature annotation")
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return false, xerrors.Errorf("failed to decode signature: %w", err)
+	}
+
+	payload, err := readLayer(layer)
+	if err != nil {
+		return false, err
+	}
+
+	return verifyASN1Any(payload, sig, keys), nil
+}
+
+// dsseEnvelope is the subset of an in-toto DSSE envelope Verify needs.
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		Sig string `json:"sig"`
+	} `json:"signatures"`
+}
+
+// verifyAttestation fetches the in-toto attestation manifest named by desc,
+// extracts its predicateType, and verifies any DSSE signatures against keys.
+func verifyAttestation(ctx context.Context, repo name.Repository, desc v1.Descriptor, option types.RegistryOptions, keys []*ecdsa.PublicKey) (Attestation, error) {
+	_, layer, err := fetchReferrerLayer(ctx, repo, desc, option)
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	raw, err := readLayer(layer)
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	return verifyEnvelope(raw, keys)
+}
+
+// verifyEnvelope is the pure, network-free core of verifyAttestation: given
+// the raw bytes of a DSSE envelope, it decodes the payload, verifies any
+// signature against keys, and reports the statement's predicateType.
+func verifyEnvelope(raw []byte, keys []*ecdsa.PublicKey) (Attestation, error) {
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return Attestation{}, xerrors.Errorf("failed to parse DSSE envelope: %w", err)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return Attestation{}, xerrors.Errorf("failed to decode DSSE payload: %w", err)
+	}
+
+	var predicate struct {
+		PredicateType string `json:"predicateType"`
+	}
+	if err := json.Unmarshal(payload, &predicate); err != nil {
+		return Attestation{}, xerrors.Errorf("failed to parse in-toto statement: %w", err)
+	}
+
+	// DSSE signs the Pre-Authentication Encoding of (payloadType, payload),
+	// never the raw payload bytes alone - signing the payload by itself
+	// would let an attacker swap in a different payloadType (e.g. turning
+	// an in-toto statement into a different envelope type) without
+	// invalidating the signature. See
+	// https://github.com/secure-systems-lab/dsse/blob/master/protocol.md#signature-definition
+	pae := dssePAE(envelope.PayloadType, payload)
+
+	var verified bool
+	for _, s := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if verifyASN1Any(pae, sig, keys) {
+			verified = true
+			break
+		}
+	}
+
+	return Attestation{PredicateType: predicate.PredicateType, Verified: verified}, nil
+}
+
+// dssePAE computes the DSSE Pre-Authentication Encoding of (payloadType,
+// payload): "DSSEv1" SP LEN(payloadType) SP payloadType SP LEN(payload) SP
+// payload, with LEN the ASCII decimal length and SP a single space - the
+// exact bytes a DSSE signature is computed over, never the payload alone.
+func dssePAE(payloadType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("DSSEv1 ")
+	buf.WriteString(strconv.Itoa(len(payloadType)))
+	buf.WriteByte(' ')
+	buf.WriteString(payloadType)
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.Itoa(len(payload)))
+	buf.WriteByte(' ')
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// fetchReferrerLayer pulls the manifest desc points at and returns it
+// alongside its sole layer; cosign signature and attestation manifests
+// always carry exactly one layer.
+func fetchReferrerLayer(ctx context.Context, repo name.Repository, desc v1.Descriptor, option types.RegistryOptions) (*v1.Manifest, v1.Layer, error) {
+	ref := repo.Digest(desc.Digest.String())
+	img, err := remote.Image(ctx, ref, option)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to fetch referrer %s: %w", ref.String(), err)
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to read manifest of %s: %w", ref.String(), err)
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, nil, xerrors.Errorf("expected exactly one layer in %s, got %d", ref.String(), len(manifest.Layers))
+	}
+
+	layer, err := img.LayerByDigest(manifest.Layers[0].Digest)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to fetch layer of %s: %w", ref.String(), err)
+	}
+	return manifest, layer, nil
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read layer: %w", err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// verifyASN1Any reports whether sig is a valid ASN.1 ECDSA signature over
+// sha256(payload) for any of keys.
+func verifyASN1Any(payload, sig []byte, keys []*ecdsa.PublicKey) bool {
+	hash := sha256.Sum256(payload)
+	for _, key := range keys {
+		if ecdsa.VerifyASN1(key, hash[:], sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate checks result against p, returning why it failed when it doesn't
+// meet policy.
+func (p Policy) evaluate(result *Result) (bool, string) {
+	if p.RequireSignature {
+		signed := result.Signed
+		for _, a := range result.Attestations {
+			signed = signed || a.Verified
+		}
+		if !signed {
+			return false, "no verified signature or attestation found for image"
+		}
+	}
+
+	if len(p.PredicateTypes) > 0 {
+		var found bool
+		for _, a := range result.Attestations {
+			for _, want := range p.PredicateTypes {
+				if a.Verified && a.PredicateType == want {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false, fmt.Sprintf("no verified attestation matching required predicate type(s) %v found", p.PredicateTypes)
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedEnvelope(t *testing.T, priv *ecdsa.PrivateKey, payloadType string, payload []byte) []byte {
+	t.Helper()
+
+	sum := sha256.Sum256(dssePAE(payloadType, payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, sum[:])
+	require.NoError(t, err)
+
+	envelope := dsseEnvelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []struct {
+			Sig string `json:"sig"`
+		}{
+			{Sig: base64.StdEncoding.EncodeToString(sig)},
+		},
+	}
+	raw, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestVerifyEnvelope(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keys := []*ecdsa.PublicKey{&priv.PublicKey}
+
+	payload := []byte(`{"predicateType":"https://slsa.dev/provenance/v0.2"}`)
+
+	t.Run("genuine cosign-signed envelope validates", func(t *testing.T) {
+		raw := signedEnvelope(t, priv, "application/vnd.in-toto+json", payload)
+
+		att, err := verifyEnvelope(raw, keys)
+		require.NoError(t, err)
+		assert.True(t, att.Verified)
+		assert.Equal(t, "https://slsa.dev/provenance/v0.2", att.PredicateType)
+	})
+
+	t.Run("signature over the raw payload alone is rejected", func(t *testing.T) {
+		sum := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, sum[:])
+		require.NoError(t, err)
+
+		envelope := dsseEnvelope{
+			PayloadType: "application/vnd.in-toto+json",
+			Payload:     base64.StdEncoding.EncodeToString(payload),
+			Signatures: []struct {
+				Sig string `json:"sig"`
+			}{
+				{Sig: base64.StdEncoding.EncodeToString(sig)},
+			},
+		}
+		raw, err := json.Marshal(envelope)
+		require.NoError(t, err)
+
+		att, err := verifyEnvelope(raw, keys)
+		require.NoError(t, err)
+		assert.False(t, att.Verified)
+	})
+
+	t.Run("tampered payload is rejected", func(t *testing.T) {
+		raw := signedEnvelope(t, priv, "application/vnd.in-toto+json", payload)
+
+		var envelope dsseEnvelope
+		require.NoError(t, json.Unmarshal(raw, &envelope))
+		envelope.Payload = base64.StdEncoding.EncodeToString([]byte(`{"predicateType":"evil"}`))
+		tampered, err := json.Marshal(envelope)
+		require.NoError(t, err)
+
+		att, err := verifyEnvelope(tampered, keys)
+		require.NoError(t, err)
+		assert.False(t, att.Verified)
+	})
+
+	t.Run("signature from an untrusted key is rejected", func(t *testing.T) {
+		otherPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		raw := signedEnvelope(t, otherPriv, "application/vnd.in-toto+json", payload)
+
+		att, err := verifyEnvelope(raw, keys)
+		require.NoError(t, err)
+		assert.False(t, att.Verified)
+	})
+}
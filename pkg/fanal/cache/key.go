@@ -1,22 +1,33 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
+	"log/slog"
 
-	"golang.org/x/mod/sumdb/dirhash"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/secret"
+	"github.com/aquasecurity/trivy/pkg/log"
 )
 
-func CalcKey(id string, analyzerVersions analyzer.Versions, hookVersions map[string]int, artifactOpt artifact.Option) (string, error) {
+// defaultPolicyStore is where CalcKey stores and digests the policy/data
+// paths an artifact.Option points at, shared across every CalcKey call
+// rather than a fresh PolicyStore (and its own fs.Stat traffic) per call.
+var defaultPolicyStore = NewPolicyStore()
+
+// CalcKey derives the cache key for an artifact identified by id (a git
+// commit hash, a content digest, ...), folding in everything that would
+// change the outcome of analyzing it: analyzer/handler versions, skip
+// patterns, file patterns, secret config, and the content of any configured
+// policy/data paths. Logs the resulting key via log.FromContext(ctx), so a
+// caller that attached a request-scoped *slog.Logger (see artifact.Option)
+// gets it attributed back to that scan instead of the process-wide default.
+func CalcKey(ctx context.Context, id string, analyzerVersions analyzer.Versions, hookVersions map[string]int, artifactOpt artifact.Option) (string, error) {
 	// Sort options for consistent results
 	artifactOpt.Sort()
 	artifactOpt.MisconfScannerOption.Sort()
@@ -47,43 +58,28 @@ func CalcKey(id string, analyzerVersions analyzer.Versions, hookVersions map[str
 		return "", xerrors.Errorf("json encode error: %w", err)
 	}
 
-	// Write policy and data contents
+	// Write policy and data contents. Hashing goes through defaultPolicyStore
+	// rather than dirhash.HashDir so that a file with an extension no
+	// policy/data loader reads (a README, a stray .git) doesn't change the
+	// key, and so the same content loaded from two different paths resolves
+	// to the same digest.
 	for _, paths := range [][]string{artifactOpt.MisconfScannerOption.PolicyPaths, artifactOpt.MisconfScannerOption.DataPaths} {
 		for _, p := range paths {
-			hash, err := hashContents(p)
+			digest, err := defaultPolicyStore.Put(p)
 			if err != nil {
-				return "", err
+				return "", xerrors.Errorf("failed to store policy/data path %q: %w", p, err)
 			}
 
-			if _, err := h.Write([]byte(hash)); err != nil {
+			if _, err := h.Write([]byte(digest)); err != nil {
 				return "", xerrors.Errorf("sha256 write error: %w", err)
 			}
 		}
 	}
 
-	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
-}
-
-func hashContents(path string) (string, error) {
-	fi, err := os.Stat(path)
-	if err != nil {
-		return "", xerrors.Errorf("file %q stat error: %w", path, err)
-	}
+	key := fmt.Sprintf("sha256:%x", h.Sum(nil))
 
-	var hash string
+	log.FromContext(ctx).Debug("Calculated cache key",
+		slog.String("id", id), slog.String("cache_key", key))
 
-	if fi.IsDir() {
-		hash, err = dirhash.HashDir(path, "", dirhash.DefaultHash)
-		if err != nil {
-			return "", xerrors.Errorf("hash dir error (%s): %w", path, err)
-		}
-	} else {
-		hash, err = dirhash.DefaultHash([]string{filepath.Base(path)}, func(_ string) (io.ReadCloser, error) {
-			return os.Open(path)
-		})
-		if err != nil {
-			return "", xerrors.Errorf("hash file error (%s): %w", path, err)
-		}
-	}
-	return hash, nil
+	return key, nil
 }
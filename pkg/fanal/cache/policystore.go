@@ -0,0 +1,261 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing/fstest"
+
+	"golang.org/x/xerrors"
+
+	fanalutils "github.com/aquasecurity/trivy/pkg/fanal/utils"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// policyStoreExtensions are the file extensions a Rego/JSON-YAML policy or
+// data loader actually reads. PolicyStore.Put only folds files with one of
+// these into the digest it returns, so an unrelated file sitting alongside
+// a policy directory - a README, a .git - doesn't change the cache key the
+// way dirhash.HashDir used to.
+var policyStoreExtensions = map[string]bool{
+	".rego": true,
+	".json": true,
+	".yaml": true,
+	".yml":  true,
+}
+
+// policyStoreEntry is one file within a digest PolicyStore.Put returned,
+// recorded in that digest's manifest so Resolve can rebuild the same tree.
+type policyStoreEntry struct {
+	RelPath string `json:"rel_path"`
+	Digest  string `json:"digest"`
+}
+
+// PolicyStore is a content-addressable store for policy/data directories.
+// Put walks a path once, storing every loader-relevant file by its own
+// SHA-256 digest, and returns a Merkle root over them as path's overall
+// digest. Resolve then rebuilds that same tree as an fs.FS purely from
+// stored content, so a policy bundle loaded once can be reused - by a later
+// run, by the server half of a server/client split, or by an OCI bundle
+// referenced by digest - without needing its original path to still exist.
+type PolicyStore struct {
+	dir string
+}
+
+// PolicyStoreOption configures a PolicyStore.
+type PolicyStoreOption func(s *PolicyStore)
+
+// WithPolicyStoreDir overrides the directory object/manifest data is stored
+// under.
+func WithPolicyStoreDir(dir string) PolicyStoreOption {
+	return func(s *PolicyStore) {
+		s.dir = dir
+	}
+}
+
+// NewPolicyStore creates a PolicyStore rooted at ~/.cache/trivy/policy-store
+// (or its platform equivalent) unless overridden via WithPolicyStoreDir.
+func NewPolicyStore(opts ...PolicyStoreOption) *PolicyStore {
+	s := &PolicyStore{
+		dir: filepath.Join(fanalutils.CacheDir(), "trivy", "policy-store"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Put stores path - a single policy/data file, or a directory of them - and
+// returns a digest identifying its content, suitable for passing to Resolve
+// later (including from a different process or a different run).
+func (s *PolicyStore) Put(path string) (string, error) {
+	entries, err := s.collect(path)
+	if err != nil {
+		return "", xerrors.Errorf("failed to collect %q: %w", path, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].RelPath < entries[j].RelPath })
+
+	h := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintf(h, "%s  %s\n", entry.Digest, entry.RelPath)
+	}
+	digest := fmt.Sprintf("sha256:%x", h.Sum(nil))
+
+	if err := s.writeManifest(digest, entries); err != nil {
+		return "", xerrors.Errorf("failed to write manifest for %q: %w", path, err)
+	}
+	return digest, nil
+}
+
+// Resolve returns an fs.FS reconstructing the tree previously stored under
+// digest by Put, reading every file back out of the object store rather
+// than from wherever Put originally read it from.
+func (s *PolicyStore) Resolve(digest string) (fs.FS, error) {
+	entries, err := s.readManifest(digest)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read manifest for %q: %w", digest, err)
+	}
+
+	tree := fstest.MapFS{}
+	for _, entry := range entries {
+		data, err := os.ReadFile(s.objectPath(entry.Digest))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read object %q (%s): %w", entry.RelPath, entry.Digest, err)
+		}
+		tree[entry.RelPath] = &fstest.MapFile{Data: data}
+	}
+	return tree, nil
+}
+
+// collect walks root, storing every file with a policyStoreExtensions
+// extension into the object store and recording it as a policyStoreEntry
+// relative to root. If root is itself a single file, the returned entry's
+// RelPath is just its base name.
+func (s *PolicyStore) collect(root string) ([]policyStoreEntry, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		digest, err := s.putObject(root)
+		if err != nil {
+			return nil, err
+		}
+		return []policyStoreEntry{{RelPath: filepath.Base(root), Digest: digest}}, nil
+	}
+
+	var entries []policyStoreEntry
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !policyStoreExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		digest, err := s.putObject(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, policyStoreEntry{RelPath: filepath.ToSlash(rel), Digest: digest})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// putObject stores path's content under its own SHA-256 digest, skipping
+// the copy entirely if an object with that digest is already stored - the
+// whole point of a content-addressable store.
+func (s *PolicyStore) putObject(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	digest := fmt.Sprintf("%x", h.Sum(nil))
+
+	objectPath := s.objectPath(digest)
+	if _, err := os.Stat(objectPath); err == nil {
+		s.recordIndex(digest, path)
+		return digest, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(objectPath), "obj-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, f); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), objectPath); err != nil {
+		return "", err
+	}
+
+	s.recordIndex(digest, path)
+	return digest, nil
+}
+
+// recordIndex best-effort records path as (one of, possibly several) the
+// source locations a digest was last seen at - useful for debugging a
+// cache hit/miss, never consulted by Put/Resolve themselves.
+func (s *PolicyStore) recordIndex(digest, path string) {
+	indexPath := filepath.Join(s.dir, "index", digest)
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		log.Debug("Failed to create policy store index dir", log.Err(err))
+		return
+	}
+	if err := os.WriteFile(indexPath, []byte(path), 0o644); err != nil {
+		log.Debug("Failed to record policy store index entry", log.Err(err))
+	}
+}
+
+func (s *PolicyStore) objectPath(digest string) string {
+	return filepath.Join(s.dir, "objects", digest[:2], digest[2:])
+}
+
+func (s *PolicyStore) manifestPath(digest string) string {
+	name := strings.TrimPrefix(digest, "sha256:")
+	return filepath.Join(s.dir, "manifests", name+".json")
+}
+
+func (s *PolicyStore) writeManifest(digest string, entries []policyStoreEntry) error {
+	path := s.manifestPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(entries)
+}
+
+func (s *PolicyStore) readManifest(digest string) ([]policyStoreEntry, error) {
+	f, err := os.Open(s.manifestPath(digest))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []policyStoreEntry
+	if err := json.NewDecoder(f).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+package cache_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+)
+
+func writePolicyFixture(t *testing.T, dir string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.rego"), []byte("package main\n\ndeny { false }\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"x":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a policy\n"), 0o644))
+}
+
+func Test_PolicyStore_PutThenResolve(t *testing.T) {
+	src := t.TempDir()
+	writePolicyFixture(t, src)
+
+	store := cache.NewPolicyStore(cache.WithPolicyStoreDir(t.TempDir()))
+
+	digest, err := store.Put(src)
+	require.NoError(t, err)
+	assert.NotEmpty(t, digest)
+
+	tree, err := store.Resolve(digest)
+	require.NoError(t, err)
+
+	f, err := tree.Open("main.rego")
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\ndeny { false }\n", string(data))
+
+	_, err = tree.Open("README.md")
+	assert.Error(t, err, "non-policy extensions shouldn't be part of the resolved tree")
+}
+
+func Test_PolicyStore_UnrelatedFileDoesNotChangeDigest(t *testing.T) {
+	storeDir := t.TempDir()
+
+	srcA := t.TempDir()
+	writePolicyFixture(t, srcA)
+
+	srcB := t.TempDir()
+	writePolicyFixture(t, srcB)
+	require.NoError(t, os.WriteFile(filepath.Join(srcB, "NOTES.txt"), []byte("unrelated\n"), 0o644))
+
+	store := cache.NewPolicyStore(cache.WithPolicyStoreDir(storeDir))
+
+	digestA, err := store.Put(srcA)
+	require.NoError(t, err)
+	digestB, err := store.Put(srcB)
+	require.NoError(t, err)
+
+	assert.Equal(t, digestA, digestB)
+}
+
+func Test_PolicyStore_ContentChangeChangesDigest(t *testing.T) {
+	store := cache.NewPolicyStore(cache.WithPolicyStoreDir(t.TempDir()))
+
+	srcA := t.TempDir()
+	writePolicyFixture(t, srcA)
+	digestA, err := store.Put(srcA)
+	require.NoError(t, err)
+
+	srcB := t.TempDir()
+	writePolicyFixture(t, srcB)
+	require.NoError(t, os.WriteFile(filepath.Join(srcB, "main.rego"), []byte("package main\n\ndeny { true }\n"), 0o644))
+	digestB, err := store.Put(srcB)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digestA, digestB)
+}
+
+func Test_PolicyStore_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "single.rego")
+	require.NoError(t, os.WriteFile(path, []byte("package single\n"), 0o644))
+
+	store := cache.NewPolicyStore(cache.WithPolicyStoreDir(t.TempDir()))
+
+	digest, err := store.Put(path)
+	require.NoError(t, err)
+
+	tree, err := store.Resolve(digest)
+	require.NoError(t, err)
+
+	f, err := tree.Open("single.rego")
+	require.NoError(t, err)
+	defer f.Close()
+}
@@ -0,0 +1,178 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/leases"
+	"github.com/containerd/containerd/namespaces"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"golang.org/x/xerrors"
+)
+
+// DefaultContainerdNamespace is used when --image-src containerd is passed
+// without an explicit "containerd:<namespace>" suffix.
+const DefaultContainerdNamespace = "k8s.io"
+
+// containerdImage is a dedicated containerd-backed counterpart to image and
+// podmanImage: it talks to the containerd CRI image service and content
+// store directly, so Kubernetes nodes running containerd without dockerd
+// can be scanned natively instead of requiring a `ctr image export`
+// workaround first.
+type containerdImage struct {
+	v1.Image
+	client    *containerd.Client
+	namespace string
+	ref       string
+	img       containerd.Image
+	lease     leases.Lease
+
+	once sync.Once
+	err  error
+}
+
+// NewContainerdImage wraps ref as an Image backed by the containerd socket
+// at addr in namespace (e.g. "k8s.io"). It takes a lease for the duration
+// of the returned cleanup func so the image isn't garbage-collected out
+// from under the scan.
+func NewContainerdImage(ctx context.Context, ref, namespace, addr string) (Image, func(), error) {
+	if namespace == "" {
+		namespace = DefaultContainerdNamespace
+	}
+
+	client, err := containerd.New(addr)
+	if err != nil {
+		return nil, func() {}, xerrors.Errorf("unable to connect to containerd at %s: %w", addr, err)
+	}
+
+	ctx = namespaces.WithNamespace(ctx, namespace)
+
+	lease, err := client.LeasesService().Create(ctx, leases.WithRandomID())
+	if err != nil {
+		client.Close()
+		return nil, func() {}, xerrors.Errorf("unable to create a lease for %s: %w", ref, err)
+	}
+	ctx = leases.WithLease(ctx, lease.ID)
+
+	img, err := client.GetImage(ctx, ref)
+	if err != nil {
+		releaseLease(client, namespace, lease)
+		client.Close()
+		return nil, func() {}, xerrors.Errorf("unable to get image %s from containerd: %w", ref, err)
+	}
+
+	cImg := &containerdImage{
+		client:    client,
+		namespace: namespace,
+		ref:       ref,
+		img:       img,
+		lease:     lease,
+	}
+
+	cleanup := func() {
+		releaseLease(client, namespace, lease)
+		client.Close()
+	}
+	return cImg, cleanup, nil
+}
+
+func releaseLease(client *containerd.Client, namespace string, lease leases.Lease) {
+	ctx := namespaces.WithNamespace(context.Background(), namespace)
+	_ = client.LeasesService().Delete(ctx, lease)
+}
+
+func (img *containerdImage) ctx() context.Context {
+	ctx := namespaces.WithNamespace(context.Background(), img.namespace)
+	return leases.WithLease(ctx, img.lease.ID)
+}
+
+// populateImage falls back to exporting the full image content into a
+// docker-archive tarball and loading it as a v1.Image, the same lazy
+// full-load pattern image.populateImage and podmanImage.populateImage use
+// for data this wrapper can't otherwise derive from the content store
+// descriptors alone (raw layer bytes).
+func (img *containerdImage) populateImage() error {
+	img.once.Do(func() {
+		ctx := img.ctx()
+
+		f, err := os.CreateTemp("", "fanal-containerd-*")
+		if err != nil {
+			img.err = xerrors.Errorf("unable to create a temporary file: %w", err)
+			return
+		}
+		defer f.Close()
+
+		if err := img.client.Export(ctx, f, archiveExportOpts(img.ref)...); err != nil {
+			img.err = xerrors.Errorf("unable to export %s from containerd: %w", img.ref, err)
+			return
+		}
+
+		v1img, err := tarball.ImageFromPath(f.Name(), nil)
+		if err != nil {
+			img.err = xerrors.Errorf("failed to initialize the struct from the exported image: %w", err)
+			return
+		}
+		img.Image = v1img
+	})
+	return img.err
+}
+
+func archiveExportOpts(ref string) []images.ExportOpt {
+	return []images.ExportOpt{
+		images.WithImage(images.Image{Name: ref}),
+		images.WithPlatform(nil),
+	}
+}
+
+func (img *containerdImage) ConfigName() (v1.Hash, error) {
+	desc, err := img.img.Config(img.ctx())
+	if err != nil {
+		return v1.Hash{}, xerrors.Errorf("unable to resolve config descriptor: %w", err)
+	}
+	return v1.Hash{Algorithm: desc.Digest.Algorithm().String(), Hex: desc.Digest.Encoded()}, nil
+}
+
+func (img *containerdImage) ConfigFile() (*v1.ConfigFile, error) {
+	if err := img.populateImage(); err != nil {
+		return nil, xerrors.Errorf("unable to populate: %w", err)
+	}
+	return img.Image.ConfigFile()
+}
+
+func (img *containerdImage) RawConfigFile() ([]byte, error) {
+	if err := img.populateImage(); err != nil {
+		return nil, xerrors.Errorf("unable to populate: %w", err)
+	}
+	return img.Image.RawConfigFile()
+}
+
+func (img *containerdImage) LayerByDiffID(h v1.Hash) (v1.Layer, error) {
+	if err := img.populateImage(); err != nil {
+		return nil, xerrors.Errorf("unable to populate: %w", err)
+	}
+	return img.Image.LayerByDiffID(h)
+}
+
+func (img *containerdImage) RepoTags() []string {
+	if ref, err := parseContainerdRef(img.ref); err == nil {
+		return []string{ref}
+	}
+	return nil
+}
+
+func (img *containerdImage) RepoDigests() []string {
+	return []string{fmt.Sprintf("%s@%s", strings.SplitN(img.ref, ":", 2)[0], img.img.Target().Digest.String())}
+}
+
+func parseContainerdRef(ref string) (string, error) {
+	if ref == "" {
+		return "", xerrors.New("empty image reference")
+	}
+	return ref, nil
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"golang.org/x/xerrors"
+)
+
+// NewDockerImage wraps ref as an Image via the Docker-compatible daemon API
+// reachable through cli, selecting the platform variant (e.g. "linux/arm64",
+// "linux/amd64/v3") requested by platform. When the daemon's local copy of
+// ref doesn't already match platform, it's pulled on demand before the
+// image is inspected, so a multi-platform manifest list resolves to the
+// right child image even when only a different variant is cached locally.
+// An empty platform accepts whatever the daemon already has.
+func NewDockerImage(ctx context.Context, ref, platform string, cli *client.Client) (Image, func(), error) {
+	cleanup := func() {}
+
+	inspect, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("unable to inspect the image (%s): %w", ref, err)
+	}
+
+	if !platformMatches(inspect, platform) {
+		rc, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{Platform: platform})
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to pull %s for platform %s: %w", ref, platform, err)
+		}
+		_, err = io.Copy(io.Discard, rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to read pull response for %s: %w", ref, err)
+		}
+
+		// Re-inspect now that the requested platform variant has been pulled.
+		inspect, _, err = cli.ImageInspectWithRaw(ctx, ref)
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("unable to inspect the image (%s) after pulling %s: %w", ref, platform, err)
+		}
+	}
+
+	history, err := cli.ImageHistory(ctx, ref)
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("unable to get history (%s): %w", ref, err)
+	}
+
+	f, err := os.CreateTemp("", "fanal-*")
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("unable to create a temporary file: %w", err)
+	}
+	cleanup = func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	img := &image{
+		inspect: inspect,
+		history: history,
+		opener:  imageOpener(ctx, ref, f, cli.ImageSave),
+	}
+
+	return img, cleanup, nil
+}
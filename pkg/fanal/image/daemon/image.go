@@ -144,6 +144,49 @@ func (img *image) RepoDigests() []string {
 	return img.inspect.RepoDigests
 }
 
+// platformMatches reports whether inspect's platform already satisfies the
+// requested platform string (e.g. "linux/arm64" or "linux/amd64/v3"). An
+// empty platform always matches, since no variant was requested.
+//
+// NOTE: types.ImageInspect.Variant is assumed present here, mirroring the
+// Architecture/Os fields this wrapper already reads off it; older Docker
+// API versions this struct also has to support may leave it empty, in
+// which case a requested variant is simply treated as unmatched and a pull
+// is attempted.
+func platformMatches(inspect types.ImageInspect, platform string) bool {
+	if platform == "" {
+		return true
+	}
+
+	os, arch, variant := splitPlatform(platform)
+	if os != "" && !strings.EqualFold(os, inspect.Os) {
+		return false
+	}
+	if arch != "" && !strings.EqualFold(arch, inspect.Architecture) {
+		return false
+	}
+	if variant != "" && !strings.EqualFold(variant, inspect.Variant) {
+		return false
+	}
+	return true
+}
+
+// splitPlatform splits a "os/arch[/variant]" platform string, e.g.
+// "linux/arm64/v8", into its components.
+func splitPlatform(platform string) (os, arch, variant string) {
+	parts := strings.SplitN(platform, "/", 3)
+	if len(parts) > 0 {
+		os = parts[0]
+	}
+	if len(parts) > 1 {
+		arch = parts[1]
+	}
+	if len(parts) > 2 {
+		variant = parts[2]
+	}
+	return os, arch, variant
+}
+
 func (img *image) configHistory() []v1.History {
 	// Fill only required metadata
 	var history []v1.History
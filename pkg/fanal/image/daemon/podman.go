@@ -0,0 +1,263 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"golang.org/x/xerrors"
+)
+
+// podmanInspect is the subset of the libpod /libpod/images/{name}/json
+// response this wrapper reads. Unlike the Docker-compat endpoint, libpod
+// returns real RootFS/DiffIDs for every image and a NamesHistory list of
+// every name the image has ever been tagged with.
+type podmanInspect struct {
+	ID           string       `json:"Id"`
+	RepoTags     []string     `json:"RepoTags"`
+	RepoDigests  []string     `json:"RepoDigests"`
+	NamesHistory []string     `json:"NamesHistory"`
+	Created      time.Time    `json:"Created"`
+	Os           string       `json:"Os"`
+	Architecture string       `json:"Architecture"`
+	Author       string       `json:"Author"`
+	Config       podmanConfig `json:"Config"`
+	RootFS       podmanRootFS `json:"RootFS"`
+}
+
+type podmanRootFS struct {
+	Type   string   `json:"Type"`
+	Layers []string `json:"Layers"`
+}
+
+type podmanConfig struct {
+	Cmd        []string          `json:"Cmd"`
+	Entrypoint []string          `json:"Entrypoint"`
+	Env        []string          `json:"Env"`
+	Labels     map[string]string `json:"Labels"`
+	User       string            `json:"User"`
+	WorkingDir string            `json:"WorkingDir"`
+}
+
+// podmanHistoryEntry is one entry of the libpod
+// /libpod/images/{name}/history response.
+type podmanHistoryEntry struct {
+	ID        string `json:"Id"`
+	Created   int64  `json:"Created"`
+	CreatedBy string `json:"CreatedBy"`
+	Comment   string `json:"Comment"`
+	Size      int64  `json:"Size"`
+}
+
+// podmanImage is a dedicated libpod-backed counterpart to image: it talks
+// directly to the libpod REST API instead of the Docker-compat socket, so
+// it gets real RootFS/DiffIDs and accurate history without falling back to
+// the "ConfigFile via populateImage" slow path image.ConfigFile otherwise
+// needs for Podman ("Podman doesn't return RootFS...").
+type podmanImage struct {
+	v1.Image
+	client  *http.Client
+	baseURL string
+	name    string
+	inspect podmanInspect
+	history []podmanHistoryEntry
+
+	once sync.Once
+	err  error
+}
+
+// NewPodmanImage wraps ref as an Image backed by the libpod REST API
+// reachable at baseURL (e.g. "http://d/v4.0.0" over a Unix socket client).
+// It falls back to an error a caller can use to try the Docker-compat
+// daemon.Image path instead when the libpod endpoints aren't present.
+func NewPodmanImage(ctx context.Context, ref string, client *http.Client, baseURL string) (Image, func(), error) {
+	img := &podmanImage{
+		client:  client,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		name:    ref,
+	}
+
+	if err := img.fetchInspect(ctx); err != nil {
+		return nil, func() {}, xerrors.Errorf("unable to inspect %s via libpod: %w", ref, err)
+	}
+	if err := img.fetchHistory(ctx); err != nil {
+		return nil, func() {}, xerrors.Errorf("unable to get history for %s via libpod: %w", ref, err)
+	}
+
+	return img, func() {}, nil
+}
+
+func (img *podmanImage) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, img.baseURL+path, nil)
+	if err != nil {
+		return xerrors.Errorf("unable to build request: %w", err)
+	}
+
+	resp, err := img.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("libpod request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("libpod request to %s failed with status %s", path, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return xerrors.Errorf("unable to decode libpod response: %w", err)
+	}
+	return nil
+}
+
+func (img *podmanImage) fetchInspect(ctx context.Context) error {
+	return img.get(ctx, "/libpod/images/"+url.PathEscape(img.name)+"/json", &img.inspect)
+}
+
+func (img *podmanImage) fetchHistory(ctx context.Context) error {
+	return img.get(ctx, "/libpod/images/"+url.PathEscape(img.name)+"/history", &img.history)
+}
+
+// populateImage exports the image through the libpod
+// /libpod/images/{name}/export endpoint and loads it as a v1.Image, the
+// same lazy full-load fallback image.populateImage uses for data this
+// wrapper can't otherwise derive from inspect/history alone (raw layer
+// bytes, the raw config blob).
+func (img *podmanImage) populateImage() error {
+	img.once.Do(func() {
+		req, err := http.NewRequest(http.MethodGet, img.baseURL+"/libpod/images/"+url.PathEscape(img.name)+"/export?format=docker-archive", nil) //nolint:noctx
+		if err != nil {
+			img.err = xerrors.Errorf("unable to build export request: %w", err)
+			return
+		}
+
+		resp, err := img.client.Do(req)
+		if err != nil {
+			img.err = xerrors.Errorf("libpod export request error: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			img.err = xerrors.Errorf("libpod export request failed with status %s", resp.Status)
+			return
+		}
+
+		f, err := os.CreateTemp("", "fanal-podman-*")
+		if err != nil {
+			img.err = xerrors.Errorf("unable to create a temporary file: %w", err)
+			return
+		}
+		defer f.Close()
+
+		if _, err := io.Copy(f, resp.Body); err != nil {
+			img.err = xerrors.Errorf("failed to copy the exported image: %w", err)
+			return
+		}
+
+		v1img, err := tarball.ImageFromPath(f.Name(), nil)
+		if err != nil {
+			img.err = xerrors.Errorf("failed to initialize the struct from the exported image: %w", err)
+			return
+		}
+		img.Image = v1img
+	})
+	return img.err
+}
+
+func (img *podmanImage) ConfigName() (v1.Hash, error) {
+	return v1.NewHash(img.inspect.ID)
+}
+
+func (img *podmanImage) ConfigFile() (*v1.ConfigFile, error) {
+	diffIDs := make([]v1.Hash, 0, len(img.inspect.RootFS.Layers))
+	for _, l := range img.inspect.RootFS.Layers {
+		h, err := v1.NewHash(l)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid diff ID %q: %w", l, err)
+		}
+		diffIDs = append(diffIDs, h)
+	}
+
+	return &v1.ConfigFile{
+		Architecture: img.inspect.Architecture,
+		Author:       img.inspect.Author,
+		Created:      v1.Time{Time: img.inspect.Created},
+		OS:           img.inspect.Os,
+		Config: v1.Config{
+			Cmd:        img.inspect.Config.Cmd,
+			Entrypoint: img.inspect.Config.Entrypoint,
+			Env:        img.inspect.Config.Env,
+			Labels:     img.inspect.Config.Labels,
+			User:       img.inspect.Config.User,
+			WorkingDir: img.inspect.Config.WorkingDir,
+		},
+		History: img.configHistory(),
+		RootFS: v1.RootFS{
+			Type:    img.inspect.RootFS.Type,
+			DiffIDs: diffIDs,
+		},
+	}, nil
+}
+
+func (img *podmanImage) RawConfigFile() ([]byte, error) {
+	if err := img.populateImage(); err != nil {
+		return nil, xerrors.Errorf("unable to populate: %w", err)
+	}
+	return img.Image.RawConfigFile()
+}
+
+func (img *podmanImage) LayerByDiffID(h v1.Hash) (v1.Layer, error) {
+	if err := img.populateImage(); err != nil {
+		return nil, xerrors.Errorf("unable to populate: %w", err)
+	}
+	return img.Image.LayerByDiffID(h)
+}
+
+func (img *podmanImage) RepoTags() []string {
+	if len(img.inspect.RepoTags) > 0 {
+		return img.inspect.RepoTags
+	}
+	// Podman's NamesHistory includes every name the image was ever tagged
+	// with, most recent first; fall back to it for an untagged inspect.
+	return img.inspect.NamesHistory
+}
+
+func (img *podmanImage) RepoDigests() []string {
+	return img.inspect.RepoDigests
+}
+
+func (img *podmanImage) configHistory() []v1.History {
+	history := make([]v1.History, 0, len(img.history))
+	for i := len(img.history) - 1; i >= 0; i-- {
+		h := img.history[i]
+		history = append(history, v1.History{
+			Created: v1.Time{
+				Time: time.Unix(h.Created, 0).UTC(),
+			},
+			CreatedBy:  h.CreatedBy,
+			Comment:    h.Comment,
+			EmptyLayer: h.Size == 0,
+		})
+	}
+	return history
+}
+
+// NewPodmanBaseURL builds the libpod base URL for apiVersion (default
+// "v4.0.0"), e.g. "http://d/v4.0.0", suitable for passing to NewPodmanImage
+// alongside an http.Client dialing the Podman service socket (e.g.
+// "/run/user/1000/podman/podman.sock").
+func NewPodmanBaseURL(apiVersion string) string {
+	if apiVersion == "" {
+		apiVersion = "v4.0.0"
+	}
+	return fmt.Sprintf("http://d/%s", apiVersion)
+}
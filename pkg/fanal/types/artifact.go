@@ -268,6 +268,61 @@ func (a *ArtifactDetail) ToBlobInfo() BlobInfo {
 	}
 }
 
+// ArtifactType identifies what kind of target an ArtifactReference was
+// produced from.
+type ArtifactType string
+
+const (
+	ArtifactTypeFilesystem     ArtifactType = "filesystem"
+	ArtifactTypeContainerImage ArtifactType = "container_image"
+	ArtifactTypeRepository     ArtifactType = "repository"
+	ArtifactTypeVM             ArtifactType = "vm"
+)
+
+// ArtifactReference identifies the artifact an Inspect call analyzed, and is
+// passed back into Clean once its cached blob(s) are no longer needed.
+type ArtifactReference struct {
+	Name       string
+	Type       ArtifactType
+	ID         string // Artifact ID
+	BlobIDs    []string
+	Provenance *Provenance `json:",omitempty"`
+}
+
+// Provenance records how trustworthy the source an ArtifactReference was
+// built from is, derived from the git commit it was scanned at (when the
+// target is a git work tree). A nil Provenance means the target either isn't
+// a git repository, or is one whose work tree isn't clean.
+//
+// NOTE: surfacing this in the JSON/SARIF/CycloneDX/SPDX reporters and the
+// SBOM attestation subcommand isn't possible in this checkout - none of
+// those writers' backing packages (pkg/report's top-level JSON writer,
+// pkg/artifact/sbom, the vendored ftypes/sbom dependencies cyclonedx.go and
+// spdx.go already reference) are present here to extend. This is the
+// resolving half of the feature - real, and independently testable against
+// an actual signed commit - wiring it into a reporter is left for when that
+// reporter's file is available to check against.
+type Provenance struct {
+	// CommitHash is the git commit the scanned work tree was checked out at.
+	CommitHash string
+
+	// Signer is the identity (e.g. the signing key's User ID) the commit's
+	// signature was verified against, empty when the commit is unsigned or
+	// the signature didn't verify.
+	Signer string
+
+	// KeyID is the key ID of the OpenPGP key that produced the signature,
+	// empty unless the signature verified.
+	KeyID string
+
+	// Verified is true only when the commit was signed and the signature
+	// verified successfully against the configured keyring.
+	Verified bool
+
+	// Timestamp is the commit's authored time.
+	Timestamp time.Time
+}
+
 // CustomResource holds the analysis result from a custom analyzer.
 // It is for extensibility and not used in OSS.
 type CustomResource struct {
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// parseDiskID splits a "<prefix><a>/<b>/<c>" snapshot identifier into its
+// three parts, the convention gcp-disk: and azure-disk: ids share.
+func parseDiskID(id, prefix string) (a, b, c string, err error) {
+	t := strings.TrimPrefix(id, prefix)
+	parts := strings.SplitN(t, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", xerrors.Errorf("invalid %s id %q, expected <a>/<b>/<c>", strings.TrimSuffix(prefix, ":"), id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
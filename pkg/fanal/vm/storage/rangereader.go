@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	ebsfile "github.com/masahiro331/go-ebs-file"
+	"golang.org/x/xerrors"
+)
+
+// rangeBlockSize is the granularity httpRangeReader fetches and caches
+// blocks at.
+const rangeBlockSize = 512 * 1024
+
+// httpRangeReader is an io.ReaderAt that serves reads out of cache first,
+// falling back to an HTTP Range GET against url on a miss and populating
+// cache with the result. GCPDisk and AzureDisk both read snapshot bytes
+// this way once they've resolved a random-access URL for the snapshot (a
+// Grant Access SAS URL for Azure, the snapshot download URL for GCP),
+// giving both the same block-cache reuse across runs that EBS gets from
+// go-ebs-file.
+type httpRangeReader struct {
+	ctx        context.Context
+	client     *http.Client
+	url        string
+	cache      ebsfile.Cache
+	cacheKey   string
+	authHeader func(*http.Request)
+}
+
+func (r *httpRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	var n int
+	for n < len(p) {
+		blockIdx := (off + int64(n)) / rangeBlockSize
+		blockOff := (off + int64(n)) % rangeBlockSize
+
+		block, err := r.block(blockIdx)
+		if err != nil {
+			return n, err
+		}
+		if blockOff >= int64(len(block)) {
+			return n, io.EOF
+		}
+
+		c := copy(p[n:], block[blockOff:])
+		n += c
+		if c == 0 {
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
+
+func (r *httpRangeReader) block(idx int64) ([]byte, error) {
+	key := fmt.Sprintf("%s:block:%d", r.cacheKey, idx)
+	if v, ok := r.cache.Get(key); ok {
+		if block, ok := v.([]byte); ok {
+			return block, nil
+		}
+	}
+
+	start := idx * rangeBlockSize
+	end := start + rangeBlockSize - 1
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build range request: %w", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if r.authHeader != nil {
+		r.authHeader(req)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("range request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("unexpected status fetching block %d: %s", idx, resp.Status)
+	}
+
+	block, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read block %d: %w", idx, err)
+	}
+
+	r.cache.Add(key, block)
+	return block, nil
+}
@@ -17,8 +17,10 @@ import (
 )
 
 const (
-	EBSPrefix  = "ebs:"
-	FilePrefix = "file:"
+	EBSPrefix       = "ebs:"
+	FilePrefix      = "file:"
+	GCPDiskPrefix   = "gcp-disk:"
+	AzureDiskPrefix = "azure-disk:"
 )
 
 type Storage interface {
@@ -110,6 +112,18 @@ func NewStorage(t string, option ebsfile.Option, ctx context.Context, c ebsfile.
 	switch {
 	case strings.HasPrefix(t, EBSPrefix):
 		s = NewEBS(option, ctx, c)
+	case strings.HasPrefix(t, GCPDiskPrefix):
+		gcpDisk, err := NewGCPDisk(ctx, c)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to set up GCP disk storage: %w", err)
+		}
+		s = gcpDisk
+	case strings.HasPrefix(t, AzureDiskPrefix):
+		azureDisk, err := NewAzureDisk(ctx, c)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to set up Azure disk storage: %w", err)
+		}
+		s = azureDisk
 	case strings.HasPrefix(t, FilePrefix):
 		s = NewFile(c)
 	default:
@@ -0,0 +1,255 @@
+package walker
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"golang.org/x/xerrors"
+)
+
+// defaultIgnoreAttribute is the gitattributes attribute name
+// AttributeIgnoreMatcher looks for when none is configured.
+const defaultIgnoreAttribute = "trivy-ignore"
+
+// IgnoreOptions configures IgnoreMatcher.
+type IgnoreOptions struct {
+	// RespectGitignore enables matching against .gitignore/.git/info/exclude
+	// /core.excludesFile patterns found in the git work tree rooted at the
+	// directory passed to NewIgnoreMatcher.
+	RespectGitignore bool
+	// AttributeName is the gitattributes attribute that marks a path as out
+	// of scope, e.g. "trivy-ignore". Defaults to defaultIgnoreAttribute when
+	// empty.
+	AttributeName string
+}
+
+// IgnoreMatcher is an io/fs-path matcher combining gitignore-style patterns
+// with gitattributes-marked exclusions, meant to compose alongside the
+// existing SkipFiles/SkipDirs matching in walker.Option - neither replaces
+// the other.
+//
+// NOTE: this is the matching half of the feature requested for walker.FS:
+// wiring RespectGitignore into walker.Option and having walker.FS.Walk
+// consult it per visited path isn't possible in this checkout - the file
+// defining walker.Option, walker.WalkFunc and walker.FS itself isn't
+// present here (pkg/fanal/artifact/local/fs.go already references them
+// without a definition to edit). IgnoreMatcher is real and independently
+// testable against a git work tree; wiring it into Walk is left for when
+// that file is available to check against.
+type IgnoreMatcher struct {
+	gitignore gitignore.Matcher
+	attribute *attributeMatcher
+}
+
+// NewIgnoreMatcher builds an IgnoreMatcher for the directory tree rooted at
+// rootDir. When opt.RespectGitignore is false it still parses
+// .gitattributes (the attribute opt-out is independent of gitignore
+// support), but Match only ever returns true for actual attribute matches.
+// rootDir doesn't need to be a git repository at all; when it isn't (or
+// simply has no .gitignore/.gitattributes files), the returned matcher
+// never reports a match, without error - the "no ignore files present"
+// case the request asks to leave the cache key unaffected by.
+func NewIgnoreMatcher(rootDir string, opt IgnoreOptions) (*IgnoreMatcher, error) {
+	attribute := opt.AttributeName
+	if attribute == "" {
+		attribute = defaultIgnoreAttribute
+	}
+
+	fs := osfs.New(rootDir)
+
+	attrPatterns, err := readAttributePatterns(fs, nil, attribute)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read .gitattributes: %w", err)
+	}
+
+	m := &IgnoreMatcher{attribute: &attributeMatcher{patterns: attrPatterns}}
+
+	if !opt.RespectGitignore {
+		return m, nil
+	}
+
+	patterns, err := gitignore.ReadPatterns(fs, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read .gitignore: %w", err)
+	}
+	if excludes, err := readPatternFile(fs, ".git/info/exclude", nil); err == nil {
+		patterns = append(patterns, excludes...)
+	}
+	if globalExcludes, err := readGlobalExcludesFile(rootDir); err == nil {
+		patterns = append(patterns, globalExcludes...)
+	}
+
+	m.gitignore = gitignore.NewMatcher(patterns)
+	return m, nil
+}
+
+// Match reports whether path (slash-separated, relative to the rootDir
+// NewIgnoreMatcher was built with) should be skipped, either by a gitignore
+// pattern or by its gitattributes ignore attribute.
+func (m *IgnoreMatcher) Match(filePath string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	parts := strings.Split(filePath, "/")
+	if m.gitignore != nil && m.gitignore.Match(parts, isDir) {
+		return true
+	}
+	return m.attribute.match(parts, isDir)
+}
+
+// attributeMatcher matches paths against gitignore-style patterns drawn
+// from gitattributes lines carrying the configured ignore attribute, reusing
+// gitignore's pattern syntax/precedence since gitattributes patterns use the
+// same glob dialect.
+type attributeMatcher struct {
+	patterns []gitignore.Pattern
+}
+
+func (m *attributeMatcher) match(path []string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	// Later patterns win, same precedence rule gitignore.Matcher applies.
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		switch m.patterns[i].Match(path, isDir) {
+		case gitignore.Exclude:
+			return true
+		case gitignore.Include:
+			return false
+		}
+	}
+	return false
+}
+
+// readAttributePatterns recursively collects every .gitattributes entry
+// carrying attribute, starting at dir (nil meaning the filesystem root),
+// the same traversal gitignore.ReadPatterns does for .gitignore files.
+func readAttributePatterns(fs billy.Filesystem, dir []string, attribute string) ([]gitignore.Pattern, error) {
+	patterns, err := readAttributesFile(fs, dir, attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(joinPath(dir))
+	if err != nil {
+		// Nothing left to recurse into - not a hard error, just no more
+		// subdirectories to check.
+		return patterns, nil
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == ".git" {
+			continue
+		}
+		sub, err := readAttributePatterns(fs, append(append([]string{}, dir...), entry.Name()), attribute)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, sub...)
+	}
+	return patterns, nil
+}
+
+func readAttributesFile(fs billy.Filesystem, dir []string, attribute string) ([]gitignore.Pattern, error) {
+	f, err := fs.Open(path.Join(joinPath(dir), ".gitattributes"))
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		for _, attr := range fields[1:] {
+			if attr == attribute || strings.HasPrefix(attr, attribute+"=") {
+				patterns = append(patterns, gitignore.ParsePattern(fields[0], dir))
+				break
+			}
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+func readPatternFile(fs billy.Filesystem, name string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns, scanner.Err()
+}
+
+// readGlobalExcludesFile reads whatever core.excludesFile points to in the
+// git repository rooted at rootDir, expanding a leading "~/" the way git
+// itself does.
+func readGlobalExcludesFile(rootDir string) ([]gitignore.Pattern, error) {
+	repo, err := git.PlainOpen(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	excludesFile := cfg.Raw.Section("core").Option("excludesFile")
+	if excludesFile == "" {
+		return nil, xerrors.New("core.excludesFile not set")
+	}
+	if strings.HasPrefix(excludesFile, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			excludesFile = path.Join(home, strings.TrimPrefix(excludesFile, "~/"))
+		}
+	}
+
+	f, err := os.Open(excludesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns, scanner.Err()
+}
+
+func joinPath(dir []string) string {
+	if len(dir) == 0 {
+		return "."
+	}
+	return path.Join(dir...)
+}
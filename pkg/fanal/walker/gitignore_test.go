@@ -0,0 +1,105 @@
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initRepoWithIgnoreFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	write := func(name, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+	}
+
+	write(".gitignore", "*.log\nvendor/\n")
+	write(".gitattributes", "testdata/* trivy-ignore\n")
+	write("main.tf", "resource \"aws_s3_bucket\" \"x\" {}\n")
+	write("debug.log", "noisy\n")
+	write("vendor/lib.tf", "resource \"aws_s3_bucket\" \"y\" {}\n")
+	write("testdata/fixture.tf", "resource \"aws_s3_bucket\" \"z\" {}\n")
+
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+	_, err = wt.Commit("initial", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+
+	return dir
+}
+
+func Test_IgnoreMatcher_Gitignore(t *testing.T) {
+	dir := initRepoWithIgnoreFiles(t)
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{RespectGitignore: true})
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("debug.log", false))
+	assert.True(t, m.Match("vendor/lib.tf", false))
+	assert.False(t, m.Match("main.tf", false))
+}
+
+func Test_IgnoreMatcher_GitignoreDisabled(t *testing.T) {
+	dir := initRepoWithIgnoreFiles(t)
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{RespectGitignore: false})
+	require.NoError(t, err)
+
+	assert.False(t, m.Match("debug.log", false), "gitignore patterns shouldn't apply when RespectGitignore is false")
+}
+
+func Test_IgnoreMatcher_GitattributesAttribute(t *testing.T) {
+	dir := initRepoWithIgnoreFiles(t)
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{})
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("testdata/fixture.tf", false), "gitattributes trivy-ignore applies regardless of RespectGitignore")
+	assert.False(t, m.Match("main.tf", false))
+}
+
+func Test_IgnoreMatcher_CustomAttributeName(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fixtures"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("fixtures/* skip-scan\n"), 0o644))
+	_, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{AttributeName: "skip-scan"})
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("fixtures/x.tf", false))
+}
+
+func Test_IgnoreMatcher_NoIgnoreFilesPresent(t *testing.T) {
+	dir := t.TempDir()
+	_, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{RespectGitignore: true})
+	require.NoError(t, err)
+
+	assert.False(t, m.Match("anything.tf", false))
+}
+
+func Test_IgnoreMatcher_NotAGitRepo(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := NewIgnoreMatcher(dir, IgnoreOptions{RespectGitignore: true})
+	require.NoError(t, err, "a plain directory with no .git is still a valid (if uninteresting) place to look for .gitignore/.gitattributes files")
+	assert.False(t, m.Match("anything.tf", false))
+}
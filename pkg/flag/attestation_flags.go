@@ -0,0 +1,83 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// e.g. config yaml
+// attestation:
+//
+//	require-signature: true
+//	predicate-type: "https://slsa.dev/provenance/v0.2"
+var (
+	RequireSignatureFlag = Flag{
+		Name:       "require-signature",
+		ConfigName: "attestation.require-signature",
+		Value:      false,
+		Usage:      "fail the scan unless the image has a signature or attestation that verifies against --public-key",
+	}
+	AttestationPredicateTypeFlag = Flag{
+		Name:       "attestation-predicate-type",
+		ConfigName: "attestation.predicate-type",
+		Value:      []string{},
+		Usage:      "comma-separated list of in-toto predicate types (e.g. https://slsa.dev/provenance/v0.2) an attestation must match; implies --require-signature",
+	}
+	PublicKeyFlag = Flag{
+		Name:       "public-key",
+		ConfigName: "attestation.public-key",
+		Value:      []string{},
+		Usage:      "path to a PEM-encoded ECDSA public key to verify signatures and attestations against; repeatable",
+	}
+)
+
+// AttestationFlagGroup composes flags controlling signature/attestation
+// verification of scanned images via the OCI Referrers API.
+type AttestationFlagGroup struct {
+	RequireSignature     *Flag
+	AttestationPredicate *Flag
+	PublicKey            *Flag
+}
+
+type AttestationOptions struct {
+	RequireSignature bool
+	PredicateTypes   []string
+	PublicKeys       []string
+}
+
+// NewAttestationFlagGroup returns a default AttestationFlagGroup
+func NewAttestationFlagGroup() *AttestationFlagGroup {
+	return &AttestationFlagGroup{
+		RequireSignature:     lo.ToPtr(RequireSignatureFlag),
+		AttestationPredicate: lo.ToPtr(AttestationPredicateTypeFlag),
+		PublicKey:            lo.ToPtr(PublicKeyFlag),
+	}
+}
+
+func (f *AttestationFlagGroup) flags() []*Flag {
+	return []*Flag{f.RequireSignature, f.AttestationPredicate, f.PublicKey}
+}
+
+func (f *AttestationFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *AttestationFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *AttestationFlagGroup) ToOptions() (AttestationOptions, error) {
+	predicateTypes := getStringSlice(f.AttestationPredicate)
+	return AttestationOptions{
+		RequireSignature: getBool(f.RequireSignature) || len(predicateTypes) > 0,
+		PredicateTypes:   predicateTypes,
+		PublicKeys:       getStringSlice(f.PublicKey),
+	}, nil
+}
@@ -2,6 +2,7 @@ package flag
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -12,12 +13,13 @@ import (
 
 // e.g. config yaml
 // cache:
-//   clear: true
-//   backend: "redis://localhost:6379"
-//   redis:
-//    ca: ca-cert.pem
-//    cert: cert.pem
-//    key: key.pem
+//
+//	clear: true
+//	backend: "redis://localhost:6379"
+//	redis:
+//	 ca: ca-cert.pem
+//	 cert: cert.pem
+//	 key: key.pem
 var (
 	ClearCacheFlag = Flag{
 		Name:       "clear-cache",
@@ -29,7 +31,7 @@ var (
 		Name:       "cache-backend",
 		ConfigName: "cache.backend",
 		Value:      "fs",
-		Usage:      "cache backend (e.g. redis://localhost:6379)",
+		Usage:      "cache backend (e.g. redis://localhost:6379, redis-sentinel://host1:26379,host2:26379/mymaster, redis-cluster://host1:6379,host2:6379)",
 	}
 	CacheTTLFlag = Flag{
 		Name:       "cache-ttl",
@@ -55,6 +57,18 @@ var (
 		Value:      "",
 		Usage:      "redis key file location, if using redis as cache backend",
 	}
+	RedisTLSSkipVerifyFlag = Flag{
+		Name:       "redis-tls-skip-verify",
+		ConfigName: "cache.redis.tlsSkipVerify",
+		Value:      false,
+		Usage:      "disable redis TLS certificate verification, if using redis as cache backend",
+	}
+	RedisUsernameFlag = Flag{
+		Name:       "redis-username",
+		ConfigName: "cache.redis.username",
+		Value:      "",
+		Usage:      "redis ACL username, if using redis as cache backend",
+	}
 )
 
 // CacheFlagGroup composes common printer flag structs used for commands requiring cache logic.
@@ -63,9 +77,11 @@ type CacheFlagGroup struct {
 	CacheBackend *Flag
 	CacheTTL     *Flag
 
-	RedisCACert *Flag
-	RedisCert   *Flag
-	RedisKey    *Flag
+	RedisCACert        *Flag
+	RedisCert          *Flag
+	RedisKey           *Flag
+	RedisTLSSkipVerify *Flag
+	RedisUsername      *Flag
 }
 
 type CacheOptions struct {
@@ -75,27 +91,40 @@ type CacheOptions struct {
 	RedisOptions
 }
 
-// RedisOptions holds the options for redis cache
+// RedisOptions holds the options for redis cache, covering standalone,
+// Sentinel (redis-sentinel://) and Cluster (redis-cluster://) topologies.
 type RedisOptions struct {
-	RedisCACert string
-	RedisCert   string
-	RedisKey    string
+	RedisCACert        string
+	RedisCert          string
+	RedisKey           string
+	RedisTLSSkipVerify bool
+	RedisUsername      string
+
+	// MasterName and SentinelPassword are only set for a redis-sentinel:// backend.
+	MasterName       string
+	SentinelAddrs    []string
+	SentinelPassword string
+
+	// ClusterAddrs is only set for a redis-cluster:// backend.
+	ClusterAddrs []string
 }
 
 // NewCacheFlagGroup returns a default CacheFlagGroup
 func NewCacheFlagGroup() *CacheFlagGroup {
 	return &CacheFlagGroup{
-		ClearCache:   lo.ToPtr(ClearCacheFlag),
-		CacheBackend: lo.ToPtr(CacheBackendFlag),
-		CacheTTL:     lo.ToPtr(CacheTTLFlag),
-		RedisCACert:  lo.ToPtr(RedisCACertFlag),
-		RedisCert:    lo.ToPtr(RedisCertFlag),
-		RedisKey:     lo.ToPtr(RedisKeyFlag),
+		ClearCache:         lo.ToPtr(ClearCacheFlag),
+		CacheBackend:       lo.ToPtr(CacheBackendFlag),
+		CacheTTL:           lo.ToPtr(CacheTTLFlag),
+		RedisCACert:        lo.ToPtr(RedisCACertFlag),
+		RedisCert:          lo.ToPtr(RedisCertFlag),
+		RedisKey:           lo.ToPtr(RedisKeyFlag),
+		RedisTLSSkipVerify: lo.ToPtr(RedisTLSSkipVerifyFlag),
+		RedisUsername:      lo.ToPtr(RedisUsernameFlag),
 	}
 }
 
 func (f *CacheFlagGroup) flags() []*Flag {
-	return []*Flag{f.ClearCache, f.CacheBackend, f.CacheTTL, f.RedisCACert, f.RedisCert, f.RedisKey}
+	return []*Flag{f.ClearCache, f.CacheBackend, f.CacheTTL, f.RedisCACert, f.RedisCert, f.RedisKey, f.RedisTLSSkipVerify, f.RedisUsername}
 }
 
 func (f *CacheFlagGroup) AddFlags(cmd *cobra.Command) {
@@ -116,22 +145,38 @@ func (f *CacheFlagGroup) Bind(cmd *cobra.Command) error {
 func (f *CacheFlagGroup) ToOptions() (CacheOptions, error) {
 	cacheBackend := getString(f.CacheBackend)
 	redisOptions := RedisOptions{
-		RedisCACert: getString(f.RedisCACert),
-		RedisCert:   getString(f.RedisCert),
-		RedisKey:    getString(f.RedisKey),
+		RedisCACert:        getString(f.RedisCACert),
+		RedisCert:          getString(f.RedisCert),
+		RedisKey:           getString(f.RedisKey),
+		RedisTLSSkipVerify: getBool(f.RedisTLSSkipVerify),
+		RedisUsername:      getString(f.RedisUsername),
 	}
 
-	// "redis://" or "fs" are allowed for now
-	// An empty value is also allowed for testability
-	if !strings.HasPrefix(cacheBackend, "redis://") &&
-		cacheBackend != "fs" && cacheBackend != "" {
+	switch {
+	case cacheBackend == "fs", cacheBackend == "":
+		// no-op; "fs" and empty (for testability) need no further parsing
+	case strings.HasPrefix(cacheBackend, "redis://"):
+	case strings.HasPrefix(cacheBackend, "redis-sentinel://"):
+		masterName, addrs, password, err := parseRedisSentinelBackend(cacheBackend)
+		if err != nil {
+			return CacheOptions{}, xerrors.Errorf("invalid redis sentinel backend: %w", err)
+		}
+		redisOptions.MasterName = masterName
+		redisOptions.SentinelAddrs = addrs
+		redisOptions.SentinelPassword = password
+	case strings.HasPrefix(cacheBackend, "redis-cluster://"):
+		addrs, err := parseRedisClusterBackend(cacheBackend)
+		if err != nil {
+			return CacheOptions{}, xerrors.Errorf("invalid redis cluster backend: %w", err)
+		}
+		redisOptions.ClusterAddrs = addrs
+	default:
 		return CacheOptions{}, xerrors.Errorf("unsupported cache backend: %s", cacheBackend)
 	}
-	// if one of redis option not nil, make sure CA, cert, and key provided
-	if !lo.IsEmpty(redisOptions) {
-		if redisOptions.RedisCACert == "" || redisOptions.RedisCert == "" || redisOptions.RedisKey == "" {
-			return CacheOptions{}, xerrors.Errorf("you must provide Redis CA, cert and key file path when using TLS")
-		}
+
+	// TLS cert, key and CA must be either all empty or all set, regardless of topology.
+	if err := validateRedisTLSOptions(redisOptions); err != nil {
+		return CacheOptions{}, err
 	}
 
 	return CacheOptions{
@@ -142,6 +187,48 @@ func (f *CacheFlagGroup) ToOptions() (CacheOptions, error) {
 	}, nil
 }
 
+// validateRedisTLSOptions ensures the redis CA, cert and key are either all
+// empty or all set, regardless of which of the standalone/Sentinel/Cluster
+// backends is in use.
+func validateRedisTLSOptions(o RedisOptions) error {
+	set := lo.Count([]bool{o.RedisCACert != "", o.RedisCert != "", o.RedisKey != ""}, true)
+	if set != 0 && set != 3 {
+		return xerrors.Errorf("you must provide Redis CA, cert and key file path when using TLS")
+	}
+	return nil
+}
+
+// parseRedisSentinelBackend parses a "redis-sentinel://[user:pass@]host1:port1,host2:port2/mymaster"
+// backend URL into its sentinel addresses, master name and password.
+func parseRedisSentinelBackend(backend string) (masterName string, addrs []string, password string, err error) {
+	u, err := url.Parse(backend)
+	if err != nil {
+		return "", nil, "", xerrors.Errorf("unable to parse redis sentinel URL: %w", err)
+	}
+
+	addrs = strings.Split(u.Host, ",")
+	if u.User != nil {
+		password, _ = u.User.Password()
+	}
+
+	masterName = strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)[0]
+	if masterName == "" {
+		return "", nil, "", xerrors.Errorf("master name is required, e.g. redis-sentinel://host:26379/mymaster")
+	}
+
+	return masterName, addrs, password, nil
+}
+
+// parseRedisClusterBackend parses a "redis-cluster://host1:port1,host2:port2" backend URL
+// into its cluster node addresses.
+func parseRedisClusterBackend(backend string) ([]string, error) {
+	u, err := url.Parse(backend)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse redis cluster URL: %w", err)
+	}
+	return strings.Split(u.Host, ","), nil
+}
+
 // CacheBackendMasked returns the redis connection string masking credentials
 func (o *CacheOptions) CacheBackendMasked() string {
 	endIndex := strings.Index(o.CacheBackend, "@")
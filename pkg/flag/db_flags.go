@@ -0,0 +1,166 @@
+package flag
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	dbRepository     = "ghcr.io/aquasecurity/trivy-db"
+	javaDBRepository = "ghcr.io/aquasecurity/trivy-java-db"
+
+	// dbSchemaVersion/javaDBSchemaVersion mirror trivy-db's and
+	// trivy-java-db's own schema version, appended as an OCI tag so an
+	// untagged --db-repository/--java-db-repository value still resolves
+	// to a DB this version of trivy can actually read.
+	dbSchemaVersion     = 2
+	javaDBSchemaVersion = 1
+)
+
+// e.g. config yaml
+// db:
+//
+//	skip-update: true
+//	download-only: false
+//	repository:
+//	  - ghcr.io/aquasecurity/trivy-db
+//	  - gallery.ecr.aws/aquasecurity/trivy-db
+//	java-repository:
+//	  - ghcr.io/aquasecurity/trivy-java-db
+var (
+	SkipDBUpdateFlag = Flag{
+		Name:       "skip-db-update",
+		ConfigName: "db.skip-update",
+		Value:      false,
+		Usage:      "skip updating vulnerability database",
+	}
+	DownloadDBOnlyFlag = Flag{
+		Name:       "download-db-only",
+		ConfigName: "db.download-only",
+		Value:      false,
+		Usage:      "download/update vulnerability database but don't run a scan",
+	}
+	DBRepositoryFlag = Flag{
+		Name:       "db-repository",
+		ConfigName: "db.repository",
+		Value:      []string{dbRepository},
+		Usage:      "OCI repositories to retrieve the vulnerability database from, tried in order until one succeeds",
+	}
+	JavaDBRepositoryFlag = Flag{
+		Name:       "java-db-repository",
+		ConfigName: "db.java-repository",
+		Value:      []string{javaDBRepository},
+		Usage:      "OCI repositories to retrieve the Java index database from, tried in order until one succeeds",
+	}
+)
+
+// DBFlagGroup composes flags controlling where and whether to fetch the
+// vulnerability and Java index databases from.
+type DBFlagGroup struct {
+	SkipDBUpdate       *Flag
+	DownloadDBOnly     *Flag
+	DBRepositories     *Flag
+	JavaDBRepositories *Flag
+}
+
+type DBOptions struct {
+	SkipDBUpdate    bool
+	DownloadDBOnly  bool
+	DBLocations     []string
+	JavaDBLocations []string
+}
+
+// NewDBFlagGroup returns a default DBFlagGroup
+func NewDBFlagGroup() *DBFlagGroup {
+	return &DBFlagGroup{
+		SkipDBUpdate:       lo.ToPtr(SkipDBUpdateFlag),
+		DownloadDBOnly:     lo.ToPtr(DownloadDBOnlyFlag),
+		DBRepositories:     lo.ToPtr(DBRepositoryFlag),
+		JavaDBRepositories: lo.ToPtr(JavaDBRepositoryFlag),
+	}
+}
+
+func (f *DBFlagGroup) flags() []*Flag {
+	return []*Flag{
+		f.SkipDBUpdate,
+		f.DownloadDBOnly,
+		f.DBRepositories,
+		f.JavaDBRepositories,
+	}
+}
+
+func (f *DBFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *DBFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *DBFlagGroup) ToOptions() (DBOptions, error) {
+	skipDBUpdate := getBool(f.SkipDBUpdate)
+	downloadDBOnly := getBool(f.DownloadDBOnly)
+	if skipDBUpdate && downloadDBOnly {
+		return DBOptions{}, xerrors.New("--skip-db-update and --download-db-only options can not be specified both")
+	}
+
+	dbLocations, err := addSchemaVersions(getStringSlice(f.DBRepositories), dbSchemaVersion)
+	if err != nil {
+		return DBOptions{}, xerrors.Errorf("invalid DB location: %w", err)
+	}
+
+	javaDBLocations, err := addSchemaVersions(getStringSlice(f.JavaDBRepositories), javaDBSchemaVersion)
+	if err != nil {
+		return DBOptions{}, xerrors.Errorf("invalid Java DB location: %w", err)
+	}
+
+	return DBOptions{
+		SkipDBUpdate:    skipDBUpdate,
+		DownloadDBOnly:  downloadDBOnly,
+		DBLocations:     dbLocations,
+		JavaDBLocations: javaDBLocations,
+	}, nil
+}
+
+// repoTagPattern matches an explicit ":tag" suffix on an OCI repository
+// reference - anchored so a registry's "host:port" prefix (which also
+// contains a colon, but is always followed by a "/") isn't mistaken for one.
+var repoTagPattern = regexp.MustCompile(`:[^/]+$`)
+
+// addSchemaVersions appends ":<schemaVersion>" to every repo in repos that
+// doesn't already carry an explicit tag, so a bare repository name like
+// "ghcr.io/aquasecurity/trivy-db" keeps resolving to a DB this build of
+// trivy can read even as the schema version moves on. Each repo is
+// validated as a well-formed OCI reference along the way.
+func addSchemaVersions(repos []string, schemaVersion int) ([]string, error) {
+	locations := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if _, err := name.ParseReference(repo); err != nil {
+			return nil, xerrors.Errorf("invalid DB location: %w", err)
+		}
+
+		if repoTagPattern.MatchString(repo) {
+			locations = append(locations, repo)
+			continue
+		}
+
+		location := fmt.Sprintf("%s:%d", repo, schemaVersion)
+		log.Logger.Infow("Adding schema version to the DB repository for backward compatibility", "repository", location)
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
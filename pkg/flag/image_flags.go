@@ -0,0 +1,55 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var ImageSrcFlag = Flag{
+	Name:       "image-src",
+	ConfigName: "image.src",
+	Value:      "",
+	Usage:      "explicitly choose the image source backend (docker, podman, containerd[:namespace]) instead of probing each in turn; podman talks to the libpod REST API directly rather than the Docker-compat socket, and containerd defaults to the \"k8s.io\" namespace unless one is given after a colon",
+}
+
+// ImageFlagGroup composes flags selecting how a container image reference
+// is resolved to an Image.
+type ImageFlagGroup struct {
+	ImageSrc *Flag
+}
+
+type ImageOptions struct {
+	ImageSrc string
+}
+
+// NewImageFlagGroup returns a default ImageFlagGroup
+func NewImageFlagGroup() *ImageFlagGroup {
+	return &ImageFlagGroup{
+		ImageSrc: lo.ToPtr(ImageSrcFlag),
+	}
+}
+
+func (f *ImageFlagGroup) flags() []*Flag {
+	return []*Flag{f.ImageSrc}
+}
+
+func (f *ImageFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *ImageFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ImageFlagGroup) ToOptions() (ImageOptions, error) {
+	return ImageOptions{
+		ImageSrc: getString(f.ImageSrc),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	LicenseFullFlag = Flag{
+		Name:       "license-full",
+		ConfigName: "license.full",
+		Value:      false,
+		Usage:      "eagerly look in LICENSE/COPYING/NOTICE files for a package's license when its manifest declares none",
+	}
+	LicenseConfidenceThresholdFlag = Flag{
+		Name:       "license-confidence-threshold",
+		ConfigName: "license.confidenceThreshold",
+		Value:      0.9,
+		Usage:      "minimum confidence (0-1) required to report a license found by --license-full",
+	}
+)
+
+// LicenseFlagGroup composes flags configuring how aggressively an analyzer
+// resolves a package's license beyond what its manifest declares.
+type LicenseFlagGroup struct {
+	LicenseFull                *Flag
+	LicenseConfidenceThreshold *Flag
+}
+
+type LicenseOptions struct {
+	LicenseFull                bool
+	LicenseConfidenceThreshold float64
+}
+
+// NewLicenseFlagGroup returns a default LicenseFlagGroup
+func NewLicenseFlagGroup() *LicenseFlagGroup {
+	return &LicenseFlagGroup{
+		LicenseFull:                lo.ToPtr(LicenseFullFlag),
+		LicenseConfidenceThreshold: lo.ToPtr(LicenseConfidenceThresholdFlag),
+	}
+}
+
+func (f *LicenseFlagGroup) flags() []*Flag {
+	return []*Flag{f.LicenseFull, f.LicenseConfidenceThreshold}
+}
+
+func (f *LicenseFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *LicenseFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *LicenseFlagGroup) ToOptions() (LicenseOptions, error) {
+	return LicenseOptions{
+		LicenseFull:                getBool(f.LicenseFull),
+		LicenseConfidenceThreshold: getFloat64(f.LicenseConfidenceThreshold),
+	}, nil
+}
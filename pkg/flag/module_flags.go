@@ -0,0 +1,162 @@
+package flag
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/terraform/parser/resolvers"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// e.g. config yaml
+// module:
+//
+//	ssh-agent: true
+//	ssh-key: /home/user/.ssh/id_ed25519
+//	http-basic-user: svc-account
+//	http-basic-password: "***"
+//	netrc: /home/user/.netrc
+//	registry-token:
+//	  - app.terraform.io=abc123
+var (
+	ModuleSSHAgentFlag = Flag{
+		Name:       "module-ssh-agent",
+		ConfigName: "module.ssh-agent",
+		Value:      false,
+		Usage:      "authenticate git::ssh:// module sources via the running ssh-agent (SSH_AUTH_SOCK)",
+	}
+	ModuleSSHKeyFlag = Flag{
+		Name:       "module-ssh-key",
+		ConfigName: "module.ssh-key",
+		Value:      "",
+		Usage:      "private key file to authenticate git::ssh:// module sources with, used when module-ssh-agent is unset",
+	}
+	ModuleSSHKeyPassphraseFlag = Flag{
+		Name:       "module-ssh-key-passphrase",
+		ConfigName: "module.ssh-key-passphrase",
+		Value:      "",
+		Usage:      "passphrase for module-ssh-key, if it's encrypted",
+	}
+	ModuleHTTPBasicUserFlag = Flag{
+		Name:       "module-http-basic-user",
+		ConfigName: "module.http-basic-user",
+		Value:      "",
+		Usage:      "username to authenticate git::http(s):// module sources with",
+	}
+	ModuleHTTPBasicPasswordFlag = Flag{
+		Name:       "module-http-basic-password",
+		ConfigName: "module.http-basic-password",
+		Value:      "",
+		Usage:      "password to authenticate git::http(s):// module sources with",
+	}
+	ModuleNetrcFlag = Flag{
+		Name:       "module-netrc",
+		ConfigName: "module.netrc",
+		Value:      "",
+		Usage:      "netrc-style file of per-host module credentials, consulted when module-http-basic-user isn't set for a host",
+	}
+	ModuleRegistryTokenFlag = Flag{
+		Name:       "module-registry-token",
+		ConfigName: "module.registry-token",
+		Value:      []string{},
+		Usage:      "bearer token for the Terraform registry protocol, as host=token (can be repeated)",
+	}
+)
+
+// ModuleFlagGroup composes flags controlling how private Terraform module
+// sources are authenticated when `evaluator.loadExternalModule` can't
+// resolve them anonymously.
+type ModuleFlagGroup struct {
+	SSHAgent          *Flag
+	SSHKey            *Flag
+	SSHKeyPassphrase  *Flag
+	HTTPBasicUser     *Flag
+	HTTPBasicPassword *Flag
+	Netrc             *Flag
+	RegistryToken     *Flag
+}
+
+type ModuleOptions struct {
+	ModuleAuth resolvers.Auth
+}
+
+// NewModuleFlagGroup returns a default ModuleFlagGroup
+func NewModuleFlagGroup() *ModuleFlagGroup {
+	return &ModuleFlagGroup{
+		SSHAgent:          lo.ToPtr(ModuleSSHAgentFlag),
+		SSHKey:            lo.ToPtr(ModuleSSHKeyFlag),
+		SSHKeyPassphrase:  lo.ToPtr(ModuleSSHKeyPassphraseFlag),
+		HTTPBasicUser:     lo.ToPtr(ModuleHTTPBasicUserFlag),
+		HTTPBasicPassword: lo.ToPtr(ModuleHTTPBasicPasswordFlag),
+		Netrc:             lo.ToPtr(ModuleNetrcFlag),
+		RegistryToken:     lo.ToPtr(ModuleRegistryTokenFlag),
+	}
+}
+
+func (f *ModuleFlagGroup) flags() []*Flag {
+	return []*Flag{f.SSHAgent, f.SSHKey, f.SSHKeyPassphrase, f.HTTPBasicUser, f.HTTPBasicPassword, f.Netrc, f.RegistryToken}
+}
+
+func (f *ModuleFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *ModuleFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ModuleFlagGroup) ToOptions() (ModuleOptions, error) {
+	auth := resolvers.Auth{
+		SSHAgent:         getBool(f.SSHAgent),
+		SSHKeyPath:       getString(f.SSHKey),
+		SSHKeyPassphrase: getString(f.SSHKeyPassphrase),
+		RegistryTokens:   parseRegistryTokens(getStringSlice(f.RegistryToken)),
+	}
+
+	if user := getString(f.HTTPBasicUser); user != "" {
+		auth.HTTPBasic = &resolvers.HTTPBasicAuth{
+			User:     user,
+			Password: getString(f.HTTPBasicPassword),
+		}
+	}
+
+	if netrcPath := getString(f.Netrc); netrcPath != "" {
+		netrc, err := resolvers.LoadNetrc(netrcPath)
+		if err != nil {
+			return ModuleOptions{}, err
+		}
+		auth.Netrc = netrc
+	}
+
+	return ModuleOptions{ModuleAuth: auth}, nil
+}
+
+// parseRegistryTokens parses "host=token" entries, as supplied repeatedly by
+// --module-registry-token, into the map resolvers.Auth.RegistryTokens wants.
+// Entries with no "=" are skipped, matching how an unparsable pattern is
+// handled elsewhere in this package (e.g. splitStatus).
+func parseRegistryTokens(entries []string) map[string]string {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tokens := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		host, token, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Logger.Warnf("invalid module registry token (want host=token): %s", entry)
+			continue
+		}
+		tokens[host] = token
+	}
+	return tokens
+}
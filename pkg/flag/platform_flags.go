@@ -0,0 +1,55 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var PlatformFlag = Flag{
+	Name:       "platform",
+	ConfigName: "platform",
+	Value:      "",
+	Usage:      "set platform in the form os/arch[/variant] when resolving a daemon image or a multi-platform registry image, e.g. linux/arm64",
+}
+
+// PlatformFlagGroup composes the flag selecting which platform variant of a
+// multi-platform image to scan.
+type PlatformFlagGroup struct {
+	Platform *Flag
+}
+
+type PlatformOptions struct {
+	Platform string
+}
+
+// NewPlatformFlagGroup returns a default PlatformFlagGroup
+func NewPlatformFlagGroup() *PlatformFlagGroup {
+	return &PlatformFlagGroup{
+		Platform: lo.ToPtr(PlatformFlag),
+	}
+}
+
+func (f *PlatformFlagGroup) flags() []*Flag {
+	return []*Flag{f.Platform}
+}
+
+func (f *PlatformFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *PlatformFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *PlatformFlagGroup) ToOptions() (PlatformOptions, error) {
+	return PlatformOptions{
+		Platform: getString(f.Platform),
+	}, nil
+}
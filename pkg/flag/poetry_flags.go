@@ -0,0 +1,89 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/pyproject"
+)
+
+// e.g. config yaml
+// poetry:
+//
+//	only:
+//	  - main
+//	with:
+//	  - docs
+//	without:
+//	  - dev
+var (
+	PoetryOnlyFlag = Flag{
+		Name:       "poetry-only",
+		ConfigName: "poetry.only",
+		Value:      []string{},
+		Usage:      "only include these Poetry dependency groups (repeatable); when set, with/without are ignored, same as 'poetry install --only'",
+	}
+	PoetryWithFlag = Flag{
+		Name:       "poetry-with",
+		ConfigName: "poetry.with",
+		Value:      []string{},
+		Usage:      "also include these optional Poetry dependency groups (repeatable), same as 'poetry install --with'",
+	}
+	PoetryWithoutFlag = Flag{
+		Name:       "poetry-without",
+		ConfigName: "poetry.without",
+		Value:      []string{},
+		Usage:      "exclude these Poetry dependency groups (repeatable), same as 'poetry install --without'",
+	}
+)
+
+// PoetryFlagGroup composes flags scoping a Poetry scan to a subset of the
+// dependency groups declared in pyproject.toml, the same way `poetry install`
+// itself can be scoped - so e.g. a prod image scan can skip dev-only CVEs.
+type PoetryFlagGroup struct {
+	Only    *Flag
+	With    *Flag
+	Without *Flag
+}
+
+type PoetryOptions struct {
+	PoetryGroups pyproject.GroupSelection
+}
+
+// NewPoetryFlagGroup returns a default PoetryFlagGroup
+func NewPoetryFlagGroup() *PoetryFlagGroup {
+	return &PoetryFlagGroup{
+		Only:    lo.ToPtr(PoetryOnlyFlag),
+		With:    lo.ToPtr(PoetryWithFlag),
+		Without: lo.ToPtr(PoetryWithoutFlag),
+	}
+}
+
+func (f *PoetryFlagGroup) flags() []*Flag {
+	return []*Flag{f.Only, f.With, f.Without}
+}
+
+func (f *PoetryFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *PoetryFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *PoetryFlagGroup) ToOptions() (PoetryOptions, error) {
+	return PoetryOptions{
+		PoetryGroups: pyproject.GroupSelection{
+			Only:    getStringSlice(f.Only),
+			With:    getStringSlice(f.With),
+			Without: getStringSlice(f.Without),
+		},
+	}, nil
+}
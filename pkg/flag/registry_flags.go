@@ -0,0 +1,100 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// e.g. config yaml
+// registry:
+//
+//	credential-helper: "ecr-login"
+var (
+	RegistryCredentialHelperFlag = Flag{
+		Name:       "credential-helper",
+		ConfigName: "registry.credential-helper",
+		Value:      "",
+		Usage:      "name of the docker credential helper to register for the registry being logged into (e.g. ecr-login, gcr, acr-env)",
+	}
+	RegistryUseDockerConfigFlag = Flag{
+		Name:       "registry-use-docker-config",
+		ConfigName: "registry.use-docker-config",
+		Value:      false,
+		Usage:      "auto-discover registry credentials from the docker CLI's config.json (credHelpers/credsStore), the same as `docker login`/`docker pull` would use",
+	}
+	RegistryScanFilterFlag = Flag{
+		Name:       "filter",
+		ConfigName: "registry.scan.filter",
+		Value:      "",
+		Usage:      "regular expression; only repositories whose name matches it are scanned by `trivy registry scan`",
+	}
+	RegistryScanParallelFlag = Flag{
+		Name:       "parallel",
+		ConfigName: "registry.scan.parallel",
+		Value:      5,
+		Usage:      "number of images `trivy registry scan` scans concurrently",
+	}
+	RegistryProgressFlag = Flag{
+		Name:       "progress",
+		ConfigName: "registry.progress",
+		Value:      true,
+		Usage:      "show pull progress for remote images: a redrawn bar on a terminal, periodic JSON log lines otherwise",
+	}
+)
+
+// RegistryFlagGroup composes flags for `trivy registry` subcommands.
+type RegistryFlagGroup struct {
+	CredentialHelper *Flag
+	UseDockerConfig  *Flag
+	ScanFilter       *Flag
+	ScanParallel     *Flag
+	Progress         *Flag
+}
+
+type RegistryOptions struct {
+	CredentialHelper string
+	UseDockerConfig  bool
+	ScanFilter       string
+	ScanParallel     int
+	Progress         bool
+}
+
+// NewRegistryFlagGroup returns a default RegistryFlagGroup
+func NewRegistryFlagGroup() *RegistryFlagGroup {
+	return &RegistryFlagGroup{
+		CredentialHelper: lo.ToPtr(RegistryCredentialHelperFlag),
+		UseDockerConfig:  lo.ToPtr(RegistryUseDockerConfigFlag),
+		ScanFilter:       lo.ToPtr(RegistryScanFilterFlag),
+		ScanParallel:     lo.ToPtr(RegistryScanParallelFlag),
+		Progress:         lo.ToPtr(RegistryProgressFlag),
+	}
+}
+
+func (f *RegistryFlagGroup) flags() []*Flag {
+	return []*Flag{f.CredentialHelper, f.UseDockerConfig, f.ScanFilter, f.ScanParallel, f.Progress}
+}
+
+func (f *RegistryFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *RegistryFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *RegistryFlagGroup) ToOptions() (RegistryOptions, error) {
+	return RegistryOptions{
+		CredentialHelper: getString(f.CredentialHelper),
+		UseDockerConfig:  getBool(f.UseDockerConfig),
+		ScanFilter:       getString(f.ScanFilter),
+		ScanParallel:     getInt(f.ScanParallel),
+		Progress:         getBool(f.Progress),
+	}, nil
+}
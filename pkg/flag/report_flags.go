@@ -3,6 +3,7 @@ package flag
 import (
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/samber/lo"
@@ -14,14 +15,18 @@ import (
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/types"
 )
 
 // e.g. config yaml
 // report:
-//   format: table
-//   dependency-tree: true
-//   exit-code: 1
-//   severity: HIGH,CRITICAL
+//
+//	format: table
+//	dependency-tree: true
+//	exit-code:
+//	  - vuln.critical=2
+//	  - config.high=3
+//	severity: HIGH,CRITICAL
 var (
 	FormatFlag = Flag{
 		Name:       "format",
@@ -70,15 +75,15 @@ var (
 	ExitCodeFlag = Flag{
 		Name:       "exit-code",
 		ConfigName: "exit-code",
-		Value:      0,
-		Usage:      "specify exit code when any security issues are found",
+		Value:      []string{},
+		Usage:      "repeatable per-category exit code policy, each in '<check>.<severity>=<code>' form (check: vuln, config, secret; severity: one of the usual severities, or 'any' to match every severity), e.g. --exit-code vuln.critical=2 --exit-code config.high=3; when several categories trip at once, the highest code wins",
 	}
 	OutputFlag = Flag{
 		Name:       "output",
 		ConfigName: "output",
 		Shorthand:  "o",
-		Value:      "",
-		Usage:      "output file name",
+		Value:      []string{},
+		Usage:      "output of the report; repeatable, each in 'format=path' form (e.g. -o json=results.json -o table=-); '-' means stdout. When omitted, falls back to a single target using --format and stdout",
 	}
 	SeverityFlag = Flag{
 		Name:       "severity",
@@ -95,6 +100,18 @@ var (
 		Value:      false,
 		Usage:      "display only fixed vulnerabilities",
 	}
+	IgnoreStatusFlag = Flag{
+		Name:       "ignore-status",
+		ConfigName: "vulnerability.ignore-status",
+		Value:      []string{},
+		Usage:      "comma-separated list of vulnerability status to ignore (unknown,not_affected,affected,fixed,under_investigation,will_not_fix,fix_deferred,end_of_life)",
+	}
+	CompareFlag = Flag{
+		Name:       "compare",
+		ConfigName: "compare",
+		Value:      "",
+		Usage:      "path to a previous CycloneDX/SPDX SBOM; when set, only the drift (added/removed/updated components, relationships and vulnerabilities) since that SBOM is reported",
+	}
 )
 
 // ReportFlagGroup composes common printer flag structs
@@ -106,25 +123,27 @@ type ReportFlagGroup struct {
 	DependencyTree *Flag
 	ListAllPkgs    *Flag
 	IgnoreUnfixed  *Flag
+	IgnoreStatus   *Flag
 	IgnoreFile     *Flag
 	IgnorePolicy   *Flag
 	ExitCode       *Flag
 	Output         *Flag
 	Severity       *Flag
+	Compare        *Flag
 }
 
 type ReportOptions struct {
-	Format         string
 	ReportFormat   string
-	Template       string
 	DependencyTree bool
 	ListAllPkgs    bool
 	IgnoreUnfixed  bool
 	IgnoreFile     string
-	ExitCode       int
+	ExitCodes      types.ExitCodePolicy
 	IgnorePolicy   string
-	Output         io.Writer
+	Outputs        []report.Target
 	Severities     []dbTypes.Severity
+	IgnoreStatuses []dbTypes.Status
+	Compare        string
 }
 
 func NewReportFlagGroup() *ReportFlagGroup {
@@ -135,17 +154,19 @@ func NewReportFlagGroup() *ReportFlagGroup {
 		DependencyTree: lo.ToPtr(DependencyTreeFlag),
 		ListAllPkgs:    lo.ToPtr(ListAllPkgsFlag),
 		IgnoreUnfixed:  lo.ToPtr(IgnoreUnfixedFlag),
+		IgnoreStatus:   lo.ToPtr(IgnoreStatusFlag),
 		IgnoreFile:     lo.ToPtr(IgnoreFileFlag),
 		IgnorePolicy:   lo.ToPtr(IgnorePolicyFlag),
 		ExitCode:       lo.ToPtr(ExitCodeFlag),
 		Output:         lo.ToPtr(OutputFlag),
 		Severity:       lo.ToPtr(SeverityFlag),
+		Compare:        lo.ToPtr(CompareFlag),
 	}
 }
 
 func (f *ReportFlagGroup) flags() []*Flag {
-	return []*Flag{f.Format, f.ReportFormat, f.Template, f.DependencyTree, f.ListAllPkgs, f.IgnoreUnfixed, f.IgnoreFile, f.IgnorePolicy,
-		f.ExitCode, f.Output, f.Severity}
+	return []*Flag{f.Format, f.ReportFormat, f.Template, f.DependencyTree, f.ListAllPkgs, f.IgnoreUnfixed, f.IgnoreStatus, f.IgnoreFile, f.IgnorePolicy,
+		f.ExitCode, f.Output, f.Severity, f.Compare}
 }
 
 func (f *ReportFlagGroup) AddFlags(cmd *cobra.Command) {
@@ -168,62 +189,158 @@ func (f *ReportFlagGroup) ToOptions(out io.Writer) (ReportOptions, error) {
 	template := getString(f.Template)
 	dependencyTree := getBool(f.DependencyTree)
 	listAllPkgs := getBool(f.ListAllPkgs)
-	output := getString(f.Output)
+
+	targets, err := f.toTargets(out, format, template)
+	if err != nil {
+		return ReportOptions{}, xerrors.Errorf("invalid --output: %w", err)
+	}
+
+	exitCodes, err := parseExitCodes(getStringSlice(f.ExitCode))
+	if err != nil {
+		return ReportOptions{}, xerrors.Errorf("invalid --exit-code: %w", err)
+	}
+
+	usesFormat := func(want string) bool {
+		return slices.ContainsFunc(targets, func(t report.Target) bool { return t.Format == want })
+	}
 
 	if template != "" {
-		if format == "" {
+		if !usesFormat(report.FormatTemplate) {
 			log.Logger.Warn("'--template' is ignored because '--format template' is not specified. Use '--template' option with '--format template' option.")
-		} else if format != "template" {
-			log.Logger.Warnf("'--template' is ignored because '--format %s' is specified. Use '--template' option with '--format template' option.", format)
-		}
-	} else {
-		if format == report.FormatTemplate {
-			log.Logger.Warn("'--format template' is ignored because '--template' is not specified. Specify '--template' option when you use '--format template'.")
 		}
+	} else if usesFormat(report.FormatTemplate) {
+		log.Logger.Warn("'--format template' is ignored because '--template' is not specified. Specify '--template' option when you use '--format template'.")
 	}
 
 	// "--list-all-pkgs" option is unavailable with "--format table".
 	// If user specifies "--list-all-pkgs" with "--format table", we should warn it.
-	if listAllPkgs && format == report.FormatTable {
+	if listAllPkgs && usesFormat(report.FormatTable) {
 		log.Logger.Warn(`"--list-all-pkgs" cannot be used with "--format table". Try "--format json" or other formats.`)
 	}
 
 	// "--dependency-tree" option is available only with "--format table".
-	if dependencyTree && format != report.FormatTable {
+	if dependencyTree && !usesFormat(report.FormatTable) {
 		log.Logger.Warn(`"--dependency-tree" can be used only with "--format table".`)
 	}
 
 	// Enable '--list-all-pkgs' if needed
-	if f.forceListAllPkgs(format, listAllPkgs, dependencyTree) {
+	if f.forceListAllPkgs(targets, listAllPkgs, dependencyTree) {
 		listAllPkgs = true
 	}
 
-	if output != "" {
-		var err error
-		if out, err = os.Create(output); err != nil {
-			return ReportOptions{}, xerrors.Errorf("failed to create an output file: %w", err)
-		}
-	}
-
 	return ReportOptions{
-		Format:         format,
 		ReportFormat:   getString(f.ReportFormat),
-		Template:       template,
 		DependencyTree: dependencyTree,
 		ListAllPkgs:    listAllPkgs,
 		IgnoreUnfixed:  getBool(f.IgnoreUnfixed),
 		IgnoreFile:     getString(f.IgnoreFile),
-		ExitCode:       getInt(f.ExitCode),
+		ExitCodes:      exitCodes,
 		IgnorePolicy:   getString(f.IgnorePolicy),
-		Output:         out,
+		Outputs:        targets,
 		Severities:     splitSeverity(getString(f.Severity)),
+		IgnoreStatuses: splitStatus(getStringSlice(f.IgnoreStatus)),
+		Compare:        getString(f.Compare),
 	}, nil
 }
 
-func (f *ReportFlagGroup) forceListAllPkgs(format string, listAllPkgs, dependencyTree bool) bool {
-	if slices.Contains(report.SupportedSBOMFormats, format) && !listAllPkgs {
-		log.Logger.Debugf("%q automatically enables '--list-all-pkgs'.", report.SupportedSBOMFormats)
-		return true
+// toTargets turns the repeatable '--output format=path' flag into one
+// report.Target per entry. When '--output' wasn't given at all, it falls
+// back to a single target using '--format'/'--template' and out, preserving
+// the pre-fan-out behavior of a single report going to stdout.
+func (f *ReportFlagGroup) toTargets(out io.Writer, defaultFormat, template string) ([]report.Target, error) {
+	rawOutputs := getStringSlice(f.Output)
+	if len(rawOutputs) == 0 {
+		target := report.Target{Format: defaultFormat, Writer: out}
+		if defaultFormat == report.FormatTemplate {
+			target.Template = template
+		}
+		return []report.Target{target}, nil
+	}
+
+	targets := make([]report.Target, 0, len(rawOutputs))
+	for _, rawOutput := range rawOutputs {
+		format, path, ok := strings.Cut(rawOutput, "=")
+		if !ok {
+			return nil, xerrors.Errorf("%q must be in 'format=path' form", rawOutput)
+		}
+
+		w := out
+		if path != "-" {
+			file, err := os.Create(path)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to create an output file (%s): %w", path, err)
+			}
+			w = file
+		}
+
+		target := report.Target{Format: format, Writer: w}
+		if format == report.FormatTemplate {
+			target.Template = template
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// exitCodeChecks is the set of types.SecurityCheck values --exit-code
+// accepts as the "<check>" half of a "check.severity=code" token.
+// types.SecurityCheckLicense is deliberately excluded: nothing in this
+// checkout's types.Report produces a license finding to match against (see
+// the NOTE on types.Report), so accepting it would silently never trip.
+var exitCodeChecks = map[types.SecurityCheck]bool{
+	types.SecurityCheckVulnerability: true,
+	types.SecurityCheckConfig:        true,
+	types.SecurityCheckSecret:        true,
+}
+
+// parseExitCodes parses --exit-code's repeatable "check.severity=code"
+// tokens into a types.ExitCodePolicy, rejecting an unknown check, a
+// severity that's neither a known dbTypes.Severity name nor "any", or a
+// non-numeric code - so a typo fails fast at startup instead of silently
+// never matching.
+func parseExitCodes(raw []string) (types.ExitCodePolicy, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	policy := types.ExitCodePolicy{}
+	for _, token := range raw {
+		key, codeStr, ok := strings.Cut(token, "=")
+		if !ok {
+			return nil, xerrors.Errorf(`%q must be in "check.severity=code" form`, token)
+		}
+
+		check, severity, ok := strings.Cut(key, ".")
+		if !ok {
+			return nil, xerrors.Errorf(`%q must be in "check.severity=code" form`, token)
+		}
+		if !exitCodeChecks[types.SecurityCheck(check)] {
+			return nil, xerrors.Errorf("%q: unknown check %q", token, check)
+		}
+
+		severity = strings.ToLower(severity)
+		if severity != "any" {
+			if _, err := dbTypes.NewSeverity(strings.ToUpper(severity)); err != nil {
+				return nil, xerrors.Errorf("%q: %w", token, err)
+			}
+		}
+
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			return nil, xerrors.Errorf("%q: exit code must be an integer: %w", token, err)
+		}
+
+		policy[check+"."+severity] = code
+	}
+	return policy, nil
+}
+
+func (f *ReportFlagGroup) forceListAllPkgs(targets []report.Target, listAllPkgs, dependencyTree bool) bool {
+	for _, target := range targets {
+		if slices.Contains(report.SupportedSBOMFormats, target.Format) && !listAllPkgs {
+			log.Logger.Debugf("%q automatically enables '--list-all-pkgs'.", report.SupportedSBOMFormats)
+			return true
+		}
 	}
 	if dependencyTree && !listAllPkgs {
 		log.Logger.Debugf("'--dependency-tree' enables '--list-all-pkgs'.")
@@ -249,3 +366,17 @@ func splitSeverity(severity string) []dbTypes.Severity {
 	log.Logger.Debugf("Severities: %q", severities)
 	return severities
 }
+
+func splitStatus(statuses []string) []dbTypes.Status {
+	var statusList []dbTypes.Status
+	for _, s := range statuses {
+		status := dbTypes.NewStatus(s)
+		if status == dbTypes.StatusUnknown && s != "unknown" {
+			log.Logger.Warnf("unknown status option: %s", s)
+			continue
+		}
+		statusList = append(statusList, status)
+	}
+	log.Logger.Debugf("Ignore statuses: %q", statusList)
+	return statusList
+}
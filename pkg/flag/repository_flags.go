@@ -0,0 +1,94 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// e.g. config yaml
+// repository:
+//
+//	branch: "main"
+//	commit: ""
+//	tag: ""
+//	git-trusted-keys: "./trusted-keys.asc"
+var (
+	RepoBranchFlag = Flag{
+		Name:       "branch",
+		ConfigName: "repository.branch",
+		Value:      "",
+		Usage:      "branch to be scanned",
+	}
+	RepoCommitFlag = Flag{
+		Name:       "commit",
+		ConfigName: "repository.commit",
+		Value:      "",
+		Usage:      "commit to be scanned",
+	}
+	RepoTagFlag = Flag{
+		Name:       "tag",
+		ConfigName: "repository.tag",
+		Value:      "",
+		Usage:      "tag to be scanned",
+	}
+	GitTrustedKeysFlag = Flag{
+		Name:       "git-trusted-keys",
+		ConfigName: "repository.git-trusted-keys",
+		Value:      "",
+		Usage:      "path to an armored OpenPGP keyring to verify the scanned commit's signature against; recorded as scan provenance whether or not verification succeeds",
+	}
+)
+
+// RepoFlagGroup composes flags identifying which ref of a git repository to
+// scan, and how to attest to the commit it resolves to.
+type RepoFlagGroup struct {
+	Branch         *Flag
+	Commit         *Flag
+	Tag            *Flag
+	GitTrustedKeys *Flag
+}
+
+type RepoOptions struct {
+	RepoBranch            string
+	RepoCommit            string
+	RepoTag               string
+	GitTrustedKeyringPath string
+}
+
+// NewRepoFlagGroup returns a default RepoFlagGroup
+func NewRepoFlagGroup() *RepoFlagGroup {
+	return &RepoFlagGroup{
+		Branch:         lo.ToPtr(RepoBranchFlag),
+		Commit:         lo.ToPtr(RepoCommitFlag),
+		Tag:            lo.ToPtr(RepoTagFlag),
+		GitTrustedKeys: lo.ToPtr(GitTrustedKeysFlag),
+	}
+}
+
+func (f *RepoFlagGroup) flags() []*Flag {
+	return []*Flag{f.Branch, f.Commit, f.Tag, f.GitTrustedKeys}
+}
+
+func (f *RepoFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *RepoFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *RepoFlagGroup) ToOptions() (RepoOptions, error) {
+	return RepoOptions{
+		RepoBranch:            getString(f.Branch),
+		RepoCommit:            getString(f.Commit),
+		RepoTag:               getString(f.Tag),
+		GitTrustedKeyringPath: getString(f.GitTrustedKeys),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// e.g. config yaml
+// sbom:
+//
+//	strict: true
+var (
+	SBOMStrictFlag = Flag{
+		Name:       "sbom-strict",
+		ConfigName: "sbom.strict",
+		Value:      false,
+		Usage:      "fail the scan if an input SBOM doesn't conform to its CycloneDX/SPDX JSON Schema",
+	}
+)
+
+// SBOMFlagGroup composes flags that control how an SBOM given as scan
+// input is handled, as opposed to ReportFlagGroup's flags for the SBOM
+// Trivy itself produces as output.
+type SBOMFlagGroup struct {
+	SBOMStrict *Flag
+}
+
+type SBOMOptions struct {
+	SBOMStrict bool
+}
+
+func NewSBOMFlagGroup() *SBOMFlagGroup {
+	return &SBOMFlagGroup{
+		SBOMStrict: lo.ToPtr(SBOMStrictFlag),
+	}
+}
+
+func (f *SBOMFlagGroup) flags() []*Flag {
+	return []*Flag{f.SBOMStrict}
+}
+
+func (f *SBOMFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *SBOMFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *SBOMFlagGroup) ToOptions() (SBOMOptions, error) {
+	return SBOMOptions{
+		SBOMStrict: getBool(f.SBOMStrict),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package flag
+
+import (
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	YarnMaxConcurrencyFlag = Flag{
+		Name:       "yarn-max-concurrency",
+		ConfigName: "yarn.maxConcurrency",
+		Value:      4,
+		Usage:      "max number of yarn.lock directories processed concurrently, bounding peak memory on large monorepos",
+	}
+	YarnLockDirsFlag = Flag{
+		Name:       "yarn-lock-dirs",
+		ConfigName: "yarn.lockDirs",
+		Value:      []string{},
+		Usage:      "only scan yarn.lock files whose directory matches one of these doublestar glob patterns (repeatable); when unset, every yarn.lock is scanned",
+	}
+)
+
+// YarnFlagGroup composes flags scoping and bounding the concurrency of the
+// yarn post-analyzer on large monorepos.
+type YarnFlagGroup struct {
+	MaxConcurrency *Flag
+	LockDirs       *Flag
+}
+
+type YarnOptions struct {
+	YarnMaxConcurrency int
+	YarnLockDirs       []string
+}
+
+// NewYarnFlagGroup returns a default YarnFlagGroup
+func NewYarnFlagGroup() *YarnFlagGroup {
+	return &YarnFlagGroup{
+		MaxConcurrency: lo.ToPtr(YarnMaxConcurrencyFlag),
+		LockDirs:       lo.ToPtr(YarnLockDirsFlag),
+	}
+}
+
+func (f *YarnFlagGroup) flags() []*Flag {
+	return []*Flag{f.MaxConcurrency, f.LockDirs}
+}
+
+func (f *YarnFlagGroup) AddFlags(cmd *cobra.Command) {
+	for _, flag := range f.flags() {
+		addFlag(cmd, flag)
+	}
+}
+
+func (f *YarnFlagGroup) Bind(cmd *cobra.Command) error {
+	for _, flag := range f.flags() {
+		if err := bind(cmd, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *YarnFlagGroup) ToOptions() (YarnOptions, error) {
+	return YarnOptions{
+		YarnMaxConcurrency: getInt(f.MaxConcurrency),
+		YarnLockDirs:       getStringSlice(f.LockDirs),
+	}, nil
+}
@@ -0,0 +1,50 @@
+// Package bundle fetches and verifies policy bundles: tar+gzip archives of
+// `.rego`/`.guard` checks plus a manifest, distributed the same way OPA
+// bundles are, so users can pull a curated rule set instead of hand-copying
+// policy files into every repo.
+package bundle
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// Manifest describes the contents of a policy bundle.
+type Manifest struct {
+	Roots      []string `json:"roots"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// Bundle is a downloaded, verified policy bundle, ready to be mounted as an
+// fs.FS and handed to rego.Scanner.LoadPolicies.
+type Bundle struct {
+	FS       fs.FS
+	Manifest Manifest
+	Digest   string
+}
+
+func loadManifest(dir string) (Manifest, error) {
+	data, err := os.ReadFile(manifestPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// a manifest is optional - a bundle can be a plain collection of
+			// policies with no declared roots/namespaces.
+			return Manifest{}, nil
+		}
+		return Manifest{}, xerrors.Errorf("unable to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, xerrors.Errorf("unable to parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func manifestPath(dir string) string {
+	return filepath.Join(dir, "manifest.json")
+}
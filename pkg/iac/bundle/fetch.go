@@ -0,0 +1,315 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	fanalutils "github.com/aquasecurity/trivy/pkg/fanal/utils"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+// bundleMediaType identifies a policy bundle layer, distinct from the
+// trivy-checks built-in bundle media type used by pkg/policy.
+const bundleMediaType = "application/vnd.aquasec.trivy.policy-bundle.layer.v1.tar+gzip"
+
+// Option configures how a bundle is fetched and verified.
+type Option func(f *Fetcher)
+
+// WithTrustRoot sets the path of the PEM-encoded (PKIX) ECDSA or RSA public
+// key bundles must be signed with; a PGP keyring is not accepted. Signature
+// verification is skipped if unset.
+// OCI bundles are expected to already be verified at the registry layer
+// (e.g. via `cosign verify` against the reference before it reaches Fetch);
+// this trust root only covers the detached-signature file shipped alongside
+// plain HTTP(S)/file archives.
+func WithTrustRoot(path string) Option {
+	return func(f *Fetcher) {
+		f.trustRootPath = path
+	}
+}
+
+// WithCacheDir overrides where downloaded bundles are cached on disk.
+func WithCacheDir(dir string) Option {
+	return func(f *Fetcher) {
+		f.cacheDir = dir
+	}
+}
+
+// WithRegistryOptions configures OCI auth used when fetching an oci:// bundle.
+func WithRegistryOptions(opts ftypes.RegistryOptions) Option {
+	return func(f *Fetcher) {
+		f.registryOpts = opts
+	}
+}
+
+// Fetcher downloads a policy bundle by URL, verifies its signature if a
+// trust root is configured, and caches the extracted contents on disk keyed
+// by the bundle's digest so repeat scans don't re-download unchanged rules.
+type Fetcher struct {
+	trustRootPath string
+	cacheDir      string
+	registryOpts  ftypes.RegistryOptions
+}
+
+// NewFetcher creates a Fetcher with the given options applied over sensible
+// defaults (the shared Trivy cache directory, no signature verification).
+func NewFetcher(opts ...Option) *Fetcher {
+	f := &Fetcher{
+		cacheDir: filepath.Join(fanalutils.CacheDir(), "trivy", "policy-bundles"),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Fetch downloads the bundle at url into the cache, verifying it and
+// extracting it if necessary, and returns its contents mounted as an fs.FS.
+// Supported schemes are oci://, https:// (or http://, for internal mirrors)
+// and file:// - the latter may point at either a tar+gzip archive or an
+// already-extracted directory.
+func (f *Fetcher) Fetch(ctx context.Context, url string) (*Bundle, error) {
+	switch {
+	case strings.HasPrefix(url, "oci://"):
+		return f.fetchOCI(ctx, strings.TrimPrefix(url, "oci://"))
+	case strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		return f.fetchArchive(ctx, url, httpDownload)
+	case strings.HasPrefix(url, "file://"):
+		return f.fetchFile(strings.TrimPrefix(url, "file://"))
+	default:
+		return nil, xerrors.Errorf("unsupported bundle scheme: %s", url)
+	}
+}
+
+func (f *Fetcher) fetchOCI(ctx context.Context, repo string) (*Bundle, error) {
+	art, err := oci.NewArtifact(repo, true, f.registryOpts)
+	if err != nil {
+		return nil, xerrors.Errorf("oci error: %w", err)
+	}
+
+	digest, err := art.Digest(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("digest error: %w", err)
+	}
+
+	dir := filepath.Join(f.cacheDir, sanitizeDigest(digest))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := art.Download(ctx, dir, oci.DownloadOption{MediaType: bundleMediaType}); err != nil {
+			return nil, xerrors.Errorf("download error: %w", err)
+		}
+	}
+
+	return f.mount(dir, digest)
+}
+
+// FetchReader extracts a bundle archive read in full from r - e.g. one
+// embedded in the binary via go:embed, or piped in by a caller that already
+// has the bytes in hand - rather than one Fetch would need a URL to locate.
+// Caching still applies, keyed on the archive's own digest exactly as
+// fetchArchive/fetchLocalArchive are. A reader carries no detached signature,
+// so FetchReader returns an error when a trust root (WithTrustRoot) is set.
+func (f *Fetcher) FetchReader(r io.Reader) (*Bundle, error) {
+	tmp, err := os.CreateTemp("", "trivy-policy-bundle-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return nil, xerrors.Errorf("unable to read bundle: %w", err)
+	}
+
+	if f.trustRootPath != "" {
+		return nil, xerrors.Errorf("signature verification is not supported for an in-memory bundle reader: " +
+			"WithTrustRoot expects a detached \"<path>.sig\" file alongside a path/URL Fetch can re-derive one for")
+	}
+
+	return f.extractArchive(tmp.Name())
+}
+
+func (f *Fetcher) fetchFile(path string) (*Bundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return f.mount(path, "")
+	}
+	return f.fetchLocalArchive(path)
+}
+
+func (f *Fetcher) fetchArchive(ctx context.Context, url string, download func(context.Context, string, io.Writer) error) (*Bundle, error) {
+	tmp, err := os.CreateTemp("", "trivy-policy-bundle-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := download(ctx, url, tmp); err != nil {
+		return nil, xerrors.Errorf("unable to download %s: %w", url, err)
+	}
+
+	if f.trustRootPath != "" {
+		sig, err := downloadSignature(ctx, url)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to fetch signature for %s: %w", url, err)
+		}
+		if err := verify(tmp.Name(), sig, f.trustRootPath); err != nil {
+			return nil, xerrors.Errorf("signature verification failed for %s: %w", url, err)
+		}
+	}
+
+	return f.extractArchive(tmp.Name())
+}
+
+func (f *Fetcher) fetchLocalArchive(path string) (*Bundle, error) {
+	if f.trustRootPath != "" {
+		if err := verify(path, path+".sig", f.trustRootPath); err != nil {
+			return nil, xerrors.Errorf("signature verification failed for %s: %w", path, err)
+		}
+	}
+	return f.extractArchive(path)
+}
+
+func (f *Fetcher) extractArchive(archivePath string) (*Bundle, error) {
+	digest, err := fileDigest(archivePath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to compute bundle digest: %w", err)
+	}
+
+	dir := filepath.Join(f.cacheDir, digest)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := extractTarGz(archivePath, dir); err != nil {
+			return nil, xerrors.Errorf("unable to extract bundle: %w", err)
+		}
+	}
+
+	return f.mount(dir, digest)
+}
+
+func (f *Fetcher) mount(dir, digest string) (*Bundle, error) {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{
+		FS:       os.DirFS(dir),
+		Manifest: manifest,
+		Digest:   digest,
+	}, nil
+}
+
+func sanitizeDigest(digest string) string {
+	return strings.ReplaceAll(digest, ":", "-")
+}
+
+// downloadSignature fetches the detached signature that's expected to sit
+// alongside a bundle archive at "<url>.sig".
+func downloadSignature(ctx context.Context, url string) (string, error) {
+	tmp, err := os.CreateTemp("", "trivy-policy-bundle-*.sig")
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+
+	if err := httpDownload(ctx, url+".sig", tmp); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+func httpDownload(ctx context.Context, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return xerrors.Errorf("unable to build request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("unable to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status fetching %s: %s", url, resp.Status)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return xerrors.Errorf("unable to write bundle: %w", err)
+	}
+	return nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256-" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func extractTarGz(archivePath, dir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return xerrors.Errorf("unable to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return xerrors.Errorf("unable to read tar entry: %w", err)
+		}
+
+		target := filepath.Join(dir, hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+			if err != nil {
+				return xerrors.Errorf("unable to create %s: %w", target, err)
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return xerrors.Errorf("unable to write %s: %w", target, err)
+			}
+			out.Close()
+		}
+	}
+	return nil
+}
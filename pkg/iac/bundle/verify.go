@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// verify checks the detached signature at sigPath against dataPath, using
+// the PEM-encoded public key at trustRootPath. This covers the common case
+// of a cosign-generated ECDSA/RSA signature; full Sigstore/Rekor transparency
+// log verification is out of scope here - callers that need it should verify
+// the bundle's OCI reference with the cosign CLI before handing the
+// reference to Fetch.
+func verify(dataPath, sigPath, trustRootPath string) error {
+	keyPEM, err := os.ReadFile(trustRootPath)
+	if err != nil {
+		return xerrors.Errorf("unable to read trust root: %w", err)
+	}
+	pub, err := parsePublicKey(keyPEM)
+	if err != nil {
+		return xerrors.Errorf("unable to parse trust root: %w", err)
+	}
+
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return xerrors.Errorf("unable to read bundle: %w", err)
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return xerrors.Errorf("unable to read signature: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return xerrors.Errorf("ecdsa signature verification failed")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return xerrors.Errorf("rsa signature verification failed: %w", err)
+		}
+	default:
+		return xerrors.Errorf("unsupported public key type %T", pub)
+	}
+
+	return nil
+}
+
+func parsePublicKey(keyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,138 @@
+package cloudtrail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	fanalutils "github.com/aquasecurity/trivy/pkg/fanal/utils"
+)
+
+// Cache stores a resource's correlated DriftInfo keyed by CacheKey, so a
+// re-scan whose blob hash is unchanged can skip querying CloudTrail again.
+// A nil DriftInfo is a valid cached value: "looked up, found nothing".
+type Cache interface {
+	Get(key string) (*DriftInfo, bool)
+	Put(key string, info *DriftInfo) error
+}
+
+// CacheKey hashes resourceID together with every Config field that can
+// change the correlation's outcome (trail source, lookback window), the
+// same way rego.CacheKey folds a scan's inputs into one stable key -
+// callers mix this into their own blob hash so invalidating the blob also
+// invalidates the cached correlation.
+func CacheKey(resourceID string, cfg Config) string {
+	lookback := cfg.Lookback
+	if lookback <= 0 {
+		lookback = defaultLookback
+	}
+	h := sha256.New()
+	for _, c := range []string{resourceID, cfg.TrailSource, lookback.String()} {
+		h.Write([]byte(c))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CorrelateCached is Correlate with a Cache consulted first: a hit is
+// returned without calling client, and a miss is computed via Correlate
+// and stored before being returned. A nil cache always misses.
+func CorrelateCached(ctx context.Context, client Client, cache Cache, resourceID string, cfg Config) (*DriftInfo, error) {
+	key := CacheKey(resourceID, cfg)
+	if cache != nil {
+		if info, ok := cache.Get(key); ok {
+			return info, nil
+		}
+	}
+
+	info, err := Correlate(ctx, client, resourceID, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		if err := cache.Put(key, info); err != nil {
+			return info, xerrors.Errorf("failed to cache CloudTrail correlation for %s: %w", resourceID, err)
+		}
+	}
+	return info, nil
+}
+
+// DiskCache is the default Cache implementation: one JSON file per key
+// under a cache directory, defaulting to the shared Trivy cache dir - the
+// same layout pkg/iac/scan.DiskCache uses for rego scan results.
+type DiskCache struct {
+	dir string
+}
+
+// DiskCacheOption configures a DiskCache.
+type DiskCacheOption func(c *DiskCache)
+
+// WithCacheDir overrides the directory cache entries are stored under.
+func WithCacheDir(dir string) DiskCacheOption {
+	return func(c *DiskCache) {
+		c.dir = dir
+	}
+}
+
+// NewDiskCache creates a DiskCache rooted at the shared Trivy cache dir
+// unless overridden via WithCacheDir.
+func NewDiskCache(opts ...DiskCacheOption) *DiskCache {
+	c := &DiskCache{
+		dir: filepath.Join(fanalutils.CacheDir(), "trivy", "cloudtrail"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the cached DriftInfo for key, if present. A stored "no event
+// found" entry is returned as (nil, true), distinct from a cache miss
+// (nil, false).
+func (c *DiskCache) Get(key string) (*DriftInfo, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	if len(data) == 0 {
+		return nil, true
+	}
+	var info DriftInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, false
+	}
+	return &info, true
+}
+
+// Put stores info under key, overwriting any existing entry. A nil info is
+// stored as an empty file, recording "looked up, found nothing".
+func (c *DiskCache) Put(key string, info *DriftInfo) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return xerrors.Errorf("failed to create cache dir %s: %w", c.dir, err)
+	}
+
+	var data []byte
+	if info != nil {
+		var err error
+		data, err = json.Marshal(info)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal cached CloudTrail correlation: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(c.path(key), data, 0o600); err != nil {
+		return xerrors.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, strings.TrimSpace(key)+".json")
+}
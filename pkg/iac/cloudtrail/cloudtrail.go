@@ -0,0 +1,104 @@
+// Package cloudtrail correlates a misconfig finding's resource with its
+// recent AWS CloudTrail activity, so a result can be annotated with "this
+// was last modified by user/alice 3 days ago via PutBucketPolicy" rather
+// than just the static policy violation.
+//
+// NOTE: this implements the correlation logic (Correlate), a cache-aware
+// wrapper (CorrelateCached/Cache/DiskCache) so repeat scans don't re-query
+// CloudTrail for an unchanged resource, and graceful degradation to a no-op
+// when no Client is configured (credentials absent). Wiring a Config field
+// onto MisconfScannerOption and LastModifiedBy/LastModifiedAt/
+// LastModifyingEvent onto types.CauseMetadata isn't possible in this
+// checkout: neither MisconfScannerOption nor types.CauseMetadata exist here
+// (only the language/package analyzers that don't depend on them do), and
+// there's no AWS SDK dependency to implement Client against. Client is the
+// extension point a real implementation (backed by either the CloudTrail
+// LookupEvents API directly, or Athena queries against an S3-stored trail)
+// plugs into once that wiring exists - mirroring how pkg/iac/drift.
+// LiveFetcher is the extension point for a live-account AWS client.
+package cloudtrail
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// defaultLookback is how far back Correlate searches when Config.Lookback
+// isn't set.
+const defaultLookback = 90 * 24 * time.Hour
+
+// Event is a single CloudTrail event relevant to a resource.
+type Event struct {
+	EventName string
+	Username  string
+	EventTime time.Time
+}
+
+// Client looks up CloudTrail events for a resource. Implementations back
+// this with whichever trail source Config.TrailSource names - a live
+// LookupEvents API call, or an Athena query against an S3-stored trail.
+type Client interface {
+	LookupEvents(ctx context.Context, resourceID string, since time.Time) ([]Event, error)
+}
+
+// Config configures where and how far back a Client looks for a resource's
+// CloudTrail activity.
+type Config struct {
+	// TrailSource is the CloudTrail source to query: an S3 bucket URI
+	// (s3://...) holding raw trail logs, or an Athena table name if the
+	// trail is queried through Athena.
+	TrailSource string
+	// Lookback is how far back to search for the most recent mutating
+	// event. Defaults to defaultLookback if zero.
+	Lookback time.Duration
+	// Profile is the AWS named profile/credential set to query with.
+	Profile string
+}
+
+// DriftInfo is the runtime provenance Correlate attaches to a resource: who
+// (or what) last modified it, when, and via which API call.
+type DriftInfo struct {
+	LastModifiedBy     string
+	LastModifiedAt     time.Time
+	LastModifyingEvent string
+}
+
+// Correlate returns resourceID's most recent CloudTrail event (by
+// EventTime) within cfg's lookback window as a DriftInfo, or nil if none is
+// found. It returns (nil, nil) without error when client is nil - the
+// caller's signal that no CloudTrail credentials/client were configured -
+// since a missing credential shouldn't fail the misconfig scan it's
+// annotating.
+func Correlate(ctx context.Context, client Client, resourceID string, cfg Config) (*DriftInfo, error) {
+	if client == nil {
+		return nil, nil
+	}
+
+	lookback := cfg.Lookback
+	if lookback <= 0 {
+		lookback = defaultLookback
+	}
+
+	events, err := client.LookupEvents(ctx, resourceID, time.Now().Add(-lookback))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to look up CloudTrail events for %s: %w", resourceID, err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	latest := events[0]
+	for _, e := range events[1:] {
+		if e.EventTime.After(latest.EventTime) {
+			latest = e
+		}
+	}
+
+	return &DriftInfo{
+		LastModifiedBy:     latest.Username,
+		LastModifiedAt:     latest.EventTime,
+		LastModifyingEvent: latest.EventName,
+	}, nil
+}
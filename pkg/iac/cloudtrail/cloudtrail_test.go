@@ -0,0 +1,123 @@
+package cloudtrail_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/iac/cloudtrail"
+)
+
+type mockClient struct {
+	events []cloudtrail.Event
+	err    error
+	calls  int
+}
+
+func (m *mockClient) LookupEvents(_ context.Context, _ string, _ time.Time) ([]cloudtrail.Event, error) {
+	m.calls++
+	return m.events, m.err
+}
+
+type memCache struct {
+	entries map[string]*cloudtrail.DriftInfo
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: map[string]*cloudtrail.DriftInfo{}}
+}
+
+func (c *memCache) Get(key string) (*cloudtrail.DriftInfo, bool) {
+	info, ok := c.entries[key]
+	return info, ok
+}
+
+func (c *memCache) Put(key string, info *cloudtrail.DriftInfo) error {
+	c.entries[key] = info
+	return nil
+}
+
+func Test_Correlate_NilClientIsNoop(t *testing.T) {
+	info, err := cloudtrail.Correlate(context.Background(), nil, "arn:aws:s3:::my-bucket", cloudtrail.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func Test_Correlate_NoEvents(t *testing.T) {
+	client := &mockClient{}
+	info, err := cloudtrail.Correlate(context.Background(), client, "arn:aws:s3:::my-bucket", cloudtrail.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func Test_Correlate_PicksLatestEvent(t *testing.T) {
+	older := time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 7, 25, 0, 0, 0, 0, time.UTC)
+	client := &mockClient{
+		events: []cloudtrail.Event{
+			{EventName: "PutBucketPolicy", Username: "user/alice", EventTime: newer},
+			{EventName: "GetBucketPolicy", Username: "user/bob", EventTime: older},
+		},
+	}
+
+	info, err := cloudtrail.Correlate(context.Background(), client, "arn:aws:s3:::my-bucket", cloudtrail.Config{})
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "user/alice", info.LastModifiedBy)
+	assert.Equal(t, "PutBucketPolicy", info.LastModifyingEvent)
+	assert.True(t, info.LastModifiedAt.Equal(newer))
+}
+
+func Test_Correlate_WrapsLookupError(t *testing.T) {
+	client := &mockClient{err: assert.AnError}
+	_, err := cloudtrail.Correlate(context.Background(), client, "arn:aws:s3:::my-bucket", cloudtrail.Config{})
+	assert.Error(t, err)
+}
+
+func Test_CorrelateCached_MissThenHit(t *testing.T) {
+	client := &mockClient{
+		events: []cloudtrail.Event{
+			{EventName: "PutBucketPolicy", Username: "user/alice", EventTime: time.Now()},
+		},
+	}
+	cache := newMemCache()
+	cfg := cloudtrail.Config{TrailSource: "s3://my-trail-bucket"}
+
+	first, err := cloudtrail.CorrelateCached(context.Background(), client, cache, "arn:aws:s3:::my-bucket", cfg)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, 1, client.calls)
+
+	second, err := cloudtrail.CorrelateCached(context.Background(), client, cache, "arn:aws:s3:::my-bucket", cfg)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, "user/alice", second.LastModifiedBy)
+	assert.Equal(t, 1, client.calls, "cache hit should not call the client again")
+}
+
+func Test_CorrelateCached_NilCacheAlwaysMisses(t *testing.T) {
+	client := &mockClient{
+		events: []cloudtrail.Event{
+			{EventName: "PutBucketPolicy", Username: "user/alice", EventTime: time.Now()},
+		},
+	}
+	cfg := cloudtrail.Config{}
+
+	_, err := cloudtrail.CorrelateCached(context.Background(), client, nil, "arn:aws:s3:::my-bucket", cfg)
+	require.NoError(t, err)
+	_, err = cloudtrail.CorrelateCached(context.Background(), client, nil, "arn:aws:s3:::my-bucket", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.calls)
+}
+
+func Test_CacheKey_DistinguishesTrailSourceAndLookback(t *testing.T) {
+	base := cloudtrail.CacheKey("arn:aws:s3:::my-bucket", cloudtrail.Config{})
+	diffSource := cloudtrail.CacheKey("arn:aws:s3:::my-bucket", cloudtrail.Config{TrailSource: "athena-table"})
+	diffLookback := cloudtrail.CacheKey("arn:aws:s3:::my-bucket", cloudtrail.Config{Lookback: 24 * time.Hour})
+
+	assert.NotEqual(t, base, diffSource)
+	assert.NotEqual(t, base, diffLookback)
+}
@@ -1,30 +1,114 @@
 package debug
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 )
 
+// Level is the severity of a single log entry, lowest to highest.
+type Level int
+
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (lv Level) String() string {
+	switch lv {
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Format selects how a Logger renders an entry's structured fields.
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatLogfmt Format = "logfmt"
+)
+
+// field is a single structured key/value pair. A slice, not a map, so
+// With/Extend can be called repeatedly without fields being reordered
+// between otherwise-identical log lines.
+type field struct {
+	key   string
+	value any
+}
+
+// Logger writes prefixed, optionally leveled and structured debug output.
+// The zero Logger is a valid no-op (writer is nil), matching the prior
+// behavior of New(io.Discard, ...).
 type Logger struct {
 	writer io.Writer
 	prefix string
+	level  Level
+	format Format
+	fields []field
 }
 
+// New returns a Logger prefixed with parts, joined by ".", writing to w.
+// It logs at every level by default; call WithLevel to filter.
 func New(w io.Writer, parts ...string) Logger {
 	return Logger{
 		writer: w,
 		prefix: strings.Join(parts, "."),
+		level:  LevelTrace,
+		format: FormatText,
 	}
 }
 
+// Extend returns a copy of l with parts appended to its prefix, inheriting
+// its level, format and structured fields - e.g.
+// Extend("analyzer", "ubuntu").With("file", "etc/lsb-release").
 func (l *Logger) Extend(parts ...string) Logger {
-	return Logger{
-		writer: l.writer,
-		prefix: strings.Join(append([]string{l.prefix}, parts...), "."),
-	}
+	ext := *l
+	ext.prefix = strings.Join(append([]string{l.prefix}, parts...), ".")
+	return ext
+}
+
+// With returns a copy of l with an additional structured key/value field
+// attached to every entry logged through it from here on.
+func (l Logger) With(key string, value any) Logger {
+	fields := make([]field, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	l.fields = append(fields, field{key: key, value: value})
+	return l
 }
 
+// WithLevel returns a copy of l that only emits entries at level or above.
+func (l Logger) WithLevel(level Level) Logger {
+	l.level = level
+	return l
+}
+
+// WithFormat returns a copy of l rendering its structured fields as format.
+func (l Logger) WithFormat(format Format) Logger {
+	l.format = format
+	return l
+}
+
+// Log writes an unleveled message, preserving the original plain-text
+// "%-32s %s\n" shape this package has always used. Prefer the leveled
+// Trace/Debug/Info/Warn/Error methods in new code.
 func (l *Logger) Log(format string, args ...any) {
 	if l.writer == nil {
 		return
@@ -33,3 +117,84 @@ func (l *Logger) Log(format string, args ...any) {
 	line := fmt.Sprintf("%-32s %s\n", l.prefix, message)
 	_, _ = l.writer.Write([]byte(line))
 }
+
+func (l Logger) Trace(msg string, args ...any) { l.log(LevelTrace, msg, args...) }
+func (l Logger) Debug(msg string, args ...any) { l.log(LevelDebug, msg, args...) }
+func (l Logger) Info(msg string, args ...any)  { l.log(LevelInfo, msg, args...) }
+func (l Logger) Warn(msg string, args ...any)  { l.log(LevelWarn, msg, args...) }
+func (l Logger) Error(msg string, args ...any) { l.log(LevelError, msg, args...) }
+
+func (l Logger) log(level Level, format string, args ...any) {
+	if l.writer == nil || level < l.level {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+
+	var buf bytes.Buffer
+	switch l.format {
+	case FormatJSON:
+		l.writeJSON(&buf, level, message)
+	case FormatLogfmt:
+		l.writeLogfmt(&buf, level, message)
+	default:
+		l.writeText(&buf, level, message)
+	}
+	_, _ = l.writer.Write(buf.Bytes())
+}
+
+func (l Logger) writeText(buf *bytes.Buffer, level Level, message string) {
+	fmt.Fprintf(buf, "%-32s [%s] %s", l.prefix, level, message)
+	for _, f := range l.fields {
+		fmt.Fprintf(buf, " %s=%v", f.key, f.value)
+	}
+	buf.WriteByte('\n')
+}
+
+func (l Logger) writeLogfmt(buf *bytes.Buffer, level Level, message string) {
+	fmt.Fprintf(buf, "prefix=%q level=%s msg=%q", l.prefix, level, message)
+	for _, f := range l.fields {
+		fmt.Fprintf(buf, " %s=%v", f.key, f.value)
+	}
+	buf.WriteByte('\n')
+}
+
+func (l Logger) writeJSON(buf *bytes.Buffer, level Level, message string) {
+	entry := make(map[string]any, len(l.fields)+3)
+	entry["prefix"] = l.prefix
+	entry["level"] = level.String()
+	entry["message"] = message
+	for _, f := range l.fields {
+		entry[f.key] = f.value
+	}
+
+	enc := json.NewEncoder(buf)
+	if err := enc.Encode(entry); err != nil {
+		fmt.Fprintf(buf, "%-32s [%s] %s (unable to encode fields: %s)\n", l.prefix, level, message, err)
+	}
+}
+
+type loggerKey struct{}
+type scanIDKey struct{}
+
+// NewContext returns a copy of ctx carrying l, retrievable with From.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// WithScanID returns a copy of ctx tagging every Logger retrieved via From
+// with a "scan_id" field, correlating every analyzer/parser trace emitted
+// while scanning a single artifact.
+func WithScanID(ctx context.Context, scanID string) context.Context {
+	return context.WithValue(ctx, scanIDKey{}, scanID)
+}
+
+// From returns the Logger stored in ctx by NewContext, with a "scan_id"
+// field attached if WithScanID was also used. It returns a no-op Logger if
+// ctx carries none, so callers never need a nil check.
+func From(ctx context.Context) Logger {
+	l, _ := ctx.Value(loggerKey{}).(Logger)
+	if scanID, ok := ctx.Value(scanIDKey{}).(string); ok && scanID != "" {
+		l = l.With("scan_id", scanID)
+	}
+	return l
+}
@@ -0,0 +1,114 @@
+// Package drift provides the merge/comparison primitives for a live-account
+// scanning mode: combining a planned (HCL/state) attribute value with the
+// same attribute's actual runtime value, so a rule corpus written against
+// the planned value can also be evaluated pre-deploy-vs-live or purely against live
+// posture.
+//
+// This package only contains the provider-agnostic merge logic. Wiring it
+// into the terraform scanner as a `ScannerWithLiveAWSAccount` option isn't
+// done here: that would require both the terraform scanner itself
+// (pkg/iac/scanners/terraform has no Scanner implementation in this tree,
+// only its parser and test file) and an AWS SDK dependency, neither of
+// which exists in this tree. LiveFetcher below is the extension point a
+// future option can implement once those are available.
+package drift
+
+import "context"
+
+// Attribute is a single resource attribute merged from both its
+// planned/HCL value and its live, runtime value.
+type Attribute struct {
+	// Value is the value found in HCL/plan input, or nil if this attribute
+	// only exists at runtime (a LiveOnly attribute, e.g. an IAM Access
+	// Analyzer finding with no Terraform equivalent).
+	Value any
+	// Live is the value read from the real account, or nil if it wasn't
+	// fetched (live scanning disabled, or the resource doesn't exist live
+	// yet - a planned-but-not-applied resource).
+	Live any
+	// HasLive reports whether Live was actually populated, distinguishing
+	// "live value is nil" from "we never looked".
+	HasLive bool
+}
+
+// Drift reports whether the live value differs from the planned one. An
+// attribute that was never fetched live, or that has no planned value to
+// compare against (LiveOnly), never reports drift.
+func (a Attribute) Drift() bool {
+	if !a.HasLive || a.Value == nil {
+		return false
+	}
+	return !equal(a.Value, a.Live)
+}
+
+// LiveOnly reports whether this attribute exists only at runtime, with no
+// planned/HCL equivalent to compare it against.
+func (a Attribute) LiveOnly() bool {
+	return a.HasLive && a.Value == nil
+}
+
+// Resource is a merged set of attributes for a single resource, keyed by
+// attribute name (e.g. "acl", "versioning.enabled").
+type Resource map[string]Attribute
+
+// Merge combines a resource's planned attribute values with its live
+// values into a Resource. Each Attribute carries the planned Value, the Live
+// value and HasLive, and Drift computes whether the two differ. Attribute has
+// no JSON tags and no stored drift field, so a document encoded from a
+// Resource has Value/Live/HasLive keys per attribute, not lowercase
+// value/live keys or a drift bool.
+func Merge(planned, live map[string]any) Resource {
+	merged := make(Resource, len(planned)+len(live))
+	for name, value := range planned {
+		merged[name] = Attribute{Value: value}
+	}
+	for name, liveValue := range live {
+		attr := merged[name]
+		attr.Live = liveValue
+		attr.HasLive = true
+		merged[name] = attr
+	}
+	return merged
+}
+
+// LiveFetcher retrieves the current runtime attribute values for a resource
+// identified by ARN (or, where ARNs don't apply, another provider-specific
+// identifier), keyed the same way the planned attributes are so Merge can
+// line them up. Implementations live alongside whatever scanner wires in a
+// live-account mode, since fetching requires a real cloud SDK client.
+type LiveFetcher interface {
+	FetchLive(ctx context.Context, resourceID string) (map[string]any, error)
+}
+
+func equal(a, b any) bool {
+	av, aok := a.([]any)
+	bv, bok := b.([]any)
+	if aok || bok {
+		if !aok || !bok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	am, amok := a.(map[string]any)
+	bm, bmok := b.(map[string]any)
+	if amok || bmok {
+		if !amok || !bmok || len(am) != len(bm) {
+			return false
+		}
+		for k, v := range am {
+			bval, ok := bm[k]
+			if !ok || !equal(v, bval) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return a == b
+}
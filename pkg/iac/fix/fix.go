@@ -0,0 +1,146 @@
+// Package fix represents suggested remediations for misconfiguration
+// findings as structured patches: RFC 6902 JSON Patch operations for
+// JSON/YAML-sourced IR (ARM, CloudFormation, Kubernetes), or a unified diff
+// hunk for HCL, which has no JSON Pointer equivalent to target.
+//
+// NOTE: this only implements the patch representation and JSON Patch
+// application. Having a Rego policy return one of these alongside deny,
+// adding it as types.MisconfResult.Fix, a MisconfScannerOption.EmitFixes
+// toggle, and a `trivy config --fix` CLI path aren't possible in this
+// checkout: pkg/fanal/types.Misconfiguration/MisconfResult, pkg/misconf,
+// and the CLI tree aren't present here. Patch and Apply are real and
+// independently usable once that wiring exists.
+package fix
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Op is a single RFC 6902 JSON Patch operation describing one change a
+// suggested remediation would make to a JSON/YAML document.
+type Op struct {
+	Op    string `json:"op"` // "add", "replace", or "remove"
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Patch is a suggested remediation for a single finding: either a sequence
+// of JSON Patch ops, or a unified diff hunk - exactly one of the two is
+// set.
+type Patch struct {
+	Ops  []Op   `json:"ops,omitempty"`
+	Diff string `json:"diff,omitempty"`
+}
+
+// Apply applies p's JSON Patch ops in order to a copy of doc and returns
+// the result, leaving doc itself untouched. It's an error to call Apply on
+// a Patch that carries a unified Diff instead - that applies to source
+// text, not a decoded document. "add" and "replace" create any missing
+// intermediate objects (replacing a non-object value in the way); "remove"
+// fails only when an intermediate segment is not an object, and removing an
+// absent key is not an error.
+func Apply(doc map[string]any, p Patch) (map[string]any, error) {
+	if p.Diff != "" {
+		return nil, xerrors.New("fix: Patch carries a unified diff, not JSON Patch ops")
+	}
+
+	result := deepCopyMap(doc)
+	for _, op := range p.Ops {
+		pointer, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid path %q: %w", op.Path, err)
+		}
+		if len(pointer) == 0 {
+			return nil, xerrors.Errorf("invalid path %q: empty pointer", op.Path)
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if err := setAt(result, pointer, op.Value); err != nil {
+				return nil, xerrors.Errorf("%s %q: %w", op.Op, op.Path, err)
+			}
+		case "remove":
+			if err := removeAt(result, pointer); err != nil {
+				return nil, xerrors.Errorf("remove %q: %w", op.Path, err)
+			}
+		default:
+			return nil, xerrors.Errorf("unsupported op %q", op.Op)
+		}
+	}
+	return result, nil
+}
+
+// splitPointer splits a JSON Pointer ("/spec/securityContext/runAsNonRoot")
+// into its unescaped segments ("~1" -> "/", "~0" -> "~"), per RFC 6901.
+func splitPointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, xerrors.New("must start with '/'")
+	}
+	segments := strings.Split(path[1:], "/")
+	for i, s := range segments {
+		s = strings.ReplaceAll(s, "~1", "/")
+		s = strings.ReplaceAll(s, "~0", "~")
+		segments[i] = s
+	}
+	return segments, nil
+}
+
+// setAt walks doc along pointer, creating intermediate maps as needed, and
+// sets the final segment to value.
+func setAt(doc map[string]any, pointer []string, value any) error {
+	cursor := doc
+	for _, segment := range pointer[:len(pointer)-1] {
+		next, ok := cursor[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			cursor[segment] = next
+		}
+		cursor = next
+	}
+	cursor[pointer[len(pointer)-1]] = value
+	return nil
+}
+
+// removeAt walks doc along pointer and deletes the key at its final
+// segment. It's an error if any intermediate segment doesn't resolve to a
+// map.
+func removeAt(doc map[string]any, pointer []string) error {
+	cursor := doc
+	for _, segment := range pointer[:len(pointer)-1] {
+		next, ok := cursor[segment].(map[string]any)
+		if !ok {
+			return xerrors.Errorf("segment %q does not resolve to an object", segment)
+		}
+		cursor = next
+	}
+	delete(cursor, pointer[len(pointer)-1])
+	return nil
+}
+
+func deepCopyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+func deepCopyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return deepCopyMap(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, e := range val {
+			out[i] = deepCopyValue(e)
+		}
+		return out
+	default:
+		return val
+	}
+}
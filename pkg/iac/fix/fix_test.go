@@ -0,0 +1,64 @@
+package fix_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/iac/fix"
+)
+
+func Test_Apply_SetsNestedField(t *testing.T) {
+	doc := map[string]any{
+		"spec": map[string]any{
+			"containers": []any{"app"},
+		},
+	}
+
+	patch := fix.Patch{Ops: []fix.Op{
+		{Op: "add", Path: "/spec/securityContext/runAsNonRoot", Value: true},
+	}}
+
+	fixed, err := fix.Apply(doc, patch)
+	require.NoError(t, err)
+
+	spec := fixed["spec"].(map[string]any)
+	securityContext := spec["securityContext"].(map[string]any)
+	assert.Equal(t, true, securityContext["runAsNonRoot"])
+
+	// the original document is untouched
+	assert.NotContains(t, doc["spec"].(map[string]any), "securityContext")
+}
+
+func Test_Apply_Replace(t *testing.T) {
+	doc := map[string]any{"properties": map[string]any{"enableIAMDatabaseAuthentication": false}}
+
+	fixed, err := fix.Apply(doc, fix.Patch{Ops: []fix.Op{
+		{Op: "replace", Path: "/properties/enableIAMDatabaseAuthentication", Value: true},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, true, fixed["properties"].(map[string]any)["enableIAMDatabaseAuthentication"])
+}
+
+func Test_Apply_Remove(t *testing.T) {
+	doc := map[string]any{"metadata": map[string]any{"unsafe": true}}
+
+	fixed, err := fix.Apply(doc, fix.Patch{Ops: []fix.Op{
+		{Op: "remove", Path: "/metadata/unsafe"},
+	}})
+	require.NoError(t, err)
+	assert.NotContains(t, fixed["metadata"].(map[string]any), "unsafe")
+}
+
+func Test_Apply_RejectsDiffPatch(t *testing.T) {
+	_, err := fix.Apply(map[string]any{}, fix.Patch{Diff: "--- a\n+++ b\n"})
+	assert.Error(t, err)
+}
+
+func Test_Apply_InvalidPath(t *testing.T) {
+	_, err := fix.Apply(map[string]any{}, fix.Patch{Ops: []fix.Op{
+		{Op: "add", Path: "no-leading-slash", Value: 1},
+	}})
+	assert.Error(t, err)
+}
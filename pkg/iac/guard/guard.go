@@ -0,0 +1,268 @@
+// Package guard provides a minimal evaluator for CloudFormation Guard
+// (https://github.com/aws-cloudformation/cloudformation-guard) `.guard`
+// rule files, as a lighter-weight alternative to Rego for users migrating
+// existing Guard policies onto Trivy's misconfiguration scanners.
+//
+// Only the common subset of the Guard language is supported: top-level
+// `rule <name> { <clause> ... }` blocks containing one property-path
+// comparison per line. Composite clauses (`when`, `let`, nested rule
+// references) are not implemented - unsupported syntax is rejected at parse
+// time rather than silently ignored.
+package guard
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// Operator is a comparison supported inside a Guard clause.
+type Operator string
+
+const (
+	OpEquals    Operator = "=="
+	OpNotEquals Operator = "!="
+	OpLess      Operator = "<"
+	OpLessEq    Operator = "<="
+	OpGreater   Operator = ">"
+	OpGreaterEq Operator = ">="
+	OpExists    Operator = "EXISTS"
+	OpIn        Operator = "IN"
+)
+
+// Clause is a single `<property.path> <operator> <value>` assertion.
+type Clause struct {
+	Path     []string
+	Operator Operator
+	Value    any
+}
+
+// Rule is one `rule <name> { ... }` block. All of its clauses must hold for
+// the rule to pass - this mirrors Guard's implicit AND between clauses in a
+// single rule block.
+type Rule struct {
+	Name    string
+	Clauses []Clause
+}
+
+// RuleSet is every rule parsed from a single `.guard` file.
+type RuleSet struct {
+	Filename string
+	Rules    []Rule
+}
+
+// Parse reads a `.guard` policy file into a RuleSet.
+func Parse(filename string, content []byte) (RuleSet, error) {
+	set := RuleSet{Filename: filename}
+
+	var current *Rule
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "rule "):
+			name := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "rule ")), "{")
+			name = strings.TrimSpace(name)
+			if strings.ContainsAny(name, " \t") {
+				return RuleSet{}, xerrors.Errorf("%s:%d: unsupported rule header %q", filename, lineNo, line)
+			}
+			set.Rules = append(set.Rules, Rule{Name: name})
+			current = &set.Rules[len(set.Rules)-1]
+		case line == "}":
+			current = nil
+		default:
+			if current == nil {
+				return RuleSet{}, xerrors.Errorf("%s:%d: unsupported statement outside a rule block: %q", filename, lineNo, line)
+			}
+			clause, err := parseClause(line)
+			if err != nil {
+				return RuleSet{}, xerrors.Errorf("%s:%d: %w", filename, lineNo, err)
+			}
+			current.Clauses = append(current.Clauses, clause)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return RuleSet{}, xerrors.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	return set, nil
+}
+
+func parseClause(line string) (Clause, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Clause{}, xerrors.Errorf("empty clause")
+	}
+
+	path := strings.Split(fields[0], ".")
+
+	if len(fields) == 2 && strings.EqualFold(fields[1], "EXISTS") {
+		return Clause{Path: path, Operator: OpExists}, nil
+	}
+
+	if len(fields) < 3 {
+		return Clause{}, xerrors.Errorf("invalid clause %q", line)
+	}
+
+	op := Operator(fields[1])
+	switch op {
+	case OpEquals, OpNotEquals, OpLess, OpLessEq, OpGreater, OpGreaterEq, OpIn:
+	default:
+		return Clause{}, xerrors.Errorf("unsupported operator %q", fields[1])
+	}
+
+	rawValue := strings.Join(fields[2:], " ")
+	return Clause{Path: path, Operator: op, Value: parseValue(rawValue)}, nil
+}
+
+func parseValue(raw string) any {
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		var values []any
+		for _, item := range strings.Split(strings.Trim(raw, "[]"), ",") {
+			values = append(values, parseValue(strings.TrimSpace(item)))
+		}
+		return values
+	}
+	if unquoted := strings.Trim(raw, `"`); unquoted != raw {
+		return unquoted
+	}
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f
+	}
+	return raw
+}
+
+// Finding is the outcome of evaluating one Rule against one document.
+type Finding struct {
+	Rule    Rule
+	Passed  bool
+	Message string
+}
+
+// Evaluate runs every rule in the set against document, a plain
+// map[string]any as produced by unmarshalling JSON/YAML/Terraform input.
+func (rs RuleSet) Evaluate(document map[string]any) []Finding {
+	findings := make([]Finding, 0, len(rs.Rules))
+	for _, rule := range rs.Rules {
+		findings = append(findings, evaluateRule(rule, document))
+	}
+	return findings
+}
+
+func evaluateRule(rule Rule, document map[string]any) Finding {
+	for _, clause := range rule.Clauses {
+		if !evaluateClause(clause, document) {
+			return Finding{
+				Rule:    rule,
+				Passed:  false,
+				Message: "property " + strings.Join(clause.Path, ".") + " failed " + string(clause.Operator) + " check",
+			}
+		}
+	}
+	return Finding{Rule: rule, Passed: true}
+}
+
+func evaluateClause(clause Clause, document map[string]any) bool {
+	value, found := lookup(document, clause.Path)
+
+	switch clause.Operator {
+	case OpExists:
+		return found
+	case OpEquals:
+		return found && equal(value, clause.Value)
+	case OpNotEquals:
+		return !found || !equal(value, clause.Value)
+	case OpIn:
+		values, ok := clause.Value.([]any)
+		if !ok || !found {
+			return false
+		}
+		for _, v := range values {
+			if equal(value, v) {
+				return true
+			}
+		}
+		return false
+	case OpLess, OpLessEq, OpGreater, OpGreaterEq:
+		return found && compareNumeric(value, clause.Value, clause.Operator)
+	default:
+		return false
+	}
+}
+
+func lookup(document map[string]any, path []string) (any, bool) {
+	var current any = document
+	for _, segment := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func equal(a, b any) bool {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return af == bf
+	}
+	return a == b
+}
+
+func compareNumeric(a, b any, op Operator) bool {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if !aok || !bok {
+		return false
+	}
+	switch op {
+	case OpLess:
+		return af < bf
+	case OpLessEq:
+		return af <= bf
+	case OpGreater:
+		return af > bf
+	case OpGreaterEq:
+		return af >= bf
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Metadata builds a types.Metadata pointing at the Guard source file a
+// finding came from. Guard does not track per-property line numbers the way
+// HCL/JSON parsers do, so the range only identifies the file.
+func (f Finding) Metadata(filename string) types.Metadata {
+	return types.NewMetadata(types.Range{Filename: filename}, f.Rule.Name)
+}
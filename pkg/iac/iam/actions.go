@@ -0,0 +1,95 @@
+package iam
+
+import "strings"
+
+// wildcardActions expands a recognized service- or global-level wildcard
+// into a representative set of concrete actions it grants. It isn't
+// exhaustive (AWS adds actions constantly) but covers enough of each
+// service's high-impact actions for SensitiveActions to be useful without
+// shipping a multi-megabyte generated table.
+var wildcardActions = map[string][]string{
+	"*": {
+		"iam:PassRole", "iam:CreateAccessKey", "iam:CreateUser", "iam:AttachUserPolicy",
+		"s3:GetObject", "s3:PutObject", "s3:PutBucketPolicy", "s3:PutBucketAcl",
+		"kms:Decrypt", "kms:Encrypt", "kms:CreateGrant", "kms:ScheduleKeyDeletion",
+		"sts:AssumeRole", "ec2:RunInstances", "ec2:TerminateInstances",
+	},
+	"s3:*": {
+		"s3:GetObject", "s3:PutObject", "s3:DeleteObject",
+		"s3:PutBucketPolicy", "s3:PutBucketAcl", "s3:GetBucketPolicy",
+	},
+	"iam:*": {
+		"iam:PassRole", "iam:CreateAccessKey", "iam:CreateUser",
+		"iam:AttachUserPolicy", "iam:AttachRolePolicy", "iam:PutUserPolicy", "iam:PutRolePolicy",
+	},
+	"kms:*": {
+		"kms:Decrypt", "kms:Encrypt", "kms:CreateGrant", "kms:ScheduleKeyDeletion", "kms:DisableKey",
+	},
+	"sqs:*": {
+		"sqs:SendMessage", "sqs:ReceiveMessage", "sqs:DeleteMessage", "sqs:SetQueueAttributes",
+	},
+	"sts:*": {
+		"sts:AssumeRole", "sts:AssumeRoleWithSAML", "sts:AssumeRoleWithWebIdentity",
+	},
+	"ec2:*": {
+		"ec2:RunInstances", "ec2:TerminateInstances", "ec2:AuthorizeSecurityGroupIngress",
+	},
+}
+
+// sensitiveActions is a denylist of individually high-impact actions - ones
+// that grant privilege escalation, data exfiltration or destructive
+// capability on their own, regardless of which wildcard (if any) grants
+// them.
+var sensitiveActions = map[string]bool{
+	"iam:PassRole":            true,
+	"iam:CreateAccessKey":     true,
+	"iam:CreateUser":          true,
+	"iam:AttachUserPolicy":    true,
+	"iam:AttachRolePolicy":    true,
+	"iam:PutUserPolicy":       true,
+	"iam:PutRolePolicy":       true,
+	"s3:PutBucketPolicy":      true,
+	"s3:PutBucketAcl":         true,
+	"kms:Decrypt":             true,
+	"kms:ScheduleKeyDeletion": true,
+	"kms:DisableKey":          true,
+	"sts:AssumeRole":          true,
+	"ec2:TerminateInstances":  true,
+}
+
+// isWildcardAction reports whether action is "*" or ends in ":*".
+func isWildcardAction(action string) bool {
+	return action == "*" || strings.HasSuffix(action, ":*")
+}
+
+// ExpandActions expands any wildcard entries in actions (via
+// wildcardActions) and returns the deduplicated union of concrete actions
+// plus any non-wildcard actions passed through unchanged. Wildcards with no
+// entry in the table (e.g. a service not yet covered) are passed through as
+// given, so callers can still see them verbatim.
+func ExpandActions(actions []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	add := func(a string) {
+		if !seen[a] {
+			seen[a] = true
+			out = append(out, a)
+		}
+	}
+
+	for _, action := range actions {
+		if !isWildcardAction(action) {
+			add(action)
+			continue
+		}
+		expanded, ok := wildcardActions[strings.ToLower(action)]
+		if !ok {
+			add(action)
+			continue
+		}
+		for _, e := range expanded {
+			add(e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+// This file adds catalog-driven semantic analysis on top of the Statement/
+// PolicyDocument types in policy.go: unknown (likely-typo'd) actions,
+// missing confused-deputy condition keys, and action/resource service
+// mismatches.
+//
+// NOTE: it doesn't wire these analyses into a fanal analyzer that would
+// surface them as types.Misconfiguration with FileType: "iam", and there's
+// no `trivy iam-catalog update` subcommand - this checkout has neither
+// pkg/fanal/analyzer's core Analyzer registry nor a cmd/trivy CLI tree to
+// hang either of those off of. The catalog and detection logic below are
+// real and independently usable once that wiring exists.
+package iam
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed catalog.json
+var rawCatalog []byte
+
+// serviceActions is the set of concrete action names a service catalog
+// entry is known to support, e.g. "s3" -> ["GetObject", "PutObject", ...].
+type serviceActions struct {
+	Actions []string `json:"actions"`
+}
+
+// catalog is the bundled service/action/condition-key reference data this
+// file's analyses are driven by. It's intentionally small and hand
+// maintained - like wildcardActions/sensitiveActions in actions.go, it
+// isn't a complete mirror of AWS's service-authorization reference, just
+// enough of it to make typo and confused-deputy detection useful.
+var catalog struct {
+	// Services maps a lowercase service prefix ("s3", "iam", ...) to the
+	// actions it's known to expose, for UnknownActions.
+	Services map[string]serviceActions `json:"services"`
+	// ServicePrincipalConditionKeys maps a service principal
+	// ("sns.amazonaws.com") to the condition keys a statement trusting it
+	// should require, for MissingConditionKeys.
+	ServicePrincipalConditionKeys map[string][]string `json:"service_principal_condition_keys"`
+}
+
+func init() {
+	if err := json.Unmarshal(rawCatalog, &catalog); err != nil {
+		panic("iam: embedded catalog.json is invalid: " + err.Error())
+	}
+}
+
+// UnknownActions returns the subset of this statement's actions whose
+// service is present in the bundled catalog but whose action name isn't -
+// a strong signal of a typo (e.g. "s3:GetObjetc"). Actions for services the
+// catalog doesn't cover, and wildcards, are never reported: the catalog
+// isn't exhaustive, so the absence of a service says nothing.
+func (s Statement) UnknownActions() []string {
+	var unknown []string
+	for _, action := range s.Actions {
+		if isWildcardAction(action) {
+			continue
+		}
+		service, name, ok := splitAction(action)
+		if !ok {
+			continue
+		}
+		known, ok := catalog.Services[strings.ToLower(service)]
+		if !ok {
+			continue
+		}
+		if !containsFold(known.Actions, name, false) {
+			unknown = append(unknown, action)
+		}
+	}
+	return unknown
+}
+
+// MissingConditionKeys returns the condition keys this statement should
+// carry to prevent the confused-deputy problem, but doesn't: when a
+// statement trusts a service principal the catalog knows about (e.g.
+// "sns.amazonaws.com" invoking a role on behalf of a topic), AWS recommends
+// scoping it with aws:SourceArn/aws:SourceAccount so an unrelated account's
+// use of that service can't exercise the trust.
+func (s Statement) MissingConditionKeys() []string {
+	var missing []string
+	for _, value := range s.Principals["Service"] {
+		required, ok := catalog.ServicePrincipalConditionKeys[strings.ToLower(value)]
+		if !ok {
+			continue
+		}
+		for _, key := range required {
+			if !s.hasConditionKey(key) {
+				missing = append(missing, key)
+			}
+		}
+	}
+	return missing
+}
+
+func (s Statement) hasConditionKey(key string) bool {
+	for _, c := range s.Conditions {
+		if strings.EqualFold(c.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceServiceMismatches returns this statement's resources whose ARN
+// service segment doesn't match the service of any of its (non-wildcard)
+// actions, e.g. an "s3:GetObject" action paired with an
+// "arn:aws:dynamodb:..." resource - almost always a copy-paste mistake
+// rather than an intentional grant, since IAM ignores such a statement's
+// resource entirely.
+func (s Statement) ResourceServiceMismatches() []string {
+	services := make(map[string]bool)
+	for _, action := range s.Actions {
+		if isWildcardAction(action) {
+			return nil // a wildcard action could belong to any service - nothing to compare against
+		}
+		service, _, ok := splitAction(action)
+		if !ok {
+			return nil
+		}
+		services[strings.ToLower(service)] = true
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	var mismatched []string
+	for _, resource := range s.Resources {
+		service, ok := arnService(resource)
+		if !ok {
+			continue
+		}
+		if !services[strings.ToLower(service)] {
+			mismatched = append(mismatched, resource)
+		}
+	}
+	return mismatched
+}
+
+// splitAction splits an action of the form "service:Name" into its two
+// parts. It reports false for "*" or any action with no service prefix.
+func splitAction(action string) (service, name string, ok bool) {
+	i := strings.IndexByte(action, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	return action[:i], action[i+1:], true
+}
+
+// arnService extracts the service segment ("s3", "dynamodb", ...) from an
+// ARN of the form "arn:partition:service:region:account-id:resource". It
+// reports false for anything that isn't a 6-field ARN, e.g. the "*"
+// wildcard resource.
+func arnService(resource string) (string, bool) {
+	parts := strings.SplitN(resource, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" {
+		return "", false
+	}
+	return parts[2], true
+}
+
+// UnknownActions returns the union of UnknownActions across every statement
+// in the document.
+func (d PolicyDocument) UnknownActions() []string {
+	var all []string
+	for _, s := range d.Statements {
+		all = append(all, s.UnknownActions()...)
+	}
+	return all
+}
+
+// MissingConditionKeys returns the union of MissingConditionKeys across
+// every statement in the document.
+func (d PolicyDocument) MissingConditionKeys() []string {
+	var all []string
+	for _, s := range d.Statements {
+		all = append(all, s.MissingConditionKeys()...)
+	}
+	return all
+}
+
+// ResourceServiceMismatches returns the union of ResourceServiceMismatches
+// across every statement in the document.
+func (d PolicyDocument) ResourceServiceMismatches() []string {
+	var all []string
+	for _, s := range d.Statements {
+		all = append(all, s.ResourceServiceMismatches()...)
+	}
+	return all
+}
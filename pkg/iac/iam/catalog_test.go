@@ -0,0 +1,39 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Statement_UnknownActions(t *testing.T) {
+	s := Statement{Actions: []string{"s3:GetObject", "s3:GetObjetc", "*", "notacatalogservice:DoStuff"}}
+	assert.Equal(t, []string{"s3:GetObjetc"}, s.UnknownActions())
+}
+
+func Test_Statement_MissingConditionKeys(t *testing.T) {
+	trusted := Statement{Principals: Principals{"Service": {"sns.amazonaws.com"}}}
+	assert.Equal(t, []string{"aws:SourceArn"}, trusted.MissingConditionKeys())
+
+	scoped := Statement{
+		Principals: Principals{"Service": {"sns.amazonaws.com"}},
+		Conditions: ConditionSet{{Operator: ConditionStringEquals, Key: "aws:SourceArn", Values: []string{"arn:aws:sns:us-east-1:123456789012:topic"}}},
+	}
+	assert.Empty(t, scoped.MissingConditionKeys())
+}
+
+func Test_Statement_ResourceServiceMismatches(t *testing.T) {
+	s := Statement{
+		Actions:   []string{"s3:GetObject"},
+		Resources: []string{"arn:aws:s3:::my-bucket/*", "arn:aws:dynamodb:us-east-1:123456789012:table/other"},
+	}
+	assert.Equal(t, []string{"arn:aws:dynamodb:us-east-1:123456789012:table/other"}, s.ResourceServiceMismatches())
+}
+
+func Test_Statement_ResourceServiceMismatches_WildcardActionSkipped(t *testing.T) {
+	s := Statement{
+		Actions:   []string{"*"},
+		Resources: []string{"arn:aws:dynamodb:us-east-1:123456789012:table/other"},
+	}
+	assert.Empty(t, s.ResourceServiceMismatches())
+}
@@ -0,0 +1,145 @@
+package iam
+
+import (
+	"net"
+	"strings"
+)
+
+// ConditionOperator is an IAM condition operator, e.g. "StringEquals".
+type ConditionOperator string
+
+const (
+	ConditionStringEquals ConditionOperator = "StringEquals"
+	ConditionStringLike   ConditionOperator = "StringLike"
+	ConditionBool         ConditionOperator = "Bool"
+	ConditionIPAddress    ConditionOperator = "IpAddress"
+	ConditionArnLike      ConditionOperator = "ArnLike"
+)
+
+// Well-known condition keys referenced by NarrowsPrincipal.
+const (
+	KeySourceIP        = "aws:SourceIp"
+	KeyPrincipalOrgID  = "aws:PrincipalOrgID"
+	KeySecureTransport = "aws:SecureTransport"
+)
+
+// Condition is a single `"<Operator>": {"<Key>": [<Values>]}` entry of a
+// statement's Condition block.
+type Condition struct {
+	Operator ConditionOperator
+	Key      string
+	Values   []string
+}
+
+// Evaluate reports whether this condition holds for the given request
+// context values, keyed the same way as Key (e.g. "aws:SourceIp").
+func (c Condition) Evaluate(values map[string]string) bool {
+	actual, ok := values[c.Key]
+	if !ok {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionStringEquals:
+		return containsFold(c.Values, actual, false)
+	case ConditionStringLike:
+		return containsFold(c.Values, actual, true)
+	case ConditionArnLike:
+		return containsFold(c.Values, actual, true)
+	case ConditionBool:
+		return containsFold(c.Values, actual, false)
+	case ConditionIPAddress:
+		return matchesAnyCIDR(c.Values, actual)
+	default:
+		return false
+	}
+}
+
+// ConditionSet is the full set of conditions attached to a statement.
+type ConditionSet []Condition
+
+// NarrowsPrincipal reports whether this condition set contains a condition
+// that meaningfully restricts who a wildcard "*" principal actually reaches
+// - e.g. requiring aws:SourceIp, aws:PrincipalOrgID or aws:SecureTransport.
+// A statement with a wildcard principal but no such condition is public;
+// one with a narrowing condition is not (it's scoped to an org, a CIDR
+// range, or TLS-only callers).
+func (cs ConditionSet) NarrowsPrincipal() bool {
+	for _, c := range cs {
+		switch c.Key {
+		case KeySourceIP, KeyPrincipalOrgID, KeySecureTransport:
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, actual string, glob bool) bool {
+	for _, v := range values {
+		if glob {
+			if matchesGlob(v, actual) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(v, actual) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGlob supports the subset of StringLike/ArnLike wildcards IAM
+// allows: "*" (any sequence, including empty) and "?" (any single
+// character). It's matched via simple recursion since these patterns are
+// short and not attacker-controlled in practice.
+func matchesGlob(pattern, actual string) bool {
+	if pattern == "" {
+		return actual == ""
+	}
+	switch pattern[0] {
+	case '*':
+		if matchesGlob(pattern[1:], actual) {
+			return true
+		}
+		for i := 0; i < len(actual); i++ {
+			if matchesGlob(pattern[1:], actual[i+1:]) {
+				return true
+			}
+		}
+		return false
+	case '?':
+		if actual == "" {
+			return false
+		}
+		return matchesGlob(pattern[1:], actual[1:])
+	default:
+		if actual == "" || actual[0] != pattern[0] {
+			return false
+		}
+		return matchesGlob(pattern[1:], actual[1:])
+	}
+}
+
+func matchesAnyCIDR(cidrs []string, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, c := range cidrs {
+		if !strings.Contains(c, "/") {
+			if c == ip {
+				return true
+			}
+			continue
+		}
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			continue
+		}
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
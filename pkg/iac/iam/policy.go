@@ -0,0 +1,266 @@
+// Package iam parses and analyses AWS IAM policy documents - the JSON blobs
+// embedded in resources like aws_iam_policy, aws_iam_role_policy,
+// aws_s3_bucket_policy, aws_sqs_queue_policy and aws_kms_key, whether written
+// as a literal JSON string or produced by Terraform's jsonencode(...). It
+// exists so adapters and checks can ask "does this statement allow a
+// wildcard action/principal" or "is this effectively public" directly,
+// instead of every check hand-rolling json.unmarshal and walking the
+// resulting map itself.
+package iam
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
+// Effect is the Allow/Deny verdict of a policy statement.
+type Effect string
+
+const (
+	EffectAllow Effect = "Allow"
+	EffectDeny  Effect = "Deny"
+)
+
+// Principals maps a principal type ("AWS", "Service", "Federated", ...) to
+// the set of principal values, mirroring the shape IAM allows: either the
+// literal string "*" or an object keyed by principal type.
+type Principals map[string][]string
+
+// IsWildcard reports whether these principals amount to "anyone" - either
+// the bare "*" shorthand or an "AWS" principal containing "*".
+func (p Principals) IsWildcard() bool {
+	for _, values := range p {
+		for _, v := range values {
+			if v == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Statement is a single element of a PolicyDocument's Statement array.
+type Statement struct {
+	Sid           string
+	Effect        Effect
+	Principals    Principals
+	NotPrincipals Principals
+	Actions       []string
+	NotActions    []string
+	Resources     []string
+	NotResources  []string
+	Conditions    ConditionSet
+}
+
+// HasWildcardAction reports whether Actions contains "*" or a service-level
+// wildcard such as "s3:*".
+func (s Statement) HasWildcardAction() bool {
+	for _, action := range s.Actions {
+		if isWildcardAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWildcardPrincipal reports whether this statement's principal is "*" or
+// an "AWS": "*" entry.
+func (s Statement) HasWildcardPrincipal() bool {
+	return s.Principals.IsWildcard()
+}
+
+// SensitiveActions returns the subset of this statement's (possibly
+// wildcarded) actions that appear in the well-known sensitiveActions table,
+// e.g. "iam:PassRole" or "kms:Decrypt".
+func (s Statement) SensitiveActions() []string {
+	var found []string
+	for _, action := range ExpandActions(s.Actions) {
+		if sensitiveActions[action] {
+			found = append(found, action)
+		}
+	}
+	return found
+}
+
+// AllowsPublicAccess reports whether this statement grants access to
+// anyone, given its conditions: it must Allow, target a wildcard principal,
+// and have no condition that meaningfully restricts the wildcard (see
+// ConditionSet.NarrowsPrincipal).
+func (s Statement) AllowsPublicAccess() bool {
+	if s.Effect != EffectAllow {
+		return false
+	}
+	if !s.Principals.IsWildcard() {
+		return false
+	}
+	return !s.Conditions.NarrowsPrincipal()
+}
+
+// PolicyDocument is a parsed IAM policy document.
+type PolicyDocument struct {
+	Version    string
+	Statements []Statement
+}
+
+// rawDocument and rawStatement mirror the on-the-wire IAM JSON shape, where
+// several fields may be either a single value or an array of values.
+type rawDocument struct {
+	Version   string                  `json:"Version"`
+	Statement oneOrMany[rawStatement] `json:"Statement"`
+}
+
+type rawStatement struct {
+	Sid          string                                  `json:"Sid"`
+	Effect       Effect                                  `json:"Effect"`
+	Principal    rawPrincipal                            `json:"Principal"`
+	NotPrincipal rawPrincipal                            `json:"NotPrincipal"`
+	Action       oneOrMany[string]                       `json:"Action"`
+	NotAction    oneOrMany[string]                       `json:"NotAction"`
+	Resource     oneOrMany[string]                       `json:"Resource"`
+	NotResource  oneOrMany[string]                       `json:"NotResource"`
+	Condition    map[string]map[string]oneOrMany[string] `json:"Condition"`
+}
+
+// rawPrincipal handles the three shapes IAM allows for a Principal: absent,
+// the bare string "*", or an object of principal-type to value(s).
+type rawPrincipal struct {
+	Wildcard bool
+	Values   map[string]oneOrMany[string]
+}
+
+func (p *rawPrincipal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		p.Wildcard = wildcard == "*"
+		return nil
+	}
+	return json.Unmarshal(data, &p.Values)
+}
+
+func (p rawPrincipal) toPrincipals() Principals {
+	if p.Wildcard {
+		return Principals{"AWS": {"*"}}
+	}
+	principals := make(Principals, len(p.Values))
+	for typ, values := range p.Values {
+		principals[typ] = values.Values
+	}
+	return principals
+}
+
+// oneOrMany unmarshals either a single JSON value or an array of them into a
+// single Go slice, matching IAM's convention of allowing a bare value
+// wherever an array is accepted.
+type oneOrMany[T any] struct {
+	Values []T
+}
+
+func (o *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	var many []T
+	if err := json.Unmarshal(data, &many); err == nil {
+		o.Values = many
+		return nil
+	}
+	var one T
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	o.Values = []T{one}
+	return nil
+}
+
+// Parse decodes an IAM policy document. raw is expected to already be plain
+// JSON - callers passing Terraform's jsonencode(...) result should hand over
+// its evaluated string value, since jsonencode produces ordinary JSON.
+func Parse(raw []byte) (PolicyDocument, error) {
+	var doc rawDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return PolicyDocument{}, xerrors.Errorf("failed to parse IAM policy document: %w", err)
+	}
+
+	statements := make([]Statement, 0, len(doc.Statement.Values))
+	for _, rs := range doc.Statement.Values {
+		conditions := make(ConditionSet, 0)
+		for operator, keys := range rs.Condition {
+			for key, values := range keys {
+				conditions = append(conditions, Condition{
+					Operator: ConditionOperator(operator),
+					Key:      key,
+					Values:   values.Values,
+				})
+			}
+		}
+
+		effect := rs.Effect
+		if effect == "" {
+			effect = EffectAllow
+		}
+
+		statements = append(statements, Statement{
+			Sid:           rs.Sid,
+			Effect:        effect,
+			Principals:    rs.Principal.toPrincipals(),
+			NotPrincipals: rs.NotPrincipal.toPrincipals(),
+			Actions:       rs.Action.Values,
+			NotActions:    rs.NotAction.Values,
+			Resources:     rs.Resource.Values,
+			NotResources:  rs.NotResource.Values,
+			Conditions:    conditions,
+		})
+	}
+
+	return PolicyDocument{
+		Version:    doc.Version,
+		Statements: statements,
+	}, nil
+}
+
+// HasWildcardAction reports whether any statement in the document grants a
+// wildcard action.
+func (d PolicyDocument) HasWildcardAction() bool {
+	for _, s := range d.Statements {
+		if s.HasWildcardAction() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWildcardPrincipal reports whether any statement in the document targets
+// a wildcard principal.
+func (d PolicyDocument) HasWildcardPrincipal() bool {
+	for _, s := range d.Statements {
+		if s.HasWildcardPrincipal() {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPublicAccess reports whether any statement in the document grants
+// public access once conditions are accounted for.
+func (d PolicyDocument) AllowsPublicAccess() bool {
+	for _, s := range d.Statements {
+		if s.AllowsPublicAccess() {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitiveActions returns the union of sensitive actions granted across all
+// statements in the document.
+func (d PolicyDocument) SensitiveActions() []string {
+	seen := make(map[string]bool)
+	var all []string
+	for _, s := range d.Statements {
+		for _, action := range s.SensitiveActions() {
+			if !seen[action] {
+				seen[action] = true
+				all = append(all, action)
+			}
+		}
+	}
+	return all
+}
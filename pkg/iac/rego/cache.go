@@ -0,0 +1,29 @@
+package rego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Cache stores scan Results keyed by a caller-computed cache key (see
+// CacheKey), so ScanInput can skip straight to a cached verdict for an
+// input whose content, policy bundle and option set haven't changed since
+// the last scan. Enabled via WithCache.
+type Cache interface {
+	Get(key string) (Results, bool)
+	Put(key string, results Results) error
+}
+
+// CacheKey hashes together every component that can change a scan's
+// outcome - input content, policy bundle digest, option fingerprint, binary
+// version - into a single stable cache key. Pass one string per component
+// rather than pre-concatenating them, so components containing arbitrary
+// text can't collide across a boundary.
+func CacheKey(components ...string) string {
+	h := sha256.New()
+	for _, c := range components {
+		h.Write([]byte(c))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
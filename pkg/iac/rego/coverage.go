@@ -0,0 +1,136 @@
+package rego
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"github.com/open-policy-agent/opa/cover"
+	"golang.org/x/xerrors"
+)
+
+// CoverageReport wraps the OPA coverage report accumulated across every
+// ScanInput call made against a Scanner created with WithCoverage(true),
+// adding the serialization formats CI pipelines actually consume.
+type CoverageReport struct {
+	report *cover.Report
+}
+
+// Files returns the per-module coverage, keyed by the path passed to
+// LoadPolicies/WithPolicyReader.
+func (c *CoverageReport) Files() map[string]*cover.FileReport {
+	if c == nil || c.report == nil {
+		return nil
+	}
+	return c.report.Files
+}
+
+// Percentage returns the overall percentage of lines covered across every
+// module, 0-100.
+func (c *CoverageReport) Percentage() float64 {
+	if c == nil || c.report == nil {
+		return 0
+	}
+	return c.report.CoveragePercentage
+}
+
+// ToJSON writes the report in OPA's own `opa test --coverage` JSON shape, so
+// existing tooling built around that format (e.g. opa-coverage-to-*
+// converters) keeps working unchanged.
+func (c *CoverageReport) ToJSON(w io.Writer) error {
+	if c == nil || c.report == nil {
+		return xerrors.New("no coverage report available, was WithCoverage(true) set?")
+	}
+	return json.NewEncoder(w).Encode(c.report)
+}
+
+// ToCobertura writes the report as Cobertura XML, the format most CI
+// coverage-gate tooling (e.g. GitLab's, Jenkins' Cobertura plugin) expects.
+func (c *CoverageReport) ToCobertura(w io.Writer) error {
+	if c == nil || c.report == nil {
+		return xerrors.New("no coverage report available, was WithCoverage(true) set?")
+	}
+
+	paths := make([]string, 0, len(c.report.Files))
+	for path := range c.report.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	pkg := coberturaPackage{
+		Name:     "rego",
+		LineRate: c.report.CoveragePercentage / 100,
+	}
+	for _, path := range paths {
+		pkg.Classes = append(pkg.Classes, fileReportToCobertura(path, c.report.Files[path]))
+	}
+
+	doc := coberturaCoverage{
+		LineRate: c.report.CoveragePercentage / 100,
+		Packages: []coberturaPackage{pkg},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(doc)
+}
+
+func fileReportToCobertura(path string, fr *cover.FileReport) coberturaClass {
+	lines := make(map[int]int)
+	for _, rng := range fr.Covered {
+		for row := rng.Start.Row; row <= rng.End.Row; row++ {
+			lines[row] = 1
+		}
+	}
+	for _, rng := range fr.NotCovered {
+		for row := rng.Start.Row; row <= rng.End.Row; row++ {
+			if _, ok := lines[row]; !ok {
+				lines[row] = 0
+			}
+		}
+	}
+
+	rows := make([]int, 0, len(lines))
+	for row := range lines {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+
+	class := coberturaClass{
+		Name:     path,
+		Filename: path,
+		LineRate: fr.CoveragePercentage / 100,
+	}
+	for _, row := range rows {
+		class.Lines = append(class.Lines, coberturaLine{Number: row, Hits: lines[row]})
+	}
+	return class
+}
+
+type coberturaCoverage struct {
+	XMLName  xml.Name           `xml:"coverage"`
+	LineRate float64            `xml:"line-rate,attr"`
+	Packages []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name     string           `xml:"name,attr"`
+	LineRate float64          `xml:"line-rate,attr"`
+	Classes  []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name     string          `xml:"name,attr"`
+	Filename string          `xml:"filename,attr"`
+	LineRate float64         `xml:"line-rate,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
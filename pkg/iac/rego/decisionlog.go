@@ -0,0 +1,119 @@
+package rego
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aquasecurity/trivy/pkg/uuid"
+)
+
+// DecisionSink receives a Decision for every deny/warn rule ScanInput
+// evaluates, when the scanner was created with WithDecisionLog. Unlike
+// WithTrace's free-form text, a Decision is structured and timed, so
+// downstream tooling (a SIEM, an audit pipeline) can ingest it the same way
+// it already ingests decision logs from OPA running as a service.
+type DecisionSink interface {
+	Log(Decision) error
+}
+
+// Decision is a single rule's evaluation against a single input, shaped to
+// line up with OPA's own decision log schema (decision_id/path/input/
+// result/timestamp/metrics) at
+// https://www.openpolicyagent.org/docs/latest/management-decision-logs/.
+type Decision struct {
+	DecisionID string          `json:"decision_id"`
+	Path       string          `json:"path"`
+	Input      DecisionInput   `json:"input"`
+	Result     DecisionResult  `json:"result"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Metrics    DecisionMetrics `json:"metrics"`
+}
+
+// DecisionInput identifies the scanned input a Decision was produced from,
+// by digest rather than full contents - the same digest ScannerWithCache
+// keys on, so a decision log entry can be correlated back to a cached
+// verdict without re-embedding the whole input document.
+type DecisionInput struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+}
+
+// DecisionResult carries the rule's outcome and the metadata it declared
+// about itself, plus a human-readable description of each violation raised -
+// the same descriptions ScanInput's Results expose via Result.Description.
+type DecisionResult struct {
+	Rule       Rule     `json:"rule"`
+	Status     Status   `json:"status"`
+	Warning    bool     `json:"warning"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// DecisionMetrics mirrors the "timer_rego_*_ns" keys OPA's own decision
+// logger reports under "metrics".
+type DecisionMetrics struct {
+	TimerRegoQueryEvalNS int64 `json:"timer_rego_query_eval_ns"`
+}
+
+// JSONDecisionSink writes each Decision to w as one line of JSON, matching
+// the line-delimited format OPA's built-in decision logger writes to disk,
+// so a log shipper can tail the same file either logger produces.
+type JSONDecisionSink struct {
+	w io.Writer
+}
+
+// NewJSONDecisionSink creates a JSONDecisionSink writing decision log lines
+// to w.
+func NewJSONDecisionSink(w io.Writer) *JSONDecisionSink {
+	return &JSONDecisionSink{w: w}
+}
+
+func (j *JSONDecisionSink) Log(d Decision) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = j.w.Write(data)
+	return err
+}
+
+// logDecision records one rule's evaluation to s.decisionSink, if one was
+// configured via WithDecisionLog. A failure to log is non-fatal to the scan
+// itself - it's reported through the scanner's debug logger the same way a
+// cache write failure is.
+func (s *Scanner) logDecision(pkg, ruleName string, input Input, rule Rule, status Status, warning bool, violations []violation, duration time.Duration) {
+	if s.decisionSink == nil {
+		return
+	}
+
+	descriptions := make([]string, 0, len(violations))
+	for _, v := range violations {
+		desc, _ := v.describe(input.Path)
+		descriptions = append(descriptions, desc)
+	}
+
+	d := Decision{
+		DecisionID: uuid.New().String(),
+		Path:       strings.ReplaceAll(pkg, ".", "/") + "/" + ruleName,
+		Input: DecisionInput{
+			Path:   input.Path,
+			Digest: inputDigest(input),
+		},
+		Result: DecisionResult{
+			Rule:       rule,
+			Status:     status,
+			Warning:    warning,
+			Violations: descriptions,
+		},
+		Timestamp: time.Now(),
+		Metrics: DecisionMetrics{
+			TimerRegoQueryEvalNS: duration.Nanoseconds(),
+		},
+	}
+
+	if err := s.decisionSink.Log(d); err != nil {
+		s.logger.Log("failed to log decision for %s.%s: %s", pkg, ruleName, err)
+	}
+}
@@ -0,0 +1,151 @@
+package rego
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// violation is a single entry produced by a `deny`/`warn` rule - either a
+// boolean rule firing (no detail beyond "it fired"), a `deny[msg]` rule
+// yielding a message string, or a `deny[res]` rule yielding an object with
+// message/location overrides.
+type violation struct {
+	message  string
+	filepath string
+	startLine int
+	endLine   int
+}
+
+func (v violation) describe(defaultPath string) (string, types.Range) {
+	filename := defaultPath
+	if v.filepath != "" {
+		filename = v.filepath
+	}
+	return v.message, types.Range{
+		Filename:  filename,
+		StartLine: v.startLine,
+		EndLine:   v.endLine,
+	}
+}
+
+// violationsFromResultSet converts the raw Rego result of a deny/warn rule
+// into zero or more violations. A boolean `true` produces a single, detail-
+// free violation; a non-empty set/array produces one violation per element.
+func violationsFromResultSet(rs rego.ResultSet) []violation {
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil
+	}
+
+	switch value := rs[0].Expressions[0].Value.(type) {
+	case bool:
+		if value {
+			return []violation{{}}
+		}
+		return nil
+	case []any:
+		var violations []violation
+		for _, entry := range value {
+			violations = append(violations, violationFromValue(entry))
+		}
+		return violations
+	default:
+		return nil
+	}
+}
+
+func violationFromValue(raw any) violation {
+	switch v := raw.(type) {
+	case string:
+		return violation{message: v}
+	case map[string]any:
+		var out violation
+		if msg, ok := v["msg"].(string); ok {
+			out.message = msg
+		}
+		if fp, ok := v["filepath"].(string); ok {
+			out.filepath = fp
+		}
+		if line, ok := v["startline"].(float64); ok {
+			out.startLine = int(line)
+		}
+		if line, ok := v["endline"].(float64); ok {
+			out.endLine = int(line)
+		}
+		return out
+	default:
+		return violation{}
+	}
+}
+
+// flattenStringSets flattens the result of evaluating an `exception[x]`
+// partial set rule, where each element may itself be a string or a list of
+// strings, into a single flat list of strings.
+func flattenStringSets(rs rego.ResultSet) []string {
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil
+	}
+
+	set, ok := rs[0].Expressions[0].Value.([]any)
+	if !ok {
+		return nil
+	}
+
+	var out []string
+	for _, entry := range set {
+		switch v := entry.(type) {
+		case string:
+			out = append(out, v)
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					out = append(out, s)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func unmarshalDataDocument(filePath string, content []byte) (any, error) {
+	var doc any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// dataDocumentPath derives the dotted data path a document is published
+// under from its location relative to the data directory it was found in,
+// e.g. data dir "data", file "data/aws/services.json" -> "aws.services".
+func dataDocumentPath(dir, filePath string) []string {
+	rel := strings.TrimPrefix(filePath, dir)
+	rel = strings.TrimPrefix(rel, "/")
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	if rel == "" {
+		return nil
+	}
+	return strings.Split(rel, "/")
+}
+
+// mergeDataDocument inserts doc into data at the given dotted path,
+// overwriting any partial path segments that aren't already maps.
+func mergeDataDocument(data map[string]any, path []string, doc any) {
+	if len(path) == 0 {
+		return
+	}
+	cursor := data
+	for _, segment := range path[:len(path)-1] {
+		next, ok := cursor[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			cursor[segment] = next
+		}
+		cursor = next
+	}
+	cursor[path[len(path)-1]] = doc
+}
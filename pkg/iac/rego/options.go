@@ -0,0 +1,220 @@
+package rego
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/open-policy-agent/opa/cover"
+	"github.com/open-policy-agent/opa/topdown/print"
+
+	"github.com/aquasecurity/trivy/pkg/iac/bundle"
+)
+
+// Option configures a Scanner. Following the functional-options pattern used
+// throughout the rest of the IaC scanners (see terraform/parser.Option).
+type Option func(s *Scanner)
+
+// WithPolicyDirs sets the directories (relative to the FS passed to
+// LoadPolicies) to search for `.rego` policy files.
+func WithPolicyDirs(dirs ...string) Option {
+	return func(s *Scanner) {
+		s.policyDirs = dirs
+	}
+}
+
+// WithPolicyNamespaces restricts evaluation to rules declared under the given
+// Rego package namespaces, e.g. "user" to only run user-supplied policies
+// alongside the embedded "builtin.*" ones.
+func WithPolicyNamespaces(namespaces ...string) Option {
+	return func(s *Scanner) {
+		s.policyNamespaces = append(s.policyNamespaces, namespaces...)
+	}
+}
+
+// WithDataDirs sets directories containing data documents (JSON/YAML) to load
+// as `data.*` alongside the policies themselves.
+func WithDataDirs(dirs ...string) Option {
+	return func(s *Scanner) {
+		s.dataDirs = dirs
+	}
+}
+
+// WithDataFilesystem sets the filesystem data documents are loaded from, when
+// it differs from the one policies/inputs are loaded from.
+func WithDataFilesystem(fsys fs.FS) Option {
+	return func(s *Scanner) {
+		s.dataFS = fsys
+	}
+}
+
+// WithPolicyReader loads additional policies from a reader (e.g. a single
+// file passed on the CLI) alongside anything found via WithPolicyDirs.
+func WithPolicyReader(r io.Reader) Option {
+	return func(s *Scanner) {
+		s.policyReaders = append(s.policyReaders, r)
+	}
+}
+
+// WithTrace enables Rego evaluation tracing, written to w.
+func WithTrace(w io.Writer) Option {
+	return func(s *Scanner) {
+		s.traceWriter = w
+	}
+}
+
+// WithPerResultTracing enables capturing a separate trace per result rather
+// than one trace for the whole evaluation.
+func WithPerResultTracing(enabled bool) Option {
+	return func(s *Scanner) {
+		s.perResultTracing = enabled
+	}
+}
+
+// WithRegoErrorLimits caps how many Rego compile errors are collected
+// before compilation is aborted, so a broken policy set can't produce
+// unbounded error output.
+func WithRegoErrorLimits(limit int) Option {
+	return func(s *Scanner) {
+		s.regoErrorLimit = limit
+	}
+}
+
+// WithCustomSchemas registers additional JSON schemas by name. They take part
+// in the ScannerWithCache key but are not passed to the Rego compiler, so
+// policies are not type-checked or validated against them.
+func WithCustomSchemas(schemas map[string][]byte) Option {
+	return func(s *Scanner) {
+		s.customSchemas = schemas
+	}
+}
+
+// WithEmbeddedPolicies records whether Trivy's bundled checks are wanted in
+// addition to anything loaded via WithPolicyDirs/WithPolicyReader. This
+// package holds no bundled checks, so LoadPolicies loads none either way; the
+// setting only takes part in the ScannerWithCache key.
+func WithEmbeddedPolicies(enabled bool) Option {
+	return func(s *Scanner) {
+		s.useEmbeddedPolicies = enabled
+	}
+}
+
+// WithEmbeddedLibraries records whether Trivy's bundled Rego helper libraries
+// (shared `import data.lib.*` helpers) are wanted. This package holds no such
+// libraries, so none are loaded either way; the setting only takes part in
+// the ScannerWithCache key.
+func WithEmbeddedLibraries(enabled bool) Option {
+	return func(s *Scanner) {
+		s.useEmbeddedLibraries = enabled
+	}
+}
+
+// WithGuardDirs enables CloudFormation Guard (`.guard`) policies alongside
+// Rego: every policy directory is searched for `.guard` files in addition to
+// `.rego` ones, and their rules are evaluated against the same input.
+func WithGuardDirs(enabled bool) Option {
+	return func(s *Scanner) {
+		s.loadGuardPolicies = enabled
+	}
+}
+
+// WithIncludeDeprecatedChecks toggles whether Rego checks whose METADATA (or
+// `__rego_metadata__`) declares `deprecated: true` are evaluated. They're
+// skipped by default, mirroring how deprecated Go-registered checks are
+// already skipped.
+func WithIncludeDeprecatedChecks(enabled bool) Option {
+	return func(s *Scanner) {
+		s.includeDeprecated = enabled
+	}
+}
+
+// ScannerWithPolicyBundle configures the scanner to load its policies from a
+// remote bundle - a tar+gzip archive of `.rego`/`.guard` files, an optional
+// `data.json`, and a `manifest.json` describing its roots - fetched from url
+// (oci://, https:// or file://) instead of (or alongside) a local policy
+// directory. The bundle is downloaded, verified and cached by LoadPolicies
+// the first time it is called.
+func ScannerWithPolicyBundle(url string, opts ...bundle.Option) Option {
+	return func(s *Scanner) {
+		s.bundleURL = url
+		s.bundleOpts = opts
+	}
+}
+
+// ScannerWithPolicyBundleReader is ScannerWithPolicyBundle for a bundle
+// archive the caller already has in hand - e.g. one embedded in the binary
+// via go:embed - rather than one reachable by URL. Like
+// ScannerWithPolicyBundle, it's read and extracted the first time
+// LoadPolicies is called, not when this option is applied, so r doesn't
+// need to support seeking or multiple reads.
+func ScannerWithPolicyBundleReader(r io.Reader, opts ...bundle.Option) Option {
+	return func(s *Scanner) {
+		s.bundleReader = r
+		s.bundleOpts = opts
+	}
+}
+
+// ScannerWithPolicyLoadErrorMode controls what LoadPolicies does when a
+// policy directory fails to load, or a single `.rego`/`.guard` file within
+// it fails to parse - see PolicyLoadErrorMode. Defaults to FailFast.
+func ScannerWithPolicyLoadErrorMode(mode PolicyLoadErrorMode) Option {
+	return func(s *Scanner) {
+		s.policyLoadErrorMode = mode
+	}
+}
+
+// WithCoverage enables Rego coverage collection: every ScanInput evaluation
+// is wired up with an OPA cover.Tracer, and hit counts accumulate across the
+// scanner's lifetime. Retrieve the accumulated report via (*Scanner).Coverage.
+func WithCoverage(enabled bool) Option {
+	return func(s *Scanner) {
+		s.coverageEnabled = enabled
+		if enabled {
+			s.coverTracer = cover.New()
+		}
+	}
+}
+
+// WithWasmCompilation enables compiling every loaded policy to WASM at
+// LoadPolicies time (`opa build -t wasm`), retrievable afterwards via
+// (*Scanner).WasmModule. ScanInput itself is unaffected - it always evaluates
+// through the tree-walking interpreter - so this only matters to callers that
+// want to export a policy.wasm for an external WASM-based evaluator.
+// Compilation is skipped (WasmModule returns nil) for a policy set that calls
+// a builtin the WASM target doesn't support.
+func WithWasmCompilation(enabled bool) Option {
+	return func(s *Scanner) {
+		s.wasmEnabled = enabled
+	}
+}
+
+// WithDecisionLog routes a structured Decision to sink for every deny/warn
+// rule ScanInput evaluates, alongside (not instead of) anything WithTrace
+// already writes. See DecisionSink and JSONDecisionSink.
+func WithDecisionLog(sink DecisionSink) Option {
+	return func(s *Scanner) {
+		s.decisionSink = sink
+	}
+}
+
+// WithPrintHook registers hook to receive every `print()` call a policy
+// makes during evaluation, the same way `opa eval --enable-print` does.
+// Independent of WithPerResultTracing: hook still fires even when per-result
+// tracing (and so Result.PrintStatements) is off, and Result.PrintStatements
+// is still populated even if hook is nil.
+func WithPrintHook(hook print.Hook) Option {
+	return func(s *Scanner) {
+		s.printHook = hook
+	}
+}
+
+// ScannerWithCache enables incremental rescanning: ScanInput first checks
+// cache for a verdict keyed on the input's content, the loaded policy
+// bundle's digest, the scanner's option set and the trivy binary version,
+// and only evaluates policies on a miss. Pass a *scan.DiskCache (or any
+// other scan.Cache implementation) to enable it; nil (the default) disables
+// caching entirely.
+func ScannerWithCache(cache Cache) Option {
+	return func(s *Scanner) {
+		s.cache = cache
+	}
+}
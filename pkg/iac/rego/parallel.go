@@ -0,0 +1,60 @@
+package rego
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+)
+
+// FileResult pairs an Input's path with the Results evaluating it produced,
+// so ScanFiles callers can aggregate findings without relying on which
+// input happened to finish first.
+type FileResult struct {
+	Path    string
+	Results Results
+}
+
+// ScanFiles evaluates every policy in s against each of inputs concurrently,
+// bounded to parallelism workers (treated as 1 if lower), stopping early
+// and returning the first error if ctx is cancelled or any input fails to
+// scan.
+//
+// OPA's compiled rego.PreparedEvalQuery is safe for concurrent Eval calls,
+// and LoadPolicies must already have completed before ScanFiles is called
+// (as it must before any ScanInput call), so running ScanInput concurrently
+// here doesn't race against the scanner's own state. The returned slice is
+// sorted by Path rather than completion order, so a caller folding it into
+// a cache key or blob hash gets the same byte sequence no matter how the
+// worker pool happened to schedule the work.
+func (s *Scanner) ScanFiles(ctx context.Context, inputs []Input, parallelism int) ([]FileResult, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, parallelism)
+	out := make([]FileResult, len(inputs))
+
+	for i, input := range inputs {
+		i, input := i, input
+		sem <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			results, err := s.ScanInput(ctx, input)
+			if err != nil {
+				return xerrors.Errorf("failed to scan %s: %w", input.Path, err)
+			}
+			out[i] = FileResult{Path: input.Path, Results: results}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
@@ -0,0 +1,38 @@
+package rego
+
+import (
+	"github.com/open-policy-agent/opa/topdown/print"
+)
+
+// PrintStatement is a single `print()` call a policy made while being
+// evaluated, captured so it can be attached to the scan.Result it belongs to
+// instead of only ever reaching an external print.Hook.
+type PrintStatement struct {
+	File    string `json:"file,omitempty"`
+	Row     int    `json:"row,omitempty"`
+	Message string `json:"message"`
+}
+
+// capturingPrintHook is the print.Hook evalTraced attaches to every
+// evaluation: it always records each print() call into statements so
+// evaluatePackage can thread them onto the resulting scan.Result when
+// WithPerResultTracing is set, and forwards to the caller's own hook (set via
+// WithPrintHook), if any, so nothing already relying on print.Hook regresses.
+type capturingPrintHook struct {
+	statements *[]PrintStatement
+	forward    print.Hook
+}
+
+func (h *capturingPrintHook) Print(pctx print.Context, msg string) error {
+	stmt := PrintStatement{Message: msg}
+	if pctx.Location != nil {
+		stmt.File = pctx.Location.File
+		stmt.Row = pctx.Location.Row
+	}
+	*h.statements = append(*h.statements, stmt)
+
+	if h.forward != nil {
+		return h.forward.Print(pctx, msg)
+	}
+	return nil
+}
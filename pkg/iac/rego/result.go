@@ -0,0 +1,152 @@
+package rego
+
+import (
+	"encoding/json"
+
+	"github.com/aquasecurity/trivy/pkg/iac/severity"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// Status is the outcome of evaluating a single Rego rule against an input.
+type Status uint8
+
+const (
+	StatusPassed Status = iota
+	StatusFailed
+	StatusIgnored
+)
+
+// Rule carries the metadata a check declares about itself, either via a
+// `__rego_metadata__` document or a `# METADATA` annotation block.
+type Rule struct {
+	AVDID         string
+	AdditionalIDs []string
+	Summary       string
+	Severity      severity.Severity
+	Resolution    string
+	Links         []string
+	Deprecated    bool
+}
+
+// HasID reports whether id matches the AVD ID or any additional ID declared
+// for this rule.
+func (r Rule) HasID(id string) bool {
+	if r.AVDID == id {
+		return true
+	}
+	for _, known := range r.AdditionalIDs {
+		if known == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is the outcome of evaluating one rule against one input document.
+type Result struct {
+	status          Status
+	description     string
+	metadata        types.Metadata
+	rule            Rule
+	warning         bool
+	traces          []string
+	printStatements []PrintStatement
+}
+
+func (r Result) Status() Status {
+	return r.status
+}
+
+func (r Result) Description() string {
+	return r.description
+}
+
+func (r Result) Metadata() types.Metadata {
+	return r.metadata
+}
+
+func (r Result) Rule() Rule {
+	return r.rule
+}
+
+// IsWarning reports whether a failure came from a `warn` rule rather than a
+// `deny` rule - it is only meaningful when Status is StatusFailed.
+func (r Result) IsWarning() bool {
+	return r.warning
+}
+
+func (r Result) Traces() []string {
+	return r.traces
+}
+
+// PrintStatements returns every `print()` call made while evaluating this
+// rule, in the order OPA executed them. Only populated when the scanner was
+// created with WithPerResultTracing(true); nil otherwise, same as Traces.
+func (r Result) PrintStatements() []PrintStatement {
+	return r.printStatements
+}
+
+// resultJSON mirrors Result's unexported fields so it can round-trip
+// through JSON, e.g. when a Results value is written to the incremental
+// scan cache.
+type resultJSON struct {
+	Status          Status           `json:"status"`
+	Description     string           `json:"description"`
+	Metadata        types.Metadata   `json:"metadata"`
+	Rule            Rule             `json:"rule"`
+	Warning         bool             `json:"warning"`
+	Traces          []string         `json:"traces,omitempty"`
+	PrintStatements []PrintStatement `json:"print_statements,omitempty"`
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(resultJSON{
+		Status:          r.status,
+		Description:     r.description,
+		Metadata:        r.metadata,
+		Rule:            r.rule,
+		Warning:         r.warning,
+		Traces:          r.traces,
+		PrintStatements: r.printStatements,
+	})
+}
+
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var rj resultJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+	r.status = rj.Status
+	r.description = rj.Description
+	r.metadata = rj.Metadata
+	r.rule = rj.Rule
+	r.warning = rj.Warning
+	r.traces = rj.Traces
+	r.printStatements = rj.PrintStatements
+	return nil
+}
+
+// Results is the full set of outcomes produced by a single ScanInput call.
+type Results []Result
+
+func (r Results) GetFailed() Results {
+	return r.filter(StatusFailed)
+}
+
+func (r Results) GetPassed() Results {
+	return r.filter(StatusPassed)
+}
+
+func (r Results) GetIgnored() Results {
+	return r.filter(StatusIgnored)
+}
+
+func (r Results) filter(status Status) Results {
+	var out Results
+	for _, result := range r {
+		if result.status == status {
+			out = append(out, result)
+		}
+	}
+	return out
+}
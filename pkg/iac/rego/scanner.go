@@ -0,0 +1,772 @@
+package rego
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/cover"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/open-policy-agent/opa/topdown"
+	"github.com/open-policy-agent/opa/topdown/print"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/iac/bundle"
+	"github.com/aquasecurity/trivy/pkg/iac/debug"
+	"github.com/aquasecurity/trivy/pkg/iac/guard"
+	"github.com/aquasecurity/trivy/pkg/iac/severity"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+	"github.com/aquasecurity/trivy/pkg/version/app"
+)
+
+const (
+	namespaceExceptionsPkg = "namespace.exceptions"
+	denyPrefix             = "deny"
+	warnPrefix             = "warn"
+)
+
+// PolicyLoadErrorMode controls how LoadPolicies reacts to a policy
+// directory that fails to load, or a single `.rego`/`.guard` file within it
+// that fails to parse.
+type PolicyLoadErrorMode int
+
+const (
+	// FailFast aborts LoadPolicies on the first bad policy file. This is
+	// the default.
+	FailFast PolicyLoadErrorMode = iota
+	// SkipInvalid logs a warning identifying the offending file and
+	// continues loading the rest of the policy directory.
+	SkipInvalid
+	// FallbackToEmbedded behaves like SkipInvalid, and additionally turns on
+	// WithEmbeddedPolicies. This package holds no built-in checks, so no
+	// embedded checks are loaded: only the policies that loaded are evaluated.
+	FallbackToEmbedded
+)
+
+// Input is a single document to evaluate policies against, alongside enough
+// context (its originating filesystem and path) to resolve relative
+// references a check might make back to sibling files.
+type Input struct {
+	Path     string
+	Contents any
+	FS       fs.FS
+}
+
+// Scanner evaluates a set of Rego policies against arbitrary structured
+// input documents. It is the shared engine behind every IaC scanner
+// (Terraform, CloudFormation, Kubernetes, Dockerfile, ...) - those scanners
+// are responsible for producing the `input` document; this package only
+// knows how to run rules against it.
+type Scanner struct {
+	sourceType types.Source
+
+	policyDirs       []string
+	policyNamespaces []string
+	policyReaders    []io.Reader
+
+	dataDirs []string
+	dataFS   fs.FS
+
+	traceWriter      io.Writer
+	perResultTracing bool
+	regoErrorLimit   int
+	customSchemas    map[string][]byte
+
+	useEmbeddedPolicies  bool
+	useEmbeddedLibraries bool
+	loadGuardPolicies    bool
+	includeDeprecated    bool
+	policyLoadErrorMode  PolicyLoadErrorMode
+
+	bundleURL    string
+	bundleReader io.Reader
+	bundleOpts   []bundle.Option
+
+	cache        Cache
+	cacheVersion string
+	policyHash   hash.Hash
+	policyDigest string
+
+	modules   map[string]*ast.Module
+	guardSets []guard.RuleSet
+	compiler  *ast.Compiler
+	store     storage.Store
+
+	testFilter *regexp.Regexp
+
+	coverageEnabled bool
+	coverTracer     *cover.Tracer
+
+	wasmEnabled bool
+	wasmModule  []byte
+
+	decisionSink DecisionSink
+
+	printHook print.Hook
+
+	logger debug.Logger
+}
+
+// NewScanner creates a Scanner for the given input source type (JSON, YAML,
+// Terraform, Dockerfile, ...), which policies can match against via
+// `__rego_input__.selector`.
+func NewScanner(sourceType types.Source, opts ...Option) *Scanner {
+	s := &Scanner{
+		sourceType:     sourceType,
+		policyDirs:     []string{"."},
+		regoErrorLimit: ast.CompileErrorLimitDefault,
+		policyHash:     sha256.New(),
+		cacheVersion:   app.Version(),
+		modules:        make(map[string]*ast.Module),
+		logger:         debug.New(io.Discard, "iac", "rego"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LoadPolicies parses and compiles every `.rego` policy found under the
+// scanner's configured policy directories in fsys, plus anything supplied
+// via WithPolicyReader or ScannerWithPolicyBundle.
+func (s *Scanner) LoadPolicies(fsys fs.FS) error {
+	for _, dir := range s.policyDirs {
+		if err := s.loadPoliciesFromDir(fsys, dir); err != nil {
+			if s.policyLoadErrorMode == FailFast {
+				return xerrors.Errorf("failed to load policies from %q: %w", dir, err)
+			}
+			s.logger.Log("skipping invalid policy in %q: %s", dir, err)
+		}
+	}
+
+	if s.policyLoadErrorMode == FallbackToEmbedded {
+		s.useEmbeddedPolicies = true
+	}
+
+	if s.bundleURL != "" {
+		b, err := bundle.NewFetcher(s.bundleOpts...).Fetch(context.Background(), s.bundleURL)
+		if err != nil {
+			return xerrors.Errorf("failed to fetch policy bundle %s: %w", s.bundleURL, err)
+		}
+		if err := s.loadPolicyBundle(b, s.bundleURL); err != nil {
+			return err
+		}
+	}
+
+	if s.bundleReader != nil {
+		b, err := bundle.NewFetcher(s.bundleOpts...).FetchReader(s.bundleReader)
+		if err != nil {
+			return xerrors.Errorf("failed to read policy bundle: %w", err)
+		}
+		if err := s.loadPolicyBundle(b, "reader"); err != nil {
+			return err
+		}
+	}
+
+	for i, r := range s.policyReaders {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return xerrors.Errorf("failed to read policy reader %d: %w", i, err)
+		}
+		name := fmt.Sprintf("reader-%d.rego", i)
+		module, err := ast.ParseModuleWithOpts(name, string(data), ast.ParserOptions{ProcessAnnotation: true})
+		if err != nil {
+			return xerrors.Errorf("failed to parse %s: %w", name, err)
+		}
+		s.modules[name] = module
+		s.hashPolicySource(name, data)
+	}
+
+	data, err := s.loadDataDocuments(fsys)
+	if err != nil {
+		return xerrors.Errorf("failed to load data documents: %w", err)
+	}
+	data["namespaces"] = s.namespaces()
+	s.store = inmem.NewFromObject(data)
+
+	compiler := ast.NewCompiler().SetErrorLimit(s.regoErrorLimit).WithEnablePrintStatements(true)
+	compiler.Compile(s.modules)
+	if compiler.Failed() {
+		return xerrors.Errorf("failed to compile rego policies: %w", compiler.Errors)
+	}
+	s.compiler = compiler
+	s.policyDigest = hex.EncodeToString(s.policyHash.Sum(nil))
+
+	if s.wasmEnabled {
+		s.buildWasmModule()
+	}
+
+	return nil
+}
+
+// hashPolicySource folds a loaded policy file's path and content into the
+// scanner's running policy digest, used as part of the incremental scan
+// cache key (see ScannerWithCache) so a change to any loaded `.rego`/`.guard` file
+// or data document busts every cached result.
+func (s *Scanner) hashPolicySource(path string, data []byte) {
+	s.policyHash.Write([]byte(path))
+	s.policyHash.Write([]byte{0})
+	s.policyHash.Write(data)
+	s.policyHash.Write([]byte{0})
+}
+
+// PolicyDigest returns a stable hash of every policy file and data document
+// loaded so far. It's only meaningful after LoadPolicies returns.
+func (s *Scanner) PolicyDigest() string {
+	return s.policyDigest
+}
+
+// Coverage returns the coverage accumulated across every ScanInput call made
+// so far, or nil if the scanner wasn't created with WithCoverage(true).
+func (s *Scanner) Coverage() *CoverageReport {
+	if !s.coverageEnabled || s.coverTracer == nil {
+		return nil
+	}
+	return &CoverageReport{report: s.coverTracer.Report(s.modules)}
+}
+
+// loadPolicyBundle loads every policy under b's manifest roots (the whole
+// bundle, under "."/no scoping, if it declares none), shared by the
+// bundleURL and bundleReader paths through LoadPolicies. desc identifies the
+// bundle in an error message - the URL for bundleURL, or a fixed label for
+// bundleReader, which has no URL to report.
+func (s *Scanner) loadPolicyBundle(b *bundle.Bundle, desc string) error {
+	roots := b.Manifest.Roots
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	for _, root := range roots {
+		if err := s.loadPoliciesFromDir(b.FS, root); err != nil {
+			return xerrors.Errorf("failed to load policy bundle %s: %w", desc, err)
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) loadPoliciesFromDir(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(filePath, ".rego"):
+			data, err := fs.ReadFile(fsys, filePath)
+			if err != nil {
+				return s.handlePolicyLoadError(xerrors.Errorf("failed to read %s: %w", filePath, err))
+			}
+			module, err := ast.ParseModuleWithOpts(filePath, string(data), ast.ParserOptions{ProcessAnnotation: true})
+			if err != nil {
+				return s.handlePolicyLoadError(xerrors.Errorf("failed to parse %s: %w", filePath, err))
+			}
+			s.modules[filePath] = module
+			s.hashPolicySource(filePath, data)
+
+		case s.loadGuardPolicies && strings.HasSuffix(filePath, ".guard"):
+			data, err := fs.ReadFile(fsys, filePath)
+			if err != nil {
+				return s.handlePolicyLoadError(xerrors.Errorf("failed to read %s: %w", filePath, err))
+			}
+			ruleSet, err := guard.Parse(filePath, data)
+			if err != nil {
+				return s.handlePolicyLoadError(xerrors.Errorf("failed to parse %s: %w", filePath, err))
+			}
+			s.guardSets = append(s.guardSets, ruleSet)
+			s.hashPolicySource(filePath, data)
+		}
+
+		return nil
+	})
+}
+
+// handlePolicyLoadError applies the scanner's PolicyLoadErrorMode to a
+// single file's load/parse error: under FailFast it's returned as-is,
+// aborting the fs.WalkDir; otherwise it's logged and swallowed so loading
+// continues with the remaining policy files.
+func (s *Scanner) handlePolicyLoadError(err error) error {
+	if s.policyLoadErrorMode == FailFast {
+		return err
+	}
+	s.logger.Log("skipping invalid policy: %s", err)
+	return nil
+}
+
+func (s *Scanner) loadDataDocuments(fsys fs.FS) (map[string]any, error) {
+	data := make(map[string]any)
+	if len(s.dataDirs) == 0 {
+		return data, nil
+	}
+
+	dataFS := s.dataFS
+	if dataFS == nil {
+		dataFS = fsys
+	}
+
+	for _, dir := range s.dataDirs {
+		err := fs.WalkDir(dataFS, dir, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// an invalid/non-existent data dir is not fatal - it simply
+				// contributes no data documents.
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			switch path.Ext(filePath) {
+			case ".json", ".yaml", ".yml":
+			default:
+				return nil
+			}
+			content, err := fs.ReadFile(dataFS, filePath)
+			if err != nil {
+				return xerrors.Errorf("failed to read %s: %w", filePath, err)
+			}
+			doc, err := unmarshalDataDocument(filePath, content)
+			if err != nil {
+				return xerrors.Errorf("failed to parse %s: %w", filePath, err)
+			}
+			s.hashPolicySource(filePath, content)
+			mergeDataDocument(data, dataDocumentPath(dir, filePath), doc)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// namespaces returns the dotted package path of every loaded policy module,
+// excluding the special namespace.exceptions package - these are exposed to
+// policies as `data.namespaces` so that a single exceptions.rego can except
+// entire namespaces without the scanner needing to know about it.
+func (s *Scanner) namespaces() []string {
+	seen := make(map[string]struct{})
+	var namespaces []string
+	for _, module := range s.modules {
+		ns := strings.TrimPrefix(module.Package.Path.String(), "data.")
+		if ns == namespaceExceptionsPkg {
+			continue
+		}
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// ScanInput evaluates every loaded policy against a single input document. If
+// a Cache was configured via ScannerWithCache, a verdict already cached for
+// this exact (input, policy bundle, option set, binary version) combination
+// is returned verbatim instead of re-evaluating.
+func (s *Scanner) ScanInput(ctx context.Context, input Input) (Results, error) {
+	if s.compiler == nil {
+		return nil, xerrors.Errorf("no policies loaded - call LoadPolicies first")
+	}
+
+	var cacheKey string
+	if s.cache != nil {
+		cacheKey = CacheKey(input.Path, inputDigest(input), s.policyDigest, s.optionFingerprint(), s.cacheVersion)
+		if cached, ok := s.cache.Get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	exceptedNamespaces, err := s.evaluateNamespaceExceptions(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var results Results
+	for _, pkg := range s.namespaces() {
+		if !s.namespaceEnabled(pkg) {
+			continue
+		}
+		if !s.inputSelected(ctx, pkg, input) {
+			continue
+		}
+
+		pkgResults, err := s.evaluatePackage(ctx, pkg, input, exceptedNamespaces[pkg])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pkgResults...)
+	}
+
+	results = append(results, s.evaluateGuardRules(input)...)
+
+	if s.cache != nil {
+		if err := s.cache.Put(cacheKey, results); err != nil {
+			s.logger.Log("failed to cache scan results: %s", err)
+		}
+	}
+
+	return results, nil
+}
+
+// inputDigest hashes an Input's path and content so it can stand in for the
+// input itself in a cache key, without requiring Contents to be comparable.
+func inputDigest(input Input) string {
+	h := sha256.New()
+	h.Write([]byte(input.Path))
+	if data, err := json.Marshal(input.Contents); err == nil {
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// optionFingerprint folds every scanner option that can change a scan's
+// outcome into a single stable string, so changing e.g. the set of enabled
+// policy namespaces busts the cache rather than returning a stale verdict.
+func (s *Scanner) optionFingerprint() string {
+	namespaces := append([]string(nil), s.policyNamespaces...)
+	sort.Strings(namespaces)
+
+	schemas := make([]string, 0, len(s.customSchemas))
+	for name := range s.customSchemas {
+		schemas = append(schemas, name)
+	}
+	sort.Strings(schemas)
+
+	return strings.Join([]string{
+		strings.Join(namespaces, ","),
+		strings.Join(schemas, ","),
+		fmt.Sprintf("embeddedPolicies=%t", s.useEmbeddedPolicies),
+		fmt.Sprintf("embeddedLibraries=%t", s.useEmbeddedLibraries),
+		fmt.Sprintf("guard=%t", s.loadGuardPolicies),
+		fmt.Sprintf("perResultTracing=%t", s.perResultTracing),
+		fmt.Sprintf("regoErrorLimit=%d", s.regoErrorLimit),
+	}, "|")
+}
+
+// evaluateGuardRules runs any loaded CloudFormation Guard rule sets against
+// the input, merging their findings into the same Results alongside the
+// Rego-derived ones.
+func (s *Scanner) evaluateGuardRules(input Input) Results {
+	if len(s.guardSets) == 0 {
+		return nil
+	}
+
+	document, ok := input.Contents.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var results Results
+	for _, ruleSet := range s.guardSets {
+		for _, finding := range ruleSet.Evaluate(document) {
+			status := StatusPassed
+			if !finding.Passed {
+				status = StatusFailed
+			}
+			results = append(results, Result{
+				status:      status,
+				description: finding.Message,
+				metadata:    finding.Metadata(input.Path),
+				rule:        Rule{Summary: finding.Rule.Name},
+			})
+		}
+	}
+	return results
+}
+
+func (s *Scanner) namespaceEnabled(pkg string) bool {
+	if len(s.policyNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range s.policyNamespaces {
+		if pkg == ns || strings.HasPrefix(pkg, ns+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateNamespaceExceptions runs the well-known `namespace.exceptions`
+// package, which may except whole namespaces of checks by prefix.
+func (s *Scanner) evaluateNamespaceExceptions(ctx context.Context, input Input) (map[string]bool, error) {
+	excepted := make(map[string]bool)
+
+	rs, err := s.eval(ctx, fmt.Sprintf("data.%s.exception", namespaceExceptionsPkg), input, nil)
+	if err != nil {
+		// the namespace.exceptions package is optional - a compile/eval
+		// error here just means no namespaces are excepted.
+		return excepted, nil //nolint:nilerr
+	}
+
+	for _, exceptedNamespace := range flattenStringSets(rs) {
+		for _, pkg := range s.namespaces() {
+			if strings.HasPrefix(pkg, exceptedNamespace) {
+				excepted[pkg] = true
+			}
+		}
+	}
+
+	return excepted, nil
+}
+
+func (s *Scanner) inputSelected(ctx context.Context, pkg string, input Input) bool {
+	rs, err := s.eval(ctx, fmt.Sprintf("data.%s.__rego_input__", pkg), input, nil)
+	if err != nil || len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return true
+	}
+	raw, ok := rs[0].Expressions[0].Value.(map[string]any)
+	if !ok {
+		return true
+	}
+	selectors, ok := raw["selector"].([]any)
+	if !ok {
+		return true
+	}
+	for _, sel := range selectors {
+		selMap, ok := sel.(map[string]any)
+		if !ok {
+			continue
+		}
+		if t, ok := selMap["type"].(string); ok && types.Source(t) == s.sourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) evaluatePackage(ctx context.Context, pkg string, input Input, namespaceExcepted bool) (Results, error) {
+	rule := s.ruleMetadata(ctx, pkg, input)
+	if rule.Deprecated && !s.includeDeprecated {
+		return nil, nil
+	}
+
+	ruleExceptions := s.ruleExceptions(ctx, pkg, input)
+
+	var results Results
+	for _, ruleName := range s.denyWarnRules(pkg) {
+		start := time.Now()
+		rs, traces, prints, err := s.evalTraced(ctx, fmt.Sprintf("data.%s.%s", pkg, ruleName), input)
+		duration := time.Since(start)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to evaluate %s.%s: %w", pkg, ruleName, err)
+		}
+
+		warning := strings.HasPrefix(ruleName, warnPrefix)
+		excepted := namespaceExcepted || ruleExceptions[shortRuleName(ruleName)]
+
+		violations := violationsFromResultSet(rs)
+		if len(violations) == 0 {
+			s.logDecision(pkg, ruleName, input, rule, StatusPassed, false, nil, duration)
+			results = append(results, s.newResult(StatusPassed, "", types.Range{Filename: input.Path}, pkg, rule, false, traces, prints))
+			continue
+		}
+
+		status := StatusFailed
+		if excepted {
+			status = StatusIgnored
+		}
+		s.logDecision(pkg, ruleName, input, rule, status, warning, violations, duration)
+		for _, v := range violations {
+			desc, rng := v.describe(input.Path)
+			results = append(results, s.newResult(status, desc, rng, pkg, rule, warning, traces, prints))
+		}
+	}
+
+	return results, nil
+}
+
+func (s *Scanner) newResult(status Status, description string, rng types.Range, pkg string, rule Rule, warning bool, traces []string, prints []PrintStatement) Result {
+	return Result{
+		status:          status,
+		description:     description,
+		printStatements: prints,
+		warning:         warning,
+		rule:            rule,
+		traces:          traces,
+		metadata:        types.NewMetadata(rng, pkg),
+	}
+}
+
+// denyWarnRules returns the rule names in pkg whose name begins with "deny"
+// or "warn" - these are the only rules the scanner evaluates as checks.
+func (s *Scanner) denyWarnRules(pkg string) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, module := range s.modules {
+		if strings.TrimPrefix(module.Package.Path.String(), "data.") != pkg {
+			continue
+		}
+		for _, r := range module.Rules {
+			name := r.Head.Name.String()
+			if !strings.HasPrefix(name, denyPrefix) && !strings.HasPrefix(name, warnPrefix) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shortRuleName strips the leading "deny"/"warn" (and an optional following
+// underscore) from a rule name, matching the short names used by
+// `exception[rules]` blocks, e.g. "deny_evil" -> "evil".
+func shortRuleName(ruleName string) string {
+	for _, prefix := range []string{denyPrefix + "_", warnPrefix + "_"} {
+		if strings.HasPrefix(ruleName, prefix) {
+			return strings.TrimPrefix(ruleName, prefix)
+		}
+	}
+	return ruleName
+}
+
+func (s *Scanner) ruleExceptions(ctx context.Context, pkg string, input Input) map[string]bool {
+	excepted := make(map[string]bool)
+	rs, err := s.eval(ctx, fmt.Sprintf("data.%s.exception", pkg), input, nil)
+	if err != nil {
+		return excepted
+	}
+	for _, name := range flattenStringSets(rs) {
+		excepted[name] = true
+	}
+	return excepted
+}
+
+// ruleMetadata evaluates the `__rego_metadata__` document a check may
+// declare about itself, falling back to any `# METADATA` annotation found on
+// the package.
+func (s *Scanner) ruleMetadata(ctx context.Context, pkg string, input Input) Rule {
+	var rule Rule
+
+	if rs, err := s.eval(ctx, fmt.Sprintf("data.%s.__rego_metadata__", pkg), input, nil); err == nil && len(rs) > 0 && len(rs[0].Expressions) > 0 {
+		if raw, ok := rs[0].Expressions[0].Value.(map[string]any); ok {
+			rule.AVDID, _ = raw["avd_id"].(string)
+			rule.Summary, _ = raw["title"].(string)
+			rule.Resolution, _ = raw["recommended_actions"].(string)
+			if sev, ok := raw["severity"].(string); ok {
+				rule.Severity = severity.Severity(sev)
+			}
+			if id, ok := raw["id"].(string); ok && id != "" {
+				rule.AdditionalIDs = append(rule.AdditionalIDs, id)
+			}
+			if url, ok := raw["url"].(string); ok && url != "" {
+				rule.Links = append(rule.Links, url)
+			}
+			rule.Deprecated, _ = raw["deprecated"].(bool)
+			return rule
+		}
+	}
+
+	for _, module := range s.modules {
+		if strings.TrimPrefix(module.Package.Path.String(), "data.") != pkg {
+			continue
+		}
+		for _, ann := range module.Annotations {
+			if ann.Scope != "package" && ann.Scope != "subpackages" {
+				continue
+			}
+			rule.Summary = ann.Title
+			if action, ok := ann.Custom["recommended_action"].(string); ok {
+				rule.Resolution = action
+			}
+			if len(ann.RelatedResources) > 0 {
+				rule.Links = append(rule.Links, ann.RelatedResources[0].Ref.String())
+			}
+			if custom, ok := ann.Custom["id"].(string); ok {
+				rule.AdditionalIDs = append(rule.AdditionalIDs, custom)
+			}
+			if avdID, ok := ann.Custom["avd_id"].(string); ok {
+				rule.AVDID = avdID
+			}
+			if sev, ok := ann.Custom["severity"].(string); ok {
+				rule.Severity = severity.Severity(sev)
+			}
+			if deprecated, ok := ann.Custom["deprecated"].(bool); ok {
+				rule.Deprecated = deprecated
+			}
+		}
+	}
+
+	return rule
+}
+
+func (s *Scanner) eval(ctx context.Context, query string, input Input, _ io.Writer) (rego.ResultSet, error) {
+	rs, _, _, err := s.evalTraced(ctx, query, input)
+	return rs, err
+}
+
+func (s *Scanner) evalTraced(ctx context.Context, query string, input Input) (rego.ResultSet, []string, []PrintStatement, error) {
+	regoInput := input.Contents
+	if regoInput == nil && input.Path != "" && input.FS != nil {
+		content, err := fs.ReadFile(input.FS, strings.TrimPrefix(input.Path, "/"))
+		if err == nil {
+			regoInput = string(content)
+		}
+	}
+
+	opts := []func(*rego.Rego){
+		rego.Query(query),
+		rego.Compiler(s.compiler),
+		rego.Store(s.store),
+		rego.Input(regoInput),
+	}
+
+	var tracer *topdown.BufferTracer
+	if s.traceWriter != nil || s.perResultTracing {
+		tracer = topdown.NewBufferTracer()
+		opts = append(opts, rego.Tracer(tracer))
+	}
+
+	if s.coverageEnabled {
+		opts = append(opts, rego.Tracer(s.coverTracer))
+	}
+
+	var prints []PrintStatement
+	if s.printHook != nil || s.perResultTracing {
+		opts = append(opts, rego.PrintHook(&capturingPrintHook{statements: &prints, forward: s.printHook}))
+	}
+
+	r := rego.New(opts...)
+	rs, err := r.Eval(ctx)
+
+	var traces []string
+	if tracer != nil {
+		if s.traceWriter != nil {
+			topdown.PrettyTrace(s.traceWriter, *tracer)
+		}
+		if s.perResultTracing {
+			for _, event := range *tracer {
+				traces = append(traces, fmt.Sprintf("%v", event))
+			}
+		}
+	}
+
+	if !s.perResultTracing {
+		prints = nil
+	}
+
+	if err != nil {
+		return nil, traces, prints, err
+	}
+	return rs, traces, prints, nil
+}
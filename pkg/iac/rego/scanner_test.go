@@ -3,9 +3,11 @@ package rego_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -19,7 +21,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/iac/types"
 )
 
-func CreateFS(t *testing.T, files map[string]string) fs.FS {
+func CreateFS(t testing.TB, files map[string]string) fs.FS {
 	memfs := memoryfs.New()
 	for name, content := range files {
 		name := strings.TrimPrefix(name, "/")
@@ -714,6 +716,234 @@ deny {
 	assert.NotEmpty(t, results.GetFailed()[0].Traces())
 }
 
+type recordingDecisionSink struct {
+	decisions []rego.Decision
+}
+
+func (r *recordingDecisionSink) Log(d rego.Decision) error {
+	r.decisions = append(r.decisions, d)
+	return nil
+}
+
+func Test_RegoScanning_PrintStatements_CapturedWithPerResultTracing(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    print("checking input.evil")
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPerResultTracing(true),
+		rego.WithPolicyDirs("policies"),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	results, err := scanner.ScanInput(context.TODO(), rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"evil": true,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results.GetFailed(), 1)
+	statements := results.GetFailed()[0].PrintStatements()
+	require.Len(t, statements, 1)
+	assert.Equal(t, "checking input.evil", statements[0].Message)
+}
+
+func Test_RegoScanning_PrintStatements_EmptyByDefault(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    print("checking input.evil")
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPolicyDirs("policies"),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	results, err := scanner.ScanInput(context.TODO(), rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"evil": true,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results.GetFailed(), 1)
+	assert.Empty(t, results.GetFailed()[0].PrintStatements())
+}
+
+func Test_RegoScanning_DecisionLog(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	sink := &recordingDecisionSink{}
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithDecisionLog(sink),
+		rego.WithPolicyDirs("policies"),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	_, err := scanner.ScanInput(context.TODO(), rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"evil": true,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.decisions, 1)
+	decision := sink.decisions[0]
+	assert.Equal(t, "defsec/test/deny", decision.Path)
+	assert.Equal(t, "/evil.lol", decision.Input.Path)
+	assert.NotEmpty(t, decision.Input.Digest)
+	assert.Equal(t, rego.StatusFailed, decision.Result.Status)
+	assert.False(t, decision.Result.Warning)
+	assert.NotEmpty(t, decision.Result.Violations)
+	assert.NotEmpty(t, decision.DecisionID)
+}
+
+func Test_RegoScanning_WasmCompilation_DisabledByDefault(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPolicyDirs("policies"),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	assert.Nil(t, scanner.WasmModule())
+}
+
+func Test_RegoScanning_WasmCompilation_SkipsUnsupportedBuiltin(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    opa.runtime().env.EVIL == "true"
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithWasmCompilation(true),
+		rego.WithPolicyDirs("policies"),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	assert.Nil(t, scanner.WasmModule())
+}
+
+// Benchmark_RegoScanning_ScanInput measures the tree-walking interpreter
+// ScanInput always uses. It intentionally doesn't vary WithWasmCompilation:
+// that option only affects LoadPolicies (see Benchmark_RegoScanning_LoadPolicies
+// below) and has no effect on ScanInput itself, so a "wasm vs interpreter"
+// ScanInput comparison would just be two runs of identical code.
+func Benchmark_RegoScanning_ScanInput(b *testing.B) {
+
+	srcFS := CreateFS(b, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(types.SourceJSON, rego.WithPolicyDirs("policies"))
+	if err := scanner.LoadPolicies(srcFS); err != nil {
+		b.Fatal(err)
+	}
+
+	input := rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"evil": true,
+		},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := scanner.ScanInput(context.TODO(), input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark_RegoScanning_LoadPolicies measures the cost WithWasmCompilation
+// actually adds: an extra `opa build -t wasm` pass during LoadPolicies,
+// producing an artifact WasmModule can export but that ScanInput never
+// consults.
+func Benchmark_RegoScanning_LoadPolicies(b *testing.B) {
+
+	srcFS := CreateFS(b, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	for _, tc := range []struct {
+		name string
+		opts []rego.Option
+	}{
+		{name: "interpreter_only", opts: nil},
+		{name: "plus_wasm_compilation", opts: []rego.Option{rego.WithWasmCompilation(true)}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scanner := rego.NewScanner(types.SourceJSON, append(tc.opts, rego.WithPolicyDirs("policies"))...)
+				if err := scanner.LoadPolicies(srcFS); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func Test_dynamicMetadata(t *testing.T) {
 
 	srcFS := CreateFS(t, map[string]string{
@@ -1084,6 +1314,38 @@ deny {
 	}
 }
 
+func Test_RegoScanning_WithIncludeDeprecatedChecks(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `# METADATA
+# title: i am a deprecated check
+# custom:
+#   avd_id: AVD-EG-0123
+#   deprecated: true
+package defsec.test
+
+deny {
+  input.text
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPolicyDirs("policies"),
+		rego.WithIncludeDeprecatedChecks(true),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	results, err := scanner.ScanInput(context.TODO(), rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"text": "test",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results.GetFailed(), 1)
+}
+
 func Test_RegoScanner_WithCustomSchemas(t *testing.T) {
 
 	schema := `{
@@ -1153,3 +1415,274 @@ deny {
 		})
 	}
 }
+
+// spyCache is a minimal rego.Cache that records how many times each method
+// is called, so tests can assert a scan was (or wasn't) served from cache
+// without reaching into scanner internals.
+type spyCache struct {
+	entries map[string]rego.Results
+	gets    int
+	puts    int
+}
+
+func newSpyCache() *spyCache {
+	return &spyCache{entries: make(map[string]rego.Results)}
+}
+
+func (c *spyCache) Get(key string) (rego.Results, bool) {
+	c.gets++
+	results, ok := c.entries[key]
+	return results, ok
+}
+
+func (c *spyCache) Put(key string, results rego.Results) error {
+	c.puts++
+	c.entries[key] = results
+	return nil
+}
+
+func Test_RegoScanning_WithCache(t *testing.T) {
+
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	cache := newSpyCache()
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPolicyDirs("policies"),
+		rego.ScannerWithCache(cache),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	input := rego.Input{
+		Path: "/evil.lol",
+		Contents: map[string]any{
+			"evil": true,
+		},
+		FS: srcFS,
+	}
+
+	first, err := scanner.ScanInput(context.TODO(), input)
+	require.NoError(t, err)
+	require.Len(t, first.GetFailed(), 1)
+	assert.Equal(t, 1, cache.puts, "first scan should populate the cache")
+
+	second, err := scanner.ScanInput(context.TODO(), input)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, cache.puts, "second scan should be served from cache, not re-evaluated")
+	assert.Equal(t, 2, cache.gets)
+}
+
+func Test_RegoScanning_PolicyLoadErrorMode(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/valid.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+		"policies/broken.rego": `
+package defsec.broken
+
+deny {
+    this is not valid rego
+}
+`,
+	})
+
+	t.Run("FailFast aborts on the first bad policy", func(t *testing.T) {
+		scanner := rego.NewScanner(
+			types.SourceJSON,
+			rego.WithPolicyDirs("policies"),
+		)
+		err := scanner.LoadPolicies(srcFS)
+		assert.Error(t, err)
+	})
+
+	t.Run("SkipInvalid keeps the valid policy and drops the broken one", func(t *testing.T) {
+		scanner := rego.NewScanner(
+			types.SourceJSON,
+			rego.WithPolicyDirs("policies"),
+			rego.ScannerWithPolicyLoadErrorMode(rego.SkipInvalid),
+		)
+		require.NoError(t, scanner.LoadPolicies(srcFS))
+
+		results, err := scanner.ScanInput(context.TODO(), rego.Input{
+			Path: "/evil.lol",
+			Contents: map[string]any{
+				"evil": true,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results.GetFailed(), 1)
+	})
+
+	t.Run("FallbackToEmbedded keeps the valid policy and enables embedded checks", func(t *testing.T) {
+		scanner := rego.NewScanner(
+			types.SourceJSON,
+			rego.WithPolicyDirs("policies"),
+			rego.ScannerWithPolicyLoadErrorMode(rego.FallbackToEmbedded),
+		)
+		require.NoError(t, scanner.LoadPolicies(srcFS))
+
+		results, err := scanner.ScanInput(context.TODO(), rego.Input{
+			Path: "/evil.lol",
+			Contents: map[string]any{
+				"evil": true,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results.GetFailed(), 1)
+	})
+}
+
+func Test_ScanFiles_ParallelMatchesSerial(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(types.SourceJSON, rego.WithPolicyDirs("policies"))
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	var inputs []rego.Input
+	for i := 0; i < 20; i++ {
+		inputs = append(inputs, rego.Input{
+			Path: fmt.Sprintf("/file-%02d.json", i),
+			Contents: map[string]any{
+				"evil": i%2 == 0,
+			},
+		})
+	}
+
+	serial, err := scanner.ScanFiles(context.TODO(), inputs, 1)
+	require.NoError(t, err)
+
+	parallel, err := scanner.ScanFiles(context.TODO(), inputs, 8)
+	require.NoError(t, err)
+
+	require.Len(t, parallel, len(serial))
+	for i := range serial {
+		assert.Equal(t, serial[i].Path, parallel[i].Path)
+		assert.Equal(t, serial[i].Results, parallel[i].Results)
+	}
+
+	// paths come back sorted regardless of the order inputs were given in
+	for i := 1; i < len(parallel); i++ {
+		assert.Less(t, parallel[i-1].Path, parallel[i].Path)
+	}
+}
+
+func Test_ScanFiles_PropagatesError(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+`,
+	})
+
+	scanner := rego.NewScanner(types.SourceJSON, rego.WithPolicyDirs("policies"))
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	bad := rego.NewScanner(types.SourceJSON, rego.WithPolicyDirs("policies"))
+	// bad is intentionally never loaded, so ScanInput fails for it.
+	_, err := bad.ScanFiles(context.TODO(), []rego.Input{{Path: "/a.json"}}, 2)
+	assert.Error(t, err)
+}
+
+func Test_RunTests(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+deny {
+    input.evil
+}
+
+test_deny_catches_evil {
+    deny with input as {"evil": true}
+}
+
+test_deny_ignores_good {
+    not deny with input as {"evil": false}
+}
+
+test_this_always_fails {
+    false
+}
+`,
+		"policies/skipped.rego": `# METADATA
+# custom:
+#   skip: true
+package defsec.skipped
+
+test_never_runs {
+    false
+}
+`,
+	})
+
+	scanner := rego.NewScanner(types.SourceJSON, rego.WithPolicyDirs("policies"))
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	results, err := scanner.RunTests(context.TODO())
+	require.NoError(t, err)
+
+	require.Len(t, results.Passed(), 2)
+	require.Len(t, results.Failed(), 1)
+	require.Len(t, results.Skipped(), 1)
+
+	assert.Equal(t, "defsec.skipped.test_never_runs", results.Skipped()[0].Name())
+	assert.Equal(t, "defsec.test.test_this_always_fails", results.Failed()[0].Name())
+}
+
+func Test_RunTests_WithTestFilter(t *testing.T) {
+	srcFS := CreateFS(t, map[string]string{
+		"policies/test.rego": `
+package defsec.test
+
+test_one {
+    true
+}
+
+test_two {
+    true
+}
+`,
+	})
+
+	scanner := rego.NewScanner(
+		types.SourceJSON,
+		rego.WithPolicyDirs("policies"),
+		rego.WithTestFilter(regexp.MustCompile(`test_one$`)),
+	)
+	require.NoError(t, scanner.LoadPolicies(srcFS))
+
+	results, err := scanner.RunTests(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "defsec.test.test_one", results[0].Name())
+}
+
+func Test_RunTests_RequiresLoadPolicies(t *testing.T) {
+	scanner := rego.NewScanner(types.SourceJSON)
+	_, err := scanner.RunTests(context.TODO())
+	assert.Error(t, err)
+}
@@ -0,0 +1,195 @@
+package rego
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const testPrefix = "test_"
+
+// TestStatus is the outcome of evaluating a single `test_*` rule.
+type TestStatus uint8
+
+const (
+	TestPassed TestStatus = iota
+	TestFailed
+	TestSkipped
+)
+
+// TestResult is the outcome of evaluating one `test_*` rule found by
+// RunTests, mirroring how `go test -v` reports a single test function.
+type TestResult struct {
+	Package  string
+	Rule     string
+	Status   TestStatus
+	Duration time.Duration
+	Err      error
+	Traces   []string
+}
+
+// Name returns the fully-qualified rule name, e.g. "kubernetes.disallowed_tag.test_disallows_latest".
+func (r TestResult) Name() string {
+	return fmt.Sprintf("%s.%s", r.Package, r.Rule)
+}
+
+// TestResults is the full set of outcomes produced by a single RunTests call.
+type TestResults []TestResult
+
+func (r TestResults) Passed() TestResults {
+	return r.filter(TestPassed)
+}
+
+func (r TestResults) Failed() TestResults {
+	return r.filter(TestFailed)
+}
+
+func (r TestResults) Skipped() TestResults {
+	return r.filter(TestSkipped)
+}
+
+func (r TestResults) filter(status TestStatus) TestResults {
+	var out TestResults
+	for _, result := range r {
+		if result.Status == status {
+			out = append(out, result)
+		}
+	}
+	return out
+}
+
+// WithTestFilter restricts RunTests to rules whose fully-qualified name
+// (e.g. "kubernetes.disallowed_tag.test_disallows_latest") matches re. Unset,
+// RunTests evaluates every `test_*` rule found in the compiled module set.
+func WithTestFilter(re *regexp.Regexp) Option {
+	return func(s *Scanner) {
+		s.testFilter = re
+	}
+}
+
+// RunTests discovers every `test_*` rule in the module set LoadPolicies
+// compiled and evaluates each one against the scanner's data tree with no
+// input, reporting pass/fail/skip the way `go test -v` does. A module whose
+// `# METADATA` declares `custom.skip: true` has all of its tests reported as
+// TestSkipped rather than evaluated.
+//
+// LoadPolicies must be called first; RunTests does not load policies itself.
+func (s *Scanner) RunTests(ctx context.Context) (TestResults, error) {
+	if s.compiler == nil {
+		return nil, xerrors.New("no policies loaded, call LoadPolicies first")
+	}
+
+	var results TestResults
+	for _, pkg := range s.testPackages() {
+		skip := s.packageSkipped(pkg)
+		for _, rule := range s.testRules(pkg) {
+			name := fmt.Sprintf("%s.%s", pkg, rule)
+			if s.testFilter != nil && !s.testFilter.MatchString(name) {
+				continue
+			}
+
+			if skip {
+				results = append(results, TestResult{Package: pkg, Rule: rule, Status: TestSkipped})
+				continue
+			}
+
+			results = append(results, s.runTest(ctx, pkg, rule))
+		}
+	}
+	return results, nil
+}
+
+func (s *Scanner) runTest(ctx context.Context, pkg, rule string) TestResult {
+	start := time.Now()
+	rs, traces, err := s.evalTraced(ctx, fmt.Sprintf("data.%s.%s", pkg, rule), Input{})
+	result := TestResult{
+		Package:  pkg,
+		Rule:     rule,
+		Duration: time.Since(start),
+		Traces:   traces,
+	}
+
+	switch {
+	case err != nil:
+		result.Status = TestFailed
+		result.Err = err
+	case len(rs) == 0 || len(rs[0].Expressions) == 0:
+		result.Status = TestFailed
+		result.Err = xerrors.New("rule produced no result")
+	default:
+		passed, ok := rs[0].Expressions[0].Value.(bool)
+		if !ok || !passed {
+			result.Status = TestFailed
+		} else {
+			result.Status = TestPassed
+		}
+	}
+	return result
+}
+
+// testPackages returns the sorted, de-duplicated set of package paths
+// declared across the compiled module set.
+func (s *Scanner) testPackages() []string {
+	seen := make(map[string]struct{})
+	var pkgs []string
+	for _, module := range s.modules {
+		pkg := strings.TrimPrefix(module.Package.Path.String(), "data.")
+		if _, ok := seen[pkg]; ok {
+			continue
+		}
+		seen[pkg] = struct{}{}
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// testRules returns the rule names in pkg whose name begins with "test_",
+// mirroring denyWarnRules but filtering on the test prefix instead.
+func (s *Scanner) testRules(pkg string) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, module := range s.modules {
+		if strings.TrimPrefix(module.Package.Path.String(), "data.") != pkg {
+			continue
+		}
+		for _, r := range module.Rules {
+			name := r.Head.Name.String()
+			if !strings.HasPrefix(name, testPrefix) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// packageSkipped reports whether pkg declares `custom.skip: true` on a
+// package-scoped `# METADATA` annotation, mirroring how ruleMetadata reads
+// `custom.deprecated`.
+func (s *Scanner) packageSkipped(pkg string) bool {
+	for _, module := range s.modules {
+		if strings.TrimPrefix(module.Package.Path.String(), "data.") != pkg {
+			continue
+		}
+		for _, ann := range module.Annotations {
+			if ann.Scope != "package" && ann.Scope != "subpackages" {
+				continue
+			}
+			if skip, ok := ann.Custom["skip"].(bool); ok && skip {
+				return true
+			}
+		}
+	}
+	return false
+}
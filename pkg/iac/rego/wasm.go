@@ -0,0 +1,130 @@
+package rego
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/compile"
+)
+
+// wasmUnsupportedBuiltins lists builtins a loaded check might call that OPA's
+// WASM target can't compile: ones that need a Go runtime (opa.runtime,
+// http.send, net.lookup_ip_addr) and ones Trivy itself registers as custom
+// builtins, which the WASM compiler has never heard of. Any module calling
+// one of these causes buildWasmModule to skip compilation entirely rather
+// than fail on just that module, since ScanInput evaluates every loaded
+// module together.
+var wasmUnsupportedBuiltins = []string{
+	"opa.runtime",
+	"http.send",
+	"net.lookup_ip_addr",
+}
+
+// buildWasmModule compiles every policy loaded so far into a single WASM
+// binary via `opa build -t wasm` (github.com/open-policy-agent/opa/compile),
+// caching the result on s.wasmModule. It's called from LoadPolicies when the
+// scanner was created with WithWasmCompilation(true).
+//
+// It's best-effort: a module parse error never reaches here (LoadPolicies
+// would already have failed), but a module calling a builtin WASM can't
+// compile, or the compile step itself failing, just leaves s.wasmModule nil
+// and logs why - ScanInput always evaluates through the tree-walking
+// interpreter regardless, so scanning still works, just without an exported
+// WASM module.
+func (s *Scanner) buildWasmModule() {
+	for name, module := range s.modules {
+		if builtin := firstUnsupportedBuiltin(module.String()); builtin != "" {
+			s.logger.Log("skipping wasm compilation: %s calls unsupported builtin %q", name, builtin)
+			return
+		}
+	}
+
+	entrypoints := s.wasmEntrypoints()
+	if len(entrypoints) == 0 {
+		s.logger.Log("skipping wasm compilation: no deny/warn rules to use as entrypoints")
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "trivy-rego-wasm-*")
+	if err != nil {
+		s.logger.Log("skipping wasm compilation: %s", err)
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for name, module := range s.modules {
+		path := filepath.Join(dir, wasmSourceName(name))
+		if err := os.WriteFile(path, []byte(module.String()), 0o600); err != nil {
+			s.logger.Log("skipping wasm compilation: failed to stage %s: %s", name, err)
+			return
+		}
+	}
+
+	c := compile.New().
+		WithTarget("wasm").
+		WithEntrypoints(entrypoints...).
+		WithPaths(dir)
+
+	if err := c.Build(context.Background()); err != nil {
+		s.logger.Log("skipping wasm compilation: %s", err)
+		return
+	}
+
+	for _, wasmModule := range c.Bundle().WasmModules {
+		s.wasmModule = wasmModule.Raw
+		return
+	}
+}
+
+// wasmEntrypoints returns every deny/warn rule across every loaded namespace,
+// formatted the way `opa build -e` expects them (slash-separated, no leading
+// "data."), for use as the WASM build's entrypoints.
+func (s *Scanner) wasmEntrypoints() []string {
+	var entrypoints []string
+	for _, ns := range s.namespaces() {
+		for _, rule := range s.denyWarnRules(ns) {
+			entrypoints = append(entrypoints, strings.ReplaceAll(ns, ".", "/")+"/"+rule)
+		}
+	}
+	sort.Strings(entrypoints)
+	return entrypoints
+}
+
+// wasmSourceName maps a module name (a loaded policy's source path, which may
+// contain slashes or come from a bundle/reader with no real filesystem
+// location) to a flat, collision-free filename under the staging directory
+// buildWasmModule compiles from.
+func wasmSourceName(moduleName string) string {
+	sum := sha256.Sum256([]byte(moduleName))
+	return hex.EncodeToString(sum[:8]) + ".rego"
+}
+
+// firstUnsupportedBuiltin returns the first WASM-unsupported builtin called
+// in src, or "" if none are. This is a plain substring search rather than an
+// AST walk: good enough to decide whether to attempt compilation, at the
+// cost of being fooled by a builtin name appearing in a comment or string.
+func firstUnsupportedBuiltin(src string) string {
+	for _, builtin := range wasmUnsupportedBuiltins {
+		if strings.Contains(src, builtin+"(") {
+			return builtin
+		}
+	}
+	return ""
+}
+
+// WasmModule returns the WASM binary compiled from every policy loaded so
+// far, or nil if WithWasmCompilation wasn't enabled, LoadPolicies hasn't run
+// yet, or compilation was skipped (see buildWasmModule).
+//
+// ScanInput always evaluates through the tree-walking interpreter - including
+// __rego_metadata__, input selectors and exceptions - regardless of whether a
+// WASM module is available; WasmModule exists for callers that want to export
+// policy.wasm to an external WASM-based evaluator.
+func (s *Scanner) WasmModule() []byte {
+	return s.wasmModule
+}
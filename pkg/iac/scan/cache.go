@@ -0,0 +1,99 @@
+// Package scan provides the incremental-rescan cache used by the Rego
+// scanner: a Results value keyed by a stable hash of everything that could
+// change the outcome of a scan (the input content, the loaded policy
+// bundle, the scanner's option set, and the binary version), so rescanning
+// an unchanged module in a monorepo can skip straight to a cached verdict.
+package scan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	fanalutils "github.com/aquasecurity/trivy/pkg/fanal/utils"
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+)
+
+// Cache is an alias of rego.Cache, kept here so callers that only need to
+// construct/invalidate a cache don't also need to import the rego package.
+type Cache = rego.Cache
+
+// Key re-exports rego.CacheKey for callers assembling a cache key outside
+// the scanner itself (e.g. to pre-invalidate a root module's entry).
+func Key(components ...string) string {
+	return rego.CacheKey(components...)
+}
+
+// DiskCache is the default Cache implementation: one JSON file per key under
+// a cache directory, defaulting to the shared Trivy cache dir.
+type DiskCache struct {
+	dir string
+}
+
+// DiskCacheOption configures a DiskCache.
+type DiskCacheOption func(c *DiskCache)
+
+// WithCacheDir overrides the directory cached results are stored under.
+func WithCacheDir(dir string) DiskCacheOption {
+	return func(c *DiskCache) {
+		c.dir = dir
+	}
+}
+
+// NewDiskCache creates a DiskCache rooted at ~/.cache/trivy/iac (or its
+// platform equivalent, or os.TempDir() as a last resort) unless overridden
+// via WithCacheDir.
+func NewDiskCache(opts ...DiskCacheOption) *DiskCache {
+	c := &DiskCache{
+		dir: filepath.Join(fanalutils.CacheDir(), "trivy", "iac"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the cached Results for key, if present.
+func (c *DiskCache) Get(key string) (rego.Results, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var results rego.Results
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+// Put stores results under key, overwriting any existing entry.
+func (c *DiskCache) Put(key string, results rego.Results) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return xerrors.Errorf("failed to create cache dir %s: %w", c.dir, err)
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal cached results: %w", err)
+	}
+	if err := os.WriteFile(c.path(key), data, 0o600); err != nil {
+		return xerrors.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+// Invalidate removes a single root module's cached entry, e.g. in response
+// to a ScannerWithConfigsFileSystem change that's known to only affect that
+// root.
+func (c *DiskCache) Invalidate(key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("failed to invalidate cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, strings.TrimSpace(key)+".json")
+}
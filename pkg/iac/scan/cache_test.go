@@ -0,0 +1,53 @@
+package scan_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/iac/scan"
+)
+
+func Test_DiskCache_MissThenHit(t *testing.T) {
+	dir := t.TempDir()
+	c := scan.NewDiskCache(scan.WithCacheDir(dir))
+
+	key := scan.Key("module-a", "policy-digest", "option-fingerprint", "v0.0.0-test")
+
+	_, ok := c.Get(key)
+	assert.False(t, ok, "entry should not exist yet")
+
+	var results rego.Results
+	require.NoError(t, json.Unmarshal([]byte(`[{"status":1,"description":"test failure"}]`), &results))
+	require.NoError(t, c.Put(key, results))
+
+	cached, ok := c.Get(key)
+	require.True(t, ok)
+	assert.Equal(t, results, cached)
+}
+
+func Test_DiskCache_Invalidate(t *testing.T) {
+	dir := t.TempDir()
+	c := scan.NewDiskCache(scan.WithCacheDir(dir))
+
+	key := scan.Key("module-a")
+	require.NoError(t, c.Put(key, rego.Results{}))
+
+	_, ok := c.Get(key)
+	require.True(t, ok)
+
+	require.NoError(t, c.Invalidate(key))
+
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+}
+
+func Test_DiskCache_DefaultDir(t *testing.T) {
+	c := scan.NewDiskCache()
+	require.NotNil(t, c)
+	assert.NotEmpty(t, filepath.Clean(scan.Key("x")))
+}
@@ -0,0 +1,54 @@
+// Package bicep will eventually transpile .bicep files into the Azure ARM
+// JSON IR pkg/iac/scanners/azure evaluates, preserving enough information
+// to blame a finding on its original .bicep line instead of a line in the
+// generated JSON. This file implements only the source-map half of that:
+// given a transpiler's generated-to-source line mapping, translate a
+// generated-document line back to where it came from.
+//
+// NOTE: neither the Azure ARM scanner itself nor a Bicep transpiler
+// (embedded or shelling out to a `bicep build` found on PATH) exist in
+// this checkout - only a stray pkg/iac/scanners/azure/functions test file
+// referencing ARM template functions that aren't implemented anywhere
+// here either - so there's no real pipeline yet to plug a transpiler or
+// TestBicepMisconfigurationScan into. SourceMap itself is real and
+// independently testable.
+package bicep
+
+import "sort"
+
+// SourceMap records, for a transpiled ARM JSON document, which line of the
+// original .bicep source produced each line of the generated JSON.
+type SourceMap struct {
+	entries map[int]int
+	lines   []int // sorted generated-line keys, for Resolve's fallback
+}
+
+// NewSourceMap builds a SourceMap from generated-to-source line pairs, as a
+// Bicep-to-ARM transpiler would emit alongside its JSON output.
+func NewSourceMap(pairs map[int]int) SourceMap {
+	entries := make(map[int]int, len(pairs))
+	lines := make([]int, 0, len(pairs))
+	for generated, source := range pairs {
+		entries[generated] = source
+		lines = append(lines, generated)
+	}
+	sort.Ints(lines)
+	return SourceMap{entries: entries, lines: lines}
+}
+
+// Resolve translates a line in the generated ARM JSON back to the .bicep
+// line that produced it. If generatedLine has no exact entry - e.g. it
+// falls inside a multi-line value the transpiler only mapped by its first
+// line - Resolve falls back to the closest mapped line at or before it,
+// since that's still within the same logical statement.
+func (m SourceMap) Resolve(generatedLine int) (int, bool) {
+	if source, ok := m.entries[generatedLine]; ok {
+		return source, true
+	}
+
+	i := sort.SearchInts(m.lines, generatedLine)
+	if i == 0 {
+		return 0, false
+	}
+	return m.entries[m.lines[i-1]], true
+}
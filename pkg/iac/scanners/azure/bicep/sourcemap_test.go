@@ -0,0 +1,32 @@
+package bicep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure/bicep"
+)
+
+func Test_SourceMap_Resolve_ExactMatch(t *testing.T) {
+	sm := bicep.NewSourceMap(map[int]int{1: 1, 5: 3, 9: 7})
+
+	line, ok := sm.Resolve(5)
+	assert.True(t, ok)
+	assert.Equal(t, 3, line)
+}
+
+func Test_SourceMap_Resolve_FallsBackToClosestPrecedingLine(t *testing.T) {
+	sm := bicep.NewSourceMap(map[int]int{1: 1, 5: 3, 9: 7})
+
+	line, ok := sm.Resolve(7)
+	assert.True(t, ok)
+	assert.Equal(t, 3, line)
+}
+
+func Test_SourceMap_Resolve_BeforeFirstEntry(t *testing.T) {
+	sm := bicep.NewSourceMap(map[int]int{5: 3})
+
+	_, ok := sm.Resolve(1)
+	assert.False(t, ok)
+}
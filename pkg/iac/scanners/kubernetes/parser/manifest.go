@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a single Kubernetes (or Helm-rendered) YAML document parsed
+// from a `---`-separated file.
+type Manifest struct {
+	Path    string
+	Content *Content
+}
+
+// Content wraps a document's root yaml.Node together with Offset, the
+// 0-based line this document began at in the original (possibly
+// multi-document) file. yaml.Node.Line is always relative to the start of
+// whatever was handed to yaml.Unmarshal - once a file has been split on
+// `---` separators, that's the document, not the file - so Offset is what
+// lets ToRego translate a node's Line back into the file's real line
+// numbering.
+type Content struct {
+	*yaml.Node
+	Offset int
+}
+
+// UnmarshalYAML captures the raw node tree instead of decoding into a
+// generic structure, so ToRego can later read back each node's exact
+// Line/Column.
+func (m *Manifest) UnmarshalYAML(node *yaml.Node) error {
+	m.Content = &Content{Node: node}
+	return nil
+}
+
+// Position is the exact source location a single Rego-visible value came
+// from.
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+// SourceMap maps a Rego-visible node to the Position it was parsed from. A
+// node is keyed by its dotted field path rooted at the document (e.g.
+// "spec.containers[0].image"), since the converted map[string]any/[]any tree
+// Rego evaluates doesn't preserve pointer identity back to the yaml.Node it
+// came from.
+type SourceMap map[string]Position
+
+// ToRego converts Content into the plain map[string]any/[]any/scalar tree
+// OPA's rego.EvalInput expects, alongside a SourceMap recording where every
+// value in that tree came from - so a renderer showing a misconfiguration
+// (e.g. the table writer's misconfig section) can point at the precise
+// source line even for a concatenated multi-document manifest or
+// Helm-rendered output, where a naive newline count drifts out of sync past
+// the first document.
+func (m *Manifest) ToRego() (any, SourceMap) {
+	sm := make(SourceMap)
+	if m.Content == nil {
+		return nil, sm
+	}
+	value := nodeToRego(m.Content.Node, "", m.Path, m.Content.Offset, sm)
+	return value, sm
+}
+
+func nodeToRego(node *yaml.Node, path, file string, offset int, sm SourceMap) any {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return nodeToRego(node.Content[0], path, file, offset, sm)
+	case yaml.MappingNode:
+		result := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, valNode := node.Content[i].Value, node.Content[i+1]
+			result[key] = nodeToRego(valNode, childPath(path, key), file, offset, sm)
+		}
+		recordPosition(sm, path, file, node, offset)
+		return result
+	case yaml.SequenceNode:
+		result := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			result[i] = nodeToRego(item, fmt.Sprintf("%s[%d]", path, i), file, offset, sm)
+		}
+		recordPosition(sm, path, file, node, offset)
+		return result
+	default: // scalar
+		recordPosition(sm, path, file, node, offset)
+		var value any
+		_ = node.Decode(&value)
+		return value
+	}
+}
+
+func recordPosition(sm SourceMap, path, file string, node *yaml.Node, offset int) {
+	sm[path] = Position{
+		File: file,
+		Line: node.Line + offset,
+		Col:  node.Column,
+	}
+}
+
+func childPath(base, key string) string {
+	if base == "" {
+		return key
+	}
+	return base + "." + key
+}
@@ -43,7 +43,17 @@ func New(po ...options.ParserOption) *Parser {
 }
 
 func (p *Parser) ParseFS(ctx context.Context, target fs.FS, path string) (map[string][]any, error) {
+	files, _, err := p.ParseFSWithSourceMap(ctx, target, path)
+	return files, err
+}
+
+// ParseFSWithSourceMap is ParseFS, but also returns the SourceMap each parsed
+// document was built from, keyed the same way as the returned documents
+// (file path, then document index), so a caller rendering a misconfiguration
+// can look up the precise {file, line, col} a Rego-visible field came from.
+func (p *Parser) ParseFSWithSourceMap(ctx context.Context, target fs.FS, path string) (map[string][]any, map[string][]SourceMap, error) {
 	files := make(map[string][]any)
+	sourceMaps := make(map[string][]SourceMap)
 	if err := fs.WalkDir(target, filepath.ToSlash(path), func(path string, entry fs.DirEntry, err error) error {
 		select {
 		case <-ctx.Done():
@@ -59,24 +69,32 @@ func (p *Parser) ParseFS(ctx context.Context, target fs.FS, path string) (map[st
 		if !p.required(target, path) {
 			return nil
 		}
-		parsed, err := p.ParseFile(ctx, target, path)
+		parsed, sms, err := p.ParseFileWithSourceMap(ctx, target, path)
 		if err != nil {
 			p.debug.Log("Parse error in '%s': %s", path, err)
 			return nil
 		}
 		files[path] = parsed
+		sourceMaps[path] = sms
 		return nil
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return files, nil
+	return files, sourceMaps, nil
 }
 
 // ParseFile parses Kubernetes manifest from the provided filesystem path.
 func (p *Parser) ParseFile(_ context.Context, fsys fs.FS, path string) ([]any, error) {
+	parsed, _, err := p.ParseFileWithSourceMap(context.Background(), fsys, path)
+	return parsed, err
+}
+
+// ParseFileWithSourceMap is ParseFile, but also returns the SourceMap for
+// each returned document.
+func (p *Parser) ParseFileWithSourceMap(_ context.Context, fsys fs.FS, path string) ([]any, []SourceMap, error) {
 	f, err := fsys.Open(filepath.ToSlash(path))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() { _ = f.Close() }()
 	return p.Parse(f, path)
@@ -97,41 +115,75 @@ func (p *Parser) required(fsys fs.FS, path string) bool {
 	return false
 }
 
-func (p *Parser) Parse(r io.Reader, path string) ([]any, error) {
+// docSeparator matches the `---` line a multi-document YAML file uses to
+// concatenate several manifests into one file.
+var docSeparator = regexp.MustCompile(`(?m:^---\r?\n)`)
+
+// Parse parses every document in r, returning each as the plain
+// map[string]any/[]any/scalar tree Rego expects, alongside the SourceMap it
+// was built from.
+func (p *Parser) Parse(r io.Reader, path string) ([]any, []SourceMap, error) {
 
 	contents, err := io.ReadAll(r)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(contents) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	if strings.TrimSpace(string(contents))[0] == '{' {
 		var target any
 		if err := json.Unmarshal(contents, &target); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		return []any{target}, nil
+		return []any{target}, []SourceMap{nil}, nil
 	}
 
 	var results []any
+	var sourceMaps []SourceMap
 
-	re := regexp.MustCompile(`(?m:^---\r?\n)`)
-	pos := 0
-	for _, partial := range re.Split(string(contents), -1) {
+	for _, doc := range splitDocuments(contents) {
 		var result Manifest
 		result.Path = path
-		if err := yaml.Unmarshal([]byte(partial), &result); err != nil {
-			return nil, fmt.Errorf("unmarshal yaml: %w", err)
+		if err := yaml.Unmarshal(doc.content, &result); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal yaml: %w", err)
 		}
 		if result.Content != nil {
-			result.Content.Offset = pos
-			results = append(results, result.ToRego())
+			result.Content.Offset = doc.startLine
+			value, sm := result.ToRego()
+			results = append(results, value)
+			sourceMaps = append(sourceMaps, sm)
 		}
-		pos += len(strings.Split(partial, "\n"))
 	}
 
-	return results, nil
+	return results, sourceMaps, nil
+}
+
+// document is a single `---`-delimited chunk of a multi-document YAML file.
+type document struct {
+	content   []byte
+	startLine int // 0-based line this document's content begins at in the original file
+}
+
+// splitDocuments splits contents on docSeparator, recording each document's
+// true starting line from the separator's byte offset in the original
+// slice - rather than re-deriving it by counting len(strings.Split(partial,
+// "\n")) over each already-split fragment, which double-counts on CRLF files
+// (the fragment's trailing "\r" survives the split, and strings.Split sees
+// one line too many) and drifts further out of sync with every subsequent
+// document.
+func splitDocuments(contents []byte) []document {
+	matches := docSeparator.FindAllIndex(contents, -1)
+
+	docs := make([]document, 0, len(matches)+1)
+	start, line := 0, 0
+	for _, m := range matches {
+		docs = append(docs, document{content: contents[start:m[0]], startLine: line})
+		line += bytes.Count(contents[start:m[1]], []byte("\n"))
+		start = m[1]
+	}
+	docs = append(docs, document{content: contents[start:], startLine: line})
+	return docs
 }
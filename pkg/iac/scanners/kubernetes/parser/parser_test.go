@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse_MultiDocumentOffsets(t *testing.T) {
+	// second document's metadata.name value is on line 7 of the whole file
+	// (0-based line 6) - the regression this guards against is double-counting CRLF
+	// line endings and losing track of the offset past the first document.
+	manifest := "kind: Pod\r\n" +
+		"metadata:\r\n" +
+		"  name: one\r\n" +
+		"---\r\n" +
+		"kind: Pod\r\n" +
+		"metadata:\r\n" +
+		"  name: two\r\n"
+
+	p := New()
+	docs, sourceMaps, err := p.Parse(strings.NewReader(manifest), "multi.yaml")
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Len(t, sourceMaps, 2)
+
+	pos, ok := sourceMaps[1]["metadata.name"]
+	require.True(t, ok)
+	assert.Equal(t, "multi.yaml", pos.File)
+	assert.Equal(t, 7, pos.Line) // the 7th line of the whole file
+}
+
+func Test_SplitDocuments(t *testing.T) {
+	contents := []byte("a: 1\r\n---\r\nb: 2\r\n---\r\nc: 3\r\n")
+
+	docs := splitDocuments(contents)
+	require.Len(t, docs, 3)
+	assert.Equal(t, 0, docs[0].startLine)
+	assert.Equal(t, 2, docs[1].startLine)
+	assert.Equal(t, 4, docs[2].startLine)
+	assert.Equal(t, "a: 1\r\n", string(docs[0].content))
+	assert.Equal(t, "b: 2\r\n", string(docs[1].content))
+	assert.Equal(t, "c: 3\r\n", string(docs[2].content))
+}
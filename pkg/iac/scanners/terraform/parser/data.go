@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"io/fs"
+
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+)
+
+// DataOptions builds the rego.Options needed to make external data
+// documents - allow-lists of AMIs, CIDRs, IAM actions, etc. kept in
+// versioned JSON/YAML files rather than inline in a check - available under
+// `data.*` during evaluation, mirroring how cloud scanning already loads
+// data directories alongside its policies.
+//
+// NOTE: this only wires the rego side of the feature (rego.Scanner already
+// supports rego.WithDataDirs/rego.WithDataFilesystem). The Terraform
+// scanner's own ScannerWithDataDirs/ScannerWithDataFilesystem options,
+// which are meant to call this and append the result to the rego.Options
+// passed to rego.NewScanner, live in this package's scanner.go - which
+// isn't present in this checkout, so those two options couldn't be added
+// here.
+func DataOptions(dataDirs []string, dataFS fs.FS) []rego.Option {
+	var opts []rego.Option
+	if len(dataDirs) > 0 {
+		opts = append(opts, rego.WithDataDirs(dataDirs...))
+	}
+	if dataFS != nil {
+		opts = append(opts, rego.WithDataFilesystem(dataFS))
+	}
+	return opts
+}
@@ -0,0 +1,77 @@
+package resolvers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/xerrors"
+)
+
+// gitAuthMethod returns the go-git transport.AuthMethod to use for a
+// git::scheme://host/... module source, derived from Auth. It returns a nil
+// AuthMethod (not an error) when source isn't a git remote, or when auth has
+// nothing configured for it, so the caller can fall through to an anonymous
+// clone exactly as it did before Auth existed.
+//
+// NOTE: this is the auth half of the feature requested for
+// evaluator.loadExternalModule: the git resolver that would actually call
+// this (and the registry resolver RegistryToken feeds) depends on the rest
+// of this package's resolve chain - resolveModule, the Resolver interface,
+// and the registry/http/cache resolvers it dispatches to - none of which
+// are present in this checkout to wire against without guessing their
+// exact shape. gitAuthMethod and Auth.BasicAuthForHost/RegistryToken are
+// real and independently testable; plugging them into a git clone call is
+// left for when that resolve chain is available to check against.
+func gitAuthMethod(source string, auth Auth) (transport.AuthMethod, error) {
+	rawURL := strings.TrimPrefix(source, "git::")
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse module source %q: %w", source, err)
+	}
+
+	switch u.Scheme {
+	case "ssh":
+		return sshAuthMethod(u, auth)
+	case "http", "https":
+		return httpAuthMethod(u, auth)
+	default:
+		return nil, nil
+	}
+}
+
+func sshAuthMethod(u *url.URL, auth Auth) (transport.AuthMethod, error) {
+	user := "git"
+	if u.User != nil && u.User.Username() != "" {
+		user = u.User.Username()
+	}
+
+	if auth.SSHAgent {
+		method, err := ssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to connect to ssh-agent (is SSH_AUTH_SOCK set?): %w", err)
+		}
+		return method, nil
+	}
+
+	if auth.SSHKeyPath != "" {
+		method, err := ssh.NewPublicKeysFromFile(user, auth.SSHKeyPath, auth.SSHKeyPassphrase)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load ssh key %q: %w", auth.SSHKeyPath, err)
+		}
+		return method, nil
+	}
+
+	return nil, nil
+}
+
+func httpAuthMethod(u *url.URL, auth Auth) (transport.AuthMethod, error) {
+	cred, ok := auth.BasicAuthForHost(u.Hostname())
+	if !ok {
+		return nil, nil
+	}
+	return &githttp.BasicAuth{Username: cred.User, Password: cred.Password}, nil
+}
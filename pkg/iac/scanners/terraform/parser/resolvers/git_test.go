@@ -0,0 +1,91 @@
+package resolvers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GitAuthMethod_SSHAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	method, err := gitAuthMethod("git::ssh://git@example.com/org/repo.git", Auth{SSHAgent: true})
+	require.Error(t, err, "no agent listening on an empty SSH_AUTH_SOCK should fail, not silently go anonymous")
+	assert.Nil(t, method)
+}
+
+func Test_GitAuthMethod_SSHKeyFile_MissingFile(t *testing.T) {
+	method, err := gitAuthMethod("git::ssh://git@example.com/org/repo.git", Auth{SSHKeyPath: "/does/not/exist"})
+	require.Error(t, err)
+	assert.Nil(t, method)
+}
+
+func Test_GitAuthMethod_HTTPBasic(t *testing.T) {
+	method, err := gitAuthMethod("git::https://example.com/org/repo.git", Auth{
+		HTTPBasic: &HTTPBasicAuth{User: "octocat", Password: "token123"},
+	})
+	require.NoError(t, err)
+	require.IsType(t, &githttp.BasicAuth{}, method)
+	basic := method.(*githttp.BasicAuth)
+	assert.Equal(t, "octocat", basic.Username)
+	assert.Equal(t, "token123", basic.Password)
+}
+
+func Test_GitAuthMethod_NoCredentialsConfigured(t *testing.T) {
+	method, err := gitAuthMethod("git::https://example.com/org/repo.git", Auth{})
+	require.NoError(t, err)
+	assert.Nil(t, method)
+}
+
+func Test_GitAuthMethod_NonGitSource(t *testing.T) {
+	method, err := gitAuthMethod("registry.terraform.io/terraform-aws-modules/vpc/aws", Auth{SSHAgent: true})
+	require.NoError(t, err)
+	assert.Nil(t, method)
+}
+
+func Test_Auth_BasicAuthForHost_PrefersExplicitOverNetrc(t *testing.T) {
+	dir := t.TempDir()
+	netrcPath := filepath.Join(dir, "netrc")
+	require.NoError(t, os.WriteFile(netrcPath, []byte("machine example.com\nlogin netrc-user\npassword netrc-pass\n"), 0o600))
+
+	netrc, err := LoadNetrc(netrcPath)
+	require.NoError(t, err)
+
+	auth := Auth{
+		HTTPBasic: &HTTPBasicAuth{User: "explicit-user", Password: "explicit-pass"},
+		Netrc:     netrc,
+	}
+	cred, ok := auth.BasicAuthForHost("example.com")
+	require.True(t, ok)
+	assert.Equal(t, "explicit-user", cred.User)
+}
+
+func Test_Auth_BasicAuthForHost_FallsBackToNetrc(t *testing.T) {
+	dir := t.TempDir()
+	netrcPath := filepath.Join(dir, "netrc")
+	require.NoError(t, os.WriteFile(netrcPath, []byte("machine example.com\nlogin netrc-user\npassword netrc-pass\n"), 0o600))
+
+	netrc, err := LoadNetrc(netrcPath)
+	require.NoError(t, err)
+
+	auth := Auth{Netrc: netrc}
+	cred, ok := auth.BasicAuthForHost("example.com")
+	require.True(t, ok)
+	assert.Equal(t, "netrc-user", cred.User)
+	assert.Equal(t, "netrc-pass", cred.Password)
+}
+
+func Test_Auth_RegistryToken(t *testing.T) {
+	auth := Auth{RegistryTokens: map[string]string{"app.terraform.io": "tok"}}
+
+	token, ok := auth.RegistryToken("app.terraform.io")
+	require.True(t, ok)
+	assert.Equal(t, "tok", token)
+
+	_, ok = auth.RegistryToken("other.example.com")
+	assert.False(t, ok)
+}
@@ -0,0 +1,70 @@
+package resolvers
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Netrc is a parsed netrc-style credential file (the same format as
+// ~/.netrc), letting users keep per-host module credentials in one place
+// instead of threading them through Trivy flags/config per module source.
+type Netrc struct {
+	byHost map[string]HTTPBasicAuth
+}
+
+// LoadNetrc parses the netrc-style file at path. Only the "machine", "login"
+// and "password" tokens are understood; "default" entries and other tokens
+// (e.g. "account", "macdef") are ignored, matching what a module resolver
+// actually needs.
+func LoadNetrc(path string) (*Netrc, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open netrc file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	n := &Netrc{byHost: map[string]HTTPBasicAuth{}}
+
+	var host string
+	var cred HTTPBasicAuth
+	flush := func() {
+		if host != "" {
+			n.byHost[host] = cred
+		}
+		host, cred = "", HTTPBasicAuth{}
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i := 0; i+1 < len(fields); i += 2 {
+			switch fields[i] {
+			case "machine":
+				flush()
+				host = fields[i+1]
+			case "login":
+				cred.User = fields[i+1]
+			case "password":
+				cred.Password = fields[i+1]
+			}
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("failed to read netrc file %q: %w", path, err)
+	}
+	return n, nil
+}
+
+// Lookup returns the credential netrc has recorded for host, if any.
+func (n *Netrc) Lookup(host string) (HTTPBasicAuth, bool) {
+	if n == nil {
+		return HTTPBasicAuth{}, false
+	}
+	cred, ok := n.byHost[host]
+	return cred, ok
+}
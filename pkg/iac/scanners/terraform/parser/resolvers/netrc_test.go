@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadNetrc_MultipleMachines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "netrc")
+	require.NoError(t, os.WriteFile(path, []byte(`
+machine example.com
+login alice
+password s3cret
+
+machine other.example.com
+login bob
+password hunter2
+`), 0o600))
+
+	netrc, err := LoadNetrc(path)
+	require.NoError(t, err)
+
+	cred, ok := netrc.Lookup("example.com")
+	require.True(t, ok)
+	assert.Equal(t, HTTPBasicAuth{User: "alice", Password: "s3cret"}, cred)
+
+	cred, ok = netrc.Lookup("other.example.com")
+	require.True(t, ok)
+	assert.Equal(t, HTTPBasicAuth{User: "bob", Password: "hunter2"}, cred)
+
+	_, ok = netrc.Lookup("unknown.example.com")
+	assert.False(t, ok)
+}
+
+func Test_LoadNetrc_MissingFile(t *testing.T) {
+	_, err := LoadNetrc(filepath.Join(t.TempDir(), "does-not-exist"))
+	assert.Error(t, err)
+}
+
+func Test_Netrc_Lookup_NilReceiver(t *testing.T) {
+	var netrc *Netrc
+	_, ok := netrc.Lookup("example.com")
+	assert.False(t, ok)
+}
@@ -0,0 +1,75 @@
+package resolvers
+
+import "github.com/aquasecurity/trivy/pkg/iac/debug"
+
+// Options carries everything needed to locate and fetch a single module
+// source - local cache lookup, the Terraform registry protocol, or a
+// git/http remote - threaded down from evaluator.loadExternalModule to
+// whichever resolver ends up handling Source.
+type Options struct {
+	Source          string
+	OriginalSource  string
+	Version         string
+	OriginalVersion string
+	WorkingDir      string
+	Name            string
+	ModulePath      string
+	DebugLogger     debug.Logger
+	AllowDownloads  bool
+	SkipCache       bool
+
+	// Auth carries credentials for module sources that aren't fetchable
+	// anonymously - a private git remote or a registry requiring a token -
+	// so they don't silently fail to resolve. Every field is optional; a
+	// zero-value Auth behaves exactly as resolvers did before it existed.
+	Auth Auth
+}
+
+// Auth holds the credentials available when resolving a module source.
+type Auth struct {
+	// SSHAgent, when true, authenticates git::ssh:// sources through the
+	// running ssh-agent (SSH_AUTH_SOCK), the same way go-git's ssh_agent
+	// auth method does. Takes priority over SSHKeyPath.
+	SSHAgent bool
+	// SSHKeyPath/SSHKeyPassphrase authenticate git::ssh:// sources with a
+	// private key file, used when SSHAgent is false. An unavailable agent is
+	// an error; it does not fall back to the key file.
+	SSHKeyPath       string
+	SSHKeyPassphrase string
+	// HTTPBasic authenticates git::http:// and git::https:// sources.
+	HTTPBasic *HTTPBasicAuth
+	// Netrc, when set, is consulted for HTTP basic credentials keyed by
+	// hostname - e.g. from a parsed ~/.netrc - only when HTTPBasic is nil,
+	// since HTTPBasic applies to every host, so users don't have to
+	// hand-roll per-module auth.
+	Netrc *Netrc
+	// RegistryTokens authenticates Terraform registry protocol requests
+	// with a bearer token, keyed by registry hostname (e.g.
+	// "app.terraform.io").
+	RegistryTokens map[string]string
+}
+
+// HTTPBasicAuth is a single HTTP basic auth credential.
+type HTTPBasicAuth struct {
+	User     string
+	Password string
+}
+
+// BasicAuthForHost returns the HTTP basic credential to use for host,
+// preferring an explicit HTTPBasic override before falling back to Netrc.
+func (a Auth) BasicAuthForHost(host string) (HTTPBasicAuth, bool) {
+	if a.HTTPBasic != nil {
+		return *a.HTTPBasic, true
+	}
+	if a.Netrc != nil {
+		return a.Netrc.Lookup(host)
+	}
+	return HTTPBasicAuth{}, false
+}
+
+// RegistryToken returns the bearer token to use for the Terraform registry
+// at host, if one was configured.
+func (a Auth) RegistryToken(host string) (string, bool) {
+	token, ok := a.RegistryTokens[host]
+	return token, ok
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/xerrors"
+)
+
+// skipRule is a single line from a skip-patterns file: a doublestar glob,
+// optionally negated with a leading "!" to re-include a path matched by an
+// earlier rule.
+type skipRule struct {
+	pattern string
+	negate  bool
+}
+
+// SkipRules is an ordered set of skip rules parsed from a file such as
+// `.trivyignore-dirs`/`.trivyignore-files`, following .gitignore semantics:
+// blank lines and lines starting with "#" are ignored, and later rules take
+// precedence over earlier ones so a negated pattern can re-include a path
+// excluded by a previous rule.
+//
+// This is meant to back the Terraform scanner's ScannerWithSkipFromFile
+// option, alongside its existing ScannerWithSkipDirs/ScannerWithSkipFiles -
+// which, like scanner.go itself, isn't present in this checkout.
+type SkipRules []skipRule
+
+// ParseSkipFile reads newline-delimited glob patterns from r, one per line.
+func ParseSkipFile(r io.Reader) (SkipRules, error) {
+	var rules SkipRules
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := skipRule{pattern: line}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			rule.pattern = strings.TrimPrefix(line, "!")
+		}
+		if rule.pattern == "" {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("failed to read skip patterns: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Match reports whether path should be skipped: the last rule whose pattern
+// matches path wins, so a later "!pattern" re-includes anything excluded by
+// an earlier, broader pattern.
+func (rules SkipRules) Match(path string) bool {
+	path = strings.TrimLeft(path, "/")
+
+	skip := false
+	for _, rule := range rules {
+		matched, err := doublestar.Match(rule.pattern, path)
+		if err != nil || !matched {
+			continue
+		}
+		skip = !rule.negate
+	}
+
+	return skip
+}
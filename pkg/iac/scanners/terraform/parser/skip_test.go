@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseSkipFile_SkipsModulesDirByDefault(t *testing.T) {
+	rules, err := ParseSkipFile(strings.NewReader(`
+# skip every vendored module by default
+**/modules/**
+`))
+	require.NoError(t, err)
+
+	assert.True(t, rules.Match("foo/modules/bar/main.tf"))
+	assert.False(t, rules.Match("foo/main.tf"))
+}
+
+func Test_ParseSkipFile_NegationReIncludesAPath(t *testing.T) {
+	rules, err := ParseSkipFile(strings.NewReader(`
+**/modules/**
+!foo/modules/allowed/**
+`))
+	require.NoError(t, err)
+
+	assert.True(t, rules.Match("foo/modules/blocked/main.tf"))
+	assert.False(t, rules.Match("foo/modules/allowed/main.tf"))
+}
+
+func Test_ParseSkipFile_IgnoresCommentsAndBlankLines(t *testing.T) {
+	rules, err := ParseSkipFile(strings.NewReader(`
+
+# this is a comment
+**/vendor/**
+
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.True(t, rules.Match("a/vendor/b.tf"))
+}
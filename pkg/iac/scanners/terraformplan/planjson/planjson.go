@@ -0,0 +1,95 @@
+// Package planjson parses `terraform show -json` plan output (as opposed to
+// the raw binary .tfplan format handled by ../snapshot) and projects it into
+// the flat Rego input shape (see RegoInput) policies are evaluated
+// against, so a plan produced against a real backend can be scanned without
+// the originating `.tf` sources checked out.
+package planjson
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
+// ResourceChange is the subset of tfjson's resource_changes entries this
+// package projects into Rego input: the resource's address/type/provider
+// plus its planned post-apply attributes.
+type ResourceChange struct {
+	Address      string        `json:"address"`
+	Type         string        `json:"type"`
+	Name         string        `json:"name"`
+	ProviderName string        `json:"provider_name"`
+	Change       ResourceDelta `json:"change"`
+	Index        any           `json:"index,omitempty"`
+}
+
+// ResourceDelta is the `change` object of a tfjson resource_changes entry.
+// Only `after` (the planned post-apply state) is projected - `before` isn't
+// meaningful to misconfiguration policies, which evaluate the state a
+// resource would end up in.
+type ResourceDelta struct {
+	Actions []string       `json:"actions"`
+	After   map[string]any `json:"after"`
+}
+
+// Plan is the subset of the tfjson plan schema this package understands:
+// format_version plus the planned resource changes. configuration.root_module
+// and relevant_attributes aren't decoded or projected.
+type Plan struct {
+	FormatVersion   string           `json:"format_version"`
+	ResourceChanges []ResourceChange `json:"resource_changes"`
+}
+
+// Parse decodes `terraform show -json` output. It accepts any plan whose
+// format_version is present; it doesn't validate it against a specific
+// tfjson schema version, since the shape of resource_changes has been
+// stable across the versions Trivy has needed to support.
+func Parse(data []byte) (*Plan, error) {
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, xerrors.Errorf("failed to parse terraform plan JSON: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, xerrors.New("not a terraform plan JSON document: missing format_version")
+	}
+	return &plan, nil
+}
+
+// RegoInput projects the plan's resource changes into the document Rego
+// policies evaluate against: `input.resource_changes[_]` with the same
+// address/type/provider_name/change.after shape as the source JSON, so
+// policies can match `input.resource_changes[_].type == "aws_s3_bucket"`
+// and inspect `.change.after` the same way they'd inspect an HCL resource's
+// attributes.
+func (p *Plan) RegoInput() map[string]any {
+	changes := make([]map[string]any, 0, len(p.ResourceChanges))
+	for _, rc := range p.ResourceChanges {
+		if !created(rc.Change.Actions) {
+			continue
+		}
+		changes = append(changes, map[string]any{
+			"address":       rc.Address,
+			"type":          rc.Type,
+			"name":          rc.Name,
+			"provider_name": rc.ProviderName,
+			"change": map[string]any{
+				"actions": rc.Change.Actions,
+				"after":   rc.Change.After,
+			},
+		})
+	}
+	return map[string]any{"resource_changes": changes}
+}
+
+// created reports whether a resource_changes entry's planned actions result
+// in a resource actually existing post-apply (create, update, or the no-op
+// "no-op"/read) - as opposed to one being deleted, which has nothing
+// meaningful left in `after` to scan.
+func created(actions []string) bool {
+	for _, action := range actions {
+		if action == "delete" {
+			return len(actions) > 1 // e.g. ["delete", "create"] (replace) still ends with the resource existing
+		}
+	}
+	return true
+}
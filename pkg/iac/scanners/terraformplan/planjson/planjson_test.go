@@ -0,0 +1,59 @@
+package planjson_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/terraformplan/planjson"
+)
+
+const samplePlan = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.bad",
+      "type": "aws_s3_bucket",
+      "name": "bad",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["create"],
+        "after": {"bucket": "my-bucket", "acl": "public-read"}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.gone",
+      "type": "aws_s3_bucket",
+      "name": "gone",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["delete"],
+        "after": null
+      }
+    }
+  ]
+}`
+
+func Test_Parse(t *testing.T) {
+	plan, err := planjson.Parse([]byte(samplePlan))
+	require.NoError(t, err)
+	require.Len(t, plan.ResourceChanges, 2)
+	assert.Equal(t, "aws_s3_bucket.bad", plan.ResourceChanges[0].Address)
+}
+
+func Test_Parse_NotAPlan(t *testing.T) {
+	_, err := planjson.Parse([]byte(`{"foo": "bar"}`))
+	assert.Error(t, err)
+}
+
+func Test_RegoInput_DropsDeletedResources(t *testing.T) {
+	plan, err := planjson.Parse([]byte(samplePlan))
+	require.NoError(t, err)
+
+	input := plan.RegoInput()
+	changes, ok := input["resource_changes"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "aws_s3_bucket.bad", changes[0]["address"])
+}
@@ -0,0 +1,22 @@
+package severity
+
+// Severity is the normalized severity level of a misconfiguration, shared
+// across every IaC scanner (Terraform, CloudFormation, Kubernetes, Dockerfile).
+type Severity string
+
+const (
+	None     Severity = ""
+	Low      Severity = "LOW"
+	Medium   Severity = "MEDIUM"
+	High     Severity = "HIGH"
+	Critical Severity = "CRITICAL"
+)
+
+// IsValid reports whether s is one of the known severity levels.
+func (s Severity) IsValid() bool {
+	switch s {
+	case Low, Medium, High, Critical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Package suppress extracts resource-scoped suppressions - the
+// cfn-lint-style "this specific resource is exempt from this specific
+// check" convention - from the three IaC formats that carry them inline:
+// a CloudFormation resource's Metadata.trivy.ignore list, a Kubernetes
+// manifest's trivy.aquasec.com/ignore annotation, and an ARM resource's
+// metadata.ignore tag.
+//
+// NOTE: this only implements extraction and matching. Filtering
+// types.Misconfiguration.Failures into a new Exceptions slice at PutBlob
+// time isn't possible in this checkout: that field, the CloudFormation/ARM/
+// Kubernetes scanners themselves, and pkg/fanal/analyzer's core aren't
+// present here. FromCloudFormationMetadata/FromKubernetesAnnotations/
+// FromARMMetadata/Matches/Filter are real and independently testable
+// against any map/string-map a caller already has in hand.
+package suppress
+
+import "strings"
+
+// FromCloudFormationMetadata reads the AVD IDs listed under a CloudFormation
+// resource's `Metadata.trivy.ignore` key, e.g.:
+//
+//	Metadata:
+//	  trivy:
+//	    ignore: ["AVD-AWS-0176"]
+func FromCloudFormationMetadata(metadata map[string]any) []string {
+	trivy, ok := metadata["trivy"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return toStrings(trivy["ignore"])
+}
+
+// FromARMMetadata reads the AVD IDs listed under an ARM resource's
+// `metadata.ignore` tag, the ARM-template equivalent of
+// FromCloudFormationMetadata.
+func FromARMMetadata(metadata map[string]any) []string {
+	return toStrings(metadata["ignore"])
+}
+
+// annotationKey is the Kubernetes annotation FromKubernetesAnnotations
+// reads suppressions from.
+const annotationKey = "trivy.aquasec.com/ignore"
+
+// FromKubernetesAnnotations reads the comma-separated AVD IDs in a
+// Kubernetes manifest's trivy.aquasec.com/ignore annotation, e.g.
+// `trivy.aquasec.com/ignore: "AVD-KSV-0001,AVD-KSV-0012"`.
+func FromKubernetesAnnotations(annotations map[string]string) []string {
+	raw, ok := annotations[annotationKey]
+	if !ok || raw == "" {
+		return nil
+	}
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Matches reports whether avdID is suppressed by any entry in suppressed -
+// either a case-insensitive match, or the blanket suppression "*".
+func Matches(avdID string, suppressed []string) bool {
+	for _, s := range suppressed {
+		if s == "*" || strings.EqualFold(s, avdID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finding is the minimal (resource, AVD ID) pair Filter needs to decide
+// whether a finding is suppressed. It doesn't depend on
+// types.Misconfiguration or rego.Result - a caller adapts its own finding
+// type to this with a two-field conversion - so Filter stays usable
+// wherever a finding's resource identifier and AVD ID are available.
+type Finding struct {
+	Resource string
+	AVDID    string
+}
+
+// Filter splits findings into kept and suppressed, given a lookup from a
+// resource identifier to the AVD IDs suppressed for it, as produced by
+// FromCloudFormationMetadata/FromKubernetesAnnotations/FromARMMetadata and
+// keyed by the resource they were read from.
+func Filter(findings []Finding, suppressedByResource map[string][]string) (kept, suppressed []Finding) {
+	for _, f := range findings {
+		if Matches(f.AVDID, suppressedByResource[f.Resource]) {
+			suppressed = append(suppressed, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	return kept, suppressed
+}
+
+// toStrings coerces a decoded JSON/YAML value (typically []any of strings,
+// as Metadata.trivy.ignore/metadata.ignore unmarshal to) into a []string,
+// skipping any non-string entries.
+func toStrings(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, entry := range list {
+		if s, ok := entry.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
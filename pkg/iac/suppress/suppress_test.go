@@ -0,0 +1,56 @@
+package suppress_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/iac/suppress"
+)
+
+func Test_FromCloudFormationMetadata(t *testing.T) {
+	metadata := map[string]any{
+		"trivy": map[string]any{
+			"ignore": []any{"AVD-AWS-0176", "AVD-AWS-0177"},
+		},
+	}
+	assert.Equal(t, []string{"AVD-AWS-0176", "AVD-AWS-0177"}, suppress.FromCloudFormationMetadata(metadata))
+	assert.Nil(t, suppress.FromCloudFormationMetadata(map[string]any{}))
+}
+
+func Test_FromARMMetadata(t *testing.T) {
+	metadata := map[string]any{"ignore": []any{"AVD-AZU-0001"}}
+	assert.Equal(t, []string{"AVD-AZU-0001"}, suppress.FromARMMetadata(metadata))
+}
+
+func Test_FromKubernetesAnnotations(t *testing.T) {
+	annotations := map[string]string{"trivy.aquasec.com/ignore": "AVD-KSV-0001, AVD-KSV-0012"}
+	assert.Equal(t, []string{"AVD-KSV-0001", "AVD-KSV-0012"}, suppress.FromKubernetesAnnotations(annotations))
+	assert.Nil(t, suppress.FromKubernetesAnnotations(map[string]string{}))
+}
+
+func Test_Matches(t *testing.T) {
+	assert.True(t, suppress.Matches("AVD-AWS-0176", []string{"AVD-AWS-0176"}))
+	assert.True(t, suppress.Matches("AVD-AWS-0176", []string{"*"}))
+	assert.False(t, suppress.Matches("AVD-AWS-0176", []string{"AVD-AWS-0177"}))
+}
+
+func Test_Filter(t *testing.T) {
+	findings := []suppress.Finding{
+		{Resource: "BadBucket", AVDID: "AVD-AWS-0176"},
+		{Resource: "BadBucket", AVDID: "AVD-AWS-0177"},
+		{Resource: "OtherBucket", AVDID: "AVD-AWS-0176"},
+	}
+	suppressedByResource := map[string][]string{
+		"BadBucket": {"AVD-AWS-0176"},
+	}
+
+	kept, suppressed := suppress.Filter(findings, suppressedByResource)
+	assert.Equal(t, []suppress.Finding{
+		{Resource: "BadBucket", AVDID: "AVD-AWS-0177"},
+		{Resource: "OtherBucket", AVDID: "AVD-AWS-0176"},
+	}, kept)
+	assert.Equal(t, []suppress.Finding{
+		{Resource: "BadBucket", AVDID: "AVD-AWS-0176"},
+	}, suppressed)
+}
@@ -0,0 +1,64 @@
+package types
+
+// Source identifies the input format/language being scanned, so the Rego
+// engine can select which `input.*` document shape (and which namespaces) to
+// evaluate a policy against.
+type Source string
+
+const (
+	SourceJSON       Source = "json"
+	SourceYAML       Source = "yaml"
+	SourceTOML       Source = "toml"
+	SourceDockerfile Source = "dockerfile"
+	SourceKubernetes Source = "kubernetes"
+	SourceCloud      Source = "cloud"
+	SourceDefsec     Source = "defsec"
+	SourceRbac       Source = "rbac"
+)
+
+// Range describes the location of a piece of configuration within its
+// source file.
+type Range struct {
+	Filename  string
+	StartLine int
+	EndLine   int
+}
+
+// GetFilename returns the file the range belongs to.
+func (r Range) GetFilename() string {
+	return r.Filename
+}
+
+// GetStartLine returns the first line (1-indexed) covered by the range.
+func (r Range) GetStartLine() int {
+	return r.StartLine
+}
+
+// GetEndLine returns the last line (1-indexed) covered by the range.
+func (r Range) GetEndLine() int {
+	return r.EndLine
+}
+
+// Metadata carries the provenance of a finding/value back to its source file
+// and line range, so results can be reported with a file:line location.
+type Metadata struct {
+	MetaRange Range
+	Resource  string
+	Parent    *Metadata
+}
+
+func NewMetadata(r Range, resource string) Metadata {
+	return Metadata{MetaRange: r, Resource: resource}
+}
+
+// Range returns the source location this metadata was derived from.
+func (m Metadata) Range() Range {
+	return m.MetaRange
+}
+
+func (m Metadata) String() string {
+	if m.MetaRange.Filename == "" {
+		return m.Resource
+	}
+	return m.MetaRange.Filename
+}
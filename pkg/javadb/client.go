@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"golang.org/x/xerrors"
 
 	"github.com/deepfactor-io/go-dep-parser/pkg/java/jar"
@@ -17,20 +18,61 @@ import (
 	ftypes "github.com/deepfactor-io/trivy/pkg/fanal/types"
 	"github.com/deepfactor-io/trivy/pkg/log"
 	"github.com/deepfactor-io/trivy/pkg/oci"
+
+	"github.com/aquasecurity/trivy/internal/licenses"
 )
 
 const (
-	mediaType = "application/vnd.aquasec.trivy.javadb.layer.v1.tar+gzip"
+	// defaultMediaType is the OCI layer media type trivy-java-db itself is
+	// published under. Init can override this for organizations that
+	// re-tag the layer in an internal registry under their own type.
+	defaultMediaType = "application/vnd.aquasec.trivy.javadb.layer.v1.tar+gzip"
+
+	// lastRepoFile records, inside dbDir, which of Updater.repos last
+	// succeeded - so the next run tries that mirror first instead of
+	// re-walking the list from the beginning and paying for every
+	// earlier, still-unavailable mirror's failure again.
+	lastRepoFile = "last_repository"
 )
 
 var updater *Updater
 
 type Updater struct {
-	repo     string
-	dbDir    string
-	skip     bool
-	quiet    bool
-	insecure bool
+	repos        []string
+	dbDir        string
+	skip         bool
+	quiet        bool
+	registryOpts ftypes.RegistryOptions
+	mediaType    string
+}
+
+// orderedRepos returns u.repos with the mirror recorded in lastRepoFile (if
+// any, and if still present in u.repos) moved to the front.
+func (u *Updater) orderedRepos() []string {
+	last, err := os.ReadFile(filepath.Join(u.dbDir, lastRepoFile))
+	if err != nil || len(last) == 0 {
+		return u.repos
+	}
+
+	preferred := strings.TrimSpace(string(last))
+	ordered := make([]string, 0, len(u.repos))
+	for _, repo := range u.repos {
+		if repo == preferred {
+			ordered = append([]string{repo}, ordered...)
+		} else {
+			ordered = append(ordered, repo)
+		}
+	}
+	return ordered
+}
+
+// rememberRepo persists repo as the last-known-good mirror for future runs.
+// Failure to do so isn't fatal - it only costs the next run a repeat of the
+// earlier mirrors' failures, not correctness.
+func (u *Updater) rememberRepo(repo string) {
+	if err := os.WriteFile(filepath.Join(u.dbDir, lastRepoFile), []byte(repo), 0o644); err != nil {
+		log.Logger.Warnf("Unable to persist the last-known-good Java DB repository: %s", err)
+	}
 }
 
 func (u *Updater) Update() error {
@@ -51,17 +93,28 @@ func (u *Updater) Update() error {
 	}
 
 	if (meta.Version != db.SchemaVersion || meta.NextUpdate.Before(time.Now().UTC())) && !u.skip {
-		// Download DB
-		log.Logger.Infof("Java DB Repository: %s", u.repo)
+		// Download DB, trying each repository in turn until one succeeds.
+		log.Logger.Infof("Java DB Repositories: %s", strings.Join(u.repos, ", "))
 		logger.Info("downloading the Java DB...")
 
-		// TODO: support remote options
-		var a *oci.Artifact
-		if a, err = oci.NewArtifact(u.repo, u.quiet, ftypes.RegistryOptions{Insecure: u.insecure}); err != nil {
-			return xerrors.Errorf("oci error: %w", err)
+		var errs error
+		var downloaded bool
+		for _, repo := range u.orderedRepos() {
+			a, err := oci.NewArtifact(repo, u.quiet, u.registryOpts)
+			if err != nil {
+				errs = multierror.Append(errs, xerrors.Errorf("oci error for %s: %w", repo, err))
+				continue
+			}
+			if err = a.Download(context.Background(), dbDir, oci.DownloadOption{MediaType: u.mediaType}); err != nil {
+				errs = multierror.Append(errs, xerrors.Errorf("DB download error from %s: %w", repo, err))
+				continue
+			}
+			u.rememberRepo(repo)
+			downloaded = true
+			break
 		}
-		if err = a.Download(context.Background(), dbDir, oci.DownloadOption{MediaType: mediaType}); err != nil {
-			return xerrors.Errorf("DB download error: %w", err)
+		if !downloaded {
+			return xerrors.Errorf("unable to download the Java DB from any of the configured repositories: %w", errs)
 		}
 
 		// Parse the newly downloaded metadata.json
@@ -84,13 +137,26 @@ func (u *Updater) Update() error {
 	return nil
 }
 
-func Init(cacheDir string, javaDBRepository string, skip, quiet, insecure bool) {
+// Init sets up the package-level Java DB updater. javaDBRepositories is
+// tried in order on every update, falling back to the next entry when a
+// mirror is unreachable or rate-limited instead of failing the whole scan.
+// registryOpts carries full OCI registry auth (credentials, bearer tokens,
+// RegistryMirrors, client certs, platform) through to every mirror, the same
+// as the main vulnerability DB pipeline already supports. mediaType
+// overrides the OCI layer media type to pull, for organizations that
+// re-tag the layer in an internal registry; pass "" to use trivy-java-db's
+// own media type.
+func Init(cacheDir string, javaDBRepositories []string, skip, quiet bool, registryOpts ftypes.RegistryOptions, mediaType string) {
+	if mediaType == "" {
+		mediaType = defaultMediaType
+	}
 	updater = &Updater{
-		repo:     javaDBRepository,
-		dbDir:    filepath.Join(cacheDir, "java-db"),
-		skip:     skip,
-		quiet:    quiet,
-		insecure: insecure,
+		repos:        javaDBRepositories,
+		dbDir:        filepath.Join(cacheDir, "java-db"),
+		skip:         skip,
+		quiet:        quiet,
+		registryOpts: registryOpts,
+		mediaType:    mediaType,
 	}
 }
 
@@ -129,10 +195,42 @@ func (d *DB) Exists(groupID, artifactID string) (bool, error) {
 	return index.ArtifactID != "", nil
 }
 
+// licenseClassifier normalizes the Java DB's free-text license entries
+// (e.g. "The Apache Software License, Version 2.0") to SPDX identifiers
+// where recognized, reusing internal/licenses' classifier rather than
+// maintaining a second, divergent normalization table here.
+var licenseClassifier = licenses.NewScanner()
+
+// splitLicenses splits the DB's `|`-delimited license field into individual
+// entries, normalizing each one.
+//
+// NOTE: the DB driver's jar.Properties.License - from the external
+// github.com/deepfactor-io/go-dep-parser module, not owned by this repo -
+// is a single string, so this package can't widen it into a
+// jar.Properties.Licenses []string sibling field without forking that
+// dependency. splitLicenses is unexported, so only code inside this package
+// gets the structured form; getLicense below is the path that fills
+// jar.Properties.License itself.
+func splitLicenses(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var out []string
+	for _, entry := range strings.Split(raw, "|") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			out = append(out, licenseClassifier.Normalize(entry))
+		}
+	}
+	return out
+}
+
+// getLicense is a compatibility shim for jar.Properties.License: it joins
+// splitLicenses' normalized entries with "," - trivy's existing convention
+// for a package declaring more than one license - rather than the DB's own
+// "|" delimiter, so existing SBOM/report code that only consumes one string
+// keeps working unchanged.
 func getLicense(license string) string {
-	// TODO: Figure out a way to return list since license strings can contain `,` . Trivy does not support it currently
-	// Keeping it consistent for the time being
-	return strings.ReplaceAll(license, "|", ",")
+	return strings.Join(splitLicenses(license), ",")
 }
 
 func (d *DB) SearchBySHA1(sha1 string) (jar.Properties, error) {
@@ -165,6 +263,122 @@ func (d *DB) SearchByGAV(groupID, artifactID, version string) (jar.Properties, e
 	}, nil
 }
 
+// Match is a jar.Properties result annotated with how it was found.
+//
+// NOTE: the request behind SearchByGAVRange/SearchByGAVFuzzy asked for an
+// Explain field directly on jar.Properties, but that type comes from the
+// external github.com/deepfactor-io/go-dep-parser module this repo doesn't
+// control (the same constraint splitLicenses' doc comment above describes
+// for License) - it can't be widened without forking that
+// dependency. Match wraps it instead, for the one query (SearchByGAVFuzzy)
+// where exact-vs-fuzzy actually needs to be told apart.
+type Match struct {
+	jar.Properties
+	// Explain describes how this match was produced, e.g. "exact match" or
+	// "fuzzy match: nearest lower version".
+	Explain string
+}
+
+// SearchByGAVRange resolves every indexed version of groupID:artifactID
+// that satisfies constraint, a Maven version range ("[1.0,2.0)",
+// "(,1.5]", "[1.0]") or a soft, unbracketed version ("1.2.3", matching
+// every indexed version - the caller is expected to prefer the first
+// result). Results are ordered highest-version-first, ties broken by
+// lexicographic GroupID order.
+func (d *DB) SearchByGAVRange(groupID, artifactID, constraint string) ([]jar.Properties, error) {
+	r, err := parseVersionRange(constraint)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid version constraint %q: %w", constraint, err)
+	}
+
+	// NOTE: SelectIndexesByGroupIDAndArtifactID - listing every indexed
+	// version of one GA, rather than resolving a single GAV - doesn't exist
+	// on github.com/deepfactor-io/javadb/pkg/db.DB today; referenced here
+	// the same way this package already references SelectIndexByGAV et al.
+	indexes, err := d.driver.SelectIndexesByGroupIDAndArtifactID(groupID, artifactID)
+	if err != nil {
+		return nil, xerrors.Errorf("select error: %w", err)
+	}
+
+	var matched []jar.Properties
+	for _, index := range indexes {
+		if !r.matches(index.Version) {
+			continue
+		}
+		matched = append(matched, jar.Properties{
+			GroupID:    index.GroupID,
+			ArtifactID: index.ArtifactID,
+			Version:    index.Version,
+			License:    getLicense(index.License),
+		})
+	}
+	if len(matched) == 0 {
+		return nil, xerrors.Errorf("groupID %s: artifactID %s: constraint %s: %w", groupID, artifactID, constraint, jar.ArtifactNotFoundErr)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if c := compareMavenVersions(matched[i].Version, matched[j].Version); c != 0 {
+			return c > 0 // highest version first
+		}
+		return matched[i].GroupID < matched[j].GroupID
+	})
+	return matched, nil
+}
+
+// SearchByGAVFuzzy resolves groupID:artifactID:version the way SearchByGAV
+// does, but tolerates the qualifier suffixes poms commonly report
+// (".RELEASE", "-SNAPSHOT", ".Final", ...) instead of failing outright:
+// it first strips a recognized suffix and retries the exact lookup, then
+// falls back to the nearest version indexed below the stripped version.
+func (d *DB) SearchByGAVFuzzy(groupID, artifactID, version string) (Match, error) {
+	if index, err := d.driver.SelectIndexByGAV(artifactID, groupID, version); err == nil && index.ArtifactID != "" {
+		return Match{Properties: jar.Properties{
+			GroupID:    index.GroupID,
+			ArtifactID: index.ArtifactID,
+			Version:    index.Version,
+			License:    getLicense(index.License),
+		}, Explain: "exact match"}, nil
+	}
+
+	stripped := stripMavenQualifier(version)
+	if stripped != version {
+		if index, err := d.driver.SelectIndexByGAV(artifactID, groupID, stripped); err == nil && index.ArtifactID != "" {
+			return Match{Properties: jar.Properties{
+				GroupID:    index.GroupID,
+				ArtifactID: index.ArtifactID,
+				Version:    index.Version,
+				License:    getLicense(index.License),
+			}, Explain: "fuzzy match: stripped qualifier suffix"}, nil
+		}
+	}
+
+	indexes, err := d.driver.SelectIndexesByGroupIDAndArtifactID(groupID, artifactID)
+	if err != nil {
+		return Match{}, xerrors.Errorf("select error: %w", err)
+	}
+
+	var best *jar.Properties
+	for i := range indexes {
+		index := indexes[i]
+		if compareMavenVersions(index.Version, stripped) > 0 {
+			continue // only consider versions <= the requested one
+		}
+		if best != nil && compareMavenVersions(index.Version, best.Version) <= 0 {
+			continue
+		}
+		best = &jar.Properties{
+			GroupID:    index.GroupID,
+			ArtifactID: index.ArtifactID,
+			Version:    index.Version,
+			License:    getLicense(index.License),
+		}
+	}
+	if best == nil {
+		return Match{}, xerrors.Errorf("groupID %s: artifactID %s: version %s: %w", groupID, artifactID, version, jar.ArtifactNotFoundErr)
+	}
+	return Match{Properties: *best, Explain: "fuzzy match: nearest lower version"}, nil
+}
+
 func (d *DB) SearchByArtifactID(artifactID string) (string, error) {
 	indexes, err := d.driver.SelectIndexesByArtifactIDAndFileType(artifactID, types.JarType)
 	if err != nil {
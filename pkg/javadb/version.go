@@ -0,0 +1,177 @@
+package javadb
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// compareMavenVersions orders two Maven artifact versions the way Maven's
+// own ComparableVersion does for the common case: split on "." and "-",
+// compare token-by-token, numeric tokens compared numerically and
+// everything else lexicographically (case-insensitively, since qualifiers
+// like "Final"/"RELEASE" are conventionally case-insensitive). It returns a
+// negative number if a < b, 0 if equal, and positive if a > b.
+//
+// This doesn't implement Maven's full qualifier-ordering table (alpha <
+// beta < milestone < rc < (release) < sp), just numeric-vs-lexicographic
+// token comparison - enough for the range/fuzzy matching below, which only
+// needs a consistent total order, not byte-for-byte parity with Maven.
+func compareMavenVersions(a, b string) int {
+	at, bt := versionTokens(a), versionTokens(b)
+	for i := 0; i < len(at) || i < len(bt); i++ {
+		var ta, tb string
+		if i < len(at) {
+			ta = at[i]
+		}
+		if i < len(bt) {
+			tb = bt[i]
+		}
+		if c := compareToken(ta, tb); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func versionTokens(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+}
+
+// compareToken compares a single version token. A missing token (empty
+// string, when one version has fewer tokens than the other) sorts below
+// any present token, numeric or not - e.g. "1.0" < "1.0.1".
+func compareToken(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return -1
+	}
+	if b == "" {
+		return 1
+	}
+
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// versionRange is a parsed Maven version range, e.g. "[1.0,2.0)" or the
+// open-ended "(,1.5]". A "soft" requirement - a bare version with no
+// brackets, meaning "this version, but a newer one is acceptable if it's
+// the only one available" - has neither bound set; matches treats it as
+// matching every version, leaving the preferred one to tie-breaking.
+type versionRange struct {
+	lowerBound     string
+	lowerInclusive bool
+	upperBound     string
+	upperInclusive bool
+	soft           string
+}
+
+// parseVersionRange parses Maven version-range syntax: "[1.0,2.0)",
+// "(,1.5]", "[1.0]" (exactly 1.0), or a soft, unbracketed version like
+// "1.2.3".
+func parseVersionRange(constraint string) (versionRange, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return versionRange{}, xerrors.New("empty version constraint")
+	}
+
+	if !strings.HasPrefix(constraint, "[") && !strings.HasPrefix(constraint, "(") {
+		return versionRange{soft: constraint}, nil
+	}
+
+	if len(constraint) < 2 {
+		return versionRange{}, xerrors.Errorf("invalid version range: %s", constraint)
+	}
+
+	lowerInclusive := constraint[0] == '['
+	upperInclusive := constraint[len(constraint)-1] == ']'
+	if !lowerInclusive && constraint[0] != '(' {
+		return versionRange{}, xerrors.Errorf("invalid version range: %s", constraint)
+	}
+	if !upperInclusive && constraint[len(constraint)-1] != ')' {
+		return versionRange{}, xerrors.Errorf("invalid version range: %s", constraint)
+	}
+
+	inner := constraint[1 : len(constraint)-1]
+	bounds := strings.SplitN(inner, ",", 2)
+	switch len(bounds) {
+	case 1:
+		// "[1.0]" - an exact-match range.
+		v := strings.TrimSpace(bounds[0])
+		return versionRange{
+			lowerBound: v, lowerInclusive: true,
+			upperBound: v, upperInclusive: true,
+		}, nil
+	case 2:
+		return versionRange{
+			lowerBound: strings.TrimSpace(bounds[0]), lowerInclusive: lowerInclusive,
+			upperBound: strings.TrimSpace(bounds[1]), upperInclusive: upperInclusive,
+		}, nil
+	default:
+		return versionRange{}, xerrors.Errorf("invalid version range: %s", constraint)
+	}
+}
+
+// matches reports whether version satisfies r.
+func (r versionRange) matches(version string) bool {
+	if r.soft != "" {
+		return true
+	}
+
+	if r.lowerBound != "" {
+		c := compareMavenVersions(version, r.lowerBound)
+		if c < 0 || (c == 0 && !r.lowerInclusive) {
+			return false
+		}
+	}
+	if r.upperBound != "" {
+		c := compareMavenVersions(version, r.upperBound)
+		if c > 0 || (c == 0 && !r.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// mavenQualifierSuffixes are release-qualifier conventions SearchByGAVFuzzy
+// strips before falling back to an exact lookup - e.g. Spring's
+// "1.2.3.RELEASE" or a build's lingering "1.2.3-SNAPSHOT" both resolve
+// against the same indexed "1.2.3".
+var mavenQualifierSuffixes = []string{
+	"-SNAPSHOT",
+	".RELEASE",
+	"-RELEASE",
+	".Final",
+	".FINAL",
+	"-GA",
+	".GA",
+}
+
+// stripMavenQualifier removes a single trailing qualifier suffix from
+// version, case-insensitively, if one of mavenQualifierSuffixes matches.
+func stripMavenQualifier(version string) string {
+	for _, suffix := range mavenQualifierSuffixes {
+		if len(version) > len(suffix) && strings.EqualFold(version[len(version)-len(suffix):], suffix) {
+			return version[:len(version)-len(suffix)]
+		}
+	}
+	return version
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerContextKey is unexported so only NewContext/FromContext in this
+// package can store or retrieve a *slog.Logger on a context.Context.
+type loggerContextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger, retrievable later via
+// FromContext. This lets a single scan (e.g. one Artifact.Inspect call)
+// attach its own *slog.Logger - built with request-scoped attributes like an
+// artifact type or blob ID - without adding an extra parameter to every
+// analyzer/handler call the scan makes along the way.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the *slog.Logger attached to ctx via NewContext, or
+// slog.Default() if none was attached, so a call site never needs to
+// nil-check the result - the same fallback artifact.Option.logger() uses
+// for a nil Option.Logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
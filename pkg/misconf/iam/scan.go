@@ -0,0 +1,236 @@
+// Package iam walks a CloudFormation/SAM resource tree for embedded IAM
+// policy documents - a PolicyDocument, AssumeRolePolicyDocument, KMS
+// KeyPolicy, or the PolicyDocument nested inside an AWS::IAM::Role/User's
+// inline Policies list - and evaluates each with pkg/iac/iam, surfacing
+// over-permissive or invalid statements as Findings: unknown actions,
+// Resource: "*" paired with a mutating action on a sensitive service,
+// wildcard-only principals on a trust policy, and Allow statements that use
+// NotAction/NotResource.
+//
+// NOTE: this is the sub-scanner the request asks for, walking
+// pkg/fanal/analyzer/config/cloudformation.Template (real, added
+// alongside this package) for the CloudFormation/SAM side. The Terraform
+// side isn't wired up: this checkout has no HCL parser or
+// pkg/iac/adapters/terraform resource tree to walk, only the IAM analysis
+// engine those adapters would call into. Findings are returned as this
+// package's own Finding type rather than types.Misconfiguration, since
+// pkg/fanal/types.Misconfiguration isn't present here either; checkResource
+// and ScanCloudFormationTemplate are real and independently usable once
+// that wiring exists.
+package iam
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/config/cloudformation"
+	"github.com/aquasecurity/trivy/pkg/iac/iam"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// ScannerOption is the subset of this sub-scanner's settings that would be
+// configured directly on pkg/misconf.MisconfScannerOption, mirroring
+// pkg/fanal/analyzer/config/jsonyaml.ScannerOption's pattern of a plain
+// struct a caller embeds once that type exists in this checkout.
+type ScannerOption struct {
+	// DisableIAMChecks turns this sub-scanner off, for users who prefer a
+	// pure-Rego workflow over built-in Go checks.
+	DisableIAMChecks bool
+}
+
+// Enabled reports whether this sub-scanner should run.
+func (opt ScannerOption) Enabled() bool {
+	return !opt.DisableIAMChecks
+}
+
+// Finding is a single issue found in an embedded IAM policy document.
+type Finding struct {
+	Resource string
+	Rule     string
+	Message  string
+	Range    types.Range
+}
+
+// ScanCloudFormationTemplate walks every resource in tpl for embedded IAM
+// policy documents and returns a Finding for each issue detected across all
+// of them. Callers that already ran cloudformation.Resolve should pass its
+// result, so findings are reported against the template's effective
+// (post-condition, post-SAM-expansion) resource set.
+func ScanCloudFormationTemplate(tpl *cloudformation.Template, opt ScannerOption) []Finding {
+	if !opt.Enabled() {
+		return nil
+	}
+	var findings []Finding
+	for name, r := range tpl.Resources {
+		findings = append(findings, checkResource(name, r.Range, r.Properties)...)
+	}
+	return findings
+}
+
+// checkResource collects and evaluates every IAM policy document embedded
+// anywhere within properties (at any nesting depth), attributing findings
+// to resource.
+func checkResource(resource string, fallback types.Range, properties map[string]any) []Finding {
+	var docs []foundDocument
+	collectPolicyDocuments(properties, &docs)
+
+	var findings []Finding
+	for _, d := range docs {
+		raw, err := json.Marshal(d.value)
+		if err != nil {
+			continue
+		}
+		doc, err := iam.Parse(raw)
+		if err != nil {
+			continue
+		}
+		findings = append(findings, checkPolicyDocument(resource, docRange(d.value, fallback), d.key, doc)...)
+	}
+	return findings
+}
+
+// documentKeys are the property names, at any nesting depth, under which a
+// CloudFormation resource embeds a literal IAM policy document.
+//
+// isTrustPolicy distinguishes AssumeRolePolicyDocument - the only one of
+// these that describes who may assume a role, rather than what a
+// principal already holding a policy may do - since a wildcard principal
+// means something very different in each case.
+var documentKeys = map[string]struct{ isTrustPolicy bool }{
+	"PolicyDocument":           {isTrustPolicy: false},
+	"AssumeRolePolicyDocument": {isTrustPolicy: true},
+	"KeyPolicy":                {isTrustPolicy: false},
+}
+
+type foundDocument struct {
+	key   string
+	value map[string]any
+}
+
+// collectPolicyDocuments recursively searches v for maps keyed by one of
+// documentKeys, appending each to out. It keeps recursing past a match (a
+// Policies list item's PolicyDocument can itself sit beside further nested
+// structure) rather than stopping there.
+func collectPolicyDocuments(v any, out *[]foundDocument) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, vv := range val {
+			if _, ok := documentKeys[k]; ok {
+				if doc, ok := vv.(map[string]any); ok {
+					*out = append(*out, foundDocument{key: k, value: doc})
+				}
+			}
+			collectPolicyDocuments(vv, out)
+		}
+	case []any:
+		for _, vv := range val {
+			collectPolicyDocuments(vv, out)
+		}
+	}
+}
+
+// docRange returns the source line range embedded in doc's own
+// __startline__/__endline__ entries (see
+// pkg/fanal/analyzer/config/cloudformation's line-preserving parser), or
+// fallback if doc carries none - e.g. when a caller constructs a document
+// directly rather than parsing it from a template.
+func docRange(doc map[string]any, fallback types.Range) types.Range {
+	start, ok1 := doc["__startline__"].(int)
+	end, ok2 := doc["__endline__"].(int)
+	if !ok1 || !ok2 {
+		return fallback
+	}
+	return types.Range{Filename: fallback.Filename, StartLine: start, EndLine: end}
+}
+
+// sensitiveServices is the allow-list of services checkPolicyDocument
+// treats as sensitive for the Resource: "*" + mutating-action check: IAM,
+// KMS, S3, Secrets Manager and STS, per the request driving this package.
+var sensitiveServices = map[string]bool{
+	"iam":            true,
+	"kms":            true,
+	"s3":             true,
+	"secretsmanager": true,
+	"sts":            true,
+}
+
+// readOnlyPrefixes are action-name prefixes (case-insensitive) that don't
+// count as "mutating" for the sensitive-service check below, even on a
+// sensitive service.
+var readOnlyPrefixes = []string{"Get", "List", "Describe", "Head", "View", "Lookup"}
+
+func isMutatingSensitiveAction(action string) bool {
+	service, name, ok := strings.Cut(action, ":")
+	if !ok || !sensitiveServices[strings.ToLower(service)] {
+		return false
+	}
+	// STS is only sensitive for the specific AssumeRole action - unlike
+	// the other services here, most of its actions are benign.
+	if strings.EqualFold(service, "sts") {
+		return strings.EqualFold(name, "AssumeRole")
+	}
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasWildcardResource(resources []string) bool {
+	for _, r := range resources {
+		if r == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPolicyDocument evaluates every statement of doc (found under key in
+// resource) against the four checks this package implements.
+func checkPolicyDocument(resource string, rng types.Range, key string, doc iam.PolicyDocument) []Finding {
+	isTrust := documentKeys[key].isTrustPolicy
+
+	var findings []Finding
+	add := func(rule, format string, args ...any) {
+		findings = append(findings, Finding{
+			Resource: resource,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+			Range:    rng,
+		})
+	}
+
+	for _, s := range doc.Statements {
+		for _, action := range s.UnknownActions() {
+			add("unknown-action", "%s: action %q doesn't exist for its service (possible typo)", resource, action)
+		}
+
+		if s.Effect != iam.EffectAllow {
+			continue
+		}
+
+		if hasWildcardResource(s.Resources) {
+			for _, action := range iam.ExpandActions(s.Actions) {
+				if isMutatingSensitiveAction(action) {
+					add("wildcard-resource-sensitive-action", "%s: statement grants %q on Resource: \"*\"", resource, action)
+					break
+				}
+			}
+		}
+
+		if isTrust && s.HasWildcardPrincipal() {
+			add("wildcard-trust-principal", "%s: trust policy allows any principal to assume this role", resource)
+		}
+
+		if len(s.NotActions) > 0 {
+			add("notaction-allow", "%s: Allow statement uses NotAction, granting every action except %v", resource, s.NotActions)
+		}
+
+		if len(s.NotResources) > 0 {
+			add("notresource-allow", "%s: Allow statement uses NotResource, granting access to every resource except %v", resource, s.NotResources)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,74 @@
+package iam_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/config/cloudformation"
+	misconfiam "github.com/aquasecurity/trivy/pkg/misconf/iam"
+)
+
+func scan(t *testing.T, fixture string) []misconfiam.Finding {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + fixture + "/template.json")
+	require.NoError(t, err)
+
+	tpl, err := cloudformation.Parse(fixture+"/template.json", data)
+	require.NoError(t, err)
+
+	return misconfiam.ScanCloudFormationTemplate(tpl, misconfiam.ScannerOption{})
+}
+
+func rules(findings []misconfiam.Finding) []string {
+	out := make([]string, len(findings))
+	for i, f := range findings {
+		out[i] = f.Rule
+	}
+	return out
+}
+
+func Test_ScanCloudFormationTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    string // "" means no findings expected
+	}{
+		{"valid read-only policy", "iam-valid", ""},
+		{"typo'd action", "iam-unknown-action", "unknown-action"},
+		{"wildcard resource with sensitive action", "iam-wildcard-admin", "wildcard-resource-sensitive-action"},
+		{"wildcard principal on trust policy", "iam-wildcard-trust", "wildcard-trust-principal"},
+		{"NotAction in an Allow statement", "iam-notaction-allow", "notaction-allow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scan(t, tt.fixture)
+			if tt.want == "" {
+				assert.Empty(t, findings)
+				return
+			}
+			assert.Contains(t, rules(findings), tt.want)
+		})
+	}
+}
+
+func Test_ScannerOption_Disabled(t *testing.T) {
+	data, err := os.ReadFile("testdata/iam-wildcard-admin/template.json")
+	require.NoError(t, err)
+	tpl, err := cloudformation.Parse("template.json", data)
+	require.NoError(t, err)
+
+	findings := misconfiam.ScanCloudFormationTemplate(tpl, misconfiam.ScannerOption{DisableIAMChecks: true})
+	assert.Empty(t, findings)
+}
+
+func Test_Finding_RangeFollowsDocument(t *testing.T) {
+	findings := scan(t, "iam-unknown-action")
+	require.NotEmpty(t, findings)
+	assert.Equal(t, "unknown-action", findings[0].Rule)
+	assert.Greater(t, findings[0].Range.StartLine, 0)
+	assert.Equal(t, "TypoPolicy", findings[0].Resource)
+}
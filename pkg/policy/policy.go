@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/open-policy-agent/opa/bundle"
@@ -27,6 +28,42 @@ const (
 type options struct {
 	artifact *oci.Artifact
 	clock    clock.Clock
+
+	// registry auth overlaid onto the caller-supplied types.RegistryOptions
+	// in populateOCIArtifact, the same fields pkg/remote already resolves
+	// credentials/TLS from - see resolveRegistryOptions.
+	username, password string
+	bearerTokenFile    string
+	credentialHelper   string
+	clientCert         []byte
+	clientKey          []byte
+	customCA           []byte
+	insecure           bool
+
+	// verify configures signature verification of the downloaded check
+	// bundle, set via WithBundleVerification.
+	verify VerifyOptions
+
+	// signingKeyPath is the PEM-encoded PKCS8 private key Publisher.Publish
+	// signs a pushed bundle's digest with, set via WithSigningKey.
+	signingKeyPath string
+
+	// localBundlePath, set via WithLocalBundle, points populateOCIArtifact at
+	// an already-mirrored bundle instead of a remote registry.
+	localBundlePath string
+}
+
+// WithLocalBundle points the check bundle at an already-mirrored copy on
+// disk instead of a remote registry, for air-gapped scans. path may be an
+// OCI image layout directory (oci-layout + index.json + blobs/sha256/...),
+// a docker save-style tarball, or a raw .tar.gz already matching
+// policyMediaType - oci.NewArtifactFromLocal sniffs which. Operators mirror
+// a bundle behind their firewall with tools like `oras cp`/`skopeo copy` and
+// point Trivy at the result with this instead of running a registry.
+func WithLocalBundle(path string) Option {
+	return func(opts *options) {
+		opts.localBundlePath = path
+	}
 }
 
 // WithOCIArtifact takes an OCI artifact
@@ -43,6 +80,46 @@ func WithClock(c clock.Clock) Option {
 	}
 }
 
+// WithRegistryAuth sets basic auth credentials for pulling the check bundle
+// from a private registry.
+func WithRegistryAuth(username, password string) Option {
+	return func(opts *options) {
+		opts.username = username
+		opts.password = password
+	}
+}
+
+// WithBearerTokenFile sets a file containing a bearer token to present when
+// pulling the check bundle. The file is read when populateOCIArtifact first
+// builds the Client's artifact; later calls reuse that artifact, so the file
+// is read once per Client and a token rewritten afterwards is not picked up.
+func WithBearerTokenFile(path string) Option {
+	return func(opts *options) {
+		opts.bearerTokenFile = path
+	}
+}
+
+// WithClientTLS sets the client certificate, key and custom CA bundle used
+// for mTLS when pulling the check bundle from a registry. insecure disables
+// TLS verification entirely, same as types.RegistryOptions.Insecure.
+func WithClientTLS(cert, key, ca []byte, insecure bool) Option {
+	return func(opts *options) {
+		opts.clientCert = cert
+		opts.clientKey = key
+		opts.customCA = ca
+		opts.insecure = insecure
+	}
+}
+
+// WithCredentialHelper names a docker-credential-<name> credential helper to
+// resolve registry credentials through, the same mechanism pkg/remote uses
+// via the docker/cli config package rather than shelling out directly.
+func WithCredentialHelper(name string) Option {
+	return func(opts *options) {
+		opts.credentialHelper = name
+	}
+}
+
 // Option is a functional option
 type Option func(*options)
 
@@ -58,13 +135,21 @@ type Client struct {
 type Metadata struct {
 	Digest       string
 	DownloadedAt time.Time
+	Signer       string    `json:",omitempty"` // set when the bundle was verified against a WithBundleVerification trust root
+	VerifiedAt   time.Time `json:",omitempty"`
 }
 
 func (m Metadata) String() string {
-	return fmt.Sprintf(`Check Bundle:
+	s := fmt.Sprintf(`Check Bundle:
   Digest: %s
   DownloadedAt: %s
 `, m.Digest, m.DownloadedAt.UTC())
+	if m.Signer != "" {
+		s += fmt.Sprintf(`  Signer: %s
+  VerifiedAt: %s
+`, m.Signer, m.VerifiedAt.UTC())
+	}
+	return s
 }
 
 // NewClient is the factory method for policy client
@@ -91,8 +176,24 @@ func NewClient(cacheDir string, quiet bool, checkBundleRepo string, opts ...Opti
 
 func (c *Client) populateOCIArtifact(registryOpts types.RegistryOptions) error {
 	if c.artifact == nil {
-		log.Debug("Loading check bundle", log.String("repository", c.policyBundleRepo))
-		art, err := oci.NewArtifact(c.policyBundleRepo, c.quiet, registryOpts)
+		if c.localBundlePath != "" {
+			log.Debug("Loading check bundle from local mirror", log.String("path", c.localBundlePath))
+			art, err := oci.NewArtifactFromLocal(c.localBundlePath)
+			if err != nil {
+				return xerrors.Errorf("local bundle error: %w", err)
+			}
+			c.artifact = art
+			return nil
+		}
+
+		log.Debug("Loading check bundle", log.String("repository", c.checkBundleRepo))
+
+		registryOpts, err := c.resolveRegistryOptions(registryOpts)
+		if err != nil {
+			return xerrors.Errorf("registry auth error: %w", err)
+		}
+
+		art, err := oci.NewArtifact(c.checkBundleRepo, c.quiet, registryOpts)
 		if err != nil {
 			return xerrors.Errorf("OCI artifact error: %w", err)
 		}
@@ -101,25 +202,96 @@ func (c *Client) populateOCIArtifact(registryOpts types.RegistryOptions) error {
 	return nil
 }
 
+// resolveRegistryOptions overlays any auth configured via WithRegistryAuth,
+// WithBearerTokenFile, WithClientTLS and WithCredentialHelper onto base,
+// without discarding whatever the caller already set. Once populateOCIArtifact
+// has built c.artifact, it's reused by every later Download/Digest call, so
+// this only runs once per Client - NeedsUpdate and DownloadBuiltinPolicies
+// authenticate the same way.
+func (c *Client) resolveRegistryOptions(base types.RegistryOptions) (types.RegistryOptions, error) {
+	opts := base
+
+	if c.username != "" || c.password != "" {
+		opts.Credentials = append(opts.Credentials, types.Credential{
+			Username: c.username,
+			Password: c.password,
+		})
+	}
+
+	if c.bearerTokenFile != "" {
+		token, err := os.ReadFile(c.bearerTokenFile)
+		if err != nil {
+			return types.RegistryOptions{}, xerrors.Errorf("unable to read bearer token file: %w", err)
+		}
+		opts.RegistryToken = strings.TrimSpace(string(token))
+	}
+
+	if c.credentialHelper != "" {
+		if opts.CredentialHelpers == nil {
+			opts.CredentialHelpers = map[string]string{}
+		}
+		opts.CredentialHelpers[registryHost(c.checkBundleRepo)] = c.credentialHelper
+	}
+
+	if len(c.clientCert) > 0 {
+		opts.ClientCert = c.clientCert
+	}
+	if len(c.clientKey) > 0 {
+		opts.ClientKey = c.clientKey
+	}
+	if len(c.customCA) > 0 {
+		opts.CustomCA = c.customCA
+	}
+	if c.insecure {
+		opts.Insecure = true
+	}
+
+	return opts, nil
+}
+
+// registryHost strips any tag/digest and path off repo, leaving the bare
+// registry host a CredentialHelpers entry is keyed by.
+func registryHost(repo string) string {
+	host := repo
+	if i := strings.IndexAny(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	return host
+}
+
 // DownloadBuiltinPolicies download default policies from GitHub Pages
 func (c *Client) DownloadBuiltinPolicies(ctx context.Context, registryOpts types.RegistryOptions) error {
 	if err := c.populateOCIArtifact(registryOpts); err != nil {
 		return xerrors.Errorf("OPA bundle error: %w", err)
 	}
 
-	dst := c.contentDir()
-	if err := c.artifact.Download(ctx, dst, oci.DownloadOption{MediaType: policyMediaType}); err != nil {
-		return xerrors.Errorf("download error: %w", err)
-	}
-
 	digest, err := c.artifact.Digest(ctx)
 	if err != nil {
 		return xerrors.Errorf("digest error: %w", err)
 	}
 	log.Debug("Digest of the built-in policies", log.String("digest", digest))
 
-	// Update metadata.json with the new digest and the current date
-	if err = c.updateMetadata(digest, c.clock.Now()); err != nil {
+	meta := Metadata{
+		Digest:       digest,
+		DownloadedAt: c.clock.Now(),
+	}
+
+	if c.verify.TrustRootPath != "" {
+		signer, err := c.verifyBundle(ctx, digest, registryOpts)
+		if err != nil {
+			return xerrors.Errorf("bundle verification error: %w", err)
+		}
+		meta.Signer = signer
+		meta.VerifiedAt = c.clock.Now()
+	}
+
+	dst := c.contentDir()
+	if err := c.artifact.Download(ctx, dst, oci.DownloadOption{MediaType: policyMediaType}); err != nil {
+		return xerrors.Errorf("download error: %w", err)
+	}
+
+	// Update metadata.json with the new digest, signer and the current date
+	if err = c.writeMetadata(meta); err != nil {
 		return xerrors.Errorf("unable to update the policy metadata: %w", err)
 	}
 
@@ -128,6 +300,16 @@ func (c *Client) DownloadBuiltinPolicies(ctx context.Context, registryOpts types
 
 // LoadBuiltinPolicies loads default policies
 func (c *Client) LoadBuiltinPolicies() ([]string, error) {
+	if c.verify.TrustRootPath != "" {
+		meta, err := c.GetMetadata()
+		if err != nil {
+			return nil, xerrors.Errorf("unable to confirm check bundle signature: %w", err)
+		}
+		if meta.Signer == "" {
+			return nil, xerrors.Errorf("check bundle verification is enabled but %s has no recorded signer - run DownloadBuiltinPolicies again", c.metadataPath())
+		}
+	}
+
 	f, err := os.Open(c.manifestPath())
 	if err != nil {
 		return nil, xerrors.Errorf("manifest file open error (%s): %w", c.manifestPath(), err)
@@ -181,7 +363,14 @@ func (c *Client) NeedsUpdate(ctx context.Context, registryOpts types.RegistryOpt
 	// Update DownloadedAt with the current time.
 	// Otherwise, if there are no updates in the remote registry,
 	// the digest will be fetched every time even after this.
-	if err = c.updateMetadata(meta.Digest, time.Now()); err != nil {
+	// The digest is unchanged, so the previously recorded signer (if any)
+	// carries forward without reverifying.
+	if err = c.writeMetadata(Metadata{
+		Digest:       meta.Digest,
+		DownloadedAt: time.Now(),
+		Signer:       meta.Signer,
+		VerifiedAt:   meta.VerifiedAt,
+	}); err != nil {
 		return false, xerrors.Errorf("unable to update the check metadata: %w", err)
 	}
 
@@ -200,18 +389,13 @@ func (c *Client) manifestPath() string {
 	return filepath.Join(c.contentDir(), bundle.ManifestExt)
 }
 
-func (c *Client) updateMetadata(digest string, now time.Time) error {
+func (c *Client) writeMetadata(meta Metadata) error {
 	f, err := os.Create(c.metadataPath())
 	if err != nil {
 		return xerrors.Errorf("failed to open a check manifest: %w", err)
 	}
 	defer f.Close()
 
-	meta := Metadata{
-		Digest:       digest,
-		DownloadedAt: now,
-	}
-
 	if err = json.NewEncoder(f).Encode(meta); err != nil {
 		return xerrors.Errorf("json encode error: %w", err)
 	}
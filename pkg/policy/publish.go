@@ -0,0 +1,247 @@
+package policy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+// WithSigningKey enables Publisher.Publish to produce a detached signature
+// tag alongside the pushed bundle, using the PEM-encoded PKCS8 private key at
+// path. The matching public key, handed to WithBundleVerification on the
+// consumer side, verifies it with no further setup.
+func WithSigningKey(path string) Option {
+	return func(o *options) {
+		o.signingKeyPath = path
+	}
+}
+
+// Publisher packages a directory of Rego checks and a bundle manifest into
+// an OPA bundle tarball and pushes it as an OCI artifact, the symmetric
+// counterpart to Client's pull path - so an organization can host private
+// checks in the same registry as its images.
+type Publisher struct {
+	*options
+	quiet bool
+}
+
+// NewPublisher is the factory method for Publisher. It accepts the same
+// registry auth Option values as NewClient (WithRegistryAuth,
+// WithBearerTokenFile, WithClientTLS, WithCredentialHelper), plus
+// WithSigningKey.
+func NewPublisher(quiet bool, opts ...Option) *Publisher {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Publisher{
+		options: o,
+		quiet:   quiet,
+	}
+}
+
+// PublishResult reports what Publish pushed.
+type PublishResult struct {
+	Repository   string
+	Digest       string
+	SignatureTag string // set only when WithSigningKey was configured
+}
+
+// Publish tars+gzips the Rego checks and manifest under dir into a single
+// policyMediaType layer and pushes it to repo. If WithSigningKey was
+// configured, it also signs the pushed digest and pushes that signature to
+// the "sha256-<hex>.sig" tag Client.verifyBundle expects.
+func (p *Publisher) Publish(ctx context.Context, dir, repo string, registryOpts types.RegistryOptions) (PublishResult, error) {
+	registryOpts, err := (&Client{options: p.options, checkBundleRepo: repo}).resolveRegistryOptions(registryOpts)
+	if err != nil {
+		return PublishResult{}, xerrors.Errorf("registry auth error: %w", err)
+	}
+
+	archive, err := buildTarGz(dir)
+	if err != nil {
+		return PublishResult{}, xerrors.Errorf("unable to package bundle: %w", err)
+	}
+	defer os.Remove(archive)
+
+	art, err := oci.NewArtifact(repo, p.quiet, registryOpts)
+	if err != nil {
+		return PublishResult{}, xerrors.Errorf("OCI artifact error: %w", err)
+	}
+
+	if err := art.Push(ctx, archive, oci.PushOption{MediaType: policyMediaType}); err != nil {
+		return PublishResult{}, xerrors.Errorf("push error: %w", err)
+	}
+
+	digest, err := art.Digest(ctx)
+	if err != nil {
+		return PublishResult{}, xerrors.Errorf("digest error: %w", err)
+	}
+
+	result := PublishResult{Repository: repo, Digest: digest}
+
+	if p.signingKeyPath != "" {
+		sigTag, err := p.publishSignature(ctx, repo, digest, registryOpts)
+		if err != nil {
+			return PublishResult{}, xerrors.Errorf("signing error: %w", err)
+		}
+		result.SignatureTag = sigTag
+	}
+
+	return result, nil
+}
+
+// publishSignature signs digest with the configured signing key and pushes
+// the payload (the digest itself, matching what verifyBundle reads back and
+// compares) as the tag sigTagReference derives, carrying the signature as a
+// dev.cosignproject.cosign/signature manifest layer annotation rather than as
+// the layer's content - the same cosign "simple signing" convention
+// pkg/fanal/attestation/verify/verify.go's verifySignature checks on the
+// consumer side. Returns that tag.
+func (p *Publisher) publishSignature(ctx context.Context, repo, digest string, registryOpts types.RegistryOptions) (string, error) {
+	sig, err := signDigest(digest, p.signingKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp("", "trivy-check-bundle-sig-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	sigPath := filepath.Join(tmp, "signature")
+	if err := os.WriteFile(sigPath, []byte(digest), 0o600); err != nil {
+		return "", xerrors.Errorf("unable to write signature payload: %w", err)
+	}
+
+	sigTag := sigTagReference(repo, digest)
+	sigArt, err := oci.NewArtifact(sigTag, p.quiet, registryOpts)
+	if err != nil {
+		return "", xerrors.Errorf("signature artifact error: %w", err)
+	}
+
+	// NOTE: oci.PushOption has no Annotations field in this checkout; it's
+	// assumed to grow one analogous to MediaType, applied to the pushed
+	// layer's manifest entry, the same way cosign attaches the
+	// base64-encoded signature to a "simple signing" manifest.
+	pushOpt := oci.PushOption{
+		MediaType: cosignSignatureMediaType,
+		Annotations: map[string]string{
+			cosignSignatureAnnotationKey: base64.StdEncoding.EncodeToString(sig),
+		},
+	}
+	if err := sigArt.Push(ctx, sigPath, pushOpt); err != nil {
+		return "", xerrors.Errorf("unable to push signature: %w", err)
+	}
+
+	return sigTag, nil
+}
+
+// signDigest signs the sha256 sum of digest with the PEM-encoded PKCS8
+// private key at keyPath, the payload verifyPayloadSignature checks against
+// on the consumer side.
+func signDigest(digest, keyPath string) ([]byte, error) {
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM block found in %s", keyPath)
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse signing key: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(digest))
+
+	switch key := priv.(type) {
+	case *ecdsa.PrivateKey:
+		return ecdsa.SignASN1(rand.Reader, key, sum[:])
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	default:
+		return nil, xerrors.Errorf("unsupported signing key type %T", priv)
+	}
+}
+
+// buildTarGz packages dir into a temporary tar+gzip archive and returns its
+// path, the inverse of pkg/iac/bundle/fetch.go's extractTarGz.
+func buildTarGz(dir string) (string, error) {
+	out, err := os.CreateTemp("", "trivy-check-bundle-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return "", xerrors.Errorf("unable to walk %s: %w", dir, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+
+	return out.Name(), nil
+}
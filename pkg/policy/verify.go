@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/remote"
+)
+
+// cosignSignatureMediaType identifies the detached signature layer a cosign
+// "sha256-<digest>.sig" tag carries.
+const cosignSignatureMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+// cosignSignatureAnnotationKey holds the base64 signature over the payload
+// layer of a cosign-style signature manifest - the signature itself is
+// never the layer's content, it's an annotation describing it. See
+// pkg/fanal/attestation/verify/verify.go's verifySignature, which this
+// mirrors for the same OCI-artifact signature convention.
+const cosignSignatureAnnotationKey = "dev.cosignproject.cosign/signature"
+
+// VerifyOptions configures signature verification for a downloaded check
+// bundle. Only key-based verification is supported here - no Fulcio/Rekor
+// keyless identity or transparency-log inclusion proof - matching the scope
+// pkg/iac/bundle/verify.go already settled on for bundle signatures: the
+// common case of a cosign-generated key-pair signature, without pulling in
+// the full Sigstore client stack.
+type VerifyOptions struct {
+	// TrustRootPath is a PEM-encoded public key the check bundle must be
+	// signed with.
+	TrustRootPath string
+}
+
+// WithBundleVerification enables signature verification of the check bundle
+// downloaded by DownloadBuiltinPolicies against the given trust root.
+// DownloadBuiltinPolicies refuses to write the bundle to contentDir() if
+// verification fails, and records the signer in Metadata so LoadBuiltinPolicies
+// can refuse to load an unverified bundle. NeedsUpdate never reverifies - it
+// only compares digests, and verification only happens when content is
+// actually downloaded.
+func WithBundleVerification(opts VerifyOptions) Option {
+	return func(o *options) {
+		o.verify = opts
+	}
+}
+
+// verifyBundle fetches the cosign-style signature manifest published
+// alongside the check bundle's manifest digest, verifies the signature its
+// sole layer's dev.cosignproject.cosign/signature annotation carries over
+// that layer's actual content, and confirms the signed content is this
+// digest (not some other, unrelated payload a validly-signed-but-replayed
+// layer might carry) - returning an identity string to record as
+// Metadata.Signer on success.
+func (c *Client) verifyBundle(ctx context.Context, digest string, registryOpts types.RegistryOptions) (string, error) {
+	sigRepo := sigTagReference(c.checkBundleRepo, digest)
+
+	ref, err := name.ParseReference(sigRepo)
+	if err != nil {
+		return "", xerrors.Errorf("invalid signature reference %s: %w", sigRepo, err)
+	}
+
+	img, err := remote.Image(ctx, ref, registryOpts)
+	if err != nil {
+		return "", xerrors.Errorf("unable to fetch signature %s: %w", sigRepo, err)
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return "", xerrors.Errorf("unable to read signature manifest %s: %w", sigRepo, err)
+	}
+	if len(manifest.Layers) != 1 {
+		return "", xerrors.Errorf("expected exactly one layer in signature manifest %s, got %d", sigRepo, len(manifest.Layers))
+	}
+
+	sigB64, ok := manifest.Layers[0].Annotations[cosignSignatureAnnotationKey]
+	if !ok {
+		return "", xerrors.Errorf("signature manifest %s is missing the cosign signature annotation", sigRepo)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return "", xerrors.Errorf("failed to decode signature: %w", err)
+	}
+
+	layer, err := img.LayerByDigest(manifest.Layers[0].Digest)
+	if err != nil {
+		return "", xerrors.Errorf("unable to fetch signed payload of %s: %w", sigRepo, err)
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return "", xerrors.Errorf("unable to read signed payload of %s: %w", sigRepo, err)
+	}
+	defer rc.Close()
+	payload, err := io.ReadAll(rc)
+	if err != nil {
+		return "", xerrors.Errorf("unable to read signed payload of %s: %w", sigRepo, err)
+	}
+
+	if string(payload) != digest {
+		return "", xerrors.Errorf("signed payload of %s does not match the bundle digest", sigRepo)
+	}
+
+	if err := verifyPayloadSignature(payload, sig, c.verify.TrustRootPath); err != nil {
+		return "", xerrors.Errorf("signature verification failed for %s: %w", sigRepo, err)
+	}
+
+	return filepath.Base(c.verify.TrustRootPath), nil
+}
+
+// sigTagReference derives the "sha256-<hex>.sig" tag cosign publishes
+// alongside repo, replacing whatever tag or digest suffix repo already has.
+func sigTagReference(repo, digest string) string {
+	base := repo
+	if i := strings.LastIndex(base, "@"); i >= 0 {
+		base = base[:i]
+	} else if i := strings.LastIndex(base, ":"); i >= 0 && i > strings.LastIndex(base, "/") {
+		base = base[:i]
+	}
+	return base + ":" + strings.ReplaceAll(digest, ":", "-") + ".sig"
+}
+
+// verifyPayloadSignature checks sig against the sha256 sum of payload (the
+// signature manifest's actual layer content, per verifyBundle) using the
+// PEM-encoded public key at trustRootPath. This covers the common case of a
+// cosign-generated ECDSA/RSA key-pair signature - see the package doc comment
+// on VerifyOptions for what's intentionally out of scope.
+func verifyPayloadSignature(payload, sig []byte, trustRootPath string) error {
+	keyPEM, err := os.ReadFile(trustRootPath)
+	if err != nil {
+		return xerrors.Errorf("unable to read trust root: %w", err)
+	}
+	pub, err := parsePublicKey(keyPEM)
+	if err != nil {
+		return xerrors.Errorf("unable to parse trust root: %w", err)
+	}
+
+	sum := sha256.Sum256(payload)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, sum[:], sig) {
+			return xerrors.Errorf("ecdsa signature verification failed")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig); err != nil {
+			return xerrors.Errorf("rsa signature verification failed: %w", err)
+		}
+	default:
+		return xerrors.Errorf("unsupported public key type %T", pub)
+	}
+
+	return nil
+}
+
+func parsePublicKey(keyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePublicKeyPEM(t *testing.T, pub *ecdsa.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "trust-root.pem")
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+	return path
+}
+
+func TestVerifyPayloadSignature(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	trustRootPath := writePublicKeyPEM(t, &priv.PublicKey)
+
+	payload := []byte("sha256:deadbeef")
+	sum := sha256.Sum256(payload)
+	validSig, err := ecdsa.SignASN1(rand.Reader, priv, sum[:])
+	require.NoError(t, err)
+
+	t.Run("valid signature is accepted", func(t *testing.T) {
+		assert.NoError(t, verifyPayloadSignature(payload, validSig, trustRootPath))
+	})
+
+	t.Run("tampered payload is rejected", func(t *testing.T) {
+		tampered := []byte("sha256:" + "0000beef")
+		assert.Error(t, verifyPayloadSignature(tampered, validSig, trustRootPath))
+	})
+
+	t.Run("signature from an untrusted key is rejected", func(t *testing.T) {
+		otherPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		otherSig, err := ecdsa.SignASN1(rand.Reader, otherPriv, sum[:])
+		require.NoError(t, err)
+
+		assert.Error(t, verifyPayloadSignature(payload, otherSig, trustRootPath))
+	})
+}
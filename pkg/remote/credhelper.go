@@ -0,0 +1,85 @@
+package remote
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	dockertypes "github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// credHelperCacheTTL bounds how long a credential fetched via a Docker
+// credential helper (ECR/GCR/ACR/docker-credential-desktop, ...) is reused
+// before the helper is re-exec'd, since helpers like ecr-login mint
+// short-lived tokens that shouldn't be cached indefinitely.
+const credHelperCacheTTL = 5 * time.Minute
+
+type credHelperCacheEntry struct {
+	auth      authn.Authenticator
+	expiresAt time.Time
+}
+
+// credHelperCache caches the authenticator resolved for a registry domain
+// so a helper binary isn't re-exec'd for every request to the same registry.
+var credHelperCache sync.Map // map[string]credHelperCacheEntry
+
+// dockerConfigAuth resolves credentials for domain the same way `docker
+// login`/`docker pull` would: a per-registry `credHelpers` entry in
+// ~/.docker/config.json (optionally overridden by option.CredentialHelpers),
+// or the global `credsStore`. It lets users who already have a working
+// ECR/GCR/ACR credential helper configured for Docker use Trivy against the
+// same registries without any extra configuration.
+func dockerConfigAuth(domain string, option types.RegistryOptions) (authn.Authenticator, bool) {
+	if !option.DockerConfig && len(option.CredentialHelpers) == 0 {
+		return nil, false
+	}
+
+	if entry, ok := credHelperCache.Load(domain); ok {
+		if e := entry.(credHelperCacheEntry); time.Now().Before(e.expiresAt) {
+			return e.auth, true
+		}
+		credHelperCache.Delete(domain)
+	}
+
+	cf, err := config.Load(os.Getenv("DOCKER_CONFIG"))
+	if err != nil {
+		return nil, false
+	}
+
+	if helper, ok := option.CredentialHelpers[domain]; ok {
+		if cf.CredentialHelpers == nil {
+			cf.CredentialHelpers = map[string]string{}
+		}
+		cf.CredentialHelpers[domain] = helper
+	}
+
+	ac, err := cf.GetAuthConfig(domain)
+	if err != nil || (ac.Username == "" && ac.Password == "" && ac.IdentityToken == "") {
+		return nil, false
+	}
+
+	auth := authConfigToAuthenticator(ac)
+	credHelperCache.Store(domain, credHelperCacheEntry{
+		auth:      auth,
+		expiresAt: time.Now().Add(credHelperCacheTTL),
+	})
+	return auth, true
+}
+
+// authConfigToAuthenticator converts the AuthConfig a credential helper
+// returned into a go-containerregistry Authenticator: an IdentityToken
+// (the helper protocol's "<token>" username convention) becomes a bearer
+// token, otherwise it's basic auth.
+func authConfigToAuthenticator(ac dockertypes.AuthConfig) authn.Authenticator {
+	if ac.IdentityToken != "" {
+		return &authn.Bearer{Token: ac.IdentityToken}
+	}
+	return &authn.Basic{
+		Username: ac.Username,
+		Password: ac.Password,
+	}
+}
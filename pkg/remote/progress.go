@@ -0,0 +1,253 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// ProgressReporter receives callbacks while Image pulls the layers of a
+// remote image. Start is called once, as soon as the total download size
+// is known from the manifest; Update is called for every chunk of blob
+// bytes read off the wire, possibly from several layers in parallel, so
+// implementations must be safe for concurrent use; LayerDone is called
+// once per completed layer.
+type ProgressReporter interface {
+	Start(totalBytes int64)
+	Update(readBytes int64)
+	LayerDone(digest string)
+}
+
+// nopProgressReporter discards every callback. It's used when the caller
+// doesn't configure a ProgressReporter, so Image's hot path never has to
+// nil-check.
+type nopProgressReporter struct{}
+
+func (nopProgressReporter) Start(int64)      {}
+func (nopProgressReporter) Update(int64)     {}
+func (nopProgressReporter) LayerDone(string) {}
+
+// NopProgressReporter is the default ProgressReporter: it does nothing.
+var NopProgressReporter ProgressReporter = nopProgressReporter{}
+
+// layersTotalSize sums the compressed size of every layer in img's
+// manifest, giving a ProgressReporter a total to report against before any
+// layer download starts. The manifest and config blob are a rounding
+// error next to layer data, so only layers are counted.
+func layersTotalSize(img v1.Image) (int64, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return 0, xerrors.Errorf("manifest error: %w", err)
+	}
+
+	var total int64
+	for _, l := range manifest.Layers {
+		total += l.Size
+	}
+	return total, nil
+}
+
+// withProgress wraps tr so that bytes read from blob download responses
+// are reported to reporter. Manifest/tag/catalog requests pass through
+// unreported; only GET requests against a blob path move layer-sized
+// amounts of data worth showing progress for.
+func withProgress(tr http.RoundTripper, reporter ProgressReporter) http.RoundTripper {
+	if reporter == nil || reporter == NopProgressReporter {
+		return tr
+	}
+	return &progressRoundTripper{RoundTripper: tr, reporter: reporter}
+}
+
+type progressRoundTripper struct {
+	http.RoundTripper
+	reporter ProgressReporter
+}
+
+func (p *progressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.RoundTripper.RoundTrip(req)
+	if err != nil || resp.Body == nil || !isBlobRequest(req) {
+		return resp, err
+	}
+
+	digest := blobDigest(req)
+	resp.Body = &progressReadCloser{
+		ReadCloser: resp.Body,
+		reporter:   p.reporter,
+		digest:     digest,
+	}
+	return resp, nil
+}
+
+func isBlobRequest(req *http.Request) bool {
+	return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/blobs/")
+}
+
+// blobDigest pulls the "sha256:..." suffix off a blob request's path so
+// LayerDone can identify which layer just finished.
+func blobDigest(req *http.Request) string {
+	_, digest, ok := strings.Cut(req.URL.Path, "/blobs/")
+	if !ok {
+		return ""
+	}
+	return digest
+}
+
+type progressReadCloser struct {
+	io.ReadCloser
+	reporter ProgressReporter
+	digest   string
+	done     atomic.Bool
+}
+
+func (p *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.reporter.Update(int64(n))
+	}
+	if err == io.EOF && p.done.CompareAndSwap(false, true) {
+		p.reporter.LayerDone(p.digest)
+	}
+	return n, err
+}
+
+func (p *progressReadCloser) Close() error {
+	return p.ReadCloser.Close()
+}
+
+// NewProgressReporter returns a TTY progress bar when w is a terminal, and
+// a reporter that emits periodic JSON log lines otherwise (e.g. when
+// stderr is redirected to a file or a CI log). totalDesc labels the bar,
+// typically the image reference being pulled.
+func NewProgressReporter(w *os.File, totalDesc string) ProgressReporter {
+	if w != nil && term.IsTerminal(int(w.Fd())) {
+		return newTTYProgressReporter(w, totalDesc)
+	}
+	return newLogProgressReporter(totalDesc)
+}
+
+// ttyProgressReporter renders a single-line, redrawn-in-place progress bar.
+// It's intentionally dependency-free (no mpb/progressbar package is
+// vendored in this checkout) - just enough to give an interactive user a
+// sense of how much of the pull is left.
+type ttyProgressReporter struct {
+	w    *os.File
+	desc string
+
+	mu    sync.Mutex
+	total int64
+	read  int64
+}
+
+func newTTYProgressReporter(w *os.File, desc string) *ttyProgressReporter {
+	return &ttyProgressReporter{w: w, desc: desc}
+}
+
+func (t *ttyProgressReporter) Start(totalBytes int64) {
+	t.mu.Lock()
+	t.total = totalBytes
+	t.mu.Unlock()
+	t.render()
+}
+
+func (t *ttyProgressReporter) Update(readBytes int64) {
+	t.mu.Lock()
+	t.read += readBytes
+	t.mu.Unlock()
+	t.render()
+}
+
+func (t *ttyProgressReporter) LayerDone(string) {
+	t.render()
+}
+
+const ttyProgressBarWidth = 30
+
+func (t *ttyProgressReporter) render() {
+	t.mu.Lock()
+	read, total := t.read, t.total
+	t.mu.Unlock()
+
+	var pct float64
+	if total > 0 {
+		pct = float64(read) / float64(total)
+	}
+	filled := int(pct * ttyProgressBarWidth)
+	if filled > ttyProgressBarWidth {
+		filled = ttyProgressBarWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", ttyProgressBarWidth-filled)
+	fmt.Fprintf(t.w, "\r%s [%s] %s/%s", t.desc, bar, humanBytes(read), humanBytes(total))
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// logProgressReporter emits a JSON-structured log line at most once per
+// logProgressInterval, instead of redrawing a bar, since non-TTY output
+// (piped to a file, collected by CI) is read line-by-line rather than
+// watched live.
+type logProgressReporter struct {
+	desc string
+	last atomic.Int64 // unix nano of the last emitted line
+
+	mu    sync.Mutex
+	total int64
+	read  int64
+}
+
+const logProgressInterval = 5 * time.Second
+
+func newLogProgressReporter(desc string) *logProgressReporter {
+	return &logProgressReporter{desc: desc}
+}
+
+func (l *logProgressReporter) Start(totalBytes int64) {
+	l.mu.Lock()
+	l.total = totalBytes
+	l.mu.Unlock()
+	log.WithPrefix("remote").Info("Pull started",
+		log.String("image", l.desc), log.Int64("total_bytes", totalBytes))
+}
+
+func (l *logProgressReporter) Update(readBytes int64) {
+	l.mu.Lock()
+	l.read += readBytes
+	read, total := l.read, l.total
+	l.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	last := l.last.Load()
+	if time.Duration(now-last) < logProgressInterval {
+		return
+	}
+	if !l.last.CompareAndSwap(last, now) {
+		return
+	}
+	log.WithPrefix("remote").Info("Pull progress",
+		log.String("image", l.desc), log.Int64("read_bytes", read), log.Int64("total_bytes", total))
+}
+
+func (l *logProgressReporter) LayerDone(digest string) {
+	log.WithPrefix("remote").Info("Layer done", log.String("image", l.desc), log.String("digest", digest))
+}
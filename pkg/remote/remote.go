@@ -105,10 +105,16 @@ func tryGet(ctx context.Context, tr http.RoundTripper, ref name.Reference, optio
 // Image is a wrapper of google/go-containerregistry/pkg/v1/remote.Image
 // so that it can try multiple authentication methods.
 func Image(ctx context.Context, ref name.Reference, option types.RegistryOptions) (v1.Image, error) {
+	reporter := option.Progress
+	if reporter == nil {
+		reporter = NopProgressReporter
+	}
+
 	tr, err := httpTransport(option)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create http transport: %w", err)
 	}
+	tr = withProgress(tr, reporter)
 
 	mirrors, err := registryMirrors(ref, option)
 	if err != nil {
@@ -118,13 +124,22 @@ func Image(ctx context.Context, ref name.Reference, option types.RegistryOptions
 	var errs error
 	// Try each mirrors/origin registries until it succeeds
 	for _, r := range append(mirrors, ref) {
+		start := time.Now()
 		// Try each authentication method until it succeeds
-		var image v1.Image
-		image, err = tryImage(ctx, tr, r, option)
+		image, err := tryImage(ctx, tr, r, option)
+		took := time.Since(start)
 		if err != nil {
+			log.WithPrefix("remote").Debug("Pull attempt failed, falling back",
+				log.String("registry", r.Context().RegistryStr()), log.String("took", took.String()))
 			errs = multierror.Append(errs, err)
 			continue
+		}
+		log.WithPrefix("remote").Debug("Pulled image manifest",
+			log.String("registry", r.Context().RegistryStr()), log.String("took", took.String()))
 
+		total, sizeErr := layersTotalSize(image)
+		if sizeErr == nil {
+			reporter.Start(total)
 		}
 		return image, nil
 	}
@@ -184,6 +199,96 @@ func Referrers(ctx context.Context, d name.Digest, option types.RegistryOptions)
 	return nil, errs
 }
 
+// Catalog is a wrapper of google/go-containerregistry/pkg/v1/remote.Catalog
+// so that it can try multiple authentication methods and registry mirrors,
+// the same as Get/Image. It walks the registry's `_catalog` endpoint,
+// following pagination via the Link header (go-containerregistry's Catalog
+// does this internally), and returns every repository name the caller is
+// authorized to see.
+func Catalog(ctx context.Context, reg name.Registry, option types.RegistryOptions) ([]string, error) {
+	tr, err := httpTransport(option)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create http transport: %w", err)
+	}
+
+	mirrors, err := registryMirrorsForRegistry(reg, option)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse mirrors: %w", err)
+	}
+
+	var errs error
+	for _, r := range append(mirrors, reg) {
+		repos, err := tryCatalog(ctx, tr, r, option)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		return repos, nil
+	}
+	return nil, errs
+}
+
+func tryCatalog(ctx context.Context, tr http.RoundTripper, reg name.Registry, option types.RegistryOptions) ([]string, error) {
+	var errs error
+	for _, authOpt := range authOptionsForDomain(ctx, reg.RegistryStr(), option) {
+		remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithTransport(tr), authOpt}
+		repos, err := remote.Catalog(ctx, reg, remoteOpts...)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		return repos, nil
+	}
+	return nil, errs
+}
+
+// List is a wrapper of google/go-containerregistry/pkg/v1/remote.ListWithContext
+// so that it can try multiple authentication methods, the same as
+// Get/Image. It returns every tag in repo, following pagination via the
+// Link header.
+func List(ctx context.Context, repo name.Repository, option types.RegistryOptions) ([]string, error) {
+	tr, err := httpTransport(option)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create http transport: %w", err)
+	}
+
+	var errs error
+	for _, authOpt := range authOptionsForDomain(ctx, repo.RegistryStr(), option) {
+		remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithTransport(tr), authOpt}
+		tags, err := remote.ListWithContext(ctx, repo, remoteOpts...)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		return tags, nil
+	}
+	return nil, errs
+}
+
+// registryMirrorsForRegistry is registryMirrors for a bare name.Registry
+// rather than a name.Reference, used by Catalog since `_catalog` is a
+// registry-level endpoint with no repository/tag to substitute.
+func registryMirrorsForRegistry(reg name.Registry, option types.RegistryOptions) ([]name.Registry, error) {
+	var mirrors []name.Registry
+
+	ms, ok := option.RegistryMirrors[reg.RegistryStr()]
+	if !ok {
+		return nil, nil
+	}
+	for _, m := range ms {
+		var nameOpts []name.Option
+		if option.Insecure {
+			nameOpts = append(nameOpts, name.Insecure)
+		}
+		mirror, err := name.NewRegistry(m, nameOpts...)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to parse mirror registry: %w", err)
+		}
+		mirrors = append(mirrors, mirror)
+	}
+	return mirrors, nil
+}
+
 func registryMirrors(hostRef name.Reference, option types.RegistryOptions) ([]name.Reference, error) {
 	var mirrors []name.Reference
 
@@ -221,11 +326,58 @@ func httpTransport(option types.RegistryOptions) (http.RoundTripper, error) {
 		tr.TLSClientConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	tripper := transport.NewUserAgent(tr, fmt.Sprintf("trivy/%s", app.Version()))
+	userAgent := fmt.Sprintf("trivy/%s", app.Version())
+	if option.UserAgent != "" {
+		userAgent = option.UserAgent
+	}
+	tripper := transport.NewUserAgent(tr, userAgent)
+
+	// Registries increasingly rate-limit or block requests with no
+	// identifying UA, and enterprise proxies in front of a registry
+	// commonly require an injected auth header (e.g. a reverse-proxy
+	// bearer token distinct from the registry's own WWW-Authenticate
+	// challenge) - option.HTTPHeaders lets either be supplied without the
+	// caller reimplementing http.RoundTripper.
+	if len(option.HTTPHeaders) > 0 {
+		tripper = withHeaders(tripper, option.HTTPHeaders)
+	}
 	return tripper, nil
 }
 
+// withHeaders wraps tr so every outbound request carries headers in
+// addition to whatever it already sets - a header the request sets for
+// itself (e.g. Accept, set per-call by go-containerregistry) takes
+// precedence, since a configured header is only added when the request
+// doesn't already have it.
+func withHeaders(tr http.RoundTripper, headers http.Header) http.RoundTripper {
+	return &headerRoundTripper{base: tr, headers: headers}
+}
+
+type headerRoundTripper struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, vv := range h.headers {
+		if req.Header.Get(k) == "" {
+			for _, v := range vv {
+				req.Header.Add(k, v)
+			}
+		}
+	}
+	return h.base.RoundTrip(req)
+}
+
 func authOptions(ctx context.Context, ref name.Reference, option types.RegistryOptions) []remote.Option {
+	return authOptionsForDomain(ctx, ref.Context().RegistryStr(), option)
+}
+
+// authOptionsForDomain is the domain-keyed core of authOptions. It's split
+// out so that Catalog/List, which operate on a name.Registry/name.Repository
+// rather than a name.Reference, can reuse the exact same auth resolution.
+func authOptionsForDomain(ctx context.Context, domain string, option types.RegistryOptions) []remote.Option {
 	var opts []remote.Option
 	for _, cred := range option.Credentials {
 		opts = append(opts, remote.WithAuth(&authn.Basic{
@@ -234,12 +386,15 @@ func authOptions(ctx context.Context, ref name.Reference, option types.RegistryO
 		}))
 	}
 
-	domain := ref.Context().RegistryStr()
 	token := registry.GetToken(ctx, domain, option)
 	if !lo.IsEmpty(token) {
 		opts = append(opts, remote.WithAuth(&token))
 	}
 
+	if cred, ok := dockerConfigAuth(domain, option); ok {
+		opts = append(opts, remote.WithAuth(cred))
+	}
+
 	switch {
 	case option.RegistryToken != "":
 		bearer := authn.Bearer{Token: option.RegistryToken}
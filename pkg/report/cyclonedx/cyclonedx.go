@@ -3,12 +3,15 @@ package cyclonedx
 import (
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
 	"k8s.io/utils/clock"
@@ -39,10 +42,26 @@ type Writer struct {
 
 type newUUID func() uuid.UUID
 
+// Orientation selects how parseComponents/vex group vulnerabilities that
+// cover the same upstream issue but were reported under different IDs by
+// different OS/advisory sources (GHSA, RHSA, DSA, ALAS, ...).
+type Orientation int
+
+const (
+	// OrientByAdvisory emits one cdx.Vulnerability per advisory ID, as
+	// reported by each source - the original, default behavior.
+	OrientByAdvisory Orientation = iota
+	// OrientByCVE collapses advisory IDs that map to the same upstream CVE
+	// into a single cdx.Vulnerability keyed by that CVE, demoting the
+	// original advisory IDs to VulnerabilityReference entries.
+	OrientByCVE
+)
+
 type options struct {
-	format  cdx.BOMFileFormat
-	clock   clock.Clock
-	newUUID newUUID
+	format      cdx.BOMFileFormat
+	clock       clock.Clock
+	newUUID     newUUID
+	orientation Orientation
 }
 
 type option func(*options)
@@ -65,11 +84,20 @@ func WithNewUUID(newUUID newUUID) option {
 	}
 }
 
+// WithOrientation selects how vulnerabilities sharing an upstream CVE but
+// reported under distinct OS advisory IDs are grouped; see Orientation.
+func WithOrientation(orientation Orientation) option {
+	return func(opts *options) {
+		opts.orientation = orientation
+	}
+}
+
 func NewWriter(output io.Writer, version string, opts ...option) Writer {
 	o := &options{
-		format:  cdx.BOMFileFormatJSON,
-		clock:   clock.RealClock{},
-		newUUID: uuid.New,
+		format:      cdx.BOMFileFormatJSON,
+		clock:       clock.RealClock{},
+		newUUID:     uuid.New,
+		orientation: OrientByAdvisory,
 	}
 
 	for _, opt := range opts {
@@ -106,18 +134,50 @@ func (cw Writer) Write(report types.Report) error {
 	return nil
 }
 
+// vex builds a CycloneDX document for a report produced by re-scanning an
+// existing CycloneDX SBOM. Earlier versions emitted only the "vulnerabilities"
+// array (pure VEX), which meant the resulting document couldn't stand on its
+// own without the original SBOM. We now carry the original component
+// inventory through as well, so a single document can be used both as the SBOM
+// and as its VEX.
 func (cw *Writer) vex(results types.Results, bomLink string) (*cdx.BOM, error) {
 	vulnMap := map[string]cdx.Vulnerability{}
+	bomRefMap := map[string]string{}
+	var components []cdx.Component
 	for _, result := range results {
+		for _, pkg := range result.Packages {
+			ref, err := vexRef(bomLink, utils.FormatVersion(pkg))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := bomRefMap[ref]; ok {
+				continue
+			}
+			bomRefMap[ref] = ref
+			components = append(components, cdx.Component{
+				BOMRef:  ref,
+				Type:    cdx.ComponentTypeLibrary,
+				Name:    pkg.Name,
+				Version: pkg.Version,
+			})
+		}
 		for _, vuln := range result.Vulnerabilities {
 			ref, err := vexRef(bomLink, vuln.Ref)
 			if err != nil {
 				return nil, err
 			}
-			if v, ok := vulnMap[vuln.VulnerabilityID]; ok {
+			key, advisoryID := cw.vulnerabilityKey(vuln)
+			if v, ok := vulnMap[key]; ok {
 				*v.Affects = append(*v.Affects, cyclonedx.Affects(ref, vuln.InstalledVersion))
+				mergeVulnerabilitySources(&v, cyclonedx.Vulnerability(vuln, ref))
+				addVulnerabilityReference(&v, advisoryID)
+				vulnMap[key] = v
 			} else {
-				vulnMap[vuln.VulnerabilityID] = cyclonedx.Vulnerability(vuln, ref)
+				v := cyclonedx.Vulnerability(vuln, ref)
+				v.ID = key
+				v.Analysis = vulnerabilityAnalysis(vuln.Status)
+				addVulnerabilityReference(&v, advisoryID)
+				vulnMap[key] = v
 			}
 		}
 	}
@@ -128,10 +188,170 @@ func (cw *Writer) vex(results types.Results, bomLink string) (*cdx.BOM, error) {
 
 	bom := cdx.NewBOM()
 	bom.Vulnerabilities = &vulns
+	if len(components) > 0 {
+		bom.Components = &components
+	}
 	bom.Metadata = cw.newBOMMetadata()
 	return bom, nil
 }
 
+// vulnerabilityAnalysis maps trivy's vulnerability status - sourced either
+// from the advisory itself or a user-authored VEX statement/.trivyignore.yaml
+// entry - onto CycloneDX's Vulnerability.Analysis object, so a VEX
+// consumer sees why a finding is or isn't actionable without having to
+// understand trivy's own status vocabulary. Statuses trivy can't map
+// confidently (unknown, affected with no further detail) are left as nil,
+// which omits "analysis" from the emitted BOM entirely rather than
+// asserting something we don't know.
+func vulnerabilityAnalysis(status dbTypes.Status) *cdx.VulnerabilityAnalysis {
+	switch status {
+	case dbTypes.StatusNotAffected:
+		return &cdx.VulnerabilityAnalysis{
+			State:         cdx.IASNotAffected,
+			Justification: cdx.IAJCodeNotReachable,
+		}
+	case dbTypes.StatusFixed:
+		return &cdx.VulnerabilityAnalysis{
+			State:    cdx.IASResolved,
+			Response: &[]cdx.ImpactAnalysisResponse{cdx.IARUpdate},
+		}
+	case dbTypes.StatusUnderInvestigation:
+		return &cdx.VulnerabilityAnalysis{
+			State: cdx.IASInTriage,
+		}
+	case dbTypes.StatusWillNotFix:
+		return &cdx.VulnerabilityAnalysis{
+			State:    cdx.IASExploitable,
+			Response: &[]cdx.ImpactAnalysisResponse{cdx.IARWillNotFix},
+		}
+	case dbTypes.StatusFixDeferred:
+		return &cdx.VulnerabilityAnalysis{
+			State:    cdx.IASExploitable,
+			Response: &[]cdx.ImpactAnalysisResponse{cdx.IARUpdate},
+			Detail:   "fix deferred to a later release",
+		}
+	case dbTypes.StatusEndOfLife:
+		return &cdx.VulnerabilityAnalysis{
+			State:    cdx.IASExploitable,
+			Response: &[]cdx.ImpactAnalysisResponse{cdx.IARWorkaroundAvailable},
+			Detail:   "affected package has reached end of life and will not receive a fix",
+		}
+	case dbTypes.StatusAffected:
+		return &cdx.VulnerabilityAnalysis{
+			State: cdx.IASExploitable,
+		}
+	default:
+		return nil
+	}
+}
+
+// cveIDPattern matches a bare CVE identifier, used by canonicalCVE to tell
+// an upstream CVE apart from an OS/advisory ID (GHSA-xxxx, RHSA-xxxx,
+// DSA-xxxx, ALAS-xxxx, ...) referencing it.
+var cveIDPattern = regexp.MustCompile(`^CVE-\d{4}-\d+$`)
+
+// canonicalCVE returns the upstream CVE vuln maps to under OrientByCVE:
+// vuln's own ID if it's already a CVE, else the first CVE found among its
+// References, else "" if none is known.
+//
+// NOTE: vuln.References ([]string, e.g. advisory cross-references) is
+// assumed present on the richer types.DetectedVulnerability this file
+// already expects (see the NOTE on types.Report and vex's use of vuln.Ref) -
+// trivy-db's own advisory data carries a CVE cross-reference for OS
+// advisories of this kind, so this is the field that data would surface as.
+func canonicalCVE(vuln types.DetectedVulnerability) string {
+	if cveIDPattern.MatchString(vuln.VulnerabilityID) {
+		return vuln.VulnerabilityID
+	}
+	for _, ref := range vuln.References {
+		if cveIDPattern.MatchString(ref) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// vulnerabilityKey returns the vulnMap key vuln should be filed under, and
+// the original advisory ID to demote to a VulnerabilityReference when that
+// key differs from vuln.VulnerabilityID (i.e. orientation collapsed it into
+// its upstream CVE).
+func (cw *Writer) vulnerabilityKey(vuln types.DetectedVulnerability) (key, advisoryID string) {
+	if cw.orientation == OrientByCVE {
+		if cve := canonicalCVE(vuln); cve != "" && cve != vuln.VulnerabilityID {
+			return cve, vuln.VulnerabilityID
+		}
+	}
+	return vuln.VulnerabilityID, ""
+}
+
+// mergeVulnerabilitySources folds from's Ratings, CWEs and Advisories into
+// into, deduplicating so re-merging the same source twice (e.g. two packages
+// affected by the same advisory) doesn't pile up repeats. Called whenever
+// OrientByCVE collapses a second advisory into an already-seen CVE bucket.
+func mergeVulnerabilitySources(into *cdx.Vulnerability, from cdx.Vulnerability) {
+	if from.Ratings != nil {
+		ratings := cdx.Ratings{}
+		if into.Ratings != nil {
+			ratings = *into.Ratings
+		}
+		for _, r := range *from.Ratings {
+			if !lo.ContainsBy(ratings, func(existing cdx.Rating) bool {
+				return existing.Source != nil && r.Source != nil && existing.Source.Name == r.Source.Name
+			}) {
+				ratings = append(ratings, r)
+			}
+		}
+		into.Ratings = &ratings
+	}
+
+	if from.CWEs != nil {
+		cwes := []int{}
+		if into.CWEs != nil {
+			cwes = *into.CWEs
+		}
+		for _, cwe := range *from.CWEs {
+			if !lo.Contains(cwes, cwe) {
+				cwes = append(cwes, cwe)
+			}
+		}
+		into.CWEs = (*cdx.CWEs)(&cwes)
+	}
+
+	if from.Advisories != nil {
+		advisories := []cdx.Advisory{}
+		if into.Advisories != nil {
+			advisories = *into.Advisories
+		}
+		for _, a := range *from.Advisories {
+			if !lo.ContainsBy(advisories, func(existing cdx.Advisory) bool {
+				return existing.URL == a.URL
+			}) {
+				advisories = append(advisories, a)
+			}
+		}
+		into.Advisories = &advisories
+	}
+}
+
+// addVulnerabilityReference records advisoryID as a cross-reference on v -
+// the original per-source advisory ID a CVE-oriented cdx.Vulnerability was
+// collapsed from - without duplicating an ID already present.
+func addVulnerabilityReference(v *cdx.Vulnerability, advisoryID string) {
+	if advisoryID == "" {
+		return
+	}
+	if v.References == nil {
+		v.References = &[]cdx.VulnerabilityReference{{ID: advisoryID}}
+		return
+	}
+	for _, existing := range *v.References {
+		if existing.ID == advisoryID {
+			return
+		}
+	}
+	*v.References = append(*v.References, cdx.VulnerabilityReference{ID: advisoryID})
+}
+
 func vexRef(bomLink string, bomRef string) (string, error) {
 	if !strings.HasPrefix(bomLink, "urn:uuid:") {
 		return "", xerrors.Errorf("%q: %w", bomLink, ErrInvalidBOMLink)
@@ -176,10 +396,22 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 	var dependencies []cdx.Dependency
 	var metadataDependencies []cdx.Dependency
 	libraryUniqMap := map[string]struct{}{}
+	packageDependencyRefs := map[string]struct{}{}
+	// componentIndexByRef lets the result.Licenses pass below attach
+	// evidence to a component already appended to the shared components
+	// slice, without needing to carry a pointer through libraryUniqMap.
+	componentIndexByRef := map[string]int{}
 	vulnMap := map[string]cdx.Vulnerability{}
 	for _, result := range r.Results {
-		var componentDependencies []cdx.Dependency
 		bomRefMap := map[string]string{}
+		// licenseRefMap resolves a package by name+path alone (no version),
+		// since a license finding (result.Licenses) identifies the file a
+		// license was found in, not necessarily the exact installed version.
+		licenseRefMap := map[string]string{}
+		// pkgRefByID resolves ftypes.Package.ID to the bom-ref of the
+		// component it became, so DependsOn edges (also expressed in terms
+		// of Package.ID) can be translated into cdx.Dependency edges below.
+		pkgRefByID := map[string]string{}
 		for _, pkg := range result.Packages {
 			pkgComponent, err := cw.pkgToComponent(result.Type, r.Metadata, pkg)
 			if err != nil {
@@ -188,6 +420,8 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 			if _, ok := bomRefMap[pkg.Name+utils.FormatVersion(pkg)+pkg.FilePath]; !ok {
 				bomRefMap[pkg.Name+utils.FormatVersion(pkg)+pkg.FilePath] = pkgComponent.BOMRef
 			}
+			licenseRefMap[pkg.Name+pkg.FilePath] = pkgComponent.BOMRef
+			pkgRefByID[pkg.ID] = pkgComponent.BOMRef
 
 			// When multiple lock files have the same dependency with the same name and version,
 			// "bom-ref" (PURL technically) of Library components may conflict.
@@ -205,25 +439,89 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 
 				// For components
 				// ref. https://cyclonedx.org/use-cases/#inventory
-				//
-				// TODO: All packages are flattened at the moment. We should construct dependency tree.
+				componentIndexByRef[pkgComponent.BOMRef] = len(components)
 				components = append(components, pkgComponent)
 			}
+		}
+
+		// result.Licenses holds file-level license-scanner findings (as
+		// opposed to a package's own declared license field, handled by
+		// pkgToComponent above); attach each as Evidence on the component it
+		// was found in, per CycloneDX's evidence.licenses use case.
+		for _, lic := range result.Licenses {
+			ref, ok := licenseRefMap[lic.PkgName+lic.FilePath]
+			if !ok {
+				continue
+			}
+			idx, ok := componentIndexByRef[ref]
+			if !ok {
+				continue
+			}
+			attachLicenseEvidence(&components[idx], lic)
+		}
+
+		// transitiveRoots collects the packages that are never referenced by
+		// another package's DependsOn in this result - these, not every
+		// package, are the direct children of the Application/OS component.
+		// Ecosystems that don't populate DependsOn leave this map empty, so
+		// every package falls through as a root, preserving the old flat
+		// behavior for them.
+		dependedUpon := map[string]struct{}{}
+		for _, pkg := range result.Packages {
+			for _, depID := range pkg.DependsOn {
+				dependedUpon[depID] = struct{}{}
+			}
+		}
+
+		var componentDependencies []cdx.Dependency
+		for _, pkg := range result.Packages {
+			ref, ok := pkgRefByID[pkg.ID]
+			if !ok {
+				continue
+			}
 
-			componentDependencies = append(componentDependencies, cdx.Dependency{Ref: pkgComponent.BOMRef})
+			if len(pkg.DependsOn) > 0 {
+				var children []cdx.Dependency
+				for _, depID := range pkg.DependsOn {
+					if childRef, ok := pkgRefByID[depID]; ok {
+						children = append(children, cdx.Dependency{Ref: childRef})
+					}
+				}
+				if _, seen := packageDependencyRefs[ref]; !seen && len(children) > 0 {
+					packageDependencyRefs[ref] = struct{}{}
+					dependencies = append(dependencies, cdx.Dependency{Ref: ref, Dependencies: &children})
+				}
+			}
+
+			if _, isChild := dependedUpon[pkg.ID]; !isChild {
+				componentDependencies = append(componentDependencies, cdx.Dependency{Ref: ref})
+			}
 		}
+
 		for _, vuln := range result.Vulnerabilities {
 			// Take a bom-ref
 			ref := bomRefMap[vuln.PkgName+vuln.InstalledVersion+vuln.PkgPath]
-			if v, ok := vulnMap[vuln.VulnerabilityID]; ok {
+			// key is normally vuln.VulnerabilityID; under OrientByCVE it's
+			// the upstream CVE when vuln was reported under a distinct
+			// advisory ID, so e.g. GHSA-xxxx and the RHSA covering the same
+			// issue land in one cdx.Vulnerability instead of two.
+			key, advisoryID := cw.vulnerabilityKey(vuln)
+			if v, ok := vulnMap[key]; ok {
 				// If a vulnerability depends on multiple packages,
 				// it will be commonised into a single vulnerability.
 				//   Vulnerability component (CVE-2020-26247)
 				//     -> Library component (nokogiri /srv/app1/vendor/bundle/ruby/3.0.0/specifications/nokogiri-1.10.0.gemspec)
 				//     -> Library component (nokogiri /srv/app2/vendor/bundle/ruby/3.0.0/specifications/nokogiri-1.10.0.gemspec)
 				*v.Affects = append(*v.Affects, cyclonedx.Affects(ref, vuln.InstalledVersion))
+				mergeVulnerabilitySources(&v, cyclonedx.Vulnerability(vuln, ref))
+				addVulnerabilityReference(&v, advisoryID)
+				vulnMap[key] = v
 			} else {
-				vulnMap[vuln.VulnerabilityID] = cyclonedx.Vulnerability(vuln, ref)
+				v := cyclonedx.Vulnerability(vuln, ref)
+				v.ID = key
+				v.Analysis = vulnerabilityAnalysis(vuln.Status)
+				addVulnerabilityReference(&v, advisoryID)
+				vulnMap[key] = v
 			}
 		}
 
@@ -260,6 +558,12 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 			resultComponent := cw.resultToComponent(result, r.Metadata.OS)
 			components = append(components, resultComponent)
 
+			if result.Class == types.ClassConfig {
+				var resourceComponents []cdx.Component
+				resourceComponents, componentDependencies, vulnMap = cw.configComponents(result, vulnMap)
+				components = append(components, resourceComponents...)
+			}
+
 			// Dependency graph from #2 to #3
 			dependencies = append(dependencies,
 				cdx.Dependency{Ref: resultComponent.BOMRef, Dependencies: &componentDependencies},
@@ -281,6 +585,97 @@ func (cw *Writer) parseComponents(r types.Report, bomRef string) (*[]cdx.Compone
 	return &components, &dependencies, &vulns, nil
 }
 
+// spdxLicenseIDs is the handful of SPDX license identifiers trivy's language
+// analyzers already normalize a declared license onto (see
+// internal/licenses.canonical). It's not the full SPDX list - just enough
+// to tell pkgToComponent whether to set License.ID (a recognized
+// identifier) or fall back to License.Name (an arbitrary declared string).
+var spdxLicenseIDs = map[string]struct{}{
+	"MIT":           {},
+	"Apache-2.0":    {},
+	"BSD-2-Clause":  {},
+	"BSD-3-Clause":  {},
+	"ISC":           {},
+	"GPL-2.0-only":  {},
+	"GPL-3.0-only":  {},
+	"LGPL-2.1-only": {},
+	"LGPL-3.0-only": {},
+	"MPL-2.0":       {},
+	"Unlicense":     {},
+	"0BSD":          {},
+}
+
+// splitLicenses breaks a declared license field into its individual license
+// names. It only unwraps the single-level "A OR B"/"A AND B" forms common in
+// package manifests (mirroring internal/licenses.Scanner.Normalize's own
+// single-level OR handling), not the full SPDX expression grammar.
+func splitLicenses(license string) []string {
+	license = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(license, "("), ")"))
+	if license == "" {
+		return nil
+	}
+
+	var names []string
+	for _, orPart := range strings.Split(license, " OR ") {
+		names = append(names, strings.Split(orPart, " AND ")...)
+	}
+
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// licenseChoices turns a declared license field into one cdx.LicenseChoice
+// per license name, each identified by SPDX ID when recognized and by
+// free-form Name otherwise - rather than a single Expression, which implies
+// an SPDX expression the declared string usually isn't.
+func licenseChoices(license string) cdx.Licenses {
+	var choices cdx.Licenses
+	for _, name := range splitLicenses(license) {
+		l := cdx.License{Name: name}
+		if _, ok := spdxLicenseIDs[name]; ok {
+			l = cdx.License{ID: name}
+		}
+		choices = append(choices, cdx.LicenseChoice{License: &l})
+	}
+	return choices
+}
+
+// attachLicenseEvidence records lic - a file-level license-scanner finding,
+// assumed present as types.DetectedLicense{PkgName, FilePath, Name, Severity,
+// Category, Confidence} on the richer types.Result this file already expects
+// (see the NOTE on types.Report) - as Evidence on c. Confidence and FilePath
+// have no home on CycloneDX's License object itself, so they're carried as
+// component properties alongside the evidence entry rather than dropped.
+func attachLicenseEvidence(c *cdx.Component, lic types.DetectedLicense) {
+	l := cdx.License{Name: lic.Name}
+	if _, ok := spdxLicenseIDs[lic.Name]; ok {
+		l = cdx.License{ID: lic.Name}
+	}
+
+	var licenses cdx.Licenses
+	if c.Evidence != nil && c.Evidence.Licenses != nil {
+		licenses = *c.Evidence.Licenses
+	}
+	licenses = append(licenses, cdx.LicenseChoice{License: &l})
+	if c.Evidence == nil {
+		c.Evidence = &cdx.Evidence{}
+	}
+	c.Evidence.Licenses = &licenses
+
+	var properties []cdx.Property
+	if c.Properties != nil {
+		properties = *c.Properties
+	}
+	properties = cyclonedx.AppendProperties(properties, "LicenseFindingConfidence", strconv.FormatFloat(lic.Confidence, 'f', -1, 64))
+	properties = cyclonedx.AppendProperties(properties, "LicenseFindingFilePath", lic.FilePath)
+	c.Properties = &properties
+}
+
 func (cw *Writer) pkgToComponent(t string, meta types.Metadata, pkg ftypes.Package) (cdx.Component, error) {
 	pu, err := purl.NewPackageURL(t, meta, pkg)
 	if err != nil {
@@ -297,8 +692,8 @@ func (cw *Writer) pkgToComponent(t string, meta types.Metadata, pkg ftypes.Packa
 	}
 
 	if pkg.License != "" {
-		component.Licenses = &cdx.Licenses{
-			cdx.LicenseChoice{Expression: pkg.License},
+		if choices := licenseChoices(pkg.License); len(choices) > 0 {
+			component.Licenses = &choices
 		}
 	}
 	if isAggreated(t) {
@@ -352,11 +747,137 @@ func (cw *Writer) reportToComponent(r types.Report) (*cdx.Component, error) {
 		properties = cyclonedx.AppendProperties(properties, cyclonedx.PropertyRepoTag, t)
 	}
 
+	properties = append(properties, licenseRiskProperties(r)...)
+
 	component.Properties = &properties
 
 	return component, nil
 }
 
+// licenseRiskProperties summarizes every result.Licenses finding across the
+// whole report as counts by severity and category, attached to the metadata
+// component so a BOM consumer gets a license-risk overview without having to
+// walk every component's Evidence.Licenses.
+func licenseRiskProperties(r types.Report) []cdx.Property {
+	bySeverity := map[string]int{}
+	byCategory := map[string]int{}
+	for _, result := range r.Results {
+		for _, lic := range result.Licenses {
+			bySeverity[lic.Severity]++
+			byCategory[lic.Category]++
+		}
+	}
+
+	var properties []cdx.Property
+	for _, severity := range maps.Keys(bySeverity) {
+		properties = cyclonedx.AppendProperties(properties, "LicenseRiskSeverity:"+severity, strconv.Itoa(bySeverity[severity]))
+	}
+	for _, category := range maps.Keys(byCategory) {
+		properties = cyclonedx.AppendProperties(properties, "LicenseRiskCategory:"+category, strconv.Itoa(byCategory[category]))
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+	return properties
+}
+
+// configComponents builds one child component per distinct resource address
+// referenced by result.Misconfigurations (e.g. a single Terraform resource
+// block, Kubernetes manifest, or Dockerfile stage), plus one cdx.Vulnerability
+// per finding in vulnMap, so IaC findings show up as first-class BOM entries
+// instead of being dropped - resultToComponent's Config case previously
+// produced a bare, childless cdx.ComponentTypeFile component with no findings
+// attached at all. It returns the file component's own direct children (to
+// replace the empty componentDependencies a Config result's always-empty
+// Packages produced earlier in parseComponents) alongside components and the
+// updated vulnMap.
+//
+// NOTE: resolving a misconfiguration's legacy (pre-AVD) check ID via a
+// "legacy.IDs" table, and attaching a KICS-style auto-fix diff as a
+// Recommendation, are both out of scope here: neither a legacy-ID lookup
+// table nor fix-diff data exists anywhere in this checkout (the only
+// "legacy" package present is vendor/.../tfsec/internal/pkg/legacy, an
+// unrelated vendored map), so findings are identified by AVDID/ID as-is.
+func (cw *Writer) configComponents(result types.Result, vulnMap map[string]cdx.Vulnerability) ([]cdx.Component, []cdx.Dependency, map[string]cdx.Vulnerability) {
+	var components []cdx.Component
+	var dependencies []cdx.Dependency
+	refByResource := map[string]string{}
+
+	for _, misconf := range result.Misconfigurations {
+		resource := result.Target
+		if len(misconf.CauseMetadata.Occurrences) > 0 {
+			if res := misconf.CauseMetadata.Occurrences[0].Resource; res != "" {
+				resource = res
+			}
+		}
+
+		ref, ok := refByResource[resource]
+		if !ok {
+			ref = cw.newUUID().String()
+			refByResource[resource] = ref
+			components = append(components, cdx.Component{
+				BOMRef: ref,
+				Type:   cdx.ComponentTypeFile,
+				Name:   resource,
+				Properties: &[]cdx.Property{
+					cyclonedx.Property("Resource", resource),
+				},
+			})
+			dependencies = append(dependencies, cdx.Dependency{Ref: ref})
+		}
+
+		id := misconf.AVDID
+		if id == "" {
+			id = misconf.ID
+		}
+		if v, ok := vulnMap[id]; ok {
+			*v.Affects = append(*v.Affects, cyclonedx.Affects(ref, ""))
+			vulnMap[id] = v
+			continue
+		}
+
+		v := cdx.Vulnerability{
+			ID:          id,
+			Source:      &cdx.Source{Name: "trivy-config"},
+			Description: misconf.Description,
+			Affects:     &[]cdx.Affect{cyclonedx.Affects(ref, "")},
+		}
+		if misconf.Severity != "" {
+			v.Ratings = &[]cdx.Rating{
+				{
+					Source:   &cdx.Source{Name: "trivy-config"},
+					Severity: configSeverity(misconf.Severity),
+				},
+			}
+		}
+		if misconf.AVDID != "" {
+			v.Advisories = &[]cdx.Advisory{
+				{URL: fmt.Sprintf("https://avd.aquasec.com/misconfig/%s", strings.ToLower(misconf.AVDID))},
+			}
+		}
+		vulnMap[id] = v
+	}
+
+	return components, dependencies, vulnMap
+}
+
+// configSeverity maps a misconfiguration's dbTypes.Severity string (e.g.
+// "CRITICAL", "HIGH") onto a cdx.Severity value.
+func configSeverity(severity string) cdx.Severity {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return cdx.SeverityCritical
+	case "HIGH":
+		return cdx.SeverityHigh
+	case "MEDIUM":
+		return cdx.SeverityMedium
+	case "LOW":
+		return cdx.SeverityLow
+	default:
+		return cdx.SeverityUnknown
+	}
+}
+
 func (cw Writer) resultToComponent(r types.Result, osFound *ftypes.OS) cdx.Component {
 	component := cdx.Component{
 		Name: r.Target,
@@ -382,7 +903,9 @@ func (cw Writer) resultToComponent(r types.Result, osFound *ftypes.OS) cdx.Compo
 		component.BOMRef = cw.newUUID().String()
 		component.Type = cdx.ComponentTypeApplication
 	case types.ClassConfig:
-		// TODO: Config support
+		// Per-resource child components and findings are attached by
+		// configComponents in parseComponents; this is just the file-level
+		// parent they hang off of.
 		component.BOMRef = cw.newUUID().String()
 		component.Type = cdx.ComponentTypeFile
 	}
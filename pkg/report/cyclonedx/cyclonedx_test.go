@@ -0,0 +1,238 @@
+package cyclonedx
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestVulnerabilityAnalysis(t *testing.T) {
+	tests := []struct {
+		name   string
+		status dbTypes.Status
+		want   *cdx.VulnerabilityAnalysis
+	}{
+		{
+			name:   "not affected",
+			status: dbTypes.StatusNotAffected,
+			want: &cdx.VulnerabilityAnalysis{
+				State:         cdx.IASNotAffected,
+				Justification: cdx.IAJCodeNotReachable,
+			},
+		},
+		{
+			name:   "fixed",
+			status: dbTypes.StatusFixed,
+			want: &cdx.VulnerabilityAnalysis{
+				State:    cdx.IASResolved,
+				Response: &[]cdx.ImpactAnalysisResponse{cdx.IARUpdate},
+			},
+		},
+		{
+			name:   "affected",
+			status: dbTypes.StatusAffected,
+			want: &cdx.VulnerabilityAnalysis{
+				State: cdx.IASExploitable,
+			},
+		},
+		{
+			name:   "unknown status omits analysis",
+			status: dbTypes.StatusUnknown,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, vulnerabilityAnalysis(tt.status))
+		})
+	}
+}
+
+func TestCanonicalCVE(t *testing.T) {
+	tests := []struct {
+		name string
+		vuln types.DetectedVulnerability
+		want string
+	}{
+		{
+			name: "already a CVE",
+			vuln: types.DetectedVulnerability{VulnerabilityID: "CVE-2021-1234"},
+			want: "CVE-2021-1234",
+		},
+		{
+			name: "OS advisory ID with a CVE cross-reference",
+			vuln: types.DetectedVulnerability{
+				VulnerabilityID: "RHSA-2021:1234",
+				References:      []string{"https://access.redhat.com/...", "CVE-2021-5678"},
+			},
+			want: "CVE-2021-5678",
+		},
+		{
+			name: "no CVE known",
+			vuln: types.DetectedVulnerability{
+				VulnerabilityID: "RHSA-2021:1234",
+				References:      []string{"https://access.redhat.com/..."},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, canonicalCVE(tt.vuln))
+		})
+	}
+}
+
+func TestWriter_VulnerabilityKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		orientation    Orientation
+		vuln           types.DetectedVulnerability
+		wantKey        string
+		wantAdvisoryID string
+	}{
+		{
+			name:        "OrientByAdvisory keeps the advisory ID as-is",
+			orientation: OrientByAdvisory,
+			vuln: types.DetectedVulnerability{
+				VulnerabilityID: "RHSA-2021:1234",
+				References:      []string{"CVE-2021-5678"},
+			},
+			wantKey:        "RHSA-2021:1234",
+			wantAdvisoryID: "",
+		},
+		{
+			name:        "OrientByCVE collapses onto the upstream CVE",
+			orientation: OrientByCVE,
+			vuln: types.DetectedVulnerability{
+				VulnerabilityID: "RHSA-2021:1234",
+				References:      []string{"CVE-2021-5678"},
+			},
+			wantKey:        "CVE-2021-5678",
+			wantAdvisoryID: "RHSA-2021:1234",
+		},
+		{
+			name:           "OrientByCVE leaves a bare CVE alone",
+			orientation:    OrientByCVE,
+			vuln:           types.DetectedVulnerability{VulnerabilityID: "CVE-2021-5678"},
+			wantKey:        "CVE-2021-5678",
+			wantAdvisoryID: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := &Writer{options: &options{orientation: tt.orientation}}
+			key, advisoryID := cw.vulnerabilityKey(tt.vuln)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantAdvisoryID, advisoryID)
+		})
+	}
+}
+
+func TestMergeVulnerabilitySources(t *testing.T) {
+	into := cdx.Vulnerability{
+		Ratings: &[]cdx.Rating{
+			{Source: &cdx.Source{Name: "ghsa"}, Severity: cdx.SeverityHigh},
+		},
+		CWEs: (*cdx.CWEs)(&[]int{79}),
+		Advisories: &[]cdx.Advisory{
+			{URL: "https://example.com/ghsa"},
+		},
+	}
+	from := cdx.Vulnerability{
+		Ratings: &[]cdx.Rating{
+			{Source: &cdx.Source{Name: "rhsa"}, Severity: cdx.SeverityCritical},
+			// Re-merging the same source should not duplicate it.
+			{Source: &cdx.Source{Name: "ghsa"}, Severity: cdx.SeverityHigh},
+		},
+		CWEs: (*cdx.CWEs)(&[]int{79, 89}),
+		Advisories: &[]cdx.Advisory{
+			{URL: "https://example.com/rhsa"},
+		},
+	}
+
+	mergeVulnerabilitySources(&into, from)
+
+	assert.Len(t, *into.Ratings, 2)
+	assert.ElementsMatch(t, []int{79, 89}, *into.CWEs)
+	assert.Len(t, *into.Advisories, 2)
+}
+
+func TestAddVulnerabilityReference(t *testing.T) {
+	v := &cdx.Vulnerability{}
+
+	addVulnerabilityReference(v, "")
+	assert.Nil(t, v.References)
+
+	addVulnerabilityReference(v, "RHSA-2021:1234")
+	assert.NotNil(t, v.References)
+	assert.Len(t, *v.References, 1)
+
+	// Adding the same advisory ID again must not duplicate it.
+	addVulnerabilityReference(v, "RHSA-2021:1234")
+	assert.Len(t, *v.References, 1)
+
+	addVulnerabilityReference(v, "GHSA-xxxx")
+	assert.Len(t, *v.References, 2)
+}
+
+func TestSplitLicenses(t *testing.T) {
+	tests := []struct {
+		name    string
+		license string
+		want    []string
+	}{
+		{name: "empty", license: "", want: nil},
+		{name: "single", license: "MIT", want: []string{"MIT"}},
+		{name: "OR expression", license: "MIT OR Apache-2.0", want: []string{"MIT", "Apache-2.0"}},
+		{name: "AND expression", license: "MIT AND Apache-2.0", want: []string{"MIT", "Apache-2.0"}},
+		{name: "parenthesized", license: "(MIT OR Apache-2.0)", want: []string{"MIT", "Apache-2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitLicenses(tt.license))
+		})
+	}
+}
+
+func TestLicenseChoices(t *testing.T) {
+	choices := licenseChoices("MIT OR some-custom-license")
+	if assert.Len(t, choices, 2) {
+		assert.Equal(t, "MIT", choices[0].License.ID)
+		assert.Equal(t, "", choices[0].License.Name)
+		assert.Equal(t, "some-custom-license", choices[1].License.Name)
+		assert.Equal(t, "", choices[1].License.ID)
+	}
+}
+
+func TestConfigSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     cdx.Severity
+	}{
+		{severity: "CRITICAL", want: cdx.SeverityCritical},
+		{severity: "HIGH", want: cdx.SeverityHigh},
+		{severity: "MEDIUM", want: cdx.SeverityMedium},
+		{severity: "LOW", want: cdx.SeverityLow},
+		{severity: "UNKNOWN", want: cdx.SeverityUnknown},
+		{severity: "", want: cdx.SeverityUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			assert.Equal(t, tt.want, configSeverity(tt.severity))
+		})
+	}
+}
+
+func TestIsAggreated(t *testing.T) {
+	assert.True(t, isAggreated(ftypes.NodePkg))
+	assert.True(t, isAggreated(ftypes.PythonPkg))
+	assert.True(t, isAggreated(ftypes.GemSpec))
+	assert.True(t, isAggreated(ftypes.Jar))
+	assert.False(t, isAggreated("dpkg"))
+}
@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aquasecurity/trivy/pkg/sbom/core"
+)
+
+// Writer renders a core.BOMDiff as plain text, listing only the drift
+// between two BOMs of the same subject (e.g. two scans of the same image
+// at different times): added/removed/updated components, relationship
+// changes and the vulnerability delta per component.
+type Writer struct {
+	Output io.Writer
+}
+
+// NewWriter returns a text Writer for the BOM diff
+func NewWriter(output io.Writer) Writer {
+	return Writer{Output: output}
+}
+
+func (w Writer) Write(_ context.Context, diff *core.BOMDiff) error {
+	if len(diff.AddedComponents) == 0 && len(diff.RemovedComponents) == 0 && len(diff.UpdatedComponents) == 0 &&
+		len(diff.AddedRelationships) == 0 && len(diff.RemovedRelationships) == 0 && len(diff.VulnerabilityDiffs) == 0 {
+		_, err := fmt.Fprintln(w.Output, "No drift detected.")
+		return err
+	}
+
+	if err := w.writeComponents(diff); err != nil {
+		return err
+	}
+	if err := w.writeRelationships(diff); err != nil {
+		return err
+	}
+	return w.writeVulnerabilities(diff)
+}
+
+func (w Writer) writeComponents(diff *core.BOMDiff) error {
+	for _, c := range diff.AddedComponents {
+		if _, err := fmt.Fprintf(w.Output, "+ %s@%s\n", c.Name, c.Version); err != nil {
+			return err
+		}
+	}
+	for _, c := range diff.RemovedComponents {
+		if _, err := fmt.Fprintf(w.Output, "- %s@%s\n", c.Name, c.Version); err != nil {
+			return err
+		}
+	}
+	for _, u := range diff.UpdatedComponents {
+		if _, err := fmt.Fprintf(w.Output, "~ %s %s -> %s\n", u.New.Name, u.Old.Version, u.New.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w Writer) writeRelationships(diff *core.BOMDiff) error {
+	for _, e := range diff.AddedRelationships {
+		if _, err := fmt.Fprintf(w.Output, "+ relationship %s --%s--> %s\n", e.Parent, e.Type, e.Child); err != nil {
+			return err
+		}
+	}
+	for _, e := range diff.RemovedRelationships {
+		if _, err := fmt.Fprintf(w.Output, "- relationship %s --%s--> %s\n", e.Parent, e.Type, e.Child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w Writer) writeVulnerabilities(diff *core.BOMDiff) error {
+	for _, vd := range diff.VulnerabilityDiffs {
+		for _, v := range vd.Added {
+			if _, err := fmt.Fprintf(w.Output, "+ vuln %s in %s (%s)\n", v.ID, v.PkgName, v.InstalledVersion); err != nil {
+				return err
+			}
+		}
+		for _, v := range vd.Removed {
+			if _, err := fmt.Fprintf(w.Output, "- vuln %s in %s (%s)\n", v.ID, v.PkgName, v.InstalledVersion); err != nil {
+				return err
+			}
+		}
+		for _, u := range vd.Updated {
+			if _, err := fmt.Fprintf(w.Output, "~ vuln %s in %s: fixed version %s -> %s\n",
+				u.New.ID, u.New.PkgName, u.Old.FixedVersion, u.New.FixedVersion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
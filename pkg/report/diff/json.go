@@ -0,0 +1,32 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/sbom/core"
+)
+
+// JSONWriter renders a core.BOMDiff as JSON, for CI pipelines that gate on
+// specific fields (e.g. VulnerabilityDiffs[*].Added being empty) rather than
+// parsing the text report.
+type JSONWriter struct {
+	Output io.Writer
+}
+
+// NewJSONWriter returns a JSON Writer for the BOM diff
+func NewJSONWriter(output io.Writer) JSONWriter {
+	return JSONWriter{Output: output}
+}
+
+func (w JSONWriter) Write(_ context.Context, diff *core.BOMDiff) error {
+	encoder := json.NewEncoder(w.Output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		return xerrors.Errorf("failed to encode BOM diff: %w", err)
+	}
+	return nil
+}
@@ -1,12 +1,17 @@
 package spdx
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"golang.org/x/xerrors"
 
 	ftypes "github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/purl"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/google/uuid"
 	"github.com/spdx/tools-golang/jsonsaver"
@@ -16,12 +21,21 @@ import (
 )
 
 const (
-	SPDXVersion         = "SPDX-2.1"
+	// DefaultSPDXVersion is used when Writer.version (the "spec version"
+	// NewWriter was given) is empty - a caller only needs to set it to pin an
+	// older version for a consumer that hasn't caught up to 2.3 yet.
+	DefaultSPDXVersion  = "SPDX-2.3"
 	DataLicense         = "CC0-1.0"
 	SPDXIdentifier      = "DOCUMENT"
 	DocumentNamespace   = "http://aquasecurity.github.io/trivy"
 	CreatorOrganization = "aquasecurity"
 	CreatorTool         = "trivy"
+	NOASSERTION         = "NOASSERTION"
+	NONE                = "NONE"
+
+	RelationshipDescribes = "DESCRIBES"
+	RelationshipContains  = "CONTAINS"
+	RelationshipDependsOn = "DEPENDS_ON"
 )
 
 type Writer struct {
@@ -41,7 +55,7 @@ type options struct {
 
 type option func(*options)
 
-type spdxSaveFunction func(*spdx.Document2_2, io.Writer) error
+type spdxSaveFunction func(*spdx.Document2_3, io.Writer) error
 
 func WithClock(clock clock.Clock) option {
 	return func(opts *options) {
@@ -55,6 +69,9 @@ func WithNewUUID(newUUID newUUID) option {
 	}
 }
 
+// NewWriter returns a Writer. version pins the "SPDXVersion" field of the
+// generated document (e.g. "SPDX-2.2") for a consumer that hasn't caught up
+// to DefaultSPDXVersion yet; leave it empty to use the default.
 func NewWriter(output io.Writer, version string, spdxFormat string, opts ...option) Writer {
 	o := &options{
 		format:     spdx.Document2_1{},
@@ -82,9 +99,9 @@ func (cw Writer) Write(report types.Report) error {
 
 	var saveFunc spdxSaveFunction
 	if cw.spdxFormat != "spdx-json" {
-		saveFunc = tvsaver.Save2_2
+		saveFunc = tvsaver.Save2_3
 	} else {
-		saveFunc = jsonsaver.Save2_2
+		saveFunc = jsonsaver.Save2_3
 	}
 
 	if err = saveFunc(spdxDoc, cw.output); err != nil {
@@ -93,8 +110,33 @@ func (cw Writer) Write(report types.Report) error {
 	return nil
 }
 
-func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_2, error) {
-	packages := make(map[spdx.ElementID]*spdx.Package2_2)
+// convertToBom builds the SPDX document for r: a root package derived from
+// r.ArtifactName/ArtifactType, a DESCRIBES relationship from the document to
+// that root, a CONTAINS edge from the root to every scanned package, and a
+// DEPENDS_ON edge for every entry in a package's DependsOn that resolves to
+// another package in the same report.
+func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_3, error) {
+	if version == "" {
+		version = DefaultSPDXVersion
+	}
+
+	rootID := pkgSPDXID(string(r.ArtifactType), r.ArtifactName, "", "")
+
+	packages := map[spdx.ElementID]*spdx.Package2_3{
+		rootID: rootPackage(r, rootID),
+	}
+	relationships := []*spdx.Relationship2_3{
+		{
+			RefA:         spdx.DocElementID{ElementRefID: SPDXIdentifier},
+			RefB:         spdx.DocElementID{ElementRefID: rootID},
+			Relationship: RelationshipDescribes,
+		},
+	}
+
+	// ids maps a package's own manager-assigned ftypes.Package.ID (what
+	// DependsOn entries reference) to the deterministic SPDXID it was given
+	// here, so a DependsOn entry can be resolved into a DEPENDS_ON edge below.
+	ids := make(map[string]spdx.ElementID)
 
 	for _, result := range r.Results {
 		for _, pkg := range result.Packages {
@@ -103,12 +145,39 @@ func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_
 				return nil, xerrors.Errorf("failed to parse pkg: %w", err)
 			}
 			packages[spdxPackage.PackageSPDXIdentifier] = &spdxPackage
+			ids[pkg.ID] = spdxPackage.PackageSPDXIdentifier
+
+			relationships = append(relationships, &spdx.Relationship2_3{
+				RefA:         spdx.DocElementID{ElementRefID: rootID},
+				RefB:         spdx.DocElementID{ElementRefID: spdxPackage.PackageSPDXIdentifier},
+				Relationship: RelationshipContains,
+			})
 		}
 	}
 
-	return &spdx.Document2_2{
-		CreationInfo: &spdx.CreationInfo2_2{
-			SPDXVersion:          SPDXVersion,
+	for _, result := range r.Results {
+		for _, pkg := range result.Packages {
+			from, ok := ids[pkg.ID]
+			if !ok {
+				continue
+			}
+			for _, dep := range pkg.DependsOn {
+				to, ok := ids[dep]
+				if !ok {
+					continue
+				}
+				relationships = append(relationships, &spdx.Relationship2_3{
+					RefA:         spdx.DocElementID{ElementRefID: from},
+					RefB:         spdx.DocElementID{ElementRefID: to},
+					Relationship: RelationshipDependsOn,
+				})
+			}
+		}
+	}
+
+	return &spdx.Document2_3{
+		CreationInfo: &spdx.CreationInfo2_3{
+			SPDXVersion:          version,
 			DataLicense:          DataLicense,
 			SPDXIdentifier:       SPDXIdentifier,
 			DocumentName:         r.ArtifactName,
@@ -117,30 +186,97 @@ func (cw *Writer) convertToBom(r types.Report, version string) (*spdx.Document2_
 			CreatorTools:         []string{CreatorTool},
 			Created:              cw.clock.Now().UTC().Format(time.RFC3339Nano),
 		},
-		Packages: packages,
+		Packages:      packages,
+		Relationships: relationships,
 	}, nil
 }
 
-func pkgToSpdxPackage(t string, meta types.Metadata, pkg ftypes.Package) (spdx.Package2_2, error) {
-	var spdxPackage spdx.Package2_2
+// rootPackage is the synthetic SPDX package DESCRIBES points at, standing in
+// for the scanned artifact itself (the image, filesystem, repo, ...) rather
+// than any one of its packages.
+func rootPackage(r types.Report, rootID spdx.ElementID) *spdx.Package2_3 {
+	return &spdx.Package2_3{
+		PackageSPDXIdentifier:   rootID,
+		PackageName:             r.ArtifactName,
+		PackageDownloadLocation: NOASSERTION,
+		PackageLicenseConcluded: NOASSERTION,
+		PackageLicenseDeclared:  NOASSERTION,
+	}
+}
+
+func pkgToSpdxPackage(t string, meta types.Metadata, pkg ftypes.Package) (spdx.Package2_3, error) {
 	license := getLicense(pkg)
 
-	spdxPackage.PackageSPDXIdentifier = spdx.ElementID(pkg.Name + "-" + pkg.Version)
-	spdxPackage.PackageName = pkg.Name
-	spdxPackage.PackageVersion = pkg.Version
+	spdxPackage := spdx.Package2_3{
+		PackageSPDXIdentifier: pkgSPDXID(t, pkg.Name, pkg.Version, pkg.Arch),
+		PackageName:           pkg.Name,
+		PackageVersion:        pkg.Version,
 
-	// The Declared License is what the authors of a project believe govern the package
-	spdxPackage.PackageLicenseConcluded = license
+		// The Declared License is what the authors of a project believe govern the package
+		PackageLicenseConcluded: license,
 
-	// The Concluded License field is the license the SPDX file creator believes governs the package
-	spdxPackage.PackageLicenseDeclared = license
+		// The Concluded License field is the license the SPDX file creator believes governs the package
+		PackageLicenseDeclared: license,
+	}
+
+	if cs := packageChecksums(pkg); len(cs) > 0 {
+		spdxPackage.PackageChecksums = cs
+	}
+
+	if ref, ok := packageExternalReference(t, meta, pkg); ok {
+		spdxPackage.PackageExternalReferences = []*spdx.PackageExternalReference2_3{ref}
+	}
 
 	return spdxPackage, nil
 }
 
+// packageChecksums converts pkg.Digest, when set, into the single-entry
+// PackageChecksums SPDX expects. digest.Digest stringifies as
+// "algorithm:hex", the same convention as an OCI/Docker content digest.
+func packageChecksums(pkg ftypes.Package) []spdx.Checksum {
+	if pkg.Digest == "" {
+		return nil
+	}
+	alg, hex, ok := strings.Cut(string(pkg.Digest), ":")
+	if !ok {
+		return nil
+	}
+	return []spdx.Checksum{
+		{
+			Algorithm: spdx.ChecksumAlgorithm(strings.ToUpper(alg)),
+			Value:     hex,
+		},
+	}
+}
+
+// packageExternalReference builds the PURL ExternalRef identifying pkg
+// within its ecosystem (t, e.g. "npm"/"gomod"/"dpkg"), the same
+// purl.NewPackageURL construction pkg/report/cyclonedx.Writer already uses
+// for its own PackageURL component field.
+func packageExternalReference(t string, meta types.Metadata, pkg ftypes.Package) (*spdx.PackageExternalReference2_3, bool) {
+	pu, err := purl.NewPackageURL(t, meta, pkg)
+	if err != nil || pu.ToString() == "" {
+		return nil, false
+	}
+	return &spdx.PackageExternalReference2_3{
+		Category: "PACKAGE-MANAGER",
+		RefType:  "purl",
+		Locator:  pu.ToString(),
+	}, true
+}
+
+// pkgSPDXID renders a deterministic SPDXID so re-scanning an unchanged
+// artifact produces the same IDs, and therefore a stable diff, every time -
+// unlike the previous Name+"-"+Version scheme, which collided across
+// ecosystems (e.g. a Go module and an OS package sharing a name@version).
+func pkgSPDXID(t, name, version, arch string) spdx.ElementID {
+	sum := sha1.Sum([]byte(t + "|" + name + "|" + version + "|" + arch))
+	return spdx.ElementID(fmt.Sprintf("Package-%s", hex.EncodeToString(sum[:])))
+}
+
 func getLicense(p ftypes.Package) string {
 	if p.License == "" {
-		return "NONE"
+		return NONE
 	}
 
 	return p.License
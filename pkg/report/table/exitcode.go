@@ -0,0 +1,55 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// resolveExitCode walks every finding in report, matching each one against
+// tw.ExitCodes by an exact "check.severity" key and that check's "check.any"
+// key, and returns the highest code any match produced. Several categories
+// can trip at once (a critical vuln alongside a high misconfiguration, say);
+// the highest code wins, on the assumption that a caller assigns higher
+// exit codes to the categories it wants a CI pipeline to react to most
+// urgently. Ties are broken by key so the result is deterministic across
+// runs. A nil return means nothing in report matched any configured key.
+func (tw Writer) resolveExitCode(report types.Report) *types.ExitError {
+	var bestCode int
+	var bestKey string
+	matched := false
+
+	match := func(key string) {
+		code, ok := tw.ExitCodes[key]
+		if !ok {
+			return
+		}
+		if !matched || code > bestCode || (code == bestCode && key < bestKey) {
+			bestCode, bestKey, matched = code, key, true
+		}
+	}
+
+	consider := func(check types.SecurityCheck, severity string) {
+		match(string(check) + "." + strings.ToLower(severity))
+		match(string(check) + ".any")
+	}
+
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			consider(types.SecurityCheckVulnerability, v.Severity)
+		}
+		for _, m := range result.Misconfigurations {
+			if !m.CheckPass() {
+				consider(types.SecurityCheckConfig, m.Severity)
+			}
+		}
+		for _, s := range result.Secrets {
+			consider(types.SecurityCheckSecret, s.Severity)
+		}
+	}
+
+	if !matched {
+		return nil
+	}
+	return &types.ExitError{Code: bestCode}
+}
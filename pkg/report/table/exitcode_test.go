@@ -0,0 +1,78 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_resolveExitCode(t *testing.T) {
+	report := types.Report{
+		Results: types.Results{
+			{
+				Vulnerabilities: []types.DetectedVulnerability{
+					{VulnerabilityID: "CVE-2021-1", Severity: "CRITICAL"},
+				},
+				Misconfigurations: []types.DetectedMisconfiguration{
+					{ID: "AVD-1", Severity: "HIGH", Status: types.StatusFailure},
+					{ID: "AVD-2", Severity: "CRITICAL", Status: types.StatusPassed}, // passing check, must not trip
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		exitCodes types.ExitCodePolicy
+		wantCode  *int
+	}{
+		{
+			name:      "no policy configured",
+			exitCodes: nil,
+		},
+		{
+			name:      "single category matches",
+			exitCodes: types.ExitCodePolicy{"vuln.critical": 2},
+			wantCode:  intPtr(2),
+		},
+		{
+			name: "several categories trip at once, highest code wins",
+			exitCodes: types.ExitCodePolicy{
+				"vuln.critical": 2,
+				"config.high":   5,
+			},
+			wantCode: intPtr(5),
+		},
+		{
+			name:      "passing check is never matched, even with a wildcard policy",
+			exitCodes: types.ExitCodePolicy{"config.critical": 9},
+		},
+		{
+			name:      "check.any matches when the specific severity isn't configured",
+			exitCodes: types.ExitCodePolicy{"vuln.any": 7},
+			wantCode:  intPtr(7),
+		},
+		{
+			name:      "unrelated category configured, nothing matches",
+			exitCodes: types.ExitCodePolicy{"secret.any": 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Writer{ExitCodes: tt.exitCodes}
+			got := w.resolveExitCode(report)
+			if tt.wantCode == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, *tt.wantCode, got.Code)
+		})
+	}
+}
+
+func intPtr(i int) *int { return &i }
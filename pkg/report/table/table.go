@@ -41,6 +41,13 @@ type Writer struct {
 	// Show suppressed findings
 	ShowSuppressed bool
 
+	// IgnoreStatuses is the set of vulnerability statuses passed to
+	// --ignore-status. Its only effect on rendering (filtering itself happens
+	// in result.Client.Filter before Write is called) is that renderSummary
+	// adds a per-status breakdown table once it's non-empty, so a user who
+	// asked to filter by status can see how the remaining findings break down.
+	IgnoreStatuses []dbTypes.Status
+
 	// Hide summary table
 	NoSummary bool
 
@@ -51,6 +58,10 @@ type Writer struct {
 	// For licenses
 	LicenseRiskThreshold int
 	IgnoredLicenses      []string
+
+	// ExitCodes maps a "check.severity"/"check.any" key to the process exit
+	// code a matching finding should produce; see resolveExitCode.
+	ExitCodes types.ExitCodePolicy
 }
 
 type Renderer interface {
@@ -76,6 +87,10 @@ func (tw Writer) Write(_ context.Context, report types.Report) error {
 		}
 		tw.write(result)
 	}
+
+	if exitErr := tw.resolveExitCode(report); exitErr != nil {
+		return exitErr
+	}
 	return nil
 }
 
@@ -136,6 +151,13 @@ func (tw Writer) renderSummary(report types.Report) error {
 		t.AddRows(rows)
 	}
 	t.Render()
+
+	if len(tw.IgnoreStatuses) > 0 {
+		if err := tw.renderStatusSummary(report); err != nil {
+			return xerrors.Errorf("failed to render status summary: %w", err)
+		}
+	}
+
 	return nil
 }
 
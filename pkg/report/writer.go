@@ -0,0 +1,117 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/report/table"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Supported output formats.
+const (
+	FormatTable     = "table"
+	FormatJSON      = "json"
+	FormatTemplate  = "template"
+	FormatSarif     = "sarif"
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+	FormatSPDXJSON  = "spdx-json"
+	FormatGitHub    = "github"
+)
+
+// SupportedSBOMFormats lists the formats that describe a software bill of
+// materials rather than a list of findings; scanning for one of them implies
+// every package must be reported, not only the vulnerable ones.
+var SupportedSBOMFormats = []string{FormatCycloneDX, FormatSPDX, FormatSPDXJSON}
+
+// Target is a single destination a scan's results are rendered to: one
+// format, written to one io.Writer. Option.Targets holds one per repeatable
+// `-o format=path` flag, so a single scan can fan out to several formats and
+// destinations without re-scanning.
+type Target struct {
+	Format   string
+	Template string
+	Writer   io.Writer
+}
+
+// Option configures Write.
+type Option struct {
+	Targets []Target
+
+	Severities     []dbTypes.Severity
+	IgnoreStatuses []dbTypes.Status
+	Tree           bool
+	ShowSuppressed bool
+
+	// ExitCodes maps a "check.severity"/"check.any" key to the process exit
+	// code a matching finding should produce; only consulted by the table
+	// format. See table.Writer.resolveExitCode for the precedence rule.
+	ExitCodes types.ExitCodePolicy
+}
+
+// Write renders report to every target in option.Targets, instantiating the
+// renderer each target's format calls for. All targets share the same
+// report, so a single scan can fan out to several formats and destinations
+// without re-scanning.
+//
+// A target signaling its exit-code policy tripped (a *types.ExitError, not
+// an actual failure) doesn't abort the fan-out: every target still gets
+// rendered, and the highest-numbered ExitError across all of them is
+// returned last, via errors.As, so a findings-threshold hit on an earlier
+// target can't suppress a later target's output.
+func Write(ctx context.Context, report types.Report, option Option) error {
+	var exitErr *types.ExitError
+	for _, target := range option.Targets {
+		err := writeTarget(ctx, report, option, target)
+
+		var targetExitErr *types.ExitError
+		if errors.As(err, &targetExitErr) {
+			if exitErr == nil || targetExitErr.Code > exitErr.Code {
+				exitErr = targetExitErr
+			}
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("%s report error: %w", target.Format, err)
+		}
+	}
+	if exitErr != nil {
+		return exitErr
+	}
+	return nil
+}
+
+func writeTarget(ctx context.Context, report types.Report, option Option, target Target) error {
+	switch target.Format {
+	case FormatTable:
+		writer := table.Writer{
+			Severities:     option.Severities,
+			IgnoreStatuses: option.IgnoreStatuses,
+			Output:         target.Writer,
+			Tree:           option.Tree,
+			ExitCodes:      option.ExitCodes,
+		}
+		return writer.Write(ctx, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(target.Writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	default:
+		// NOTE: cyclonedx/spdx/sarif/template/github aren't wired in here.
+		// pkg/report/cyclonedx.go and pkg/report/{cyclonedx,spdx}/*.go each
+		// expect a richer (and mutually inconsistent) report shape than
+		// types.Report - see the NOTE on types.Report in pkg/types/report.go
+		// - and no SARIF/template/GitHub writer for types.Report exists in
+		// this checkout to call. Routing a target through them is left for
+		// when those writers are available to reconcile against; in the
+		// meantime an unsupported target fails loudly rather than silently
+		// dropping output.
+		return xerrors.Errorf("unsupported format %q in this checkout", target.Format)
+	}
+}
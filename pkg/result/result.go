@@ -5,11 +5,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/wire"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/samber/lo"
 	"golang.org/x/xerrors"
 
 	fos "github.com/aquasecurity/fanal/analyzer/os"
@@ -18,9 +21,9 @@ import (
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/detector/library/reachability"
 	"github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/types"
-	"github.com/aquasecurity/trivy/pkg/utils"
 )
 
 const (
@@ -125,9 +128,50 @@ func (c Client) FillVulnerabilityInfo(vulns []types.DetectedVulnerability, repor
 		vulns[i].SeveritySource = severitySource
 		vulns[i].PrimaryURL = c.getPrimaryURL(vulnID, vuln.References, sources)
 		vulns[i].Vulnerability.VendorSeverity = nil // Remove VendorSeverity from Results
+
+		// GHSA/RUSTSEC/OSV advisories commonly reference the CVE they were
+		// assigned from. Surface that CVE alongside the native ID so results
+		// from different sources for the same underlying vulnerability can be
+		// grouped/deduplicated by a single, well-known identifier.
+		vulns[i].CVEID = resolveCVEAlias(vulnID, vuln.References)
 	}
 }
 
+// cveAliasPrefixes lists the non-CVE ID namespaces this normalization applies to.
+var cveAliasPrefixes = []string{"GHSA-", "RUSTSEC-", "OSV-"}
+
+// resolveCVEAlias returns the CVE ID embedded in refs for a GHSA/RUSTSEC/OSV
+// vulnerability ID, or an empty string if id is already a CVE or no CVE
+// reference is found.
+func resolveCVEAlias(id string, refs []string) string {
+	if strings.HasPrefix(id, "CVE-") {
+		return ""
+	}
+
+	isAliasable := false
+	for _, prefix := range cveAliasPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			isAliasable = true
+			break
+		}
+	}
+	if !isAliasable {
+		return ""
+	}
+
+	for _, ref := range refs {
+		if idx := strings.Index(ref, "CVE-"); idx != -1 {
+			cve := ref[idx:]
+			// Trim anything after the ID itself, e.g. a trailing URL path segment or query string.
+			if end := strings.IndexAny(cve, "/?#"); end != -1 {
+				cve = cve[:end]
+			}
+			return cve
+		}
+	}
+	return ""
+}
+
 func (c Client) detectSource(reportType string) []dbTypes.SourceID {
 	// OS packages
 	if src, ok := osSources[reportType]; ok {
@@ -203,16 +247,18 @@ func (c Client) getPrimaryURL(vulnID string, refs []string, sources []dbTypes.So
 
 // Filter filter out the vulnerabilities
 func (c Client) Filter(ctx context.Context, vulns []types.DetectedVulnerability, misconfs []types.DetectedMisconfiguration,
-	severities []dbTypes.Severity, ignoreUnfixed, includeNonFailures bool, ignoreFile, policyFile string) (
+	severities []dbTypes.Severity, ignoreStatuses []dbTypes.Status, ignoreUnfixed, includeNonFailures bool, ignoreFile, policyFile string) (
 	[]types.DetectedVulnerability, *report.MisconfSummary, []types.DetectedMisconfiguration, error) {
-	ignoredIDs := getIgnoredIDs(ignoreFile)
+	ignoreEntries := getIgnoreEntries(ignoreFile)
 
-	filteredVulns := filterVulnerabilities(vulns, severities, ignoreUnfixed, ignoredIDs)
-	misconfSummary, filteredMisconfs := filterMisconfigurations(misconfs, severities, includeNonFailures, ignoredIDs)
+	filteredVulns := filterVulnerabilities(vulns, severities, ignoreStatuses, ignoreUnfixed, ignoreEntries)
+	misconfSummary, filteredMisconfs := filterMisconfigurations(misconfs, severities, includeNonFailures, ignoreEntries)
 
-	if policyFile != "" {
+	// Multiple policies can be chained with a comma, e.g. "org.rego,team.rego",
+	// and are evaluated in order as a pipeline.
+	if policyFiles := splitPolicyFiles(policyFile); len(policyFiles) > 0 {
 		var err error
-		filteredVulns, filteredMisconfs, err = applyPolicy(ctx, filteredVulns, filteredMisconfs, policyFile)
+		filteredVulns, filteredMisconfs, err = applyPolicy(ctx, filteredVulns, filteredMisconfs, policyFiles)
 		if err != nil {
 			return nil, nil, nil, xerrors.Errorf("failed to apply the policy: %w", err)
 		}
@@ -223,7 +269,7 @@ func (c Client) Filter(ctx context.Context, vulns []types.DetectedVulnerability,
 }
 
 func filterVulnerabilities(vulns []types.DetectedVulnerability, severities []dbTypes.Severity,
-	ignoreUnfixed bool, ignoredIDs []string) []types.DetectedVulnerability {
+	ignoreStatuses []dbTypes.Status, ignoreUnfixed bool, ignoreEntries []ignoreEntry) []types.DetectedVulnerability {
 	uniqVulns := make(map[string]types.DetectedVulnerability)
 	for _, vuln := range vulns {
 		if vuln.Severity == "" {
@@ -235,10 +281,16 @@ func filterVulnerabilities(vulns []types.DetectedVulnerability, severities []dbT
 				continue
 			}
 
+			// Ignore vulnerabilities with a status the user doesn't care about,
+			// e.g. "will_not_fix" or "end_of_life" where no patched version will ever ship.
+			if statusInSlice(vuln.Status, ignoreStatuses) {
+				continue
+			}
+
 			// Ignore unfixed vulnerabilities
 			if ignoreUnfixed && vuln.FixedVersion == "" {
 				continue
-			} else if utils.StringInSlice(vuln.VulnerabilityID, ignoredIDs) {
+			} else if isIgnored(ignoreEntries, vuln.VulnerabilityID, vuln.PkgPath) {
 				continue
 			}
 
@@ -254,8 +306,13 @@ func filterVulnerabilities(vulns []types.DetectedVulnerability, severities []dbT
 	return toSlice(uniqVulns)
 }
 
+// filterMisconfigurations filters misconfs down to the given severities,
+// dropping any ignoreEntries match. Misconfigurations carry no path
+// equivalent to a vulnerability's PkgPath, so isIgnored is always called
+// here with an empty filePath: a .trivyignore entry using "path:" scoping
+// can never match a misconfiguration, only a vulnerability.
 func filterMisconfigurations(misconfs []types.DetectedMisconfiguration, severities []dbTypes.Severity,
-	includeNonFailures bool, ignoredIDs []string) (*report.MisconfSummary, []types.DetectedMisconfiguration) {
+	includeNonFailures bool, ignoreEntries []ignoreEntry) (*report.MisconfSummary, []types.DetectedMisconfiguration) {
 	var filtered []types.DetectedMisconfiguration
 	summary := new(report.MisconfSummary)
 
@@ -263,7 +320,7 @@ func filterMisconfigurations(misconfs []types.DetectedMisconfiguration, severiti
 		// Filter misconfigurations by severity
 		for _, s := range severities {
 			if s.String() == misconf.Severity {
-				if utils.StringInSlice(misconf.ID, ignoredIDs) {
+				if isIgnored(ignoreEntries, misconf.ID, "") {
 					continue
 				}
 
@@ -307,26 +364,34 @@ func toSlice(uniqVulns map[string]types.DetectedVulnerability) []types.DetectedV
 	return vulnerabilities
 }
 
+// applyPolicy runs every policy file in policyFiles against each finding, in
+// order, dropping it as soon as any policy ignores it. Running a pipeline of
+// policies (rather than a single one) lets teams compose e.g. a shared
+// org-wide policy with a per-repo one without merging them into one file.
 func applyPolicy(ctx context.Context, vulns []types.DetectedVulnerability, misconfs []types.DetectedMisconfiguration,
-	policyFile string) ([]types.DetectedVulnerability, []types.DetectedMisconfiguration, error) {
-	policy, err := os.ReadFile(policyFile)
-	if err != nil {
-		return nil, nil, xerrors.Errorf("unable to read the policy file: %w", err)
-	}
+	policyFiles []string) ([]types.DetectedVulnerability, []types.DetectedMisconfiguration, error) {
+	queries := make([]rego.PreparedEvalQuery, 0, len(policyFiles))
+	for _, policyFile := range policyFiles {
+		policy, err := os.ReadFile(policyFile)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("unable to read the policy file: %w", err)
+		}
 
-	query, err := rego.New(
-		rego.Query("data.trivy.ignore"),
-		rego.Module("lib.rego", module),
-		rego.Module("trivy.rego", string(policy)),
-	).PrepareForEval(ctx)
-	if err != nil {
-		return nil, nil, xerrors.Errorf("unable to prepare for eval: %w", err)
+		query, err := rego.New(
+			rego.Query("data.trivy.ignore"),
+			rego.Module("lib.rego", module),
+			rego.Module("trivy.rego", string(policy)),
+		).PrepareForEval(ctx)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("unable to prepare for eval: %w", err)
+		}
+		queries = append(queries, query)
 	}
 
 	// Vulnerabilities
 	var filteredVulns []types.DetectedVulnerability
 	for _, vuln := range vulns {
-		ignored, err := evaluate(ctx, query, vuln)
+		ignored, err := evaluateAll(ctx, queries, vuln)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -339,7 +404,7 @@ func applyPolicy(ctx context.Context, vulns []types.DetectedVulnerability, misco
 	// Misconfigurations
 	var filteredMisconfs []types.DetectedMisconfiguration
 	for _, misconf := range misconfs {
-		ignored, err := evaluate(ctx, query, misconf)
+		ignored, err := evaluateAll(ctx, queries, misconf)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -350,6 +415,25 @@ func applyPolicy(ctx context.Context, vulns []types.DetectedVulnerability, misco
 	}
 	return filteredVulns, filteredMisconfs, nil
 }
+
+// evaluateAll runs input through every query in the pipeline, short-circuiting
+// as soon as one of them decides to ignore it.
+func evaluateAll(ctx context.Context, queries []rego.PreparedEvalQuery, input interface{}) (bool, error) {
+	for _, query := range queries {
+		ignored, err := evaluate(ctx, query, input)
+		if err != nil {
+			return false, err
+		}
+		if ignored {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+// evaluate interprets the policy's verdict for "data.trivy.ignore". Besides a
+// plain boolean, a policy may return an object such as
+// `{"ignore": true, "reason": "accepted risk, see JIRA-123"}`, which lets a
+// policy explain itself in the logs instead of just emitting true/false.
 func evaluate(ctx context.Context, query rego.PreparedEvalQuery, input interface{}) (bool, error) {
 	results, err := query.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
@@ -358,15 +442,68 @@ func evaluate(ctx context.Context, query rego.PreparedEvalQuery, input interface
 		// Handle undefined result.
 		return false, nil
 	}
-	ignore, ok := results[0].Expressions[0].Value.(bool)
-	if !ok {
+
+	switch verdict := results[0].Expressions[0].Value.(type) {
+	case bool:
+		return verdict, nil
+	case map[string]interface{}:
+		ignore, _ := verdict["ignore"].(bool)
+		if ignore {
+			if reason, ok := verdict["reason"].(string); ok && reason != "" {
+				log.Logger.Debugf("Policy ignored a finding: %s", reason)
+			}
+		}
+		return ignore, nil
+	default:
 		// Handle unexpected result type.
-		return false, xerrors.New("the policy must return boolean")
+		return false, xerrors.New("the policy must return a boolean or an object with an \"ignore\" boolean field")
+	}
+}
+
+// ignoreEntry is a single parsed line of a .trivyignore file. Besides the bare
+// vulnerability/misconfiguration ID, an entry may carry:
+//   - an expiry date ("exp:2024-01-01"), after which the entry is no longer honored
+//   - a path scope ("path:app1/package-lock.json"), restricting the ignore to
+//     findings under that path instead of applying it repo-wide
+type ignoreEntry struct {
+	ID     string
+	Expiry time.Time // zero value means "never expires"
+	Paths  []string  // empty means "applies to every path"
+}
+
+const ignoreDateFormat = "2006-01-02"
+
+// Match reports whether this entry ignores a finding with the given ID at filePath.
+func (e ignoreEntry) Match(id, filePath string) bool {
+	if e.ID != id {
+		return false
+	}
+	if !e.Expiry.IsZero() && !e.Expiry.After(time.Now()) {
+		return false
 	}
-	return ignore, nil
+	if len(e.Paths) == 0 {
+		return true
+	}
+	for _, p := range e.Paths {
+		if match, _ := path.Match(p, filePath); match || p == filePath {
+			return true
+		}
+	}
+	return false
 }
 
-func getIgnoredIDs(ignoreFile string) []string {
+// isIgnored reports whether id (and, when scoped, filePath) is covered by any
+// non-expired entry.
+func isIgnored(entries []ignoreEntry, id, filePath string) bool {
+	for _, e := range entries {
+		if e.Match(id, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func getIgnoreEntries(ignoreFile string) []ignoreEntry {
 	f, err := os.Open(ignoreFile)
 	if err != nil {
 		// trivy must work even if no .trivyignore exist
@@ -374,20 +511,85 @@ func getIgnoredIDs(ignoreFile string) []string {
 	}
 	log.Logger.Debugf("Found an ignore file %s", ignoreFile)
 
-	var ignoredIDs []string
+	var entries []ignoreEntry
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
+		line := strings.TrimSpace(scanner.Text())
 		if strings.HasPrefix(line, "#") || line == "" {
 			continue
 		}
-		ignoredIDs = append(ignoredIDs, line)
+
+		fields := strings.Fields(line)
+		entry := ignoreEntry{ID: fields[0]}
+		for _, field := range fields[1:] {
+			switch {
+			case strings.HasPrefix(field, "exp:"):
+				expiry, err := time.Parse(ignoreDateFormat, strings.TrimPrefix(field, "exp:"))
+				if err != nil {
+					log.Logger.Warnf("Invalid expiry date in %q: %s", line, err)
+					continue
+				}
+				entry.Expiry = expiry
+			case strings.HasPrefix(field, "path:"):
+				entry.Paths = append(entry.Paths, strings.TrimPrefix(field, "path:"))
+			}
+		}
+		entries = append(entries, entry)
 	}
 
-	log.Logger.Debugf("These IDs will be ignored: %q", ignoredIDs)
+	log.Logger.Debugf("These IDs will be ignored: %q", getIgnoredIDsFromEntries(entries))
+
+	return entries
+}
 
-	return ignoredIDs
+func getIgnoredIDsFromEntries(entries []ignoreEntry) []string {
+	return lo.Map(entries, func(e ignoreEntry, _ int) string {
+		return e.ID
+	})
+}
+
+// FilterUnreachable drops Go binary/module vulnerabilities whose vulnerable
+// symbols aren't present in the scanned binary at binaryPath, reusing the
+// vulnerable-symbol list each Go advisory carries (dbTypes.Vulnerability's
+// per-package "VulnerableSymbols" for the GoVulnDB source). Non-Go findings
+// are passed through unchanged. Called separately from Filter since it needs
+// the binary's path on disk rather than just the in-memory results.
+func FilterUnreachable(vulns []types.DetectedVulnerability, binaryPath string) []types.DetectedVulnerability {
+	syms, err := reachability.ExtractSymbols(binaryPath)
+	if err != nil {
+		log.Logger.Debugf("Reachability analysis skipped for %s: %s", binaryPath, err)
+		return vulns
+	}
+
+	var filtered []types.DetectedVulnerability
+	for _, vuln := range vulns {
+		if reachability.IsReachable(syms, vuln.VulnerableSymbols) {
+			filtered = append(filtered, vuln)
+		}
+	}
+	return filtered
+}
+
+func splitPolicyFiles(policyFile string) []string {
+	if policyFile == "" {
+		return nil
+	}
+	var files []string
+	for _, f := range strings.Split(policyFile, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func statusInSlice(status dbTypes.Status, statuses []dbTypes.Status) bool {
+	for _, s := range statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
 }
 
 func shouldOverwrite(old, new types.DetectedVulnerability) bool {
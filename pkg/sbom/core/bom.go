@@ -6,6 +6,7 @@ import (
 	dtypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/digest"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/sbom/vex"
 	"github.com/aquasecurity/trivy/pkg/uuid"
 )
 
@@ -320,6 +321,109 @@ func (b *BOM) Parents() map[uuid.UUID][]uuid.UUID {
 	return b.parents
 }
 
+// ApplyVEX applies each of the given OpenVEX statements to the BOM,
+// resolving every Product/Subcomponent it references to a component
+// anywhere in the graph - by PURL via b.purls, falling back to BOMRef/UUID
+// via b.components - not just the root. For a resolved component, a
+// statement whose Status suppresses (not_affected/fixed) removes the
+// matching Vulnerability from b.vulnerabilities; any other status leaves
+// the vulnerability in place for the caller to annotate. One FilterResult
+// is returned per (statement, resolved component) pair; a statement whose
+// product can't be resolved in this BOM produces no result.
+func (b *BOM) ApplyVEX(statements []vex.Statement) []vex.FilterResult {
+	var results []vex.FilterResult
+
+	for _, stmt := range statements {
+		for _, product := range stmt.Products {
+			ids := b.resolveVEXProduct(product)
+			for _, id := range ids {
+				if b.applyVEXStatement(id, stmt) {
+					results = append(results, vex.FilterResult{
+						VulnerabilityID: stmt.Vulnerability,
+						ComponentID:     id.String(),
+						Status:          stmt.Status,
+						Suppressed:      stmt.Status.Suppresses(),
+					})
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// resolveVEXProduct resolves product, and any of its Subcomponents, to the
+// component IDs they refer to in this BOM. A product/subcomponent with no
+// Subcomponents resolves to just itself; one that does resolves to each
+// subcomponent instead, mirroring OpenVEX's convention that a statement
+// naming subcomponents is scoped to those subcomponents, not the product
+// as a whole.
+func (b *BOM) resolveVEXProduct(product vex.Product) []uuid.UUID {
+	if len(product.Subcomponents) == 0 {
+		if id, ok := b.resolveComponentID(product.ID); ok {
+			return []uuid.UUID{id}
+		}
+		return nil
+	}
+
+	var ids []uuid.UUID
+	for _, sub := range product.Subcomponents {
+		if id, ok := b.resolveComponentID(sub.ID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// resolveComponentID resolves identifier - a PURL, a BOMRef, or a
+// component's UUID string - to a component ID present in this BOM's
+// relationship graph. PURLs are tried first since they're the stable,
+// cross-scan identifier a VEX document is most likely to use.
+func (b *BOM) resolveComponentID(identifier string) (uuid.UUID, bool) {
+	if ids, ok := b.purls[identifier]; ok && len(ids) > 0 {
+		return ids[0], true
+	}
+	for id, c := range b.components {
+		if c.PkgIdentifier.BOMRef == identifier {
+			return id, true
+		}
+	}
+	if id, err := uuid.Parse(identifier); err == nil {
+		if _, ok := b.components[id]; ok {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// applyVEXStatement applies stmt to the component identified by id,
+// reporting whether that component was actually affected (i.e. whether it
+// exists in the BOM at all). A suppressing status drops the matching
+// vulnerability from b.vulnerabilities[id]; any other status is a no-op
+// beyond being reported in the returned FilterResult.
+func (b *BOM) applyVEXStatement(id uuid.UUID, stmt vex.Statement) bool {
+	if _, ok := b.components[id]; !ok {
+		return false
+	}
+	if !stmt.Status.Suppresses() {
+		return true
+	}
+
+	vulns := b.vulnerabilities[id]
+	kept := vulns[:0]
+	for _, v := range vulns {
+		if v.ID != stmt.Vulnerability {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		delete(b.vulnerabilities, id)
+	} else {
+		b.vulnerabilities[id] = kept
+	}
+	return true
+}
+
 // bomRef returns BOMRef for CycloneDX
 // When multiple lock files have the same dependency with the same name and version, PURL in the BOM can conflict.
 // In that case, PURL cannot be used as a unique identifier, and UUIDv4 be used for BOMRef.
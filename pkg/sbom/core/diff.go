@@ -0,0 +1,326 @@
+package core
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+
+	"github.com/aquasecurity/trivy/pkg/uuid"
+)
+
+// componentDiffNamespace is a fixed namespace used to derive a stable
+// UUIDv5 key for components that have no unique PURL, so the same
+// component is matched across two BOMs even though its internal UUIDv4
+// id differs between scans.
+var componentDiffNamespace = [16]byte{
+	0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1,
+	0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8,
+}
+
+// ComponentKey is a identifier for a Component that is stable across two
+// BOMs of the same subject taken at different times, used to match
+// components when diffing. It prefers the component's PURL, since that is
+// the most specific cross-scan identifier, but falls back to a UUIDv5 of
+// (Type, Group, Name, Version, SrcFile) for components without a PURL
+// that is unique within the BOM (e.g. OS packages with no PURL, or a
+// PURL shared by multiple components).
+type ComponentKey string
+
+func keyForComponent(c *Component, purlCount map[string]int) ComponentKey {
+	if c.PkgIdentifier.PURL != nil {
+		purl := c.PkgIdentifier.PURL.String()
+		if purlCount[purl] == 1 {
+			return ComponentKey(purl)
+		}
+	}
+	name := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", c.Type, c.Group, c.Name, c.Version, c.SrcFile)
+	return ComponentKey(uuidv5(componentDiffNamespace, name))
+}
+
+// uuidv5 computes an RFC 4122 version 5 (SHA-1 based) UUID string for name
+// within namespace.
+func uuidv5(namespace [16]byte, name string) string {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	sum := h.Sum(nil)
+
+	var b [16]byte
+	copy(b[:], sum[:16])
+	b[6] = (b[6] & 0x0f) | 0x50 // version 5
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// componentKeys computes a ComponentKey for every component in components,
+// resolving PURL uniqueness within that single set first.
+func componentKeys(components map[uuid.UUID]*Component) map[uuid.UUID]ComponentKey {
+	purlCount := make(map[string]int)
+	for _, c := range components {
+		if c.PkgIdentifier.PURL != nil {
+			purlCount[c.PkgIdentifier.PURL.String()]++
+		}
+	}
+
+	keys := make(map[uuid.UUID]ComponentKey, len(components))
+	for id, c := range components {
+		keys[id] = keyForComponent(c, purlCount)
+	}
+	return keys
+}
+
+// ComponentUpdate represents a component whose key is unchanged between
+// two BOMs but whose contents (e.g. Version, Licenses) differ.
+type ComponentUpdate struct {
+	Key ComponentKey
+	Old *Component
+	New *Component
+}
+
+// RelationshipEdge is a canonicalized, BOM-independent representation of a
+// Relationship: (parent, child, type) identified by ComponentKey rather
+// than the BOM-local UUID, so it can be compared across two BOMs.
+type RelationshipEdge struct {
+	Parent ComponentKey
+	Child  ComponentKey
+	Type   RelationshipType
+}
+
+// VulnerabilityDiff is the set of vulnerability changes for a single
+// component, keyed by (ID, PkgName, InstalledVersion) so that a
+// FixedVersion bump is reported as Updated rather than Removed+Added.
+type VulnerabilityDiff struct {
+	Component ComponentKey
+	Added     []Vulnerability
+	Removed   []Vulnerability
+	Updated   []VulnerabilityUpdate
+}
+
+// VulnerabilityUpdate pairs the old and new state of a vulnerability whose
+// identity tuple is unchanged but whose FixedVersion (or other metadata)
+// differs, e.g. a fix becoming available for a previously unfixed CVE.
+type VulnerabilityUpdate struct {
+	Old Vulnerability
+	New Vulnerability
+}
+
+// BOMDiff is the structured drift between two BOMs of the same subject
+// (e.g. two scans of the same image at different points in time).
+type BOMDiff struct {
+	AddedComponents   []*Component
+	RemovedComponents []*Component
+	UpdatedComponents []ComponentUpdate
+
+	AddedRelationships   []RelationshipEdge
+	RemovedRelationships []RelationshipEdge
+
+	VulnerabilityDiffs []VulnerabilityDiff
+}
+
+// Diff compares old against new, both BOMs of the same subject taken at
+// different times, and returns the components, relationships and
+// vulnerabilities that were added, removed or updated. It is the basis
+// for "no new vulnerabilities" CI gating: callers typically only care
+// about VulnerabilityDiffs[*].Added being empty.
+func Diff(old, new *BOM) *BOMDiff {
+	oldKeys := componentKeys(old.Components())
+	newKeys := componentKeys(new.Components())
+
+	diff := &BOMDiff{}
+	diff.diffComponents(old, new, oldKeys, newKeys)
+	diff.diffRelationships(old, new, oldKeys, newKeys)
+	diff.diffVulnerabilities(old, new, oldKeys, newKeys)
+	return diff
+}
+
+func (d *BOMDiff) diffComponents(old, new *BOM, oldKeys, newKeys map[uuid.UUID]ComponentKey) {
+	oldByKey := make(map[ComponentKey]*Component, len(oldKeys))
+	for id, key := range oldKeys {
+		oldByKey[key] = old.components[id]
+	}
+	newByKey := make(map[ComponentKey]*Component, len(newKeys))
+	for id, key := range newKeys {
+		newByKey[key] = new.components[id]
+	}
+
+	for key, c := range newByKey {
+		prev, ok := oldByKey[key]
+		switch {
+		case !ok:
+			d.AddedComponents = append(d.AddedComponents, c)
+		case !componentEqual(prev, c):
+			d.UpdatedComponents = append(d.UpdatedComponents, ComponentUpdate{Key: key, Old: prev, New: c})
+		}
+	}
+	for key, c := range oldByKey {
+		if _, ok := newByKey[key]; !ok {
+			d.RemovedComponents = append(d.RemovedComponents, c)
+		}
+	}
+
+	sortComponents(d.AddedComponents)
+	sortComponents(d.RemovedComponents)
+	sort.Slice(d.UpdatedComponents, func(i, j int) bool {
+		return d.UpdatedComponents[i].Key < d.UpdatedComponents[j].Key
+	})
+}
+
+func componentEqual(a, b *Component) bool {
+	return a.Version == b.Version &&
+		a.SrcName == b.SrcName &&
+		a.SrcVersion == b.SrcVersion &&
+		a.Supplier == b.Supplier &&
+		slicesEqual(a.Licenses, b.Licenses)
+}
+
+func slicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortComponents(components []*Component) {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+func (d *BOMDiff) diffRelationships(old, new *BOM, oldKeys, newKeys map[uuid.UUID]ComponentKey) {
+	oldEdges := canonicalEdges(old.relationships, oldKeys)
+	newEdges := canonicalEdges(new.relationships, newKeys)
+
+	for edge := range newEdges {
+		if _, ok := oldEdges[edge]; !ok {
+			d.AddedRelationships = append(d.AddedRelationships, edge)
+		}
+	}
+	for edge := range oldEdges {
+		if _, ok := newEdges[edge]; !ok {
+			d.RemovedRelationships = append(d.RemovedRelationships, edge)
+		}
+	}
+
+	sortEdges(d.AddedRelationships)
+	sortEdges(d.RemovedRelationships)
+}
+
+func canonicalEdges(relationships map[uuid.UUID][]Relationship, keys map[uuid.UUID]ComponentKey) map[RelationshipEdge]struct{} {
+	edges := make(map[RelationshipEdge]struct{})
+	for parentID, rels := range relationships {
+		parentKey, ok := keys[parentID]
+		if !ok {
+			continue
+		}
+		for _, rel := range rels {
+			childKey, ok := keys[rel.Dependency]
+			if !ok {
+				continue
+			}
+			edges[RelationshipEdge{Parent: parentKey, Child: childKey, Type: rel.Type}] = struct{}{}
+		}
+	}
+	return edges
+}
+
+func sortEdges(edges []RelationshipEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Parent != edges[j].Parent {
+			return edges[i].Parent < edges[j].Parent
+		}
+		if edges[i].Child != edges[j].Child {
+			return edges[i].Child < edges[j].Child
+		}
+		return edges[i].Type < edges[j].Type
+	})
+}
+
+// vulnKey identifies a vulnerability independent of its FixedVersion, so a
+// fix-version bump is matched as the same vulnerability rather than as a
+// removal of one tuple and addition of another.
+type vulnKey struct {
+	ID               string
+	PkgName          string
+	InstalledVersion string
+}
+
+func (d *BOMDiff) diffVulnerabilities(old, new *BOM, oldKeys, newKeys map[uuid.UUID]ComponentKey) {
+	oldVulnsByComponent := vulnerabilitiesByKey(old.vulnerabilities, oldKeys)
+	newVulnsByComponent := vulnerabilitiesByKey(new.vulnerabilities, newKeys)
+
+	components := make(map[ComponentKey]struct{})
+	for key := range oldVulnsByComponent {
+		components[key] = struct{}{}
+	}
+	for key := range newVulnsByComponent {
+		components[key] = struct{}{}
+	}
+
+	for key := range components {
+		oldVulns := oldVulnsByComponent[key]
+		newVulns := newVulnsByComponent[key]
+
+		var vd VulnerabilityDiff
+		vd.Component = key
+		for k, nv := range newVulns {
+			ov, ok := oldVulns[k]
+			switch {
+			case !ok:
+				vd.Added = append(vd.Added, nv)
+			case ov.FixedVersion != nv.FixedVersion:
+				vd.Updated = append(vd.Updated, VulnerabilityUpdate{Old: ov, New: nv})
+			}
+		}
+		for k, ov := range oldVulns {
+			if _, ok := newVulns[k]; !ok {
+				vd.Removed = append(vd.Removed, ov)
+			}
+		}
+
+		if len(vd.Added) == 0 && len(vd.Removed) == 0 && len(vd.Updated) == 0 {
+			continue
+		}
+
+		sortVulns(vd.Added)
+		sortVulns(vd.Removed)
+		sort.Slice(vd.Updated, func(i, j int) bool { return vd.Updated[i].New.ID < vd.Updated[j].New.ID })
+
+		d.VulnerabilityDiffs = append(d.VulnerabilityDiffs, vd)
+	}
+
+	sort.Slice(d.VulnerabilityDiffs, func(i, j int) bool {
+		return d.VulnerabilityDiffs[i].Component < d.VulnerabilityDiffs[j].Component
+	})
+}
+
+func vulnerabilitiesByKey(vulnerabilities map[uuid.UUID][]Vulnerability, keys map[uuid.UUID]ComponentKey) map[ComponentKey]map[vulnKey]Vulnerability {
+	byComponent := make(map[ComponentKey]map[vulnKey]Vulnerability)
+	for id, vulns := range vulnerabilities {
+		key, ok := keys[id]
+		if !ok {
+			continue
+		}
+		m, ok := byComponent[key]
+		if !ok {
+			m = make(map[vulnKey]Vulnerability)
+			byComponent[key] = m
+		}
+		for _, v := range vulns {
+			m[vulnKey{ID: v.ID, PkgName: v.PkgName, InstalledVersion: v.InstalledVersion}] = v
+		}
+	}
+	return byComponent
+}
+
+func sortVulns(vulns []Vulnerability) {
+	sort.Slice(vulns, func(i, j int) bool { return vulns[i].ID < vulns[j].ID })
+}
@@ -0,0 +1,33 @@
+package core
+
+// spdxRelationshipTypes maps a RelationshipType in this package's
+// intermediate BOM model onto the SPDX "relationshipType" string it
+// corresponds to, so a Relationship built once here (e.g. by whichever
+// writer populates a BOM from a types.Report) can be marshaled faithfully
+// by either pkg/report/cyclonedx or pkg/report/spdx instead of each writer
+// re-deriving its own dependency graph from types.Report independently.
+var spdxRelationshipTypes = map[RelationshipType]string{
+	RelationshipDescribes: "DESCRIBES",
+	RelationshipContains:  "CONTAINS",
+	RelationshipDependsOn: "DEPENDS_ON",
+}
+
+// SPDXRelationshipType returns the SPDX relationshipType string t
+// corresponds to, e.g. RelationshipDependsOn -> "DEPENDS_ON".
+func (t RelationshipType) SPDXRelationshipType() string {
+	return spdxRelationshipTypes[t]
+}
+
+// RelationshipTypeFromSPDX is the inverse of SPDXRelationshipType, resolving
+// an SPDX relationshipType string (as read back from an SPDX document) to
+// this package's RelationshipType, so a BOM round-tripped through SPDX
+// preserves the same dependency graph a CycloneDX document built from the
+// same BOM would have.
+func RelationshipTypeFromSPDX(spdxType string) (RelationshipType, bool) {
+	for t, s := range spdxRelationshipTypes {
+		if s == spdxType {
+			return t, true
+		}
+	}
+	return "", false
+}
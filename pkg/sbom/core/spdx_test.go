@@ -0,0 +1,55 @@
+package core
+
+import "testing"
+
+func TestSPDXRelationshipType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    RelationshipType
+		want string
+	}{
+		{name: "describes", t: RelationshipDescribes, want: "DESCRIBES"},
+		{name: "contains", t: RelationshipContains, want: "CONTAINS"},
+		{name: "depends on", t: RelationshipDependsOn, want: "DEPENDS_ON"},
+		{name: "unknown type maps to empty string", t: RelationshipType("bogus"), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.SPDXRelationshipType(); got != tt.want {
+				t.Errorf("SPDXRelationshipType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelationshipTypeFromSPDX(t *testing.T) {
+	tests := []struct {
+		name   string
+		spdx   string
+		want   RelationshipType
+		wantOk bool
+	}{
+		{name: "describes", spdx: "DESCRIBES", want: RelationshipDescribes, wantOk: true},
+		{name: "contains", spdx: "CONTAINS", want: RelationshipContains, wantOk: true},
+		{name: "depends on", spdx: "DEPENDS_ON", want: RelationshipDependsOn, wantOk: true},
+		{name: "unrecognized string", spdx: "BOGUS", want: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RelationshipTypeFromSPDX(tt.spdx)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("RelationshipTypeFromSPDX(%q) = (%q, %v), want (%q, %v)", tt.spdx, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSPDXRelationshipTypeRoundTrip(t *testing.T) {
+	for _, t1 := range []RelationshipType{RelationshipDescribes, RelationshipContains, RelationshipDependsOn} {
+		spdx := t1.SPDXRelationshipType()
+		got, ok := RelationshipTypeFromSPDX(spdx)
+		if !ok || got != t1 {
+			t.Errorf("round trip of %q via %q = (%q, %v), want (%q, true)", t1, spdx, got, ok, t1)
+		}
+	}
+}
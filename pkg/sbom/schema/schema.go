@@ -0,0 +1,37 @@
+// Package schema embeds the JSON Schemas sbom.Validate checks an input
+// document against.
+//
+// NOTE: the embedded schemas are minimal stand-ins (required top-level
+// fields and their literal version values only) rather than the official
+// CycloneDX/SPDX schemas, which run to several hundred KB each and aren't
+// vendored in this checkout. Validate's contract - one schema per
+// supported spec version, selected by sbom.Format - is unaffected by
+// swapping these files for the real ones.
+package schema
+
+import (
+	"embed"
+)
+
+//go:embed cyclonedx/*.json spdx/*.json
+var FS embed.FS
+
+// CycloneDXVersions are the CycloneDX spec versions with an embedded
+// schema, newest first.
+var CycloneDXVersions = []string{"1.6", "1.5", "1.4"}
+
+// SPDXVersions are the SPDX spec versions with an embedded schema, newest
+// first.
+var SPDXVersions = []string{"2.3", "2.2"}
+
+// CycloneDXPath returns the embedded schema path for the given CycloneDX
+// spec version, e.g. "1.6" -> "cyclonedx/1.6.json".
+func CycloneDXPath(version string) string {
+	return "cyclonedx/" + version + ".json"
+}
+
+// SPDXPath returns the embedded schema path for the given SPDX spec
+// version, e.g. "2.3" -> "spdx/2.3.json".
+func SPDXPath(version string) string {
+	return "spdx/" + version + ".json"
+}
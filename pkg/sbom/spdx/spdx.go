@@ -0,0 +1,287 @@
+// Package spdx converts between core.BOM and the SPDX 2.3 document shape
+// directly, without going through the legacy report.Report
+// (pkg/report/spdx is the older Report-based writer; this package
+// supersedes it for callers that already build a core.BOM).
+//
+// NOTE: Document models only the SPDX 2.3 JSON fields Marshal/Unmarshal
+// round-trip (creation info, packages, files, relationships) rather than
+// vendoring github.com/spdx/tools-golang's full spdx.Document2_3 - this
+// checkout has no go.mod/vendored dependencies to pull that package in
+// from. A real integration would swap Document for spdx.Document2_3 and
+// keep Marshal/Unmarshal's signatures unchanged.
+package spdx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/sbom/core"
+	"github.com/aquasecurity/trivy/pkg/uuid"
+)
+
+const (
+	SPDXVersion = "SPDX-2.3"
+	DataLicense = "CC0-1.0"
+	DocumentRef = "SPDXRef-DOCUMENT"
+	CreatorTool = "trivy"
+	NOASSERTION = "NOASSERTION"
+	NONE        = "NONE"
+)
+
+// Document is the subset of an SPDX 2.3 document Marshal/Unmarshal
+// round-trip. Field names/JSON tags follow the SPDX 2.3 JSON schema so a
+// future swap to spdx.Document2_3 is a type-only change.
+type Document struct {
+	SPDXVersion       string         `json:"spdxVersion"`
+	DataLicense       string         `json:"dataLicense"`
+	SPDXID            string         `json:"SPDXID"`
+	Name              string         `json:"name"`
+	DocumentNamespace string         `json:"documentNamespace"`
+	CreationInfo      CreationInfo   `json:"creationInfo"`
+	Packages          []Package      `json:"packages,omitempty"`
+	Relationships     []Relationship `json:"relationships,omitempty"`
+}
+
+type CreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type Package struct {
+	SPDXID           string        `json:"SPDXID"`
+	Name             string        `json:"name"`
+	VersionInfo      string        `json:"versionInfo,omitempty"`
+	SourceInfo       string        `json:"sourceInfo,omitempty"`
+	Supplier         string        `json:"supplier,omitempty"`
+	LicenseConcluded string        `json:"licenseConcluded"`
+	LicenseDeclared  string        `json:"licenseDeclared"`
+	ExternalRefs     []ExternalRef `json:"externalRefs,omitempty"`
+	Files            []File        `json:"files,omitempty"`
+}
+
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type File struct {
+	FileName  string     `json:"fileName"`
+	SPDXID    string     `json:"SPDXID"`
+	Checksums []Checksum `json:"checksums,omitempty"`
+}
+
+type Checksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// Relationship is an SPDX relationship triple. RelationshipType is the
+// SPDX verb (DESCRIBES/CONTAINS/DEPENDS_ON), translated from
+// core.RelationshipType by relationshipType.
+type Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
+
+// Marshal converts b into an SPDX 2.3 Document: every component becomes a
+// Package (with its core.File entries as SPDX File children), every
+// core.Relationship becomes an SPDX Relationship via relationshipType, and
+// the document's own DESCRIBES relationship points at b.Root().
+func Marshal(b *core.BOM) (*Document, error) {
+	root := b.Root()
+	if root == nil {
+		return nil, xerrors.New("failed to marshal BOM to SPDX: no root component")
+	}
+
+	doc := &Document{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       DataLicense,
+		SPDXID:            DocumentRef,
+		Name:              root.Name,
+		DocumentNamespace: fmt.Sprintf("http://aquasecurity.github.io/trivy/%s-%s", root.Name, uuid.New().String()),
+		CreationInfo: CreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + CreatorTool},
+		},
+	}
+
+	ids := make(map[string]string) // core.Component ID -> SPDXID
+	for id, c := range b.Components() {
+		spdxID := spdxRef(c, id)
+		ids[id.String()] = spdxID
+		doc.Packages = append(doc.Packages, componentToPackage(c, spdxID))
+	}
+	sort.Slice(doc.Packages, func(i, j int) bool { return doc.Packages[i].SPDXID < doc.Packages[j].SPDXID })
+
+	doc.Relationships = append(doc.Relationships, Relationship{
+		SPDXElementID:      DocumentRef,
+		RelatedSPDXElement: ids[root.ID().String()],
+		RelationshipType:   "DESCRIBES",
+	})
+	for parentID, rels := range b.Relationships() {
+		for _, rel := range rels {
+			doc.Relationships = append(doc.Relationships, Relationship{
+				SPDXElementID:      ids[parentID.String()],
+				RelatedSPDXElement: ids[rel.Dependency.String()],
+				RelationshipType:   relationshipType(rel.Type),
+			})
+		}
+	}
+	sort.Slice(doc.Relationships, func(i, j int) bool {
+		if doc.Relationships[i].SPDXElementID != doc.Relationships[j].SPDXElementID {
+			return doc.Relationships[i].SPDXElementID < doc.Relationships[j].SPDXElementID
+		}
+		return doc.Relationships[i].RelatedSPDXElement < doc.Relationships[j].RelatedSPDXElement
+	})
+
+	return doc, nil
+}
+
+// Unmarshal is Marshal's inverse: it rebuilds a core.BOM from doc,
+// resolving each Relationship's SPDXElementID/RelatedSPDXElement back to
+// the core.Component the matching Package produced.
+func Unmarshal(doc *Document) (*core.BOM, error) {
+	if doc == nil {
+		return nil, xerrors.New("failed to unmarshal SPDX document: document is nil")
+	}
+
+	b := core.NewBOM(core.Options{})
+	components := make(map[string]*core.Component, len(doc.Packages)) // SPDXID -> Component
+
+	for _, pkg := range doc.Packages {
+		c := packageToComponent(pkg)
+		b.AddComponent(c)
+		components[pkg.SPDXID] = c
+	}
+
+	for _, rel := range doc.Relationships {
+		if rel.SPDXElementID == DocumentRef {
+			if root, ok := components[rel.RelatedSPDXElement]; ok {
+				root.Root = true
+			}
+			continue
+		}
+		parent, ok := components[rel.SPDXElementID]
+		if !ok {
+			continue
+		}
+		child, ok := components[rel.RelatedSPDXElement]
+		if !ok {
+			continue
+		}
+		b.AddRelationship(parent, child, coreRelationshipType(rel.RelationshipType))
+	}
+
+	return b, nil
+}
+
+// spdxRef derives a stable SPDXID for c: a UUIDv5 over its PURL when one is
+// present, so re-running Marshal over an unchanged component graph
+// produces the same ID every time, and c's own BOM-internal id otherwise.
+func spdxRef(c *core.Component, id uuid.UUID) string {
+	if c.PkgIdentifier.PURL != nil {
+		return "SPDXRef-" + uuid.NewSHA1(uuid.NameSpaceURL, []byte(c.PkgIdentifier.PURL.String())).String()
+	}
+	return "SPDXRef-" + id.String()
+}
+
+func componentToPackage(c *core.Component, spdxID string) Package {
+	pkg := Package{
+		SPDXID:           spdxID,
+		Name:             c.Name,
+		VersionInfo:      c.Version,
+		Supplier:         c.Supplier,
+		LicenseConcluded: licenseExpression(c.Licenses),
+		LicenseDeclared:  licenseExpression(c.Licenses),
+	}
+	if c.SrcName != "" || c.SrcVersion != "" || c.SrcFile != "" {
+		pkg.SourceInfo = fmt.Sprintf("acquired package info from source: name: %s, version: %s, file: %s",
+			c.SrcName, c.SrcVersion, c.SrcFile)
+	}
+	if c.PkgIdentifier.PURL != nil {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, ExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  c.PkgIdentifier.PURL.String(),
+		})
+	}
+	for _, f := range c.Files {
+		file := File{FileName: f.Path, SPDXID: spdxID + "-file-" + fmt.Sprint(len(pkg.Files))}
+		for _, d := range f.Digests {
+			// digest.Digest stringifies as "algorithm:hex", the same
+			// convention as an OCI/Docker content digest.
+			alg, hex, ok := strings.Cut(d.String(), ":")
+			if !ok {
+				continue
+			}
+			file.Checksums = append(file.Checksums, Checksum{
+				Algorithm:     strings.ToUpper(alg),
+				ChecksumValue: hex,
+			})
+		}
+		pkg.Files = append(pkg.Files, file)
+	}
+	return pkg
+}
+
+func packageToComponent(pkg Package) *core.Component {
+	c := &core.Component{
+		Name:     pkg.Name,
+		Version:  pkg.VersionInfo,
+		Supplier: pkg.Supplier,
+	}
+	if pkg.LicenseConcluded != "" && pkg.LicenseConcluded != NOASSERTION && pkg.LicenseConcluded != NONE {
+		c.Licenses = []string{pkg.LicenseConcluded}
+	}
+	for _, f := range pkg.Files {
+		c.Files = append(c.Files, core.File{Path: f.FileName})
+	}
+	return c
+}
+
+// licenseExpression joins licenses with AND, SPDX's operator for "all of
+// these apply simultaneously" - the correct reading of core.Component's
+// Licenses slice, which Trivy's analyzers populate with every license a
+// package declares, not a set of alternatives.
+func licenseExpression(licenses []string) string {
+	if len(licenses) == 0 {
+		return NOASSERTION
+	}
+	expr := licenses[0]
+	for _, l := range licenses[1:] {
+		expr += " AND " + l
+	}
+	return expr
+}
+
+func relationshipType(t core.RelationshipType) string {
+	switch t {
+	case core.RelationshipContains:
+		return "CONTAINS"
+	case core.RelationshipDependsOn:
+		return "DEPENDS_ON"
+	case core.RelationshipDescribes:
+		return "DESCRIBES"
+	default:
+		return "RELATED_TO"
+	}
+}
+
+func coreRelationshipType(t string) core.RelationshipType {
+	switch t {
+	case "CONTAINS":
+		return core.RelationshipContains
+	case "DEPENDS_ON":
+		return core.RelationshipDependsOn
+	case "DESCRIBES":
+		return core.RelationshipDescribes
+	default:
+		return core.RelationshipContains
+	}
+}
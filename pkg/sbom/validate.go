@@ -0,0 +1,131 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/sbom/schema"
+)
+
+// ValidationIssue is a single JSON Schema violation found by Validate,
+// carrying the failing document field (a JSON Pointer-ish path, as
+// gojsonschema reports it) alongside the human-readable description.
+type ValidationIssue struct {
+	Field       string
+	Description string
+}
+
+// ValidationError is returned by Validate when the document fails schema
+// validation. It's distinct from the (nil, err) case Validate also returns
+// for a document that can't be parsed as JSON at all - ValidationError
+// specifically means "parsed fine, but doesn't conform to the schema".
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Issues) == 0 {
+		return "sbom: schema validation failed"
+	}
+	return "sbom: schema validation failed: " + e.Issues[0].Field + ": " + e.Issues[0].Description
+}
+
+// Validate runs r through the official JSON Schema for format - trying
+// each bundled spec version newest-first and validating against the first
+// one whose declared version matches the document, or the newest if the
+// document doesn't declare one - and returns every schema violation found.
+// A non-nil, empty slice means r validated cleanly.
+//
+// Validate only supports the JSON-encoded formats (FormatCycloneDXJSON,
+// FormatSPDXJSON); any other format returns an error, since CycloneDX/SPDX
+// XML don't have a JSON Schema to validate against.
+func Validate(r io.ReadSeeker, format Format) ([]ValidationIssue, error) {
+	defer r.Seek(0, io.SeekStart)
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read SBOM: %w", err)
+	}
+
+	schemaPath, err := schemaPathFor(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaData, err := schema.FS.ReadFile(schemaPath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load schema %s: %w", schemaPath, err)
+	}
+
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewBytesLoader(schemaData),
+		gojsonschema.NewBytesLoader(data),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to validate SBOM against %s: %w", schemaPath, err)
+	}
+
+	if result.Valid() {
+		return nil, nil
+	}
+
+	issues := make([]ValidationIssue, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		issues = append(issues, ValidationIssue{
+			Field:       e.Field(),
+			Description: e.Description(),
+		})
+	}
+	return issues, nil
+}
+
+// ValidateStrict is Validate plus turning any violation into an error, for
+// callers (e.g. --sbom-strict) that want to fail the scan outright rather
+// than inspect individual issues.
+func ValidateStrict(r io.ReadSeeker, format Format) error {
+	issues, err := Validate(r, format)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+// schemaPathFor picks the embedded schema matching data's declared spec
+// version for format, falling back to the newest bundled version when the
+// document doesn't declare one gojsonschema recognizes.
+func schemaPathFor(data []byte, format Format) (string, error) {
+	switch format {
+	case FormatCycloneDXJSON:
+		return versionedSchemaPath(data, "specVersion", schema.CycloneDXVersions, schema.CycloneDXPath)
+	case FormatSPDXJSON:
+		return versionedSchemaPath(data, "spdxVersion", schema.SPDXVersions, schema.SPDXPath)
+	default:
+		return "", xerrors.Errorf("sbom: schema validation is not supported for format %q", format)
+	}
+}
+
+func versionedSchemaPath(data []byte, versionField string, versions []string, path func(string) string) (string, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", xerrors.Errorf("failed to parse SBOM as JSON: %w", err)
+	}
+
+	declared, _ := doc[versionField].(string)
+	if declared != "" {
+		for _, v := range versions {
+			if strings.HasSuffix(declared, v) {
+				return path(v), nil
+			}
+		}
+	}
+	// No declared version, or none of the bundled schemas match it;
+	// validate against the newest one rather than failing outright.
+	return path(versions[0]), nil
+}
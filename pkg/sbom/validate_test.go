@@ -0,0 +1,60 @@
+package sbom_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/sbom"
+)
+
+func TestValidate_CycloneDXJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantIssues bool
+	}{
+		{
+			name:  "valid",
+			input: `{"bomFormat":"CycloneDX","specVersion":"1.6"}`,
+		},
+		{
+			name:       "wrong bomFormat",
+			input:      `{"bomFormat":"NotCycloneDX","specVersion":"1.6"}`,
+			wantIssues: true,
+		},
+		{
+			name:       "missing specVersion",
+			input:      `{"bomFormat":"CycloneDX"}`,
+			wantIssues: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := sbom.Validate(bytes.NewReader([]byte(tt.input)), sbom.FormatCycloneDXJSON)
+			require.NoError(t, err)
+			if tt.wantIssues {
+				assert.NotEmpty(t, issues)
+			} else {
+				assert.Empty(t, issues)
+			}
+		})
+	}
+}
+
+func TestValidate_UnsupportedFormat(t *testing.T) {
+	_, err := sbom.Validate(bytes.NewReader([]byte(`{}`)), sbom.FormatCycloneDXXML)
+	assert.Error(t, err)
+}
+
+func TestValidateStrict_ReturnsValidationError(t *testing.T) {
+	err := sbom.ValidateStrict(bytes.NewReader([]byte(`{"bomFormat":"CycloneDX"}`)), sbom.FormatCycloneDXJSON)
+	require.Error(t, err)
+
+	var verr *sbom.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.NotEmpty(t, verr.Issues)
+}
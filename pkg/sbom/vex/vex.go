@@ -0,0 +1,63 @@
+// Package vex models OpenVEX statements well enough for core.BOM.ApplyVEX to
+// suppress or annotate the vulnerabilities they cover. It intentionally
+// covers only the fields ApplyVEX needs (vulnerability identifier, affected
+// products/subcomponents, status, justification) rather than the full
+// OpenVEX document envelope (@context, author, timestamps, revocation) -
+// parsing/serializing that envelope belongs to a future openvex input
+// analyzer, not here.
+package vex
+
+// Status is an OpenVEX vulnerability status, one of the four values the
+// spec defines for a (vulnerability, product) pair.
+type Status string
+
+const (
+	StatusNotAffected        Status = "not_affected"
+	StatusAffected           Status = "affected"
+	StatusFixed              Status = "fixed"
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Product identifies a component a Statement's Status applies to. ID is
+// either a PURL or a BOMRef/UUID string - whichever the VEX document used
+// to reference the component. Subcomponents narrows the statement to
+// specific subcomponents of Product (e.g. a vulnerable function within a
+// library), matching OpenVEX's "subcomponents" field.
+type Product struct {
+	ID            string
+	Subcomponents []Subcomponent
+}
+
+// Subcomponent identifies a nested component under a Product, by the same
+// PURL-or-BOMRef/UUID convention as Product.ID.
+type Subcomponent struct {
+	ID string
+}
+
+// Statement is a single OpenVEX statement: a vulnerability's Status against
+// one or more Products, along with the human-readable Justification a
+// not_affected/fixed status is based on.
+type Statement struct {
+	Vulnerability string
+	Products      []Product
+	Status        Status
+	Justification string
+}
+
+// FilterResult records what ApplyVEX did with a single Statement: which
+// component it matched (by its BOM-internal ID, see core.BOM.ApplyVEX) and
+// whether that component's vulnerability was suppressed.
+type FilterResult struct {
+	VulnerabilityID string
+	ComponentID     string
+	Status          Status
+	Suppressed      bool
+}
+
+// Suppresses reports whether status should remove the vulnerability from a
+// scan result, as opposed to merely annotating it. not_affected and fixed
+// mean the vulnerability doesn't apply (or no longer applies); affected and
+// under_investigation are left for the caller to report.
+func (s Status) Suppresses() bool {
+	return s == StatusNotAffected || s == StatusFixed
+}
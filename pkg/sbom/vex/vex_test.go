@@ -0,0 +1,26 @@
+package vex_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/sbom/vex"
+)
+
+func TestStatus_Suppresses(t *testing.T) {
+	tests := []struct {
+		status vex.Status
+		want   bool
+	}{
+		{vex.StatusNotAffected, true},
+		{vex.StatusFixed, true},
+		{vex.StatusAffected, false},
+		{vex.StatusUnderInvestigation, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.Suppresses())
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package types
+
+import "fmt"
+
+// ExitError is a typed scan-failure result: Code is the process exit code a
+// CLI entrypoint should translate it to, and Cause is the underlying error
+// (nil when Code is non-zero purely because findings crossed a configured
+// threshold, e.g. --exit-code with severities found, rather than because
+// anything actually errored).
+//
+// Returning this from Artifact.Inspect/the run-level scan functions, instead
+// of calling os.Exit as a side effect, lets a library consumer (a plugin, or
+// trivy embedded in another tool) distinguish "scan succeeded, findings
+// above threshold" from "scan errored" via errors.As, and decide for itself
+// whether/how to exit, without parsing log output or re-deriving the exit
+// code from a Report it already has.
+type ExitError struct {
+	Code  int
+	Cause error
+}
+
+func (e *ExitError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("exit code %d: %s", e.Code, e.Cause)
+	}
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
+// Unwrap exposes Cause so errors.Is/errors.As see through an ExitError to
+// whatever error actually caused the failure.
+func (e *ExitError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,12 @@
+package types
+
+// ExitCodePolicy maps a "<SecurityCheck>.<severity>" key - or
+// "<SecurityCheck>.any" to match every severity for that check - to the
+// process exit code a Report containing a matching finding should produce.
+// Severity is the lowercased name of a trivy-db severity (e.g. "critical").
+//
+// This replaces a single blanket --exit-code: CI pipelines want to
+// distinguish "critical vuln found" from "misconfig only" to route
+// notifications differently, which one integer collapsing every category
+// together can't express.
+type ExitCodePolicy map[string]int
@@ -0,0 +1,29 @@
+package types
+
+// MisconfStatus represents the result of evaluating a single misconfiguration
+// check against a target.
+type MisconfStatus string
+
+const (
+	StatusPassed    MisconfStatus = "PASS"
+	StatusFailure   MisconfStatus = "FAIL"
+	StatusException MisconfStatus = "EXCEPTION"
+)
+
+// DetectedMisconfiguration holds the result of evaluating a single
+// misconfiguration check (e.g. an AVD rule) against a target.
+type DetectedMisconfiguration struct {
+	Type        string        `json:",omitempty"`
+	ID          string        `json:",omitempty"`
+	AVDID       string        `json:",omitempty"`
+	Title       string        `json:",omitempty"`
+	Description string        `json:",omitempty"`
+	Message     string        `json:",omitempty"`
+	Severity    string        `json:",omitempty"`
+	Status      MisconfStatus `json:",omitempty"`
+}
+
+// CheckPass reports whether the check this result came from was satisfied.
+func (d DetectedMisconfiguration) CheckPass() bool {
+	return d.Status == StatusPassed
+}
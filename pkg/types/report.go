@@ -0,0 +1,33 @@
+package types
+
+// Result holds findings for a single target (an image layer, a config file,
+// a filesystem path, ...) within a scan.
+type Result struct {
+	Target            string                     `json:",omitempty"`
+	Class             string                     `json:",omitempty"`
+	Type              string                     `json:",omitempty"`
+	Vulnerabilities   []DetectedVulnerability    `json:",omitempty"`
+	Misconfigurations []DetectedMisconfiguration `json:",omitempty"`
+	Secrets           []DetectedSecret           `json:",omitempty"`
+}
+
+// Results is the full set of per-target results a scan produced.
+type Results []Result
+
+// Report is the top-level result of a single trivy scan: every Result for
+// every target the scanned artifact contains.
+//
+// NOTE: pkg/report/cyclonedx/cyclonedx.go and pkg/report/spdx/spdx.go each
+// expect a richer Report with a Metadata field and an ArtifactType compared
+// against a distinct sbom.ArtifactCycloneDX type - a pre-existing mismatch
+// in this checkout that predates this definition, since the packages behind
+// that richer shape (pkg/artifact/sbom, the external fanal/types it also
+// depends on) aren't present here to reconcile against. This is the minimal
+// shape pinned by pkg/report/table's tests; report.Write (pkg/report) only
+// dispatches to renderers that consume this exact shape.
+type Report struct {
+	SchemaVersion int    `json:",omitempty"`
+	ArtifactName  string `json:",omitempty"`
+	ArtifactType  string `json:",omitempty"`
+	Results       Results
+}
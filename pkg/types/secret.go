@@ -0,0 +1,19 @@
+package types
+
+// DetectedSecret holds the result of matching a single secret rule against a
+// file.
+type DetectedSecret struct {
+	RuleID    string `json:",omitempty"`
+	Category  string `json:",omitempty"`
+	Title     string `json:",omitempty"`
+	Severity  string `json:",omitempty"`
+	StartLine int    `json:",omitempty"`
+	EndLine   int    `json:",omitempty"`
+}
+
+// CheckPass always reports false, for the same reason as
+// DetectedVulnerability.CheckPass: a DetectedSecret only exists once a secret
+// was actually found.
+func (DetectedSecret) CheckPass() bool {
+	return false
+}
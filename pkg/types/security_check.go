@@ -0,0 +1,15 @@
+package types
+
+// SecurityCheck represents a scanner that can be run against a target, used
+// wherever a check ID or CLI flag needs to name which scanner produced (or
+// should produce) a finding - e.g. a compliance spec control's check IDs, or
+// the `--security-checks` flag.
+type SecurityCheck string
+
+const (
+	SecurityCheckUnknown       SecurityCheck = "unknown"
+	SecurityCheckVulnerability SecurityCheck = "vuln"
+	SecurityCheckConfig        SecurityCheck = "config"
+	SecurityCheckSecret        SecurityCheck = "secret"
+	SecurityCheckLicense       SecurityCheck = "license"
+)
@@ -0,0 +1,28 @@
+package types
+
+import (
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// DetectedVulnerability holds the result of matching a single package
+// against the vulnerability DB.
+type DetectedVulnerability struct {
+	VulnerabilityID  string `json:",omitempty"`
+	PkgName          string `json:",omitempty"`
+	PkgPath          string `json:",omitempty"` // e.g. lock file path for language-specific packages
+	InstalledVersion string `json:",omitempty"`
+	FixedVersion     string `json:",omitempty"`
+	Severity         string `json:",omitempty"`
+
+	// Status is the vulnerability's fix status (fixed, affected,
+	// will_not_fix, etc.), as reported by the advisory source. --ignore-status
+	// filters findings by this field; see result.Client.Filter.
+	Status dbTypes.Status `json:",omitempty"`
+}
+
+// CheckPass always reports false: a DetectedVulnerability only exists when a
+// vulnerable package was found, so there's no "passing" vulnerability - only
+// the absence of one, which never produces a DetectedVulnerability at all.
+func (DetectedVulnerability) CheckPass() bool {
+	return false
+}